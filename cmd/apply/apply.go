@@ -0,0 +1,139 @@
+package apply
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	jiraclient "github.com/lburgazzoli/gira/internal/client"
+	"github.com/lburgazzoli/gira/pkg/config"
+	"github.com/lburgazzoli/gira/pkg/spec"
+	"github.com/spf13/cobra"
+)
+
+var (
+	applyFile string
+	applyYes  bool
+)
+
+var Cmd = &cobra.Command{
+	Use:   "apply -f SPEC",
+	Short: "Create or update issues to match a spec",
+	Long: `Create or update the issues described by a YAML spec, after showing the
+changes and asking for confirmation (unless --yes is given).
+
+Every issue has an external ID, stored in a label (gira:<id> by default) or
+in an entity property, through which it is found again: applying the same
+spec twice changes nothing. Issues are never deleted, and only the
+attributes given in the spec are managed. The issue type is only used on
+creation.
+
+  project: PROJ
+  externalId:
+    store: label            # or property
+    # prefix: "gira:"       # label prefix
+    # property: gira        # entity property, holding {"externalId": ID}
+    # scope: "project = PROJ AND labels = release"   # searched for properties
+  issues:
+    - id: release-1.5
+      type: Epic
+      summary: Release 1.5
+      assignee: me
+      labels: [release]
+      fixVersions: [1.5.0]
+      children:
+        - id: release-1.5-docs
+          type: Story
+          summary: Update the documentation
+          fields:
+            Story Points: 3
+          links:
+            - type: blocks
+              to: release-1.5-announce
+          children:
+            - id: release-1.5-docs-api
+              type: Sub-task
+              summary: Document the new API
+        - id: release-1.5-announce
+          type: Story
+          summary: Announce the release
+
+Stories are attached to epics through the Epic Link field when the instance
+has one, and through parent otherwise. Links target issues of the spec by ID
+or any issue by key.
+
+Examples:
+  gira apply -f release-checklist.yaml
+  gira apply -f onboarding.yaml --yes`,
+	Args: cobra.NoArgs,
+	RunE: runApply,
+}
+
+func init() {
+	Cmd.Flags().StringVarP(&applyFile, "file", "f", "", "Spec file")
+	Cmd.Flags().BoolVarP(&applyYes, "yes", "y", false, "Do not ask for confirmation")
+	_ = Cmd.MarkFlagRequired("file")
+}
+
+func runApply(_ *cobra.Command, _ []string) error {
+	s, err := spec.Load(applyFile)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	client, err := jiraclient.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create JIRA client: %w", err)
+	}
+
+	plan, err := spec.NewPlan(client, s)
+	if err != nil {
+		return err
+	}
+
+	spec.WritePlan(os.Stdout, plan)
+
+	if !plan.HasChanges() {
+		return nil
+	}
+
+	if !applyYes {
+		fmt.Print("\nApply these changes? [y/N]: ")
+
+		input, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if answer := strings.ToLower(strings.TrimSpace(input)); answer != "y" && answer != "yes" {
+			fmt.Println("Aborted, nothing was changed.")
+			return nil
+		}
+	}
+
+	fmt.Println()
+
+	green := color.New(color.FgGreen).SprintFunc()
+
+	err = plan.Apply(client, func(issue *spec.IssueChange, link *spec.LinkChange) {
+		switch {
+		case link != nil:
+			fmt.Printf("%s Linked %s %s %s\n", green("✓"), link.From, link.Relation, link.To)
+		case issue.Action == spec.ActionCreate:
+			fmt.Printf("%s Created %s as %s\n", green("✓"), issue.ID, issue.Key)
+		default:
+			fmt.Printf("%s Updated %s (%s)\n", green("✓"), issue.ID, issue.Key)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	create, update, _, links := plan.Count()
+	fmt.Printf("\nApplied: %d created, %d updated, %d links added.\n", create, update, links)
+
+	return nil
+}
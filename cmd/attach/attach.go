@@ -0,0 +1,136 @@
+package attach
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	jiraclient "github.com/lburgazzoli/gira/internal/client"
+	"github.com/lburgazzoli/gira/pkg/config"
+	"github.com/lburgazzoli/gira/pkg/jira"
+	"github.com/lburgazzoli/gira/pkg/utils/progress"
+	sizeutils "github.com/lburgazzoli/gira/pkg/utils/size"
+	"github.com/spf13/cobra"
+)
+
+var attachMaxSize string
+
+var Cmd = &cobra.Command{
+	Use:   "attach ISSUE-KEY FILE...",
+	Short: "Attach files to an issue",
+	Long: `Attach files to an issue, reporting the progress of each upload.
+
+Files larger than the attachment limit of the JIRA instance are rejected
+before anything is uploaded; --max-size sets a lower limit.
+
+Examples:
+  gira attach PROJ-123 screenshot.png
+  gira attach PROJ-123 logs/*.txt --max-size 2MB`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runAttach,
+}
+
+func init() {
+	Cmd.Flags().StringVar(&attachMaxSize, "max-size", "", "Maximum size of each file, e.g. 5MB (default: the JIRA upload limit)")
+}
+
+func runAttach(_ *cobra.Command, args []string) error {
+	key := strings.ToUpper(args[0])
+
+	limit := int64(0)
+	if attachMaxSize != "" {
+		n, err := sizeutils.Parse(attachMaxSize)
+		if err != nil {
+			return err
+		}
+		limit = n
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	client, err := jiraclient.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create JIRA client: %w", err)
+	}
+
+	meta, err := client.GetAttachmentMeta()
+	if err != nil {
+		return err
+	}
+	if !meta.Enabled {
+		return fmt.Errorf("attachments are disabled on this JIRA instance")
+	}
+	if meta.UploadLimit > 0 && (limit == 0 || meta.UploadLimit < limit) {
+		limit = meta.UploadLimit
+	}
+
+	// Check every file before uploading any of them
+	sizes := make([]int64, 0, len(args)-1)
+	for _, path := range args[1:] {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return fmt.Errorf("%s is not a regular file", path)
+		}
+		if limit > 0 && info.Size() > limit {
+			return fmt.Errorf("%s is too large: %s, the limit is %s",
+				path, sizeutils.Format(info.Size()), sizeutils.Format(limit))
+		}
+
+		sizes = append(sizes, info.Size())
+	}
+
+	green := color.New(color.FgGreen).SprintFunc()
+
+	for i, path := range args[1:] {
+		attachment, err := upload(client, key, path, sizes[i])
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s Attached %s (%s) to %s, attachment %s\n",
+			green("✓"), attachment.Filename, sizeutils.Format(attachment.Size), key, attachment.ID)
+	}
+
+	return nil
+}
+
+// upload attaches a file, reporting its progress
+func upload(client *jira.Client, key string, path string, size int64) (*jira.Attachment, error) {
+	filename := filepath.Base(path)
+	counter := progress.NewCounter("Uploading "+filename, size)
+	defer counter.Done()
+
+	attachments, err := client.UploadAttachment(key, jira.AttachmentUpload{
+		Filename: filename,
+		Open: func() (io.ReadCloser, error) {
+			f, err := os.Open(path)
+			if err != nil {
+				return nil, err
+			}
+
+			counter.Reset()
+
+			return struct {
+				io.Reader
+				io.Closer
+			}{io.TeeReader(f, counter), f}, nil
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to attach %s: %w", path, err)
+	}
+	if len(attachments) == 0 {
+		return nil, fmt.Errorf("failed to attach %s: no attachment was created", path)
+	}
+
+	return &attachments[0], nil
+}
@@ -0,0 +1,371 @@
+package bulk
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/fatih/color"
+	jiraclient "github.com/lburgazzoli/gira/internal/client"
+	"github.com/lburgazzoli/gira/pkg/config"
+	"github.com/lburgazzoli/gira/pkg/jira"
+	stringutils "github.com/lburgazzoli/gira/pkg/utils/strings"
+	tableutils "github.com/lburgazzoli/gira/pkg/utils/table"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// defaultConcurrency is the number of issues changed at the same time,
+	// kept low since JIRA rate limits writes
+	defaultConcurrency = 4
+)
+
+// previewFields are the issue fields displayed in the preview
+var previewFields = []string{"summary", "status", "issuetype", "assignee"}
+
+var (
+	bulkJQL         string
+	bulkYes         bool
+	bulkConcurrency int
+
+	updateSet         []string
+	updateAddLabel    []string
+	updateRemoveLabel []string
+
+	transitionTo string
+)
+
+var Cmd = &cobra.Command{
+	Use:   "bulk",
+	Short: "Change many issues at once",
+	Long: `Update or transition all the issues matching a JQL query.
+
+The affected issues are listed before any change is made and the operation
+must be confirmed, unless --yes is given. Each issue is reported as it is
+changed, and the command fails when any issue could not be changed.`,
+}
+
+var updateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Update the fields of the issues matching a query",
+	Long: `Update the fields of the issues matching a query.
+
+--set takes FIELD=VALUE, where FIELD is a field ID or name, custom fields
+included. Array fields take comma separated values, users take "me", an
+account ID, a username, an email or a name, and an empty value or "none"
+clears the field.
+
+Examples:
+  gira bulk update --jql "project = PROJ AND sprint in openSprints() AND status != Done" --set Sprint=124
+  gira bulk update --jql "labels = triage" --set assignee=alice@example.com --remove-label triage
+  gira bulk update --jql "fixVersion = 1.4.0 AND resolution IS EMPTY" --set fixVersions=1.5.0 --yes
+  gira bulk update --jql "project = PROJ AND component = api" --add-label backend --set "Story Points=3"`,
+	Args: cobra.NoArgs,
+	RunE: runUpdate,
+}
+
+var transitionCmd = &cobra.Command{
+	Use:   "transition",
+	Short: "Transition the issues matching a query",
+	Long: `Transition the issues matching a query, given a transition or target
+status name. Issues already in the target status are skipped.
+
+Examples:
+  gira bulk transition --jql "project = PROJ AND fixVersion = 1.4.0 AND status = Review" --to Done
+  gira bulk transition --jql "sprint = 123 AND status = 'To Do'" --to "In Progress" --yes`,
+	Args: cobra.NoArgs,
+	RunE: runTransition,
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{updateCmd, transitionCmd} {
+		cmd.Flags().StringVar(&bulkJQL, "jql", "", "JQL query selecting the issues")
+		cmd.Flags().BoolVarP(&bulkYes, "yes", "y", false, "Do not ask for confirmation")
+		cmd.Flags().IntVar(&bulkConcurrency, "concurrency", defaultConcurrency, "Number of issues changed at the same time")
+		_ = cmd.MarkFlagRequired("jql")
+	}
+
+	updateCmd.Flags().StringArrayVar(&updateSet, "set", nil, "Set a field, as FIELD=VALUE (repeatable)")
+	updateCmd.Flags().StringSliceVar(&updateAddLabel, "add-label", nil, "Add labels (repeatable)")
+	updateCmd.Flags().StringSliceVar(&updateRemoveLabel, "remove-label", nil, "Remove labels (repeatable)")
+
+	transitionCmd.Flags().StringVar(&transitionTo, "to", "", "Transition or target status name")
+	_ = transitionCmd.MarkFlagRequired("to")
+
+	Cmd.AddCommand(updateCmd)
+	Cmd.AddCommand(transitionCmd)
+}
+
+func runUpdate(_ *cobra.Command, _ []string) error {
+	if len(updateSet) == 0 && len(updateAddLabel) == 0 && len(updateRemoveLabel) == 0 {
+		return fmt.Errorf("nothing to update, use --set, --add-label or --remove-label")
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	update, changes, err := buildUpdate(client)
+	if err != nil {
+		return err
+	}
+
+	issues, err := searchIssues(client)
+	if err != nil {
+		return err
+	}
+
+	if !confirm(issues, "update", changes) {
+		return nil
+	}
+
+	return run(issues, "update", func(issue jira.Issue) (string, error) {
+		if _, err := client.UpdateIssue(issue.Key, update); err != nil {
+			return "", err
+		}
+		return "updated", nil
+	})
+}
+
+func runTransition(_ *cobra.Command, _ []string) error {
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	issues, err := searchIssues(client)
+	if err != nil {
+		return err
+	}
+
+	if !confirm(issues, "transition", []string{"to " + transitionTo}) {
+		return nil
+	}
+
+	return run(issues, "transition", func(issue jira.Issue) (string, error) {
+		if strings.EqualFold(issue.Fields.Status.Name, transitionTo) {
+			return "already " + issue.Fields.Status.Name + ", skipped", nil
+		}
+
+		// Workflows differ between issue types, transitions are looked up
+		// per issue
+		transitions, err := client.GetTransitions(issue.Key)
+		if err != nil {
+			return "", err
+		}
+
+		transition, err := jira.FindTransition(transitions, transitionTo)
+		if err != nil {
+			return "", err
+		}
+
+		if err := client.TransitionIssue(issue.Key, transition.ID); err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("%s → %s", issue.Fields.Status.Name, transition.To.Name), nil
+	})
+}
+
+// searchIssues returns the issues matching --jql. Changes are decided on the
+// current state of the issues, so cached searches are not used.
+func searchIssues(client *jira.Client) ([]jira.Issue, error) {
+	client.InvalidateSearches()
+
+	issues, err := client.SearchAllIssues(bulkJQL, previewFields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search issues: %w", err)
+	}
+
+	return issues, nil
+}
+
+// buildUpdate converts --set, --add-label and --remove-label to an issue
+// update, and describes the changes for the preview
+func buildUpdate(client *jira.Client) (jira.IssueUpdate, []string, error) {
+	update := jira.IssueUpdate{}
+	changes := make([]string, 0)
+
+	if len(updateSet) > 0 {
+		fields, err := client.GetFields()
+		if err != nil {
+			return update, nil, err
+		}
+
+		update.Fields = make(map[string]interface{}, len(updateSet))
+		for _, set := range updateSet {
+			name, value, ok := strings.Cut(set, "=")
+			if !ok || strings.TrimSpace(name) == "" {
+				return update, nil, fmt.Errorf("invalid --set %q, expected FIELD=VALUE", set)
+			}
+
+			field, err := jira.FindField(fields, strings.TrimSpace(name))
+			if err != nil {
+				return update, nil, err
+			}
+			if _, ok := update.Fields[field.ID]; ok {
+				return update, nil, fmt.Errorf("%s is set more than once", field.Name)
+			}
+
+			v, err := client.FieldValue(field, value)
+			if err != nil {
+				return update, nil, err
+			}
+
+			update.Fields[field.ID] = v
+
+			value = strings.TrimSpace(value)
+			if value == "" || strings.EqualFold(value, "none") {
+				changes = append(changes, fmt.Sprintf("clear %s", field.Name))
+			} else {
+				changes = append(changes, fmt.Sprintf("set %s = %s", field.Name, value))
+			}
+		}
+	}
+
+	if len(updateAddLabel) > 0 || len(updateRemoveLabel) > 0 {
+		if _, ok := update.Fields["labels"]; ok {
+			return update, nil, fmt.Errorf("labels cannot be both set and added or removed")
+		}
+
+		operations := make([]interface{}, 0, len(updateAddLabel)+len(updateRemoveLabel))
+		for _, label := range updateAddLabel {
+			operations = append(operations, map[string]string{"add": label})
+			changes = append(changes, fmt.Sprintf("add label %s", label))
+		}
+		for _, label := range updateRemoveLabel {
+			operations = append(operations, map[string]string{"remove": label})
+			changes = append(changes, fmt.Sprintf("remove label %s", label))
+		}
+
+		update.Update = map[string]interface{}{"labels": operations}
+	}
+
+	return update, changes, nil
+}
+
+// confirm previews the issues about to be changed and asks for confirmation
+// unless --yes is given
+func confirm(issues []jira.Issue, verb string, changes []string) bool {
+	if len(issues) == 0 {
+		fmt.Println("No issues match the query.")
+		return false
+	}
+
+	if err := outputPreview(issues); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to render preview: %v\n", err)
+	}
+
+	fmt.Printf("\nAbout to %s %d issues: %s\n", verb, len(issues), strings.Join(changes, ", "))
+
+	if bulkYes {
+		return true
+	}
+
+	fmt.Print("Proceed? [y/N]: ")
+
+	input, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(input)) {
+	case "y", "yes":
+		return true
+	default:
+		fmt.Println("Aborted, no issue was changed.")
+		return false
+	}
+}
+
+// run applies a change to the issues with bounded concurrency, reporting
+// each issue as it completes
+func run(issues []jira.Issue, verb string, change func(issue jira.Issue) (string, error)) error {
+	concurrency := max(bulkConcurrency, 1)
+
+	green := color.New(color.FgGreen).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		failed int
+	)
+
+	slots := make(chan struct{}, concurrency)
+	for _, issue := range issues {
+		wg.Add(1)
+		slots <- struct{}{}
+
+		go func() {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+
+			result, err := change(issue)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				failed++
+				fmt.Printf("%s %s: %s\n", red("✗"), issue.Key, strings.TrimSpace(err.Error()))
+				return
+			}
+
+			fmt.Printf("%s %s: %s\n", green("✓"), issue.Key, result)
+		}()
+	}
+
+	wg.Wait()
+
+	if failed > 0 {
+		return fmt.Errorf("failed to %s %d of %d issues", verb, failed, len(issues))
+	}
+
+	fmt.Printf("\n%s Done, %d issues processed\n", green("✓"), len(issues))
+
+	return nil
+}
+
+func outputPreview(issues []jira.Issue) error {
+	renderer := tableutils.NewRenderer(
+		tableutils.WithHeaders("KEY", "TYPE", "STATUS", "ASSIGNEE", "SUMMARY"),
+	)
+
+	rows := make([][]any, 0, len(issues))
+	for _, issue := range issues {
+		assignee := "Unassigned"
+		if issue.Fields.Assignee != nil {
+			assignee = issue.Fields.Assignee.DisplayName
+		}
+
+		rows = append(rows, []any{
+			issue.Key,
+			issue.Fields.IssueType.Name,
+			issue.Fields.Status.Name,
+			assignee,
+			stringutils.Truncate(issue.Fields.Summary, 60),
+		})
+	}
+
+	if err := renderer.AppendAll(rows); err != nil {
+		return err
+	}
+
+	return renderer.Render()
+}
+
+func newClient() (*jira.Client, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	client, err := jiraclient.New(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create JIRA client: %w", err)
+	}
+
+	return client, nil
+}
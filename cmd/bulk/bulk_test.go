@@ -0,0 +1,218 @@
+package bulk
+
+import (
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lburgazzoli/gira/pkg/jira"
+	"github.com/lburgazzoli/gira/pkg/jira/jiratest"
+)
+
+// captureStdout returns what f prints to the standard output
+func captureStdout(t *testing.T, f func()) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	out := make(chan string)
+	go func() {
+		b, _ := io.ReadAll(r)
+		out <- string(b)
+	}()
+
+	f()
+
+	_ = w.Close()
+	return <-out
+}
+
+func TestBuildUpdate(t *testing.T) {
+	tests := []struct {
+		name        string
+		set         []string
+		add         []string
+		remove      []string
+		wantFields  map[string]interface{}
+		wantUpdate  map[string]interface{}
+		wantChanges []string
+		wantErr     bool
+	}{
+		{
+			name: "fields by ID and name",
+			set:  []string{"summary=New summary", "Story Points=3", "assignee=john@example.com", "priority=none"},
+			wantFields: map[string]interface{}{
+				"summary":                 "New summary",
+				jiratest.StoryPointsField: 3.0,
+				"assignee":                map[string]string{"accountId": "acc-john"},
+				"priority":                nil,
+			},
+			wantChanges: []string{"set Summary = New summary", "set Story Points = 3", "set Assignee = john@example.com", "clear Priority"},
+		},
+		{
+			name:   "labels",
+			add:    []string{"backend"},
+			remove: []string{"triage"},
+			wantUpdate: map[string]interface{}{
+				"labels": []interface{}{map[string]string{"add": "backend"}, map[string]string{"remove": "triage"}},
+			},
+			wantChanges: []string{"add label backend", "remove label triage"},
+		},
+		{name: "missing value", set: []string{"summary"}, wantErr: true},
+		{name: "unknown field", set: []string{"Sprint=1"}, wantErr: true},
+		{name: "invalid value", set: []string{"Story Points=many"}, wantErr: true},
+		{name: "field set twice", set: []string{"summary=a", "Summary=b"}, wantErr: true},
+		{name: "labels set and added", set: []string{"labels=a"}, add: []string{"b"}, wantErr: true},
+	}
+
+	srv := jiratest.NewServer(jiratest.WithUsers(jira.User{AccountID: "acc-john", DisplayName: "John", EmailAddress: "john@example.com"}))
+	t.Cleanup(srv.Close)
+
+	client, err := srv.NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updateSet, updateAddLabel, updateRemoveLabel = tt.set, tt.add, tt.remove
+			t.Cleanup(func() { updateSet, updateAddLabel, updateRemoveLabel = nil, nil, nil })
+
+			update, changes, err := buildUpdate(client)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("built %+v", update)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(update.Fields, tt.wantFields) {
+				t.Errorf("fields = %#v, want %#v", update.Fields, tt.wantFields)
+			}
+			if !reflect.DeepEqual(update.Update, tt.wantUpdate) {
+				t.Errorf("update = %#v, want %#v", update.Update, tt.wantUpdate)
+			}
+			if !reflect.DeepEqual(changes, tt.wantChanges) {
+				t.Errorf("changes = %q, want %q", changes, tt.wantChanges)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	issues := make([]jira.Issue, 0)
+	for _, key := range []string{"DEMO-1", "DEMO-2", "DEMO-3", "DEMO-4", "DEMO-5", "DEMO-6", "DEMO-7"} {
+		issues = append(issues, jira.Issue{Key: key})
+	}
+
+	bulkConcurrency = 3
+	t.Cleanup(func() { bulkConcurrency = defaultConcurrency })
+
+	var running, peak atomic.Int32
+	change := func(issue jira.Issue) (string, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+
+		time.Sleep(20 * time.Millisecond)
+
+		if issue.Key == "DEMO-2" || issue.Key == "DEMO-5" {
+			return "", errors.New("forbidden\n")
+		}
+		return "updated", nil
+	}
+
+	var err error
+	out := captureStdout(t, func() {
+		err = run(issues, "update", change)
+	})
+
+	if err == nil || err.Error() != "failed to update 2 of 7 issues" {
+		t.Errorf("err = %v, want 2 of 7 issues failed", err)
+	}
+	if p := peak.Load(); p > 3 || p < 2 {
+		t.Errorf("%d changes ran at the same time, want at most 3", p)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	sort.Strings(lines)
+	want := []string{
+		"✓ DEMO-1: updated",
+		"✓ DEMO-3: updated",
+		"✓ DEMO-4: updated",
+		"✓ DEMO-6: updated",
+		"✓ DEMO-7: updated",
+		"✗ DEMO-2: forbidden",
+		"✗ DEMO-5: forbidden",
+	}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("output = %q, want %q", lines, want)
+	}
+
+	out = captureStdout(t, func() {
+		err = run(issues[:2], "transition", func(jira.Issue) (string, error) { return "done", nil })
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "Done, 2 issues processed") {
+		t.Errorf("output = %q, want the summary", out)
+	}
+}
+
+func TestSearchIssuesIgnoresCache(t *testing.T) {
+	srv := jiratest.NewServer()
+	t.Cleanup(srv.Close)
+
+	cache, err := jira.NewFileCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := srv.NewClient(jira.WithCache(cache, jira.DefaultCachePolicy()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bulkJQL = "project = DEMO"
+	t.Cleanup(func() { bulkJQL = "" })
+
+	add := func() {
+		srv.AddIssue(jira.Issue{Fields: jira.IssueFields{
+			Summary:   "Issue",
+			IssueType: jira.IssueType{Name: "Task"},
+			Project:   jira.Project{Key: "DEMO"},
+		}})
+	}
+
+	add()
+	if issues, err := searchIssues(client); err != nil || len(issues) != 1 {
+		t.Fatalf("got %d issues (%v), want 1", len(issues), err)
+	}
+
+	// Changed by another client meanwhile
+	add()
+	if issues, err := searchIssues(client); err != nil || len(issues) != 2 {
+		t.Errorf("got %d issues (%v), want 2", len(issues), err)
+	}
+}
@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/lburgazzoli/gira/pkg/jira"
+	"github.com/spf13/cobra"
+)
+
+var pruneAll bool
+
+var Cmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the local response cache",
+	Long:  `Manage the responses cached under $XDG_CACHE_HOME/gira.`,
+}
+
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove expired cache entries",
+	Long: `Remove the cached responses that can no longer be served. Expired entries
+are also pruned the first time gira writes to the cache.
+
+Examples:
+  gira cache prune
+  gira cache prune --all`,
+	Args: cobra.NoArgs,
+	RunE: runPrune,
+}
+
+func init() {
+	pruneCmd.Flags().BoolVar(&pruneAll, "all", false, "Remove every entry, expired or not")
+
+	Cmd.AddCommand(pruneCmd)
+}
+
+func runPrune(_ *cobra.Command, _ []string) error {
+	dir, err := jira.DefaultCacheDir()
+	if err != nil {
+		return fmt.Errorf("failed to determine cache directory: %w", err)
+	}
+
+	cache, err := jira.NewFileCache(dir)
+	if err != nil {
+		return err
+	}
+
+	policy := jira.DefaultCachePolicy()
+
+	removed := 0
+	err = cache.Invalidate(func(entry *jira.CacheEntry) bool {
+		if pruneAll || policy.Expired(entry) {
+			removed++
+			return true
+		}
+		return false
+	})
+	if err != nil {
+		return fmt.Errorf("failed to prune cache: %w", err)
+	}
+
+	green := color.New(color.FgGreen).SprintFunc()
+	fmt.Printf("%s Removed %d cache entries from %s\n", green("✓"), removed, dir)
+
+	return nil
+}
@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/lburgazzoli/gira/pkg/config"
 	"github.com/spf13/cobra"
@@ -38,11 +40,21 @@ var setCmd = &cobra.Command{
 	Long: `Set a configuration value. Supported keys:
   jira.base_url    - JIRA instance URL
   jira.token       - JIRA Personal Access Token
+  jira.proxy       - HTTP(S) proxy URL used to reach JIRA
+  jira.timeout     - Time to wait for a response (e.g. 30s, 1m)
+  jira.tls.ca_file - PEM bundle of additional CAs to trust
+  jira.tls.cert_file - PEM client certificate for mTLS
+  jira.tls.key_file  - PEM client key for mTLS
+  jira.tls.insecure_skip_verify - Skip server certificate verification (true, false)
+  jira.retry.max   - Maximum number of retries of a failed request
+  jira.retry.max_wait - Maximum wait between retries (e.g. 30s)
+  jira.rate_limit  - Maximum requests per second (0 disables the limiter)
   ai.provider      - AI provider (google)
   ai.api_key       - AI API key
   cli.output_format - Output format (table, json, yaml)
   cli.color        - Enable colored output (true, false)
-  cli.verbose      - Enable verbose output (true, false)`,
+  cli.verbose      - Enable verbose output (true, false)
+  mirror.path      - SQLite file of the offline mirror`,
 	Args: cobra.ExactArgs(2),
 	RunE: runSet,
 }
@@ -103,38 +115,42 @@ func runInit(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to read color setting: %w", err)
 	}
-	color := strings.ToLower(colorStr) == "true"
+	color, err := strconv.ParseBool(colorStr)
+	if err != nil {
+		return fmt.Errorf("invalid color setting %q: must be true or false", colorStr)
+	}
 	
 	verboseStr, err := promptString(reader, "Enable Verbose Output", "false")
 	if err != nil {
 		return fmt.Errorf("failed to read verbose setting: %w", err)
 	}
-	verbose := strings.ToLower(verboseStr) == "true"
-	
-	// Create configuration
-	cfg := config.Config{
-		JIRA: config.JIRAConfig{
-			BaseURL: baseURL,
-			Token:   token,
-		},
-		AI: config.AIConfig{
-			Provider: provider,
-			APIKey:   apiKey,
-			Models: map[string]string{
-				"explain": "gemini-pro",
-				"enhance": "gemini-pro",
-				"chat":    "gemini-pro",
-			},
-		},
-		CLI: config.CLIConfig{
-			OutputFormat: outputFormat,
-			Color:        color,
-			Verbose:      verbose,
-		},
+	verbose, err := strconv.ParseBool(verboseStr)
+	if err != nil {
+		return fmt.Errorf("invalid verbose setting %q: must be true or false", verboseStr)
 	}
 	
+	// Start from the loaded configuration so that defaults, and settings
+	// not covered by the wizard, are saved along with the answers
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	cfg.JIRA.BaseURL = baseURL
+	cfg.JIRA.Token = token
+	cfg.AI.Provider = provider
+	cfg.AI.APIKey = apiKey
+	cfg.AI.Models = map[string]string{
+		"explain": "gemini-pro",
+		"enhance": "gemini-pro",
+		"chat":    "gemini-pro",
+	}
+	cfg.CLI.OutputFormat = outputFormat
+	cfg.CLI.Color = color
+	cfg.CLI.Verbose = verbose
+
 	// Save configuration
-	return saveConfig(&cfg)
+	return saveConfig(cfg)
 }
 
 func runShow(cmd *cobra.Command, args []string) error {
@@ -165,7 +181,7 @@ func runSet(cmd *cobra.Command, args []string) error {
 	}
 	
 	// Parse key and update configuration
-	parts := strings.Split(key, ".")
+	parts := strings.SplitN(key, ".", 2)
 	if len(parts) != 2 {
 		return fmt.Errorf("invalid key format. Use section.key (e.g., jira.base_url)")
 	}
@@ -179,6 +195,44 @@ func runSet(cmd *cobra.Command, args []string) error {
 			cfg.JIRA.BaseURL = value
 		case "token":
 			cfg.JIRA.Token = value
+		case "proxy":
+			cfg.JIRA.Proxy = value
+		case "timeout":
+			timeout, err := time.ParseDuration(value)
+			if err != nil {
+				return fmt.Errorf("invalid timeout %q: %w", value, err)
+			}
+			cfg.JIRA.Timeout = timeout
+		case "tls.ca_file":
+			cfg.JIRA.TLS.CAFile = value
+		case "tls.cert_file":
+			cfg.JIRA.TLS.CertFile = value
+		case "tls.key_file":
+			cfg.JIRA.TLS.KeyFile = value
+		case "tls.insecure_skip_verify":
+			insecure, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("invalid tls.insecure_skip_verify %q: must be true or false", value)
+			}
+			cfg.JIRA.TLS.InsecureSkipVerify = insecure
+		case "retry.max":
+			retryMax, err := strconv.Atoi(value)
+			if err != nil || retryMax < 0 {
+				return fmt.Errorf("invalid retry max %q: must be a non-negative integer", value)
+			}
+			cfg.JIRA.Retry.Max = &retryMax
+		case "retry.max_wait":
+			maxWait, err := time.ParseDuration(value)
+			if err != nil {
+				return fmt.Errorf("invalid retry max wait %q: %w", value, err)
+			}
+			cfg.JIRA.Retry.MaxWait = maxWait
+		case "rate_limit":
+			rateLimit, err := strconv.ParseFloat(value, 64)
+			if err != nil || rateLimit < 0 {
+				return fmt.Errorf("invalid rate limit %q: must be a non-negative number", value)
+			}
+			cfg.JIRA.RateLimit = rateLimit
 		default:
 			return fmt.Errorf("unknown JIRA config field: %s", field)
 		}
@@ -196,12 +250,27 @@ func runSet(cmd *cobra.Command, args []string) error {
 		case "output_format":
 			cfg.CLI.OutputFormat = value
 		case "color":
-			cfg.CLI.Color = strings.ToLower(value) == "true"
+			color, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("invalid color %q: must be true or false", value)
+			}
+			cfg.CLI.Color = color
 		case "verbose":
-			cfg.CLI.Verbose = strings.ToLower(value) == "true"
+			verbose, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("invalid verbose %q: must be true or false", value)
+			}
+			cfg.CLI.Verbose = verbose
 		default:
 			return fmt.Errorf("unknown CLI config field: %s", field)
 		}
+	case "mirror":
+		switch field {
+		case "path":
+			cfg.Mirror.Path = value
+		default:
+			return fmt.Errorf("unknown mirror config field: %s", field)
+		}
 	default:
 		return fmt.Errorf("unknown config section: %s", section)
 	}
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lburgazzoli/gira/pkg/config"
+)
+
+// withConfigHome points the configuration at an empty temporary directory
+func withConfigHome(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Chdir(t.TempDir())
+
+	return filepath.Join(dir, "gira", "config.yaml")
+}
+
+// withStdin feeds the answers of the init wizard, one per line
+func withStdin(t *testing.T, answers ...string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "stdin")
+	if err := os.WriteFile(path, []byte(strings.Join(answers, "\n")+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stdin := os.Stdin
+	os.Stdin = f
+	t.Cleanup(func() {
+		os.Stdin = stdin
+		_ = f.Close()
+	})
+}
+
+func TestInitKeepsDefaults(t *testing.T) {
+	path := withConfigHome(t)
+	withStdin(t, "https://jira.example.com", "secret", "", "", "", "", "")
+
+	if err := runInit(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, zero := range []string{"timeout: 0s", "max: 0", "max_wait: 0s", "rate_limit: 0"} {
+		if strings.Contains(string(data), zero) {
+			t.Errorf("config contains %q:\n%s", zero, data)
+		}
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.JIRA.BaseURL != "https://jira.example.com" {
+		t.Errorf("base URL = %q", cfg.JIRA.BaseURL)
+	}
+	if cfg.JIRA.Timeout != 30*time.Second {
+		t.Errorf("timeout = %s, want 30s", cfg.JIRA.Timeout)
+	}
+	if cfg.JIRA.Retry.Max == nil || *cfg.JIRA.Retry.Max != 3 {
+		t.Errorf("retry max = %v, want 3", cfg.JIRA.Retry.Max)
+	}
+	if cfg.JIRA.Retry.MaxWait != 30*time.Second {
+		t.Errorf("retry max wait = %s, want 30s", cfg.JIRA.Retry.MaxWait)
+	}
+	if !cfg.CLI.Color {
+		t.Error("color disabled, want enabled")
+	}
+}
+
+func TestInitRejectsInvalidBoolean(t *testing.T) {
+	withConfigHome(t)
+	withStdin(t, "https://jira.example.com", "secret", "", "", "", "maybe", "")
+
+	if err := runInit(nil, nil); err == nil {
+		t.Fatal("expected an error for an invalid boolean")
+	}
+}
+
+func TestSetRetryMaxZero(t *testing.T) {
+	withConfigHome(t)
+
+	if err := runSet(nil, []string{"jira.retry.max", "0"}); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.JIRA.Retry.Max == nil || *cfg.JIRA.Retry.Max != 0 {
+		t.Errorf("retry max = %v, want 0", cfg.JIRA.Retry.Max)
+	}
+}
@@ -0,0 +1,216 @@
+package download
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	jiraclient "github.com/lburgazzoli/gira/internal/client"
+	"github.com/lburgazzoli/gira/pkg/config"
+	"github.com/lburgazzoli/gira/pkg/jira"
+	"github.com/lburgazzoli/gira/pkg/utils/progress"
+	sizeutils "github.com/lburgazzoli/gira/pkg/utils/size"
+	"github.com/spf13/cobra"
+)
+
+var (
+	downloadAll     bool
+	downloadDir     string
+	downloadMaxSize string
+	downloadForce   bool
+)
+
+var Cmd = &cobra.Command{
+	Use:   "download ISSUE-KEY [FILENAME|ID...]",
+	Short: "Download the attachments of an issue",
+	Long: `Download attachments of an issue, given by file name or ID, or all of
+them with --all. Content is streamed to disk, reporting the progress.
+
+Existing files are not overwritten unless --force is given. Attachments
+larger than --max-size are skipped. Attachments sharing a file name are
+saved with their ID appended.
+
+Examples:
+  gira download PROJ-123 screenshot.png
+  gira download PROJ-123 --all --dir ./out
+  gira download PROJ-123 --all --max-size 20MB`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runDownload,
+}
+
+func init() {
+	Cmd.Flags().BoolVar(&downloadAll, "all", false, "Download all the attachments of the issue")
+	Cmd.Flags().StringVar(&downloadDir, "dir", ".", "Directory to save the attachments to")
+	Cmd.Flags().StringVar(&downloadMaxSize, "max-size", "", "Skip attachments larger than this size, e.g. 20MB")
+	Cmd.Flags().BoolVar(&downloadForce, "force", false, "Overwrite existing files")
+}
+
+func runDownload(_ *cobra.Command, args []string) error {
+	key := strings.ToUpper(args[0])
+	names := args[1:]
+
+	switch {
+	case downloadAll && len(names) > 0:
+		return fmt.Errorf("--all cannot be combined with attachment names")
+	case !downloadAll && len(names) == 0:
+		return fmt.Errorf("specify the attachments to download or use --all")
+	}
+
+	limit := int64(0)
+	if downloadMaxSize != "" {
+		n, err := sizeutils.Parse(downloadMaxSize)
+		if err != nil {
+			return err
+		}
+		limit = n
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	client, err := jiraclient.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create JIRA client: %w", err)
+	}
+
+	attachments, err := client.ListAttachments(key)
+	if err != nil {
+		return err
+	}
+
+	selected := attachments
+	if !downloadAll {
+		selected, err = selectAttachments(attachments, names)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(selected) == 0 {
+		fmt.Printf("%s has no attachments.\n", key)
+		return nil
+	}
+
+	if err := os.MkdirAll(downloadDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", downloadDir, err)
+	}
+
+	green := color.New(color.FgGreen).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	paths := targetPaths(selected)
+	for i := range selected {
+		attachment := &selected[i]
+
+		if limit > 0 && attachment.Size > limit {
+			fmt.Printf("%s Skipped %s: %s exceeds %s\n",
+				yellow("!"), attachment.Filename, sizeutils.Format(attachment.Size), sizeutils.Format(limit))
+			continue
+		}
+
+		path := paths[attachment.ID]
+		if _, err := os.Stat(path); err == nil && !downloadForce {
+			fmt.Printf("%s Skipped %s: %s exists, use --force to overwrite\n", yellow("!"), attachment.Filename, path)
+			continue
+		}
+
+		n, err := save(client, attachment, path, limit)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s Downloaded %s (%s)\n", green("✓"), path, sizeutils.Format(n))
+	}
+
+	return nil
+}
+
+// selectAttachments returns the attachments matching the given file names
+// or IDs, in the order given
+func selectAttachments(attachments []jira.Attachment, names []string) ([]jira.Attachment, error) {
+	selected := make([]jira.Attachment, 0, len(names))
+
+	for _, name := range names {
+		found := false
+		for _, a := range attachments {
+			if a.ID == name || a.Filename == name {
+				selected = append(selected, a)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("no attachment named %q", name)
+		}
+	}
+
+	return selected, nil
+}
+
+// targetPaths maps attachments to the files they are saved to. File names
+// are stripped of directories, duplicates get the attachment ID appended.
+func targetPaths(attachments []jira.Attachment) map[string]string {
+	counts := make(map[string]int, len(attachments))
+	for _, a := range attachments {
+		counts[safeFilename(a)]++
+	}
+
+	paths := make(map[string]string, len(attachments))
+	for _, a := range attachments {
+		name := safeFilename(a)
+		if counts[name] > 1 {
+			ext := filepath.Ext(name)
+			name = strings.TrimSuffix(name, ext) + "-" + a.ID + ext
+		}
+		paths[a.ID] = filepath.Join(downloadDir, name)
+	}
+
+	return paths
+}
+
+// safeFilename keeps attachment names from escaping the target directory
+func safeFilename(attachment jira.Attachment) string {
+	name := filepath.Base(filepath.FromSlash(strings.ReplaceAll(attachment.Filename, "\\", "/")))
+	if name == "." || name == ".." || name == string(filepath.Separator) {
+		return "attachment-" + attachment.ID
+	}
+	return name
+}
+
+// save streams an attachment to a temporary file renamed once complete, so
+// that interrupted downloads do not leave truncated files behind. Content
+// beyond limit fails the download, even when the metadata was smaller.
+func save(client *jira.Client, attachment *jira.Attachment, path string, limit int64) (int64, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	counter := progress.NewCounter("Downloading "+attachment.Filename, attachment.Size)
+	n, err := client.DownloadAttachment(attachment, io.MultiWriter(tmp, counter), limit)
+	counter.Done()
+
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	// Temporary files are private, saved attachments are not
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return 0, fmt.Errorf("failed to save %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return 0, fmt.Errorf("failed to save %s: %w", path, err)
+	}
+
+	return n, nil
+}
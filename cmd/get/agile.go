@@ -0,0 +1,171 @@
+package get
+
+import (
+	"fmt"
+
+	jiraclient "github.com/lburgazzoli/gira/internal/client"
+	"github.com/lburgazzoli/gira/pkg/config"
+	"github.com/lburgazzoli/gira/pkg/jira"
+	tableutils "github.com/lburgazzoli/gira/pkg/utils/table"
+	"github.com/spf13/cobra"
+)
+
+// sprintDateLayout is used to display sprint start and end dates
+const sprintDateLayout = "2006-01-02"
+
+var (
+	boardsProject string
+	boardsType    string
+	boardsName    string
+
+	sprintsBoard string
+	sprintsState string
+)
+
+var boardsCmd = &cobra.Command{
+	Use:   "boards",
+	Short: "List Agile boards",
+	Long: `List the Scrum and Kanban boards visible to you.
+
+Examples:
+  gira get boards
+  gira get boards --project PROJ --type scrum`,
+	Args: cobra.NoArgs,
+	RunE: runGetBoards,
+}
+
+var sprintsCmd = &cobra.Command{
+	Use:   "sprints",
+	Short: "List the sprints of a board",
+	Long: `List the sprints of a Scrum board, given by ID or name.
+
+Examples:
+  gira get sprints --board 42
+  gira get sprints --board "PROJ board" --state active
+  gira get sprints --board 42 --state future,active`,
+	Args: cobra.NoArgs,
+	RunE: runGetSprints,
+}
+
+func init() {
+	boardsCmd.Flags().StringVar(&boardsProject, "project", "", "Only list the boards of a project")
+	boardsCmd.Flags().StringVar(&boardsType, "type", "", "Only list boards of a type (scrum|kanban)")
+	boardsCmd.Flags().StringVar(&boardsName, "name", "", "Only list boards whose name contains the value")
+
+	sprintsCmd.Flags().StringVar(&sprintsBoard, "board", "", "Board ID or name")
+	sprintsCmd.Flags().StringVar(&sprintsState, "state", "", "Only list sprints in the given states (future,active,closed)")
+	_ = sprintsCmd.MarkFlagRequired("board")
+
+	Cmd.AddCommand(boardsCmd)
+	Cmd.AddCommand(sprintsCmd)
+}
+
+func runGetBoards(cmd *cobra.Command, _ []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	client, err := jiraclient.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create JIRA client: %w", err)
+	}
+
+	boards, err := client.ListBoards(jira.BoardFilter{
+		ProjectKey: boardsProject,
+		Type:       boardsType,
+		Name:       boardsName,
+	})
+	if err != nil {
+		return err
+	}
+
+	return outputResult(cmd, boards)
+}
+
+func runGetSprints(cmd *cobra.Command, _ []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	client, err := jiraclient.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create JIRA client: %w", err)
+	}
+
+	board, err := client.FindBoard(sprintsBoard)
+	if err != nil {
+		return fmt.Errorf("failed to get board %s: %w", sprintsBoard, err)
+	}
+
+	sprints, err := client.ListSprints(board.ID, sprintsState)
+	if err != nil {
+		return err
+	}
+
+	return outputResult(cmd, sprints)
+}
+
+func outputBoardsTable(boards []jira.Board) error {
+	if len(boards) == 0 {
+		fmt.Println("No boards found.")
+		return nil
+	}
+
+	renderer := tableutils.NewRenderer(
+		tableutils.WithHeaders("ID", "NAME", "TYPE", "PROJECT"),
+	)
+
+	rows := make([][]any, 0, len(boards))
+	for _, b := range boards {
+		project := ""
+		if b.Location != nil {
+			project = b.Location.ProjectKey
+		}
+
+		rows = append(rows, []any{b.ID, b.Name, b.Type, project})
+	}
+
+	if err := renderer.AppendAll(rows); err != nil {
+		return err
+	}
+
+	return renderer.Render()
+}
+
+func outputSprintsTable(sprints []jira.Sprint) error {
+	if len(sprints) == 0 {
+		fmt.Println("No sprints found.")
+		return nil
+	}
+
+	renderer := tableutils.NewRenderer(
+		tableutils.WithHeaders("ID", "NAME", "STATE", "START", "END", "GOAL"),
+	)
+
+	rows := make([][]any, 0, len(sprints))
+	for _, s := range sprints {
+		rows = append(rows, []any{
+			s.ID,
+			s.Name,
+			s.State,
+			formatSprintDate(s.StartDate),
+			formatSprintDate(s.EndDate),
+			s.Goal,
+		})
+	}
+
+	if err := renderer.AppendAll(rows); err != nil {
+		return err
+	}
+
+	return renderer.Render()
+}
+
+func formatSprintDate(t *jira.JIRATime) string {
+	if t == nil || t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format(sprintDateLayout)
+}
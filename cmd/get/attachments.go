@@ -0,0 +1,82 @@
+package get
+
+import (
+	"fmt"
+	"strings"
+
+	jiraclient "github.com/lburgazzoli/gira/internal/client"
+	"github.com/lburgazzoli/gira/pkg/config"
+	"github.com/lburgazzoli/gira/pkg/jira"
+	sizeutils "github.com/lburgazzoli/gira/pkg/utils/size"
+	tableutils "github.com/lburgazzoli/gira/pkg/utils/table"
+	"github.com/spf13/cobra"
+)
+
+var attachmentsCmd = &cobra.Command{
+	Use:   "attachments ISSUE-KEY",
+	Short: "List the attachments of an issue",
+	Long: `List the files attached to an issue.
+
+Examples:
+  gira get attachments PROJ-123
+  gira get attachments PROJ-123 --output json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runGetAttachments,
+}
+
+func init() {
+	Cmd.AddCommand(attachmentsCmd)
+}
+
+func runGetAttachments(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	client, err := jiraclient.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create JIRA client: %w", err)
+	}
+
+	attachments, err := client.ListAttachments(strings.ToUpper(args[0]))
+	if err != nil {
+		return err
+	}
+
+	return outputResult(cmd, attachments)
+}
+
+func outputAttachmentsTable(attachments []jira.Attachment) error {
+	if len(attachments) == 0 {
+		fmt.Println("No attachments found.")
+		return nil
+	}
+
+	renderer := tableutils.NewRenderer(
+		tableutils.WithHeaders("ID", "FILENAME", "SIZE", "TYPE", "AUTHOR", "CREATED"),
+	)
+
+	rows := make([][]any, 0, len(attachments))
+	for _, a := range attachments {
+		author := ""
+		if a.Author != nil {
+			author = a.Author.DisplayName
+		}
+
+		rows = append(rows, []any{
+			a.ID,
+			a.Filename,
+			sizeutils.Format(a.Size),
+			a.MimeType,
+			author,
+			a.Created.Local().Format("2006-01-02 15:04"),
+		})
+	}
+
+	if err := renderer.AppendAll(rows); err != nil {
+		return err
+	}
+
+	return renderer.Render()
+}
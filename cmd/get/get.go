@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/fatih/color"
+	jiraclient "github.com/lburgazzoli/gira/internal/client"
 	"github.com/lburgazzoli/gira/pkg/config"
 	"github.com/lburgazzoli/gira/pkg/jira"
 	stringutils "github.com/lburgazzoli/gira/pkg/utils/strings"
@@ -26,22 +27,38 @@ var (
 	treeDepth   int
 	treeReverse bool
 	treeShowAll bool
+
+	treeFilter        string
+	treeHideDone      bool
+	treeCollapseDepth int
 )
 
 var issueCmd = &cobra.Command{
 	Use:   "issue ISSUE-KEY",
 	Short: "Get a JIRA issue",
-	Long:  `Get details of a specific JIRA issue by its key. Use --tree to show issue hierarchy.`,
-	Args:  cobra.ExactArgs(1),
-	RunE:  runGetIssue,
+	Long: `Get details of a specific JIRA issue by its key. Use --tree to show issue hierarchy.
+
+Tree results can be pruned with --tree-filter, which keeps matching issues
+and their ancestors. Supported fields are key, summary, status, category,
+type, priority, assignee and reporter; operators are =, !=, ~, !~, in and
+not in, and conditions can be combined with AND / OR.
+
+Examples:
+  gira get issue EPIC-123 --tree --tree-filter "status != Done"
+  gira get issue EPIC-123 --tree --tree-filter "assignee = me"
+  gira get issue EPIC-123 --tree --tree-filter "type in (Story, Bug)"
+  gira get issue EPIC-123 --tree --hide-done --collapse-depth 1`,
+	Args: cobra.ExactArgs(1),
+	RunE: runGetIssue,
 }
 
 var projectCmd = &cobra.Command{
 	Use:   "project PROJECT-KEY",
 	Short: "Get a JIRA project",
-	Long:  `Get details of a specific JIRA project by its key.`,
-	Args:  cobra.ExactArgs(1),
-	RunE:  runGetProject,
+	Long: `Get details of a specific JIRA project by its key: lead, category, issue
+types, components, versions and roles.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runGetProject,
 }
 
 func init() {
@@ -49,6 +66,12 @@ func init() {
 	issueCmd.Flags().IntVar(&treeDepth, "tree-depth", 3, "Maximum depth to traverse for tree view")
 	issueCmd.Flags().BoolVar(&treeReverse, "tree-reverse", false, "Show children first, then parents in tree view")
 	issueCmd.Flags().BoolVar(&treeShowAll, "tree-all", false, "Show all fields for each issue in tree view")
+	issueCmd.Flags().StringVar(&treeFilter, "tree-filter", "", "Only show tree issues matching the expression (and their ancestors)")
+	issueCmd.Flags().BoolVar(&treeHideDone, "hide-done", false, "Hide tree issues whose status category is done")
+	issueCmd.Flags().IntVar(&treeCollapseDepth, "collapse-depth", 0, "Collapse tree issues below the given depth (0 disables collapsing)")
+
+	// Override the global output flag to include the tree export formats
+	issueCmd.Flags().StringP("output", "o", "", "output format (table|json|yaml), tree view also supports (dot|mermaid|plantuml|markdown)")
 
 	Cmd.AddCommand(issueCmd)
 	Cmd.AddCommand(projectCmd)
@@ -62,9 +85,7 @@ func runGetIssue(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
 
-	client, err := jira.NewClient(cfg.JIRA.BaseURL, jira.AuthConfig{
-		Token: cfg.JIRA.Token,
-	})
+	client, err := jiraclient.New(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to create JIRA client: %w", err)
 	}
@@ -80,12 +101,55 @@ func runGetIssue(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return fmt.Errorf("failed to build issue tree: %w", err)
 		}
+
+		opts, err := treeFilterOptions(client)
+		if err != nil {
+			return err
+		}
+		jira.FilterIssueTree(issue, opts)
+
 		return outputTreeResult(cmd, issue)
 	}
 
+	// The local mirror only holds issues, remote links are not part of them
+	if !jiraclient.Offline {
+		issue.RemoteLinks, err = client.ListRemoteLinks(issue.Key)
+		if err != nil {
+			return fmt.Errorf("failed to get remote links of %s: %w", issue.Key, err)
+		}
+	}
+
 	return outputResult(cmd, issue)
 }
 
+// treeFilterOptions builds the tree pruning options from the command line flags
+func treeFilterOptions(client *jira.Client) (jira.TreeFilterOptions, error) {
+	opts := jira.TreeFilterOptions{
+		HideDone:      treeHideDone,
+		CollapseDepth: treeCollapseDepth,
+	}
+
+	if treeFilter == "" {
+		return opts, nil
+	}
+
+	filter, err := jira.ParseTreeFilter(treeFilter)
+	if err != nil {
+		return opts, fmt.Errorf("invalid tree filter: %w", err)
+	}
+
+	if filter.RequiresCurrentUser() {
+		filter.CurrentUser, err = client.Myself()
+		if err != nil {
+			return opts, fmt.Errorf("failed to resolve current user: %w", err)
+		}
+	}
+
+	opts.Filter = filter
+
+	return opts, nil
+}
+
 func runGetProject(cmd *cobra.Command, args []string) error {
 	projectKey := args[0]
 
@@ -94,9 +158,7 @@ func runGetProject(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
 
-	client, err := jira.NewClient(cfg.JIRA.BaseURL, jira.AuthConfig{
-		Token: cfg.JIRA.Token,
-	})
+	client, err := jiraclient.New(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to create JIRA client: %w", err)
 	}
@@ -109,9 +171,17 @@ func runGetProject(cmd *cobra.Command, args []string) error {
 	return outputResult(cmd, project)
 }
 
+// getOutputFormat checks the local output flag first, then falls back to the global flag
+func getOutputFormat(cmd *cobra.Command) string {
+	outputFormat, _ := cmd.Flags().GetString("output")
+	if outputFormat == "" {
+		outputFormat, _ = cmd.Root().PersistentFlags().GetString("output")
+	}
+	return outputFormat
+}
+
 func outputResult(cmd *cobra.Command, result interface{}) error {
-	// Get output format from global flag
-	outputFormat, _ := cmd.Root().PersistentFlags().GetString("output")
+	outputFormat := getOutputFormat(cmd)
 
 	switch outputFormat {
 	case "json":
@@ -157,6 +227,10 @@ func outputTable(result interface{}) error {
 			{"Description", stringutils.Truncate(v.Fields.Description, 100)},
 		}
 
+		for _, l := range v.RemoteLinks {
+			rows = append(rows, []any{"Web Link", l.Object.Title + " " + l.Object.URL})
+		}
+
 		if err := renderer.AppendAll(rows); err != nil {
 			return err
 		}
@@ -168,11 +242,8 @@ func outputTable(result interface{}) error {
 			tableutils.WithHeaders("Field", "Value"),
 		)
 
-		rows := [][]any{
-			{"Key", v.Key},
-			{"Name", v.Name},
-			{"ID", v.ID},
-		}
+		rows := projectRows(v)
+		rows = append(rows, []any{"Description", stringutils.Truncate(v.Description, 100)})
 
 		if err := renderer.AppendAll(rows); err != nil {
 			return err
@@ -180,6 +251,44 @@ func outputTable(result interface{}) error {
 
 		return renderer.Render()
 
+	case []jira.Project:
+		return outputProjectsTable(v)
+
+	case []jira.Component:
+		return outputComponentsTable(v)
+
+	case []jira.Version:
+		return outputVersionsTable(v)
+
+	case []jira.Board:
+		return outputBoardsTable(v)
+
+	case []jira.Sprint:
+		return outputSprintsTable(v)
+
+	case []jira.Attachment:
+		return outputAttachmentsTable(v)
+
+	case []jira.IssueLink:
+		return outputLinksTable(v)
+
+	case []jira.Issue:
+		return outputIssuesTable(v)
+
+	case []jira.User:
+		return outputUsersTable(v)
+
+	case *jira.User:
+		renderer := tableutils.NewRenderer(
+			tableutils.WithHeaders("Field", "Value"),
+		)
+
+		if err := renderer.AppendAll(userRows(v)); err != nil {
+			return err
+		}
+
+		return renderer.Render()
+
 	case *config.Config:
 		renderer := tableutils.NewRenderer(
 			tableutils.WithHeaders("Configuration", "Value"),
@@ -238,6 +347,21 @@ func outputPlain(result interface{}) error {
 		fmt.Printf("%-11s: %s\n", "Created", v.Fields.Created.Format("2006-01-02 15:04:05"))
 		fmt.Printf("%-11s: %s\n", "Updated", v.Fields.Updated.Format("2006-01-02 15:04:05"))
 
+		if len(v.Fields.Attachments) > 0 {
+			names := make([]string, 0, len(v.Fields.Attachments))
+			for _, a := range v.Fields.Attachments {
+				names = append(names, a.Filename)
+			}
+			fmt.Printf("%-11s: %s\n", "Attachments", strings.Join(names, ", "))
+		}
+
+		if len(v.RemoteLinks) > 0 {
+			fmt.Printf("%-11s:\n", "Web Links")
+			for _, l := range v.RemoteLinks {
+				fmt.Printf("  %s <%s>\n", l.Object.Title, l.Object.URL)
+			}
+		}
+
 		if v.Fields.Description != "" {
 			fmt.Printf("\n")
 			if err := stringutils.PrintWrapped(os.Stdout, v.Fields.Description, 100); err != nil {
@@ -246,9 +370,26 @@ func outputPlain(result interface{}) error {
 		}
 
 	case *jira.Project:
-		fmt.Printf("Project: %s\n", v.Key)
-		fmt.Printf("Name: %s\n", v.Name)
-		fmt.Printf("ID: %s\n", v.ID)
+		for _, row := range projectRows(v) {
+			if row[1] != "" {
+				fmt.Printf("%-11s: %s\n", row[0], row[1])
+			}
+		}
+
+		if v.Description != "" {
+			fmt.Printf("\n")
+			if err := stringutils.PrintWrapped(os.Stdout, v.Description, 100); err != nil {
+				return err
+			}
+		}
+
+	case []jira.IssueLink:
+		return outputLinksPlain(v)
+
+	case *jira.User:
+		for _, row := range userRows(v) {
+			fmt.Printf("%-10s: %s\n", row[0], row[1])
+		}
 
 	default:
 		// For other types, fall back to table format
@@ -258,8 +399,7 @@ func outputPlain(result interface{}) error {
 }
 
 func outputTreeResult(cmd *cobra.Command, issue *jira.Issue) error {
-	// Get output format from global flag
-	outputFormat, _ := cmd.Root().PersistentFlags().GetString("output")
+	outputFormat := getOutputFormat(cmd)
 
 	switch outputFormat {
 	case "json":
@@ -271,6 +411,14 @@ func outputTreeResult(cmd *cobra.Command, issue *jira.Issue) error {
 		return encoder.Encode(issue)
 	case "table":
 		return renderTreeTable(issue)
+	case "dot":
+		return exportTreeDOT(os.Stdout, issue)
+	case "mermaid":
+		return exportTreeMermaid(os.Stdout, issue)
+	case "plantuml":
+		return exportTreePlantUML(os.Stdout, issue)
+	case "markdown":
+		return exportTreeMarkdown(os.Stdout, issue)
 	case "":
 		// Default to ASCII tree format for tree view when no output format is specified
 		if treeReverse {
@@ -357,13 +505,19 @@ func renderTreeReverse(issue *jira.Issue, prefix string, depth int, isLast bool)
 }
 
 func formatIssueInfo(issue *jira.Issue) string {
+	collapsed := ""
+	if issue.Collapsed > 0 {
+		collapsed = fmt.Sprintf(" (+%d collapsed)", issue.Collapsed)
+	}
+
 	if treeShowAll {
-		return fmt.Sprintf("%s: %s [%s] (%s) - %s",
+		return fmt.Sprintf("%s: %s [%s] (%s) - %s%s",
 			issue.Key,
 			issue.Fields.Summary,
 			issue.Fields.Status.Name,
 			issue.Fields.IssueType.Name,
-			getAssigneeDisplay(issue))
+			getAssigneeDisplay(issue),
+			collapsed)
 	}
 
 	// Compact format
@@ -372,7 +526,7 @@ func formatIssueInfo(issue *jira.Issue) string {
 		status = fmt.Sprintf(" [%s]", issue.Fields.Status.Name)
 	}
 
-	return fmt.Sprintf("%s: %s%s", issue.Key, issue.Fields.Summary, status)
+	return fmt.Sprintf("%s: %s%s%s", issue.Key, issue.Fields.Summary, status, collapsed)
 }
 
 func getAssigneeDisplay(issue *jira.Issue) string {
@@ -0,0 +1,124 @@
+package get
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/fatih/color"
+	jiraclient "github.com/lburgazzoli/gira/internal/client"
+	"github.com/lburgazzoli/gira/pkg/config"
+	"github.com/lburgazzoli/gira/pkg/jira"
+	stringutils "github.com/lburgazzoli/gira/pkg/utils/strings"
+	tableutils "github.com/lburgazzoli/gira/pkg/utils/table"
+	"github.com/spf13/cobra"
+)
+
+var linksCmd = &cobra.Command{
+	Use:   "links ISSUE-KEY",
+	Short: "List the links of an issue",
+	Long: `List the issues linked to an issue, grouped by relation.
+
+Examples:
+  gira get links PROJ-123
+  gira get links PROJ-123 --output table`,
+	Args: cobra.ExactArgs(1),
+	RunE: runGetLinks,
+}
+
+func init() {
+	Cmd.AddCommand(linksCmd)
+}
+
+func runGetLinks(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	client, err := jiraclient.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create JIRA client: %w", err)
+	}
+
+	links, err := client.ListIssueLinks(strings.ToUpper(args[0]))
+	if err != nil {
+		return err
+	}
+
+	return outputResult(cmd, links)
+}
+
+// groupLinks groups links by relation, in order of first appearance
+func groupLinks(links []jira.IssueLink) ([]string, map[string][]jira.IssueLink) {
+	relations := make([]string, 0)
+	groups := make(map[string][]jira.IssueLink)
+
+	for _, l := range links {
+		relation := l.Relation()
+		if !slices.Contains(relations, relation) {
+			relations = append(relations, relation)
+		}
+		groups[relation] = append(groups[relation], l)
+	}
+
+	return relations, groups
+}
+
+func outputLinksPlain(links []jira.IssueLink) error {
+	if len(links) == 0 {
+		fmt.Println("No links found.")
+		return nil
+	}
+
+	cyan := color.New(color.FgCyan).SprintFunc()
+
+	relations, groups := groupLinks(links)
+	for i, relation := range relations {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Println(cyan(relation))
+
+		for _, l := range groups[relation] {
+			issue := l.LinkedIssue()
+			fmt.Printf("  %-12s %-14s %s\n", issue.Key, issue.Fields.Status.Name, stringutils.Truncate(issue.Fields.Summary, 70))
+		}
+	}
+
+	return nil
+}
+
+func outputLinksTable(links []jira.IssueLink) error {
+	if len(links) == 0 {
+		fmt.Println("No links found.")
+		return nil
+	}
+
+	renderer := tableutils.NewRenderer(
+		tableutils.WithHeaders("RELATION", "KEY", "TYPE", "STATUS", "SUMMARY", "LINK ID"),
+	)
+
+	relations, groups := groupLinks(links)
+
+	rows := make([][]any, 0, len(links))
+	for _, relation := range relations {
+		for _, l := range groups[relation] {
+			issue := l.LinkedIssue()
+			rows = append(rows, []any{
+				relation,
+				issue.Key,
+				issue.Fields.IssueType.Name,
+				issue.Fields.Status.Name,
+				stringutils.Truncate(issue.Fields.Summary, 50),
+				l.ID,
+			})
+		}
+	}
+
+	if err := renderer.AppendAll(rows); err != nil {
+		return err
+	}
+
+	return renderer.Render()
+}
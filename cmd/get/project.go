@@ -0,0 +1,303 @@
+package get
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	jiraclient "github.com/lburgazzoli/gira/internal/client"
+	"github.com/lburgazzoli/gira/pkg/config"
+	"github.com/lburgazzoli/gira/pkg/jira"
+	stringutils "github.com/lburgazzoli/gira/pkg/utils/strings"
+	tableutils "github.com/lburgazzoli/gira/pkg/utils/table"
+	"github.com/spf13/cobra"
+)
+
+var projectsCmd = &cobra.Command{
+	Use:   "projects",
+	Short: "List JIRA projects",
+	Long: `List the projects visible to the current user.
+
+Examples:
+  gira get projects
+  gira get projects --output json`,
+	Args: cobra.NoArgs,
+	RunE: runGetProjects,
+}
+
+var componentsCmd = &cobra.Command{
+	Use:   "components PROJECT-KEY",
+	Short: "List the components of a project",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runGetComponents,
+}
+
+var versionsCmd = &cobra.Command{
+	Use:   "versions PROJECT-KEY",
+	Short: "List the versions of a project",
+	Long: `List the versions of a project, archived versions are hidden unless
+--archived is given.
+
+Examples:
+  gira get versions PROJ
+  gira get versions PROJ --archived`,
+	Args: cobra.ExactArgs(1),
+	RunE: runGetVersions,
+}
+
+var versionsArchived bool
+
+func init() {
+	versionsCmd.Flags().BoolVar(&versionsArchived, "archived", false, "Include archived versions")
+
+	Cmd.AddCommand(projectsCmd)
+	Cmd.AddCommand(componentsCmd)
+	Cmd.AddCommand(versionsCmd)
+}
+
+func runGetProjects(cmd *cobra.Command, _ []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	client, err := jiraclient.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create JIRA client: %w", err)
+	}
+
+	projects, err := client.ListProjects()
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(projects, func(i, j int) bool { return projects[i].Key < projects[j].Key })
+
+	return outputResult(cmd, projects)
+}
+
+func runGetComponents(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	client, err := jiraclient.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create JIRA client: %w", err)
+	}
+
+	components, err := client.ListComponents(strings.ToUpper(args[0]))
+	if err != nil {
+		return err
+	}
+
+	return outputResult(cmd, components)
+}
+
+func runGetVersions(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	client, err := jiraclient.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create JIRA client: %w", err)
+	}
+
+	versions, err := client.ListVersions(strings.ToUpper(args[0]))
+	if err != nil {
+		return err
+	}
+
+	if !versionsArchived {
+		shown := make([]jira.Version, 0, len(versions))
+		for _, v := range versions {
+			if !v.Archived {
+				shown = append(shown, v)
+			}
+		}
+		versions = shown
+	}
+
+	return outputResult(cmd, versions)
+}
+
+// projectRows returns the summary of a project as field/value pairs
+func projectRows(p *jira.Project) [][]any {
+	lead := ""
+	if p.Lead != nil {
+		lead = p.Lead.DisplayName
+	}
+
+	category := ""
+	if p.ProjectCategory != nil {
+		category = p.ProjectCategory.Name
+	}
+
+	issueTypes := make([]string, 0, len(p.IssueTypes))
+	for _, t := range p.IssueTypes {
+		issueTypes = append(issueTypes, t.Name)
+	}
+
+	components := make([]string, 0, len(p.Components))
+	for _, c := range p.Components {
+		components = append(components, c.Name)
+	}
+
+	roles := make([]string, 0, len(p.Roles))
+	for role := range p.Roles {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+
+	return [][]any{
+		{"Key", p.Key},
+		{"Name", p.Name},
+		{"ID", p.ID},
+		{"Lead", lead},
+		{"Category", category},
+		{"Type", p.ProjectTypeKey},
+		{"Issue Types", strings.Join(issueTypes, ", ")},
+		{"Components", strings.Join(components, ", ")},
+		{"Versions", summarizeVersions(p.Versions)},
+		{"Roles", strings.Join(roles, ", ")},
+	}
+}
+
+// summarizeVersions counts the released versions and lists the unreleased
+// ones, archived versions are left out
+func summarizeVersions(versions []jira.Version) string {
+	released := 0
+	unreleased := make([]string, 0)
+
+	for _, v := range versions {
+		switch {
+		case v.Archived:
+		case v.Released:
+			released++
+		default:
+			unreleased = append(unreleased, v.Name)
+		}
+	}
+
+	if released == 0 && len(unreleased) == 0 {
+		return ""
+	}
+
+	summary := fmt.Sprintf("%d released", released)
+	if len(unreleased) > 0 {
+		summary += ", unreleased: " + strings.Join(unreleased, ", ")
+	}
+
+	return summary
+}
+
+func outputProjectsTable(projects []jira.Project) error {
+	if len(projects) == 0 {
+		fmt.Println("No projects found.")
+		return nil
+	}
+
+	renderer := tableutils.NewRenderer(
+		tableutils.WithHeaders("KEY", "NAME", "LEAD", "CATEGORY", "TYPE"),
+	)
+
+	rows := make([][]any, 0, len(projects))
+	for _, p := range projects {
+		lead := ""
+		if p.Lead != nil {
+			lead = p.Lead.DisplayName
+		}
+
+		category := ""
+		if p.ProjectCategory != nil {
+			category = p.ProjectCategory.Name
+		}
+
+		rows = append(rows, []any{p.Key, p.Name, lead, category, p.ProjectTypeKey})
+	}
+
+	if err := renderer.AppendAll(rows); err != nil {
+		return err
+	}
+
+	return renderer.Render()
+}
+
+func outputComponentsTable(components []jira.Component) error {
+	if len(components) == 0 {
+		fmt.Println("No components found.")
+		return nil
+	}
+
+	renderer := tableutils.NewRenderer(
+		tableutils.WithHeaders("ID", "NAME", "LEAD", "DESCRIPTION"),
+	)
+
+	rows := make([][]any, 0, len(components))
+	for _, c := range components {
+		lead := ""
+		if c.Lead != nil {
+			lead = c.Lead.DisplayName
+		}
+
+		rows = append(rows, []any{c.ID, c.Name, lead, stringutils.Truncate(c.Description, 60)})
+	}
+
+	if err := renderer.AppendAll(rows); err != nil {
+		return err
+	}
+
+	return renderer.Render()
+}
+
+func outputVersionsTable(versions []jira.Version) error {
+	if len(versions) == 0 {
+		fmt.Println("No versions found.")
+		return nil
+	}
+
+	renderer := tableutils.NewRenderer(
+		tableutils.WithHeaders("ID", "NAME", "STATE", "START", "RELEASE", "DESCRIPTION"),
+	)
+
+	rows := make([][]any, 0, len(versions))
+	for _, v := range versions {
+		rows = append(rows, []any{
+			v.ID,
+			v.Name,
+			versionState(v),
+			dateOrDash(v.StartDate),
+			dateOrDash(v.ReleaseDate),
+			stringutils.Truncate(v.Description, 50),
+		})
+	}
+
+	if err := renderer.AppendAll(rows); err != nil {
+		return err
+	}
+
+	return renderer.Render()
+}
+
+func versionState(v jira.Version) string {
+	switch {
+	case v.Archived:
+		return "archived"
+	case v.Released:
+		return "released"
+	case v.Overdue:
+		return "overdue"
+	default:
+		return "unreleased"
+	}
+}
+
+func dateOrDash(date string) string {
+	if date == "" {
+		return "-"
+	}
+	return date
+}
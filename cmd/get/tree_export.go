@@ -0,0 +1,245 @@
+package get
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/lburgazzoli/gira/pkg/jira"
+)
+
+// treeNodeStyle describes how an issue is drawn in diagram exports
+type treeNodeStyle struct {
+	fill   string
+	stroke string
+}
+
+var (
+	// treeStatusStyles maps a JIRA status category key to node colors
+	treeStatusStyles = map[string]treeNodeStyle{
+		jira.StatusCategoryNew:        {fill: "#dfe1e6", stroke: "#42526e"},
+		jira.StatusCategoryInProgress: {fill: "#deebff", stroke: "#0052cc"},
+		jira.StatusCategoryDone:       {fill: "#e3fcef", stroke: "#006644"},
+	}
+
+	treeDefaultStyle = treeNodeStyle{fill: "#ffffff", stroke: "#6b778c"}
+)
+
+// treeVisitor is invoked for every parent/child pair while walking an issue tree
+type treeVisitor func(parent *jira.Issue, child *jira.Issue)
+
+// walkTree visits every issue of the tree exactly once in depth-first order
+func walkTree(root *jira.Issue, nodeFn func(issue *jira.Issue, depth int), edgeFn treeVisitor) {
+	visited := make(map[string]bool)
+
+	var walk func(issue *jira.Issue, depth int)
+	walk = func(issue *jira.Issue, depth int) {
+		if issue == nil || visited[issue.Key] {
+			return
+		}
+		visited[issue.Key] = true
+
+		if nodeFn != nil {
+			nodeFn(issue, depth)
+		}
+
+		for _, child := range issue.Children {
+			if edgeFn != nil {
+				edgeFn(issue, child)
+			}
+			walk(child, depth+1)
+		}
+	}
+
+	walk(root, 0)
+}
+
+func statusStyle(issue *jira.Issue) treeNodeStyle {
+	if s, ok := treeStatusStyles[issue.Fields.Status.StatusCategory.Key]; ok {
+		return s
+	}
+	return treeDefaultStyle
+}
+
+func statusCategoryKey(issue *jira.Issue) string {
+	key := issue.Fields.Status.StatusCategory.Key
+	if key == "" {
+		return jira.StatusCategoryUndefined
+	}
+	return key
+}
+
+func isSubtaskType(issueType string) bool {
+	t := strings.ToLower(issueType)
+	return t == "sub-task" || t == "subtask"
+}
+
+func nodeLabel(issue *jira.Issue) string {
+	label := issue.Key
+	if issue.Fields.Summary != "" {
+		label += ": " + issue.Fields.Summary
+	}
+	if issue.Fields.Status.Name != "" {
+		label += " [" + issue.Fields.Status.Name + "]"
+	}
+	return label
+}
+
+// nodeID returns an identifier usable by all diagram languages (no dashes)
+func nodeID(issue *jira.Issue) string {
+	return strings.NewReplacer("-", "_", ".", "_", " ", "_").Replace(issue.Key)
+}
+
+// exportTreeDOT renders the tree as a Graphviz digraph
+func exportTreeDOT(w io.Writer, root *jira.Issue) error {
+	var b strings.Builder
+
+	b.WriteString("digraph issues {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [style=filled, fontname=\"Helvetica\"];\n")
+
+	walkTree(root, func(issue *jira.Issue, _ int) {
+		style := statusStyle(issue)
+		fmt.Fprintf(&b, "  %q [label=%q, shape=%s, fillcolor=%q, color=%q];\n",
+			issue.Key,
+			nodeLabel(issue),
+			dotShape(issue.Fields.IssueType.Name),
+			style.fill,
+			style.stroke)
+	}, func(parent *jira.Issue, child *jira.Issue) {
+		fmt.Fprintf(&b, "  %q -> %q;\n", parent.Key, child.Key)
+	})
+
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func dotShape(issueType string) string {
+	switch {
+	case strings.EqualFold(issueType, "Epic"):
+		return "hexagon"
+	case strings.EqualFold(issueType, "Bug"):
+		return "octagon"
+	case strings.EqualFold(issueType, "Story"):
+		return "box"
+	case isSubtaskType(issueType):
+		return "ellipse"
+	default:
+		return "note"
+	}
+}
+
+// exportTreeMermaid renders the tree as a Mermaid flowchart
+func exportTreeMermaid(w io.Writer, root *jira.Issue) error {
+	var b strings.Builder
+
+	b.WriteString("flowchart LR\n")
+
+	walkTree(root, func(issue *jira.Issue, _ int) {
+		label := strings.ReplaceAll(nodeLabel(issue), `"`, "#quot;")
+		open, closing := mermaidShape(issue.Fields.IssueType.Name)
+		fmt.Fprintf(&b, "  %s%s\"%s\"%s:::%s\n", nodeID(issue), open, label, closing, statusCategoryKey(issue))
+	}, func(parent *jira.Issue, child *jira.Issue) {
+		fmt.Fprintf(&b, "  %s --> %s\n", nodeID(parent), nodeID(child))
+	})
+
+	for _, key := range []string{jira.StatusCategoryNew, jira.StatusCategoryInProgress, jira.StatusCategoryDone} {
+		style := treeStatusStyles[key]
+		fmt.Fprintf(&b, "  classDef %s fill:%s,stroke:%s\n", key, style.fill, style.stroke)
+	}
+	fmt.Fprintf(&b, "  classDef %s fill:%s,stroke:%s\n", jira.StatusCategoryUndefined, treeDefaultStyle.fill, treeDefaultStyle.stroke)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func mermaidShape(issueType string) (string, string) {
+	switch {
+	case strings.EqualFold(issueType, "Epic"):
+		return "{{", "}}"
+	case strings.EqualFold(issueType, "Bug"):
+		return ">", "]"
+	case strings.EqualFold(issueType, "Story"):
+		return "[", "]"
+	case isSubtaskType(issueType):
+		return "(", ")"
+	default:
+		return "[/", "/]"
+	}
+}
+
+// exportTreePlantUML renders the tree as a PlantUML component-style diagram
+func exportTreePlantUML(w io.Writer, root *jira.Issue) error {
+	var b strings.Builder
+
+	b.WriteString("@startuml\n")
+	b.WriteString("left to right direction\n")
+
+	walkTree(root, func(issue *jira.Issue, _ int) {
+		label := strings.ReplaceAll(nodeLabel(issue), `"`, `'`)
+		style := statusStyle(issue)
+		fmt.Fprintf(&b, "%s \"%s\" as %s %s;line:%s\n",
+			plantUMLShape(issue.Fields.IssueType.Name),
+			label,
+			nodeID(issue),
+			style.fill,
+			strings.TrimPrefix(style.stroke, "#"))
+	}, func(parent *jira.Issue, child *jira.Issue) {
+		fmt.Fprintf(&b, "%s --> %s\n", nodeID(parent), nodeID(child))
+	})
+
+	b.WriteString("@enduml\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func plantUMLShape(issueType string) string {
+	switch {
+	case strings.EqualFold(issueType, "Epic"):
+		return "hexagon"
+	case strings.EqualFold(issueType, "Bug"):
+		return "card"
+	case strings.EqualFold(issueType, "Story"):
+		return "rectangle"
+	case isSubtaskType(issueType):
+		return "usecase"
+	default:
+		return "component"
+	}
+}
+
+// exportTreeMarkdown renders the tree as a nested Markdown task list
+func exportTreeMarkdown(w io.Writer, root *jira.Issue) error {
+	var b strings.Builder
+
+	walkTree(root, func(issue *jira.Issue, depth int) {
+		check := " "
+		if issue.Fields.Status.StatusCategory.Key == jira.StatusCategoryDone {
+			check = "x"
+		}
+
+		fmt.Fprintf(&b, "%s- [%s] **%s** %s", strings.Repeat("  ", depth), check, issue.Key, issue.Fields.Summary)
+
+		details := make([]string, 0, 3)
+		if issue.Fields.IssueType.Name != "" {
+			details = append(details, issue.Fields.IssueType.Name)
+		}
+		if issue.Fields.Status.Name != "" {
+			details = append(details, issue.Fields.Status.Name)
+		}
+		if assignee := getAssigneeDisplay(issue); assignee != "" {
+			details = append(details, assignee)
+		}
+		if len(details) > 0 {
+			fmt.Fprintf(&b, " _(%s)_", strings.Join(details, ", "))
+		}
+
+		b.WriteString("\n")
+	}, nil)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,125 @@
+package get
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/lburgazzoli/gira/pkg/jira"
+)
+
+func newTreeIssue(key string, issueType string, summary string, category string, children ...*jira.Issue) *jira.Issue {
+	issue := &jira.Issue{Key: key, Children: children}
+	issue.Fields.Summary = summary
+	issue.Fields.IssueType.Name = issueType
+	issue.Fields.Status.StatusCategory.Key = category
+
+	switch category {
+	case jira.StatusCategoryDone:
+		issue.Fields.Status.Name = "Done"
+	case jira.StatusCategoryInProgress:
+		issue.Fields.Status.Name = "In Progress"
+	case jira.StatusCategoryNew:
+		issue.Fields.Status.Name = "To Do"
+	}
+
+	return issue
+}
+
+// exportTestTree is an epic with a story, a bug and a sub-task shared by both,
+// so that exports must visit every issue once
+func exportTestTree() *jira.Issue {
+	subtask := newTreeIssue("DEMO-4", "Sub-task", `Say "hi"`, "")
+	story := newTreeIssue("DEMO-2", "Story", "Story", jira.StatusCategoryInProgress, subtask)
+	bug := newTreeIssue("DEMO-3", "Bug", "Bug", jira.StatusCategoryDone, subtask)
+	bug.Fields.Assignee = &jira.User{DisplayName: "Jane Doe"}
+
+	return newTreeIssue("DEMO-1", "Epic", "Epic", jira.StatusCategoryNew, story, bug)
+}
+
+func TestTreeExports(t *testing.T) {
+	tests := []struct {
+		name   string
+		export func(w io.Writer, root *jira.Issue) error
+		want   []string
+		// once are lines that must appear exactly once
+		once []string
+	}{
+		{
+			name:   "dot",
+			export: exportTreeDOT,
+			want: []string{
+				"digraph issues {\n",
+				`"DEMO-1" [label="DEMO-1: Epic [To Do]", shape=hexagon, fillcolor="#dfe1e6", color="#42526e"];`,
+				`"DEMO-2" [label="DEMO-2: Story [In Progress]", shape=box, fillcolor="#deebff", color="#0052cc"];`,
+				`"DEMO-3" [label="DEMO-3: Bug [Done]", shape=octagon, fillcolor="#e3fcef", color="#006644"];`,
+				`"DEMO-4" [label="DEMO-4: Say \"hi\"", shape=ellipse, fillcolor="#ffffff", color="#6b778c"];`,
+				`"DEMO-1" -> "DEMO-2";`,
+				`"DEMO-2" -> "DEMO-4";`,
+				`"DEMO-3" -> "DEMO-4";`,
+			},
+			once: []string{`"DEMO-4" [label=`},
+		},
+		{
+			name:   "mermaid",
+			export: exportTreeMermaid,
+			want: []string{
+				"flowchart LR\n",
+				`DEMO_1{{"DEMO-1: Epic [To Do]"}}:::new`,
+				`DEMO_2["DEMO-2: Story [In Progress]"]:::indeterminate`,
+				`DEMO_3>"DEMO-3: Bug [Done]"]:::done`,
+				`DEMO_4("DEMO-4: Say #quot;hi#quot;"):::undefined`,
+				"DEMO_3 --> DEMO_4",
+				"classDef done fill:#e3fcef,stroke:#006644",
+				"classDef undefined fill:#ffffff,stroke:#6b778c",
+			},
+			once: []string{`DEMO_4("`},
+		},
+		{
+			name:   "plantuml",
+			export: exportTreePlantUML,
+			want: []string{
+				"@startuml\n",
+				`hexagon "DEMO-1: Epic [To Do]" as DEMO_1 #dfe1e6;line:42526e`,
+				`card "DEMO-3: Bug [Done]" as DEMO_3 #e3fcef;line:006644`,
+				`usecase "DEMO-4: Say 'hi'" as DEMO_4 #ffffff;line:6b778c`,
+				"DEMO_1 --> DEMO_2",
+				"@enduml\n",
+			},
+			once: []string{`usecase "DEMO-4`},
+		},
+		{
+			name:   "markdown",
+			export: exportTreeMarkdown,
+			want: []string{
+				"- [ ] **DEMO-1** Epic _(Epic, To Do)_\n",
+				"  - [ ] **DEMO-2** Story _(Story, In Progress)_\n",
+				"    - [ ] **DEMO-4** Say \"hi\" _(Sub-task)_\n",
+				"  - [x] **DEMO-3** Bug _(Bug, Done, Jane Doe)_\n",
+			},
+			once: []string{"**DEMO-4**"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.export(&buf, exportTestTree()); err != nil {
+				t.Fatal(err)
+			}
+
+			out := buf.String()
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("output does not contain %q:\n%s", want, out)
+				}
+			}
+			for _, once := range tt.once {
+				if n := strings.Count(out, once); n != 1 {
+					t.Errorf("%q appears %d times, want 1:\n%s", once, n, out)
+				}
+			}
+		})
+	}
+}
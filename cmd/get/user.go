@@ -0,0 +1,94 @@
+package get
+
+import (
+	"fmt"
+	"strings"
+
+	jiraclient "github.com/lburgazzoli/gira/internal/client"
+	"github.com/lburgazzoli/gira/pkg/config"
+	"github.com/lburgazzoli/gira/pkg/jira"
+	"github.com/spf13/cobra"
+)
+
+var userCmd = &cobra.Command{
+	Use:   "user QUERY",
+	Short: "Look up JIRA users",
+	Long: `Look up users by "me", account ID, username, email address or display
+name. A query matching a single user shows its details, otherwise all the
+matching users are listed.
+
+Examples:
+  gira get user me
+  gira get user alice@example.com
+  gira get user "Alice Smith"
+  gira get user ali`,
+	Args: cobra.ExactArgs(1),
+	RunE: runGetUser,
+}
+
+func init() {
+	Cmd.AddCommand(userCmd)
+}
+
+func runGetUser(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	client, err := jiraclient.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create JIRA client: %w", err)
+	}
+
+	query := strings.TrimSpace(args[0])
+
+	if strings.EqualFold(query, jira.CurrentUserAlias) {
+		me, err := client.Myself()
+		if err != nil {
+			return fmt.Errorf("failed to get current user: %w", err)
+		}
+		return outputResult(cmd, me)
+	}
+
+	users, err := client.SearchUsers(query)
+	if err != nil {
+		return err
+	}
+
+	switch len(users) {
+	case 0:
+		// Account IDs are not searchable on every instance
+		user, err := client.GetUser(query)
+		if err != nil {
+			return fmt.Errorf("no user matches %q", query)
+		}
+		return outputResult(cmd, user)
+	case 1:
+		return outputResult(cmd, &users[0])
+	}
+
+	return outputResult(cmd, users)
+}
+
+func userRows(user *jira.User) [][]any {
+	rows := [][]any{
+		{"Name", user.DisplayName},
+		{"Email", user.EmailAddress},
+	}
+
+	if user.AccountID != "" {
+		rows = append(rows, []any{"Account ID", user.AccountID})
+	}
+	if user.Name != "" {
+		rows = append(rows, []any{"Username", user.Name})
+	}
+	if user.Key != "" {
+		rows = append(rows, []any{"Key", user.Key})
+	}
+	if user.TimeZone != "" {
+		rows = append(rows, []any{"Time Zone", user.TimeZone})
+	}
+
+	return append(rows, []any{"Active", fmt.Sprintf("%t", user.Active)})
+}
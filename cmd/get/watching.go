@@ -0,0 +1,141 @@
+package get
+
+import (
+	"fmt"
+	"strings"
+
+	jiraclient "github.com/lburgazzoli/gira/internal/client"
+	"github.com/lburgazzoli/gira/pkg/config"
+	"github.com/lburgazzoli/gira/pkg/jira"
+	stringutils "github.com/lburgazzoli/gira/pkg/utils/strings"
+	tableutils "github.com/lburgazzoli/gira/pkg/utils/table"
+	"github.com/spf13/cobra"
+)
+
+const watchingJQL = "watcher = currentUser()"
+
+// watchingFields are the issue fields displayed for watched issues
+var watchingFields = []string{"summary", "status", "assignee", "issuetype"}
+
+var watchingQuery string
+
+var watchingCmd = &cobra.Command{
+	Use:   "watching",
+	Short: "List the issues watched by the current user",
+	Long: `List the issues watched by the current user, optionally restricted by a
+JQL query.
+
+Examples:
+  gira get watching
+  gira get watching --jql "project = OPS AND resolution = Unresolved"
+  gira get watching --output json`,
+	Args: cobra.NoArgs,
+	RunE: runGetWatching,
+}
+
+var watchersCmd = &cobra.Command{
+	Use:   "watchers ISSUE-KEY",
+	Short: "List the watchers of an issue",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runGetWatchers,
+}
+
+func init() {
+	watchingCmd.Flags().StringVar(&watchingQuery, "jql", "", "Only list watched issues matching a JQL query")
+
+	Cmd.AddCommand(watchingCmd)
+	Cmd.AddCommand(watchersCmd)
+}
+
+func runGetWatching(cmd *cobra.Command, _ []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	client, err := jiraclient.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create JIRA client: %w", err)
+	}
+
+	jql := watchingJQL
+	if watchingQuery != "" {
+		jql += " AND (" + watchingQuery + ")"
+	}
+
+	issues, err := client.SearchAllIssues(jql+" ORDER BY updated DESC", watchingFields)
+	if err != nil {
+		return fmt.Errorf("failed to search watched issues: %w", err)
+	}
+
+	return outputResult(cmd, issues)
+}
+
+func runGetWatchers(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	client, err := jiraclient.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create JIRA client: %w", err)
+	}
+
+	watchers, err := client.GetWatchers(strings.ToUpper(args[0]))
+	if err != nil {
+		return err
+	}
+
+	return outputResult(cmd, watchers.Watchers)
+}
+
+func outputIssuesTable(issues []jira.Issue) error {
+	if len(issues) == 0 {
+		fmt.Println("No issues found.")
+		return nil
+	}
+
+	renderer := tableutils.NewRenderer(
+		tableutils.WithHeaders("KEY", "TYPE", "STATUS", "ASSIGNEE", "SUMMARY"),
+	)
+
+	rows := make([][]any, 0, len(issues))
+	for _, issue := range issues {
+		rows = append(rows, []any{
+			issue.Key,
+			issue.Fields.IssueType.Name,
+			issue.Fields.Status.Name,
+			getAssigneeDisplay(&issue),
+			stringutils.Truncate(issue.Fields.Summary, 60),
+		})
+	}
+
+	if err := renderer.AppendAll(rows); err != nil {
+		return err
+	}
+
+	return renderer.Render()
+}
+
+func outputUsersTable(users []jira.User) error {
+	if len(users) == 0 {
+		fmt.Println("No users found.")
+		return nil
+	}
+
+	renderer := tableutils.NewRenderer(
+		tableutils.WithHeaders("NAME", "EMAIL", "ACCOUNT ID"),
+	)
+
+	rows := make([][]any, 0, len(users))
+	for _, u := range users {
+		rows = append(rows, []any{u.DisplayName, u.EmailAddress, u.AccountID})
+	}
+
+	if err := renderer.AppendAll(rows); err != nil {
+		return err
+	}
+
+	return renderer.Render()
+}
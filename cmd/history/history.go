@@ -0,0 +1,195 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	jiraclient "github.com/lburgazzoli/gira/internal/client"
+	"github.com/lburgazzoli/gira/pkg/config"
+	"github.com/lburgazzoli/gira/pkg/jira"
+	stringutils "github.com/lburgazzoli/gira/pkg/utils/strings"
+	tableutils "github.com/lburgazzoli/gira/pkg/utils/table"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// timestampLayout is used to display the time of a change
+	timestampLayout = "2006-01-02 15:04"
+	// emptyValue is displayed for fields that were unset
+	emptyValue = "(none)"
+)
+
+var (
+	historyFields []string
+)
+
+var Cmd = &cobra.Command{
+	Use:   "history ISSUE-KEY",
+	Short: "Show the change history of an issue",
+	Long: `Show a timeline of who changed what and when on an issue.
+
+Examples:
+  gira history PROJ-123
+  gira history PROJ-123 --field status
+  gira history PROJ-123 --field status --field assignee --output table
+  gira history PROJ-123 --output json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runHistory,
+}
+
+func init() {
+	Cmd.Flags().StringSliceVar(&historyFields, "field", nil, "Only show changes of the given fields (repeatable)")
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	issueKey := args[0]
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	client, err := jiraclient.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create JIRA client: %w", err)
+	}
+
+	histories, err := client.GetChangelog(issueKey)
+	if err != nil {
+		return fmt.Errorf("failed to get history of %s: %w", issueKey, err)
+	}
+
+	return outputResult(cmd, issueKey, filterHistories(histories, historyFields))
+}
+
+// filterHistories keeps the changes of the given fields, dropping the
+// histories left without changes. Fields match by name or ID, ignoring case.
+func filterHistories(histories []jira.ChangeHistory, fields []string) []jira.ChangeHistory {
+	if len(fields) == 0 {
+		return histories
+	}
+
+	filtered := make([]jira.ChangeHistory, 0, len(histories))
+	for _, history := range histories {
+		items := make([]jira.ChangeItem, 0, len(history.Items))
+		for _, item := range history.Items {
+			if matchesField(item, fields) {
+				items = append(items, item)
+			}
+		}
+
+		if len(items) > 0 {
+			history.Items = items
+			filtered = append(filtered, history)
+		}
+	}
+
+	return filtered
+}
+
+func matchesField(item jira.ChangeItem, fields []string) bool {
+	for _, field := range fields {
+		if strings.EqualFold(item.Field, field) || (item.FieldID != "" && strings.EqualFold(item.FieldID, field)) {
+			return true
+		}
+	}
+	return false
+}
+
+func outputResult(cmd *cobra.Command, issueKey string, histories []jira.ChangeHistory) error {
+	outputFormat, _ := cmd.Root().PersistentFlags().GetString("output")
+
+	switch outputFormat {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(histories)
+	case "yaml":
+		encoder := yaml.NewEncoder(os.Stdout)
+		return encoder.Encode(histories)
+	case "table":
+		return outputTable(histories)
+	case "":
+		return outputPlain(issueKey, histories)
+	default:
+		return fmt.Errorf("unsupported output format: %s", outputFormat)
+	}
+}
+
+func outputTable(histories []jira.ChangeHistory) error {
+	if len(histories) == 0 {
+		fmt.Println("No changes found.")
+		return nil
+	}
+
+	renderer := tableutils.NewRenderer(
+		tableutils.WithHeaders("DATE", "AUTHOR", "FIELD", "FROM", "TO"),
+	)
+
+	rows := make([][]any, 0, len(histories))
+	for _, history := range histories {
+		for _, item := range history.Items {
+			rows = append(rows, []any{
+				history.Created.Local().Format(timestampLayout),
+				authorName(history.Author),
+				item.Field,
+				stringutils.Truncate(valueOrEmpty(item.FromString), 40),
+				stringutils.Truncate(valueOrEmpty(item.ToString), 40),
+			})
+		}
+	}
+
+	if err := renderer.AppendAll(rows); err != nil {
+		return err
+	}
+
+	return renderer.Render()
+}
+
+func outputPlain(issueKey string, histories []jira.ChangeHistory) error {
+	bold := color.New(color.Bold).SprintFunc()
+	cyan := color.New(color.FgCyan).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+	green := color.New(color.FgGreen).SprintFunc()
+
+	fmt.Printf("History of %s\n", issueKey)
+
+	if len(histories) == 0 {
+		fmt.Println("No changes found.")
+		return nil
+	}
+
+	for _, history := range histories {
+		fmt.Println()
+		fmt.Printf("%s  %s\n", cyan(history.Created.Local().Format(timestampLayout)), bold(authorName(history.Author)))
+
+		for _, item := range history.Items {
+			fmt.Printf("  %s: %s → %s\n",
+				item.Field,
+				red(stringutils.Truncate(valueOrEmpty(item.FromString), 60)),
+				green(stringutils.Truncate(valueOrEmpty(item.ToString), 60)))
+		}
+	}
+
+	return nil
+}
+
+func authorName(user *jira.User) string {
+	if user == nil || user.DisplayName == "" {
+		return "Unknown"
+	}
+	return user.DisplayName
+}
+
+func valueOrEmpty(value string) string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return emptyValue
+	}
+	// Multi-line values such as descriptions are shown on a single line
+	return strings.Join(strings.Fields(value), " ")
+}
@@ -0,0 +1,388 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	jiraclient "github.com/lburgazzoli/gira/internal/client"
+	"github.com/lburgazzoli/gira/pkg/config"
+	"github.com/lburgazzoli/gira/pkg/jira"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importFile    string
+	importMapping string
+	importResults string
+	importDryRun  bool
+)
+
+var Cmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import data into JIRA",
+	Long:  `Import data into JIRA.`,
+}
+
+var issuesCmd = &cobra.Command{
+	Use:   "issues --file FILE --mapping MAPPING",
+	Short: "Create issues from a CSV or JSON file",
+	Long: `Create issues from the rows of a CSV file, whose first line names the
+columns, or from a JSON file holding an array of objects keyed by column,
+list values being joined with commas. A YAML mapping tells which field each
+column sets, by field ID or name, custom fields included:
+
+  project: PROJ             # unless a column maps to project
+  columns:
+    Summary: summary
+    Type: issuetype
+    Details: description
+    Owner: assignee
+    Estimate: Story Points
+    Tags: labels            # comma separated
+  defaults:
+    issuetype: Task         # for empty cells
+  id: Ref                   # identifies rows, by default their number
+  parent: Parent            # a row identifier or an existing issue key
+
+Every row is validated before anything is created. Issues are then created
+with the bulk endpoint, 50 at a time, parents before their children: stories
+are attached to epics through the Epic Link field when the instance has one,
+and through parent otherwise.
+
+The results file maps each row to the created issue key, or to the reason it
+failed; it defaults to the input file name with a -results suffix.
+
+Examples:
+  gira import issues --file backlog.csv --mapping mapping.yaml
+  gira import issues --file backlog.csv --mapping mapping.yaml --dry-run
+  gira import issues --file backlog.csv --mapping mapping.yaml --results created.csv
+  gira import issues --file backlog.json --mapping mapping.yaml`,
+	Args: cobra.NoArgs,
+	RunE: runImportIssues,
+}
+
+func init() {
+	issuesCmd.Flags().StringVar(&importFile, "file", "", "CSV or JSON file with one issue per row")
+	issuesCmd.Flags().StringVar(&importMapping, "mapping", "", "YAML file mapping columns to fields")
+	issuesCmd.Flags().StringVar(&importResults, "results", "", "CSV file receiving the created issue keys")
+	issuesCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "Only validate the rows")
+	_ = issuesCmd.MarkFlagRequired("file")
+	_ = issuesCmd.MarkFlagRequired("mapping")
+
+	Cmd.AddCommand(issuesCmd)
+}
+
+// row is an issue to import
+type row struct {
+	// number is the position of the row in the file, from 1
+	number int
+	id     string
+	fields map[string]interface{}
+	// parentRef is the content of the parent column
+	parentRef string
+	parent    *row
+	parentKey string
+	// parentField is the field referencing the parent
+	parentField string
+	// level is the depth of the row within the hierarchy of the import
+	level int
+
+	key string
+	err error
+}
+
+func (r *row) summary() string {
+	s, _ := r.fields["summary"].(string)
+	return s
+}
+
+func (r *row) issueType() string {
+	if t, ok := r.fields["issuetype"].(map[string]string); ok {
+		return t["name"]
+	}
+	return ""
+}
+
+func runImportIssues(_ *cobra.Command, _ []string) error {
+	mapping, err := loadMapping(importMapping)
+	if err != nil {
+		return err
+	}
+
+	header, records, err := readRecords(importFile)
+	if err != nil {
+		return err
+	}
+
+	columns, err := mapping.checkHeader(header)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	client, err := jiraclient.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create JIRA client: %w", err)
+	}
+
+	v, err := newValidator(client, mapping, columns)
+	if err != nil {
+		return err
+	}
+
+	rows := v.rows(records)
+
+	red := color.New(color.FgRed).SprintFunc()
+	green := color.New(color.FgGreen).SprintFunc()
+
+	if len(v.problems) > 0 {
+		for _, p := range v.problems {
+			fmt.Printf("%s %s\n", red("✗"), p.message)
+		}
+		return fmt.Errorf("%d problems found in %s, nothing was imported", len(v.problems), importFile)
+	}
+
+	if importDryRun {
+		fmt.Printf("%s All %d rows are valid\n", green("✓"), len(rows))
+		return nil
+	}
+
+	created := createRows(client, rows)
+
+	results := importResults
+	if results == "" {
+		results = strings.TrimSuffix(importFile, filepath.Ext(importFile)) + "-results.csv"
+	}
+	if err := writeResults(results, mapping, rows); err != nil {
+		return err
+	}
+
+	fmt.Printf("\nImported %d of %d issues, results written to %s\n", created, len(rows), results)
+
+	if created < len(rows) {
+		return fmt.Errorf("failed to import %d of %d issues", len(rows)-created, len(rows))
+	}
+
+	return nil
+}
+
+// createRows creates the issues level by level, so that parents exist before
+// their children, in batches of jira.BulkCreateLimit. It returns the number
+// of issues created.
+func createRows(client *jira.Client, rows []*row) int {
+	green := color.New(color.FgGreen).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+
+	maxLevel := 0
+	for _, r := range rows {
+		maxLevel = max(maxLevel, r.level)
+	}
+
+	created := 0
+	for level := 0; level <= maxLevel; level++ {
+		pending := make([]*row, 0)
+		for _, r := range rows {
+			if r.level != level {
+				continue
+			}
+
+			if r.parent != nil {
+				if r.parent.key == "" {
+					r.err = fmt.Errorf("parent row %d was not created", r.parent.number)
+					fmt.Printf("%s Row %d: %v\n", red("✗"), r.number, r.err)
+					continue
+				}
+				r.parentKey = r.parent.key
+			}
+			if r.parentKey != "" {
+				r.fields[r.parentField] = jira.ParentValue(r.parentField, r.parentKey)
+			}
+
+			pending = append(pending, r)
+		}
+
+		for start := 0; start < len(pending); start += jira.BulkCreateLimit {
+			batch := pending[start:min(start+jira.BulkCreateLimit, len(pending))]
+
+			issues := make([]map[string]interface{}, 0, len(batch))
+			for _, r := range batch {
+				issues = append(issues, r.fields)
+			}
+
+			result, err := client.CreateIssuesBulk(issues)
+			if err != nil {
+				for _, r := range batch {
+					r.err = err
+				}
+				fmt.Printf("%s Failed to create %d issues: %v\n", red("✗"), len(batch), err)
+				continue
+			}
+
+			keys, errs := result.Outcome(len(batch))
+			batchCreated := 0
+			for i, r := range batch {
+				r.key, r.err = keys[i], errs[i]
+				if r.err != nil {
+					fmt.Printf("%s Row %d: %v\n", red("✗"), r.number, r.err)
+					continue
+				}
+				batchCreated++
+			}
+			created += batchCreated
+
+			fmt.Printf("%s Created %d of %d issues\n", green("✓"), batchCreated, len(batch))
+		}
+	}
+
+	return created
+}
+
+// readRecords reads the header and the rows of a CSV or JSON file
+func readRecords(path string) ([]string, [][]string, error) {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return readJSON(path)
+	}
+	return readCSV(path)
+}
+
+func readCSV(path string) ([]string, [][]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	if len(records) < 2 {
+		return nil, nil, fmt.Errorf("%s has no rows to import", path)
+	}
+
+	// Spreadsheets commonly export a byte order mark
+	records[0][0] = strings.TrimPrefix(records[0][0], "\ufeff")
+
+	return records[0], records[1:], nil
+}
+
+// readJSON reads an array of objects keyed by column. Columns are the keys of
+// all the objects, values are converted to the text a CSV cell would hold.
+func readJSON(path string) ([]string, [][]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var objects []map[string]any
+	if err := decoder.Decode(&objects); err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s, expected an array of objects: %w", path, err)
+	}
+
+	if len(objects) == 0 {
+		return nil, nil, fmt.Errorf("%s has no rows to import", path)
+	}
+
+	seen := make(map[string]bool)
+	header := make([]string, 0)
+	for _, object := range objects {
+		for column := range object {
+			if !seen[column] {
+				seen[column] = true
+				header = append(header, column)
+			}
+		}
+	}
+	sort.Strings(header)
+
+	records := make([][]string, 0, len(objects))
+	for i, object := range objects {
+		record := make([]string, len(header))
+		for j, column := range header {
+			value, err := cellText(object[column])
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: row %d: %s: %w", path, i+1, column, err)
+			}
+			record[j] = value
+		}
+		records = append(records, record)
+	}
+
+	return header, records, nil
+}
+
+// cellText converts a JSON value to the content of a cell, lists being comma
+// separated
+func cellText(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case json.Number, bool:
+		return fmt.Sprint(v), nil
+	case []any:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			text, err := cellText(item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, text)
+		}
+		return strings.Join(items, ","), nil
+	default:
+		return "", fmt.Errorf("objects are not supported as values")
+	}
+}
+
+func writeResults(path string, mapping *Mapping, rows []*row) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create results file: %w", err)
+	}
+
+	writer := csv.NewWriter(file)
+
+	idColumn := mapping.ID
+	if idColumn == "" {
+		idColumn = "id"
+	}
+
+	records := [][]string{{"row", idColumn, "summary", "key", "status", "error"}}
+	for _, r := range rows {
+		status, message := "created", ""
+		if r.err != nil {
+			status, message = "failed", r.err.Error()
+		}
+		records = append(records, []string{strconv.Itoa(r.number), r.id, r.summary(), r.key, status, message})
+	}
+
+	if err := writer.WriteAll(records); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to write results: %w", err)
+	}
+
+	return file.Close()
+}
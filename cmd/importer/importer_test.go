@@ -0,0 +1,238 @@
+package importer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lburgazzoli/gira/pkg/jira"
+	"github.com/lburgazzoli/gira/pkg/jira/jiratest"
+)
+
+var testMapping = &Mapping{
+	Project: "DEMO",
+	Columns: map[string]string{
+		"Summary":   "summary",
+		"Type":      "issuetype",
+		"Component": "Component/s",
+	},
+	ID:     "Ref",
+	Parent: "Parent",
+}
+
+var testHeader = []string{"Ref", "Summary", "Type", "Parent", "Component"}
+
+func newTestClient(t *testing.T) (*jiratest.Server, *jira.Client) {
+	t.Helper()
+
+	srv := jiratest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.AddProject(jira.Project{Key: "DEMO", Components: []jira.Component{{Name: "Backend"}}})
+
+	client, err := srv.NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return srv, client
+}
+
+// validate converts records to rows with the test mapping
+func validate(t *testing.T, client *jira.Client, records [][]string) (*validator, []*row) {
+	t.Helper()
+
+	columns, err := testMapping.checkHeader(testHeader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := newValidator(client, testMapping, columns)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return v, v.rows(records)
+}
+
+func TestValidateCollectsProblems(t *testing.T) {
+	srv, client := newTestClient(t)
+
+	_, rows := validate(t, client, [][]string{{"X", "Valid", "Task", "", ""}})
+	if len(rows) != 1 {
+		t.Fatalf("got %d rows, want 1", len(rows))
+	}
+
+	v, _ := validate(t, client, [][]string{
+		{"A", "", "Task", "", ""},
+		{"B", "Two", "", "B", ""},
+		{"C", "Three", "Task", "D", ""},
+		{"D", "Four", "Task", "C", ""},
+		{"A", "Five", "Task", "nope", ""},
+		{"F", "Six", "Task", "DEMO-99", ""},
+		{"G", "Seven", "Task", "", "Backend"},
+	})
+
+	want := []struct {
+		row     int
+		message string
+	}{
+		{row: 1, message: "summary is required"},
+		{row: 2, message: "issue type is required"},
+		{row: 2, message: "cannot be its own parent"},
+		{row: 3, message: "form a cycle"},
+		{row: 5, message: `identifier "A" is already used by row 1`},
+		{row: 5, message: `"nope" is neither a row of the file nor an issue key`},
+		{row: 6, message: "parent DEMO-99"},
+	}
+
+	if len(v.problems) != len(want) {
+		t.Fatalf("problems = %v, want %d", v.problems, len(want))
+	}
+	for i, w := range want {
+		p := v.problems[i]
+		if p.row != w.row || !strings.Contains(p.message, w.message) {
+			t.Errorf("problem %d = row %d %q, want row %d %q", i, p.row, p.message, w.row, w.message)
+		}
+	}
+
+	if n := len(srv.Requests()); n == 0 {
+		t.Error("no request was sent")
+	}
+	for _, r := range srv.Requests() {
+		if r.Method != http.MethodGet {
+			t.Errorf("validation sent %s %s", r.Method, r.Path)
+		}
+	}
+}
+
+func TestImport(t *testing.T) {
+	srv, client := newTestClient(t)
+
+	// An epic, 52 stories taking two batches, one of them failing, and
+	// subtasks of a created and of the failed story
+	records := [][]string{{"E1", "The epic", "Epic", "", ""}}
+	for i := 2; i <= 53; i++ {
+		component := "Backend"
+		if i == 53 {
+			component = "Missing"
+		}
+		records = append(records, []string{fmt.Sprintf("S%d", i), fmt.Sprintf("Story %d", i), "Story", "E1", component})
+	}
+	records = append(records,
+		[]string{"T54", "Orphan", "Sub-task", "S53", ""},
+		[]string{"T55", "Subtask", "Sub-task", "S2", ""},
+	)
+
+	v, rows := validate(t, client, records)
+	if len(v.problems) > 0 {
+		t.Fatalf("problems = %v", v.problems)
+	}
+
+	if created := createRows(client, rows); created != 53 {
+		t.Errorf("created %d issues, want 53", created)
+	}
+
+	bulk := 0
+	for _, r := range srv.Requests() {
+		if r.Method == http.MethodPost && r.Path == "/rest/api/2/issue/bulk" {
+			bulk++
+		}
+	}
+	if bulk != 4 {
+		t.Errorf("%d bulk requests, want 4", bulk)
+	}
+
+	subtask, ok := srv.Issue(rows[54].key)
+	if !ok || subtask.Fields.Parent == nil || subtask.Fields.Parent.Key != rows[1].key {
+		t.Errorf("subtask %s = %+v, want %s as parent", rows[54].key, subtask, rows[1].key)
+	}
+
+	path := filepath.Join(t.TempDir(), "results.csv")
+	if err := writeResults(path, testMapping, rows); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	results, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 56 {
+		t.Fatalf("got %d result lines, want 56", len(results))
+	}
+	if got := strings.Join(results[0], ","); got != "row,Ref,summary,key,status,error" {
+		t.Errorf("header = %s", got)
+	}
+
+	for _, tt := range []struct {
+		line    int
+		want    []string
+		message string
+	}{
+		{line: 1, want: []string{"1", "E1", "The epic", "DEMO-1", "created", ""}},
+		{line: 52, want: []string{"52", "S52", "Story 52", "DEMO-52", "created", ""}},
+		{line: 53, want: []string{"53", "S53", "Story 53", "", "failed"}, message: "Missing"},
+		{line: 54, want: []string{"54", "T54", "Orphan", "", "failed"}, message: "parent row 53 was not created"},
+		{line: 55, want: []string{"55", "T55", "Subtask", "DEMO-53", "created", ""}},
+	} {
+		got := results[tt.line]
+		if strings.Join(got[:len(tt.want)], ",") != strings.Join(tt.want, ",") || !strings.Contains(got[5], tt.message) {
+			t.Errorf("line %d = %q, want %q with error %q", tt.line, got, tt.want, tt.message)
+		}
+	}
+}
+
+func TestReadJSON(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "backlog.json")
+	data := `[
+  {"Ref": "E1", "Summary": "The epic", "Type": "Epic", "Estimate": 3.5},
+  {"Ref": "S2", "Summary": "A story", "Type": "Story", "Parent": "E1", "Tags": ["a", "b"], "Done": false, "Owner": null}
+]`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	header, records, err := readRecords(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := strings.Join(header, ","); got != "Done,Estimate,Owner,Parent,Ref,Summary,Tags,Type" {
+		t.Errorf("header = %s", got)
+	}
+	want := [][]string{
+		{"", "3.5", "", "", "E1", "The epic", "", "Epic"},
+		{"false", "", "", "E1", "S2", "A story", "a,b", "Story"},
+	}
+	if fmt.Sprint(records) != fmt.Sprint(want) {
+		t.Errorf("records = %q, want %q", records, want)
+	}
+
+	for name, data := range map[string]string{
+		"object.json": `[{"Summary": {"text": "nested"}}]`,
+		"empty.json":  `[]`,
+		"single.json": `{"Summary": "not an array"}`,
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := readRecords(path); err == nil {
+			t.Errorf("reading %s succeeded", name)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package importer
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Mapping describes how the columns of a CSV file map to issue fields
+type Mapping struct {
+	// Project is the project of the issues, unless a column maps to project
+	Project string `yaml:"project"`
+	// Columns maps column names to field IDs or names
+	Columns map[string]string `yaml:"columns"`
+	// Defaults are field values used for empty cells and unmapped fields
+	Defaults map[string]string `yaml:"defaults"`
+	// ID is the column identifying rows for parent references, rows are
+	// identified by their number otherwise
+	ID string `yaml:"id"`
+	// Parent is the column holding the parent of an issue, as a row
+	// identifier or the key of an existing issue
+	Parent string `yaml:"parent"`
+}
+
+func loadMapping(path string) (*Mapping, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mapping: %w", err)
+	}
+
+	var mapping Mapping
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&mapping); err != nil {
+		return nil, fmt.Errorf("invalid mapping %s: %w", path, err)
+	}
+
+	if len(mapping.Columns) == 0 {
+		return nil, fmt.Errorf("invalid mapping %s: no columns are mapped", path)
+	}
+
+	mapping.Project = strings.ToUpper(strings.TrimSpace(mapping.Project))
+
+	return &mapping, nil
+}
+
+// checkHeader verifies that the columns named by the mapping exist
+func (m *Mapping) checkHeader(header []string) (map[string]int, error) {
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[strings.TrimSpace(name)] = i
+	}
+
+	missing := make([]string, 0)
+	for column := range m.Columns {
+		if _, ok := index[column]; !ok {
+			missing = append(missing, column)
+		}
+	}
+	for _, column := range []string{m.ID, m.Parent} {
+		if _, ok := index[column]; column != "" && !ok {
+			missing = append(missing, column)
+		}
+	}
+
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("columns missing from the file: %s", strings.Join(missing, ", "))
+	}
+
+	return index, nil
+}
@@ -0,0 +1,294 @@
+package importer
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lburgazzoli/gira/pkg/jira"
+)
+
+// validator converts the records of the file to rows, collecting every
+// problem found instead of stopping at the first one
+type validator struct {
+	client  *jira.Client
+	mapping *Mapping
+	columns map[string]int
+
+	// fields maps the mapped columns to their field
+	fields   map[string]*jira.Field
+	defaults map[*jira.Field]string
+	epicLink *jira.Field
+
+	// values memoizes converted values, resolving users is a request
+	values map[string]interface{}
+	// existing memoizes the issues referenced as parents
+	existing map[string]*jira.Issue
+
+	problems []problem
+}
+
+// problem is an invalid cell of the file
+type problem struct {
+	row     int
+	message string
+}
+
+func newValidator(client *jira.Client, mapping *Mapping, columns map[string]int) (*validator, error) {
+	fields, err := client.GetFields()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fields: %w", err)
+	}
+
+	v := &validator{
+		client:   client,
+		mapping:  mapping,
+		columns:  columns,
+		fields:   make(map[string]*jira.Field),
+		defaults: make(map[*jira.Field]string),
+		values:   make(map[string]interface{}),
+		existing: make(map[string]*jira.Issue),
+		epicLink: jira.EpicLinkField(fields),
+	}
+
+	problems := make([]string, 0)
+	for _, column := range sortedKeys(mapping.Columns) {
+		field, err := jira.FindField(fields, mapping.Columns[column])
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("column %s: %v", column, err))
+			continue
+		}
+		v.fields[column] = field
+	}
+	for _, ref := range sortedKeys(mapping.Defaults) {
+		field, err := jira.FindField(fields, ref)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("default %s: %v", ref, err))
+			continue
+		}
+		v.defaults[field] = mapping.Defaults[ref]
+	}
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid mapping %s:\n  %s", importMapping, strings.Join(problems, "\n  "))
+	}
+
+	return v, nil
+}
+
+// rows validates the records and returns the rows to import, ordered as in
+// the file
+func (v *validator) rows(records [][]string) []*row {
+	rows := make([]*row, 0, len(records))
+	byID := make(map[string]*row, len(records))
+
+	for i, record := range records {
+		r := &row{number: i + 1, id: strconv.Itoa(i + 1), fields: make(map[string]interface{})}
+		if v.mapping.ID != "" {
+			r.id = cell(record, v.columns[v.mapping.ID])
+			if r.id == "" {
+				v.problem(r, v.mapping.ID, "identifier is empty")
+			} else if other, ok := byID[r.id]; ok {
+				v.problem(r, v.mapping.ID, fmt.Sprintf("identifier %q is already used by row %d", r.id, other.number))
+			}
+		}
+		if v.mapping.Parent != "" {
+			r.parentRef = cell(record, v.columns[v.mapping.Parent])
+		}
+
+		v.convert(r, record)
+
+		rows = append(rows, r)
+		if r.id != "" {
+			if _, ok := byID[r.id]; !ok {
+				byID[r.id] = r
+			}
+		}
+	}
+
+	for _, r := range rows {
+		v.resolveParent(r, byID)
+	}
+
+	v.computeLevels(rows)
+
+	sort.SliceStable(v.problems, func(i, j int) bool {
+		return v.problems[i].row < v.problems[j].row
+	})
+
+	return rows
+}
+
+// convert sets the fields of a row from its cells and the defaults
+func (v *validator) convert(r *row, record []string) {
+	set := make(map[string]bool)
+
+	for _, column := range sortedKeys(v.fields) {
+		field := v.fields[column]
+		value := cell(record, v.columns[column])
+		if value == "" {
+			continue
+		}
+
+		converted, err := v.value(field, value)
+		if err != nil {
+			v.problem(r, column, err.Error())
+			continue
+		}
+		r.fields[field.ID] = converted
+		set[field.ID] = true
+	}
+
+	for field, value := range v.defaults {
+		if set[field.ID] {
+			continue
+		}
+
+		converted, err := v.value(field, value)
+		if err != nil {
+			v.problem(r, "default "+field.Name, err.Error())
+			continue
+		}
+		r.fields[field.ID] = converted
+	}
+
+	if _, ok := r.fields["project"]; !ok {
+		if v.mapping.Project == "" {
+			v.problem(r, "project", "no project given, set it in the mapping or map a column to it")
+		} else {
+			r.fields["project"] = map[string]string{"key": v.mapping.Project}
+		}
+	}
+	if r.summary() == "" {
+		v.problem(r, "summary", "summary is required")
+	}
+	if r.issueType() == "" {
+		v.problem(r, "issuetype", "issue type is required")
+	}
+}
+
+func (v *validator) value(field *jira.Field, value string) (interface{}, error) {
+	key := field.ID + "\x00" + value
+	if converted, ok := v.values[key]; ok {
+		return converted, nil
+	}
+
+	converted, err := v.client.FieldValue(field, value)
+	if err != nil {
+		return nil, err
+	}
+
+	v.values[key] = converted
+	return converted, nil
+}
+
+// resolveParent links a row to its parent, either another row of the file
+// or an existing issue, and picks the field referencing it
+func (v *validator) resolveParent(r *row, byID map[string]*row) {
+	if r.parentRef == "" {
+		return
+	}
+
+	parentType := ""
+	if parent, ok := byID[r.parentRef]; ok {
+		if parent == r {
+			v.problem(r, v.mapping.Parent, "an issue cannot be its own parent")
+			return
+		}
+		r.parent = parent
+		parentType = parent.issueType()
+	} else {
+		key := strings.ToUpper(r.parentRef)
+		if !jira.IsIssueKey(key) {
+			v.problem(r, v.mapping.Parent, fmt.Sprintf("%q is neither a row of the file nor an issue key", r.parentRef))
+			return
+		}
+
+		issue, ok := v.existing[key]
+		if !ok {
+			var err error
+			issue, err = v.client.GetIssue(key)
+			if err != nil {
+				v.problem(r, v.mapping.Parent, fmt.Sprintf("parent %s: %v", key, err))
+				return
+			}
+			v.existing[key] = issue
+		}
+		r.parentKey = issue.Key
+		parentType = issue.Fields.IssueType.Name
+	}
+
+	r.parentField = "parent"
+	if v.epicLink != nil && strings.EqualFold(parentType, "Epic") {
+		r.parentField = v.epicLink.ID
+	}
+}
+
+// computeLevels sets the depth of each row within the import, reporting
+// cycles among parent references
+func (v *validator) computeLevels(rows []*row) {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+
+	state := make(map[*row]int, len(rows))
+
+	var visit func(r *row) bool
+	visit = func(r *row) bool {
+		switch state[r] {
+		case done:
+			return true
+		case visiting:
+			return false
+		}
+
+		state[r] = visiting
+		if r.parent != nil {
+			if !visit(r.parent) {
+				return false
+			}
+			r.level = r.parent.level + 1
+		}
+		state[r] = done
+
+		return true
+	}
+
+	for _, r := range rows {
+		if state[r] == unvisited && !visit(r) {
+			v.problem(r, v.mapping.Parent, "parent references form a cycle")
+			// Break the cycle so that other rows are still checked
+			for _, other := range rows {
+				if state[other] == visiting {
+					state[other] = done
+				}
+			}
+		}
+	}
+}
+
+func (v *validator) problem(r *row, column string, message string) {
+	v.problems = append(v.problems, problem{
+		row:     r.number,
+		message: fmt.Sprintf("row %d: %s: %s", r.number, column, strings.TrimSpace(message)),
+	})
+}
+
+func cell(record []string, index int) string {
+	if index >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[index])
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,78 @@
+package link
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	jiraclient "github.com/lburgazzoli/gira/internal/client"
+	"github.com/lburgazzoli/gira/pkg/config"
+	"github.com/lburgazzoli/gira/pkg/jira"
+	"github.com/spf13/cobra"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "link ISSUE-KEY RELATION ISSUE-KEY",
+	Short: "Link two issues",
+	Long: `Link two issues. The relation is the outward or inward description of a
+link type, such as "blocks" or "is blocked by", or a link type name; words
+may be given as separate arguments or joined with dashes.
+
+Examples:
+  gira link PROJ-1 blocks PROJ-2
+  gira link PROJ-2 is blocked by PROJ-1
+  gira link PROJ-3 relates-to PROJ-4
+  gira link PROJ-5 duplicates PROJ-6`,
+	Args: cobra.MinimumNArgs(3),
+	RunE: runLink,
+}
+
+func runLink(_ *cobra.Command, args []string) error {
+	from := strings.ToUpper(args[0])
+	to := strings.ToUpper(args[len(args)-1])
+	phrase := strings.Join(args[1:len(args)-1], " ")
+
+	if from == to {
+		return fmt.Errorf("cannot link %s to itself", from)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	client, err := jiraclient.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create JIRA client: %w", err)
+	}
+
+	types, err := client.ListLinkTypes()
+	if err != nil {
+		return err
+	}
+
+	linkType, reversed, err := jira.ResolveLinkType(types, phrase)
+	if err != nil {
+		return err
+	}
+
+	// "A is blocked by B" is stored as "B blocks A"
+	source, destination := from, to
+	if reversed {
+		source, destination = to, from
+	}
+
+	if err := client.CreateIssueLink(linkType.Name, source, destination); err != nil {
+		return err
+	}
+
+	relation := linkType.Outward
+	if reversed {
+		relation = linkType.Inward
+	}
+
+	green := color.New(color.FgGreen).SprintFunc()
+	fmt.Printf("%s %s %s %s\n", green("✓"), from, relation, to)
+
+	return nil
+}
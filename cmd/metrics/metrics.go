@@ -0,0 +1,442 @@
+package metrics
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	jiraclient "github.com/lburgazzoli/gira/internal/client"
+	"github.com/lburgazzoli/gira/pkg/config"
+	"github.com/lburgazzoli/gira/pkg/jira"
+	stringutils "github.com/lburgazzoli/gira/pkg/utils/strings"
+	tableutils "github.com/lburgazzoli/gira/pkg/utils/table"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// dateLayout is used to display start and finish dates
+	dateLayout = "2006-01-02"
+	// groupByAuto groups by category when the issues span several projects
+	groupByAuto = "auto"
+)
+
+// flowFields are the issue fields needed to compute flow metrics
+var flowFields = []string{"summary", "status", "issuetype", "project", "created"}
+
+var (
+	flowJQL        string
+	flowFromStatus string
+	flowToStatus   string
+	flowGroupBy    string
+)
+
+var Cmd = &cobra.Command{
+	Use:   "metrics",
+	Short: "Compute delivery metrics",
+	Long:  `Compute delivery metrics from the history of JIRA issues.`,
+}
+
+var flowCmd = &cobra.Command{
+	Use:   "flow",
+	Short: "Compute cycle time, lead time and throughput",
+	Long: `Compute flow metrics from the changelog of the issues matching a JQL query.
+
+The cycle time runs from the first time an issue entered --from-status to
+the time it entered --to-status; the lead time runs from its creation to
+the same point. Only issues currently in --to-status count as finished.
+Statuses match by name or by status category, so --from-status "In Progress"
+also matches "In Review" when it belongs to the In Progress category.
+
+Time in status is grouped by status category when the issues span several
+projects, since their workflows may name statuses differently; use
+--group-by to force a grouping.
+
+The csv output has one row per issue, with durations in days, for charting.
+
+Examples:
+  gira metrics flow --jql "project = PROJ AND resolved >= -90d"
+  gira metrics flow --jql "project in (A, B)" --from-status "In Progress" --to-status Done
+  gira metrics flow --jql "project = PROJ" --group-by status --output csv > flow.csv`,
+	Args: cobra.NoArgs,
+	RunE: runFlow,
+}
+
+func init() {
+	flowCmd.Flags().StringVar(&flowJQL, "jql", "", "JQL query selecting the issues to analyze")
+	flowCmd.Flags().StringVar(&flowFromStatus, "from-status", "In Progress", "Status or status category starting the cycle time")
+	flowCmd.Flags().StringVar(&flowToStatus, "to-status", "Done", "Status or status category ending the cycle and lead time")
+	flowCmd.Flags().StringVar(&flowGroupBy, "group-by", groupByAuto, "Group time in status by (auto|status|category)")
+
+	// Override the global output flag to include csv
+	flowCmd.Flags().StringP("output", "o", "", "output format (table|json|yaml|csv)")
+
+	_ = flowCmd.MarkFlagRequired("jql")
+
+	Cmd.AddCommand(flowCmd)
+}
+
+func runFlow(cmd *cobra.Command, _ []string) error {
+	switch flowGroupBy {
+	case groupByAuto, string(jira.FlowGroupByStatus), string(jira.FlowGroupByCategory):
+	default:
+		return fmt.Errorf("unsupported grouping: %s", flowGroupBy)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	client, err := jiraclient.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create JIRA client: %w", err)
+	}
+
+	issues, err := client.SearchAllIssues(flowJQL, flowFields)
+	if err != nil {
+		return fmt.Errorf("failed to search issues: %w", err)
+	}
+
+	statuses, err := client.GetStatuses()
+	if err != nil {
+		return fmt.Errorf("failed to get statuses: %w", err)
+	}
+
+	opts := jira.FlowOptions{
+		FromStatus: flowFromStatus,
+		ToStatus:   flowToStatus,
+		GroupBy:    groupBy(issues),
+		Statuses:   statuses,
+		Now:        time.Now(),
+	}
+
+	flows := make([]jira.IssueFlow, 0, len(issues))
+	for i := range issues {
+		histories, err := client.GetChangelog(issues[i].Key)
+		if err != nil {
+			return fmt.Errorf("failed to get history of %s: %w", issues[i].Key, err)
+		}
+
+		flows = append(flows, jira.ComputeIssueFlow(&issues[i], histories, opts))
+	}
+
+	return outputResult(cmd, jira.BuildFlowReport(flows, opts))
+}
+
+// groupBy resolves the --group-by flag, grouping by status category when
+// the issues belong to projects with possibly different workflows
+func groupBy(issues []jira.Issue) jira.FlowGrouping {
+	if flowGroupBy != groupByAuto {
+		return jira.FlowGrouping(flowGroupBy)
+	}
+
+	projects := make(map[string]bool)
+	for _, issue := range issues {
+		project := issue.Fields.Project.Key
+		if project == "" {
+			project, _, _ = strings.Cut(issue.Key, "-")
+		}
+		projects[project] = true
+	}
+
+	if len(projects) > 1 {
+		return jira.FlowGroupByCategory
+	}
+	return jira.FlowGroupByStatus
+}
+
+// getOutputFormat checks the local output flag first, then falls back to the global flag
+func getOutputFormat(cmd *cobra.Command) string {
+	outputFormat, _ := cmd.Flags().GetString("output")
+	if outputFormat == "" {
+		outputFormat, _ = cmd.Root().PersistentFlags().GetString("output")
+	}
+	return outputFormat
+}
+
+func outputResult(cmd *cobra.Command, report *jira.FlowReport) error {
+	outputFormat := getOutputFormat(cmd)
+
+	switch outputFormat {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(newReportView(report))
+	case "yaml":
+		encoder := yaml.NewEncoder(os.Stdout)
+		return encoder.Encode(newReportView(report))
+	case "csv":
+		return outputCSV(report)
+	case "table":
+		return outputTable(report)
+	case "":
+		return outputPlain(report)
+	default:
+		return fmt.Errorf("unsupported output format: %s", outputFormat)
+	}
+}
+
+func outputTable(report *jira.FlowReport) error {
+	if len(report.Issues) == 0 {
+		fmt.Println("No issues found.")
+		return nil
+	}
+
+	renderer := tableutils.NewRenderer(
+		tableutils.WithHeaders("KEY", "TYPE", "STATUS", "STARTED", "FINISHED", "CYCLE DAYS", "LEAD DAYS", "SUMMARY"),
+	)
+
+	rows := make([][]any, 0, len(report.Issues))
+	for _, flow := range report.Issues {
+		rows = append(rows, []any{
+			flow.Key,
+			flow.Type,
+			flow.Status,
+			formatDate(flow.Started),
+			formatDate(flow.Finished),
+			formatFinishedDays(flow, flow.CycleTime, flow.Started != nil),
+			formatFinishedDays(flow, flow.LeadTime, true),
+			stringutils.Truncate(flow.Summary, 50),
+		})
+	}
+
+	if err := renderer.AppendAll(rows); err != nil {
+		return err
+	}
+
+	return renderer.Render()
+}
+
+func outputPlain(report *jira.FlowReport) error {
+	bold := color.New(color.Bold).SprintFunc()
+	cyan := color.New(color.FgCyan).SprintFunc()
+
+	finished := 0
+	for _, flow := range report.Issues {
+		if flow.Finished != nil {
+			finished++
+		}
+	}
+
+	fmt.Printf("Flow from %s to %s: %d issues, %d finished\n",
+		bold(report.FromStatus), bold(report.ToStatus), len(report.Issues), finished)
+
+	fmt.Println()
+	fmt.Println(cyan("Cycle time (days)"))
+	fmt.Printf("  %s\n", formatPercentiles(report.CycleTime))
+	fmt.Println(cyan("Lead time (days)"))
+	fmt.Printf("  %s\n", formatPercentiles(report.LeadTime))
+
+	fmt.Println()
+	fmt.Println(cyan(fmt.Sprintf("Time in %s (days)", report.GroupBy)))
+	for _, status := range sortedKeys(report.TimeInStatus) {
+		fmt.Printf("  %-20s %s\n", status, formatPercentiles(report.TimeInStatus[status]))
+	}
+
+	fmt.Println()
+	fmt.Println(cyan("Weekly throughput"))
+	if len(report.Throughput) == 0 {
+		fmt.Println("  No finished issues.")
+	}
+	for _, week := range report.Throughput {
+		fmt.Printf("  %s  %3d %s\n", week.Week.Format(dateLayout), week.Finished, strings.Repeat("▇", week.Finished))
+	}
+
+	return nil
+}
+
+// outputCSV writes one row per issue, with durations in days and a column
+// per status (or category) for the time in status
+func outputCSV(report *jira.FlowReport) error {
+	statuses := sortedKeys(report.TimeInStatus)
+
+	w := csv.NewWriter(os.Stdout)
+
+	header := []string{"key", "type", "status", "created", "started", "finished", "cycle_time_days", "lead_time_days"}
+	for _, status := range statuses {
+		header = append(header, "time_in_"+strings.ReplaceAll(strings.ToLower(status), " ", "_")+"_days")
+	}
+	if err := w.Write(header); err != nil {
+		return err
+	}
+
+	for _, flow := range report.Issues {
+		row := []string{
+			flow.Key,
+			flow.Type,
+			flow.Status,
+			flow.Created.Format(time.RFC3339),
+			formatTimestamp(flow.Started),
+			formatTimestamp(flow.Finished),
+			csvDays(flow, flow.CycleTime, flow.Started != nil),
+			csvDays(flow, flow.LeadTime, true),
+		}
+		for _, status := range statuses {
+			row = append(row, strconv.FormatFloat(days(flow.TimeInStatus[status]), 'f', 2, 64))
+		}
+
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+// reportView renders a flow report with durations in days
+type reportView struct {
+	FromStatus   string                     `json:"fromStatus" yaml:"fromStatus"`
+	ToStatus     string                     `json:"toStatus" yaml:"toStatus"`
+	GroupBy      jira.FlowGrouping          `json:"groupBy" yaml:"groupBy"`
+	CycleTime    percentilesView            `json:"cycleTimeDays" yaml:"cycleTimeDays"`
+	LeadTime     percentilesView            `json:"leadTimeDays" yaml:"leadTimeDays"`
+	TimeInStatus map[string]percentilesView `json:"timeInStatusDays" yaml:"timeInStatusDays"`
+	Throughput   []throughputView           `json:"throughput" yaml:"throughput"`
+	Issues       []issueView                `json:"issues" yaml:"issues"`
+}
+
+type percentilesView struct {
+	Count int     `json:"count" yaml:"count"`
+	P50   float64 `json:"p50" yaml:"p50"`
+	P85   float64 `json:"p85" yaml:"p85"`
+	P95   float64 `json:"p95" yaml:"p95"`
+}
+
+type throughputView struct {
+	Week     string `json:"week" yaml:"week"`
+	Finished int    `json:"finished" yaml:"finished"`
+}
+
+type issueView struct {
+	Key          string             `json:"key" yaml:"key"`
+	Summary      string             `json:"summary" yaml:"summary"`
+	Type         string             `json:"type" yaml:"type"`
+	Status       string             `json:"status" yaml:"status"`
+	Created      time.Time          `json:"created" yaml:"created"`
+	Started      *time.Time         `json:"started,omitempty" yaml:"started,omitempty"`
+	Finished     *time.Time         `json:"finished,omitempty" yaml:"finished,omitempty"`
+	CycleTime    *float64           `json:"cycleTimeDays,omitempty" yaml:"cycleTimeDays,omitempty"`
+	LeadTime     *float64           `json:"leadTimeDays,omitempty" yaml:"leadTimeDays,omitempty"`
+	TimeInStatus map[string]float64 `json:"timeInStatusDays" yaml:"timeInStatusDays"`
+}
+
+func newReportView(report *jira.FlowReport) reportView {
+	view := reportView{
+		FromStatus:   report.FromStatus,
+		ToStatus:     report.ToStatus,
+		GroupBy:      report.GroupBy,
+		CycleTime:    newPercentilesView(report.CycleTime),
+		LeadTime:     newPercentilesView(report.LeadTime),
+		TimeInStatus: make(map[string]percentilesView, len(report.TimeInStatus)),
+		Throughput:   make([]throughputView, 0, len(report.Throughput)),
+		Issues:       make([]issueView, 0, len(report.Issues)),
+	}
+
+	for status, p := range report.TimeInStatus {
+		view.TimeInStatus[status] = newPercentilesView(p)
+	}
+
+	for _, week := range report.Throughput {
+		view.Throughput = append(view.Throughput, throughputView{
+			Week:     week.Week.Format(dateLayout),
+			Finished: week.Finished,
+		})
+	}
+
+	for _, flow := range report.Issues {
+		issue := issueView{
+			Key:          flow.Key,
+			Summary:      flow.Summary,
+			Type:         flow.Type,
+			Status:       flow.Status,
+			Created:      flow.Created,
+			Started:      flow.Started,
+			Finished:     flow.Finished,
+			TimeInStatus: make(map[string]float64, len(flow.TimeInStatus)),
+		}
+
+		if flow.Finished != nil {
+			lead := days(flow.LeadTime)
+			issue.LeadTime = &lead
+			if flow.Started != nil {
+				cycle := days(flow.CycleTime)
+				issue.CycleTime = &cycle
+			}
+		}
+
+		for status, d := range flow.TimeInStatus {
+			issue.TimeInStatus[status] = days(d)
+		}
+
+		view.Issues = append(view.Issues, issue)
+	}
+
+	return view
+}
+
+func newPercentilesView(p jira.Percentiles) percentilesView {
+	return percentilesView{
+		Count: p.Count,
+		P50:   days(p.P50),
+		P85:   days(p.P85),
+		P95:   days(p.P95),
+	}
+}
+
+// days converts a duration to days, rounded to two decimals
+func days(d time.Duration) float64 {
+	return math.Round(d.Hours()/24*100) / 100
+}
+
+func formatPercentiles(p jira.Percentiles) string {
+	if p.Count == 0 {
+		return "no data"
+	}
+	return fmt.Sprintf("p50 %6.2f  p85 %6.2f  p95 %6.2f  (n=%d)", days(p.P50), days(p.P85), days(p.P95), p.Count)
+}
+
+func formatFinishedDays(flow jira.IssueFlow, d time.Duration, available bool) string {
+	if flow.Finished == nil || !available {
+		return "-"
+	}
+	return strconv.FormatFloat(days(d), 'f', 2, 64)
+}
+
+func csvDays(flow jira.IssueFlow, d time.Duration, available bool) string {
+	if flow.Finished == nil || !available {
+		return ""
+	}
+	return strconv.FormatFloat(days(d), 'f', 2, 64)
+}
+
+func formatDate(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Local().Format(dateLayout)
+}
+
+func formatTimestamp(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
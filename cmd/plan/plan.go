@@ -0,0 +1,76 @@
+package plan
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	jiraclient "github.com/lburgazzoli/gira/internal/client"
+	"github.com/lburgazzoli/gira/pkg/config"
+	"github.com/lburgazzoli/gira/pkg/spec"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var planFile string
+
+var Cmd = &cobra.Command{
+	Use:   "plan -f SPEC",
+	Short: "Show the changes needed to match a spec",
+	Long: `Compare the issues described by a YAML spec with JIRA and show the changes
+"gira apply" would make, without making them.
+
+Issues are matched through their external ID, stored in a label
+(gira:<id> by default) or in an entity property. See "gira apply --help"
+for the spec format.
+
+Examples:
+  gira plan -f release-checklist.yaml
+  gira plan -f onboarding.yaml --output json`,
+	Args: cobra.NoArgs,
+	RunE: runPlan,
+}
+
+func init() {
+	Cmd.Flags().StringVarP(&planFile, "file", "f", "", "Spec file")
+	_ = Cmd.MarkFlagRequired("file")
+}
+
+func runPlan(cmd *cobra.Command, _ []string) error {
+	s, err := spec.Load(planFile)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	client, err := jiraclient.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create JIRA client: %w", err)
+	}
+
+	plan, err := spec.NewPlan(client, s)
+	if err != nil {
+		return err
+	}
+
+	outputFormat, _ := cmd.Root().PersistentFlags().GetString("output")
+
+	switch outputFormat {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(plan)
+	case "yaml":
+		encoder := yaml.NewEncoder(os.Stdout)
+		return encoder.Encode(plan)
+	case "table", "":
+		spec.WritePlan(os.Stdout, plan)
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format: %s", outputFormat)
+	}
+}
@@ -0,0 +1,442 @@
+package release
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/fatih/color"
+	jiraclient "github.com/lburgazzoli/gira/internal/client"
+	"github.com/lburgazzoli/gira/pkg/config"
+	"github.com/lburgazzoli/gira/pkg/jira"
+	timeutils "github.com/lburgazzoli/gira/pkg/utils/time"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// nextVersion selects the version following the released one
+	nextVersion = "next"
+
+	formatMarkdown = "markdown"
+	formatText     = "text"
+	formatJSON     = "json"
+)
+
+// notesFields are the issue fields needed to build release notes
+var notesFields = []string{"summary", "issuetype", "status", "components", "labels"}
+
+const markdownTemplate = `## {{ .Version.Name }}{{ with .Version.ReleaseDate }} ({{ . }}){{ end }}
+{{ with .Version.Description }}
+{{ . }}
+{{ end }}
+{{- range .Groups }}
+### {{ .Name }}
+
+{{ range .Entries }}- [{{ .Key }}]({{ .URL }}) {{ .Summary }}
+{{ end }}
+{{- else }}
+No issues are fixed in this version.
+{{ end }}`
+
+const textTemplate = `{{ .Project }} {{ .Version.Name }}{{ with .Version.ReleaseDate }} ({{ . }}){{ end }}
+{{ range .Groups }}
+{{ .Name }}
+{{ range .Entries }}  {{ .Key }}  {{ .Summary }}
+{{ end }}
+{{- else }}
+No issues are fixed in this version.
+{{ end }}`
+
+var (
+	versionDescription string
+	versionStart       string
+	versionDate        string
+	versionName        string
+
+	publishDate   string
+	publishMoveTo string
+
+	notesFormat   string
+	notesGroupBy  string
+	notesTemplate string
+	notesJQL      string
+)
+
+var Cmd = &cobra.Command{
+	Use:   "release",
+	Short: "Manage project versions and release notes",
+	Long:  `Create, update and release project versions, and generate release notes.`,
+}
+
+var createCmd = &cobra.Command{
+	Use:   "create PROJECT-KEY VERSION",
+	Short: "Create a version",
+	Long: `Create a version in a project. Dates are given as YYYY-MM-DD or "today".
+
+Examples:
+  gira release create PROJ 1.5.0
+  gira release create PROJ 1.5.0 --start today --date 2026-12-01 --description "Winter release"`,
+	Args: cobra.ExactArgs(2),
+	RunE: runCreate,
+}
+
+var updateCmd = &cobra.Command{
+	Use:   "update PROJECT-KEY VERSION",
+	Short: "Update a version",
+	Long: `Update the name, description or dates of a version; only the given flags
+are changed.
+
+Examples:
+  gira release update PROJ 1.5.0 --date 2026-12-15
+  gira release update PROJ 1.5.0 --name 2.0.0`,
+	Args: cobra.ExactArgs(2),
+	RunE: runUpdate,
+}
+
+var publishCmd = &cobra.Command{
+	Use:   "publish PROJECT-KEY VERSION",
+	Short: "Mark a version as released",
+	Long: `Mark a version as released, by default today. The unresolved issues of the
+version can be moved to another version, where "next" is the first unreleased
+version following it.
+
+Examples:
+  gira release publish PROJ 1.4.0
+  gira release publish PROJ 1.4.0 --move-unresolved-to next
+  gira release publish PROJ 1.4.0 --date 2026-10-16 --move-unresolved-to 1.4.1`,
+	Args: cobra.ExactArgs(2),
+	RunE: runPublish,
+}
+
+var notesCmd = &cobra.Command{
+	Use:   "notes PROJECT-KEY VERSION",
+	Short: "Generate release notes",
+	Long: `Generate release notes from the issues whose fix version is the given
+version, grouped by issue type, component or label.
+
+Release notes are rendered as markdown, text or json, or with a custom Go
+template (text/template) given with --template. Templates receive the
+jira.ReleaseNotes structure: .Project, .Version (.Name, .Description,
+.ReleaseDate, ...), .GroupBy, .Groups (.Name, .Entries) and .Entries, where
+entries have .Key, .Summary, .Type, .Status, .Components, .Labels and .URL.
+The join, lower and upper functions are available.
+
+Examples:
+  gira release notes PROJ 1.4.0
+  gira release notes PROJ 1.4.0 --group-by component > CHANGELOG.md
+  gira release notes PROJ 1.4.0 --jql "resolution = Done" --format text
+  gira release notes PROJ 1.4.0 --template .github/release.tmpl`,
+	Args: cobra.ExactArgs(2),
+	RunE: runNotes,
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{createCmd, updateCmd} {
+		cmd.Flags().StringVar(&versionDescription, "description", "", "Description of the version")
+		cmd.Flags().StringVar(&versionStart, "start", "", "Start date of the version")
+		cmd.Flags().StringVar(&versionDate, "date", "", "Release date of the version")
+	}
+	updateCmd.Flags().StringVar(&versionName, "name", "", "New name of the version")
+
+	publishCmd.Flags().StringVar(&publishDate, "date", "today", "Release date")
+	publishCmd.Flags().StringVar(&publishMoveTo, "move-unresolved-to", "", `Version receiving the unresolved issues, or "next"`)
+
+	notesCmd.Flags().StringVar(&notesFormat, "format", formatMarkdown, "Release notes format (markdown|text|json)")
+	notesCmd.Flags().StringVar(&notesGroupBy, "group-by", jira.GroupByType, "Group issues by (type|component|label)")
+	notesCmd.Flags().StringVar(&notesTemplate, "template", "", "Go template file rendering the release notes")
+	notesCmd.Flags().StringVar(&notesJQL, "jql", "", "Only include the issues matching a JQL query")
+
+	Cmd.AddCommand(createCmd)
+	Cmd.AddCommand(updateCmd)
+	Cmd.AddCommand(publishCmd)
+	Cmd.AddCommand(notesCmd)
+}
+
+func runCreate(_ *cobra.Command, args []string) error {
+	projectKey := strings.ToUpper(args[0])
+
+	version := jira.Version{Name: args[1], Description: versionDescription}
+
+	var err error
+	if version.StartDate, err = parseDate(versionStart, "--start"); err != nil {
+		return err
+	}
+	if version.ReleaseDate, err = parseDate(versionDate, "--date"); err != nil {
+		return err
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	created, err := client.CreateVersion(projectKey, version)
+	if err != nil {
+		return err
+	}
+
+	green := color.New(color.FgGreen).SprintFunc()
+	fmt.Printf("%s Created version %s %s (%s)\n", green("✓"), projectKey, created.Name, created.ID)
+
+	return nil
+}
+
+func runUpdate(cmd *cobra.Command, args []string) error {
+	projectKey := strings.ToUpper(args[0])
+
+	update := jira.VersionUpdate{}
+	if cmd.Flags().Changed("name") {
+		update.Name = &versionName
+	}
+	if cmd.Flags().Changed("description") {
+		update.Description = &versionDescription
+	}
+	if cmd.Flags().Changed("start") {
+		start, err := parseDate(versionStart, "--start")
+		if err != nil {
+			return err
+		}
+		update.StartDate = &start
+	}
+	if cmd.Flags().Changed("date") {
+		date, err := parseDate(versionDate, "--date")
+		if err != nil {
+			return err
+		}
+		update.ReleaseDate = &date
+	}
+
+	if update == (jira.VersionUpdate{}) {
+		return fmt.Errorf("nothing to update, use --name, --description, --start or --date")
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	version, err := client.FindVersion(projectKey, args[1])
+	if err != nil {
+		return err
+	}
+
+	updated, err := client.UpdateVersion(version.ID, update)
+	if err != nil {
+		return err
+	}
+
+	green := color.New(color.FgGreen).SprintFunc()
+	fmt.Printf("%s Updated version %s %s\n", green("✓"), projectKey, updated.Name)
+
+	return nil
+}
+
+func runPublish(_ *cobra.Command, args []string) error {
+	projectKey := strings.ToUpper(args[0])
+
+	date, err := parseDate(publishDate, "--date")
+	if err != nil {
+		return err
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	// The versions may have been released or added in the UI since they
+	// were cached
+	client.InvalidateProjects()
+
+	versions, err := client.ListVersions(projectKey)
+	if err != nil {
+		return err
+	}
+
+	index := jira.VersionIndex(versions, args[1])
+	if index < 0 {
+		return fmt.Errorf("version %s does not exist in project %s", args[1], projectKey)
+	}
+	version := versions[index]
+
+	if version.Released {
+		return fmt.Errorf("version %s %s is already released", projectKey, version.Name)
+	}
+
+	var target *jira.Version
+	switch {
+	case strings.EqualFold(publishMoveTo, nextVersion):
+		for i := index + 1; i < len(versions); i++ {
+			if !versions[i].Released && !versions[i].Archived {
+				target = &versions[i]
+				break
+			}
+		}
+		if target == nil {
+			return fmt.Errorf("no unreleased version follows %s, create one first", version.Name)
+		}
+	case publishMoveTo != "":
+		i := jira.VersionIndex(versions, publishMoveTo)
+		if i < 0 {
+			return fmt.Errorf("version %s does not exist in project %s", publishMoveTo, projectKey)
+		}
+		if i == index {
+			return fmt.Errorf("cannot move the unresolved issues of %s to itself", version.Name)
+		}
+		target = &versions[i]
+	}
+
+	unresolved, err := client.SearchIssues(
+		fmt.Sprintf("project = %q AND fixVersion = %s AND resolution IS EMPTY", projectKey, version.ID),
+		0, 1, []string{"key"})
+	if err != nil {
+		return fmt.Errorf("failed to search unresolved issues: %w", err)
+	}
+
+	targetID := ""
+	if target != nil {
+		targetID = target.ID
+	}
+
+	if _, err := client.ReleaseVersion(version.ID, date, targetID); err != nil {
+		return err
+	}
+
+	green := color.New(color.FgGreen).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	fmt.Printf("%s Released %s %s on %s\n", green("✓"), projectKey, version.Name, date)
+
+	switch {
+	case unresolved.Total == 0:
+	case target != nil:
+		fmt.Printf("%s Moved %d unresolved issues to %s\n", green("✓"), unresolved.Total, target.Name)
+	default:
+		fmt.Printf("%s %d unresolved issues remain in %s, use --move-unresolved-to to move them\n",
+			yellow("!"), unresolved.Total, version.Name)
+	}
+
+	return nil
+}
+
+func runNotes(_ *cobra.Command, args []string) error {
+	projectKey := strings.ToUpper(args[0])
+
+	tmpl, err := notesTemplateFor(notesFormat, notesTemplate)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	client, err := jiraclient.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create JIRA client: %w", err)
+	}
+
+	// The version may have been released or renamed in the UI since it was
+	// cached
+	client.InvalidateProjects()
+
+	version, err := client.FindVersion(projectKey, args[1])
+	if err != nil {
+		return err
+	}
+
+	jql := fmt.Sprintf("project = %q AND fixVersion = %s", projectKey, version.ID)
+	if notesJQL != "" {
+		jql += " AND (" + notesJQL + ")"
+	}
+
+	issues, err := client.SearchAllIssues(jql+" ORDER BY key", notesFields)
+	if err != nil {
+		return fmt.Errorf("failed to search issues: %w", err)
+	}
+
+	browseURL := strings.TrimSuffix(cfg.JIRA.BaseURL, "/") + "/browse"
+	notes, err := jira.BuildReleaseNotes(projectKey, *version, issues, notesGroupBy, browseURL)
+	if err != nil {
+		return err
+	}
+
+	if tmpl == nil {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(notes)
+	}
+
+	return tmpl.Execute(os.Stdout, notes)
+}
+
+// notesTemplateFor returns the template rendering release notes, nil for the
+// json format
+func notesTemplateFor(format string, file string) (*template.Template, error) {
+	funcs := template.FuncMap{
+		"join":  strings.Join,
+		"lower": strings.ToLower,
+		"upper": strings.ToUpper,
+	}
+
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template: %w", err)
+		}
+
+		tmpl, err := template.New(file).Funcs(funcs).Parse(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("invalid template %s: %w", file, err)
+		}
+
+		return tmpl, nil
+	}
+
+	switch format {
+	case formatMarkdown:
+		return template.Must(template.New(format).Funcs(funcs).Parse(markdownTemplate)), nil
+	case formatText:
+		return template.Must(template.New(format).Funcs(funcs).Parse(textTemplate)), nil
+	case formatJSON:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+// parseDate accepts YYYY-MM-DD or "today", and returns an empty date for an
+// empty value
+func parseDate(value string, flag string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "":
+		return "", nil
+	case "today":
+		return time.Now().Format(timeutils.DateLayout), nil
+	}
+
+	t, err := time.Parse(timeutils.DateLayout, strings.TrimSpace(value))
+	if err != nil {
+		return "", fmt.Errorf("invalid %s %q, expected YYYY-MM-DD or today", flag, value)
+	}
+
+	return t.Format(timeutils.DateLayout), nil
+}
+
+func newClient() (*jira.Client, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	client, err := jiraclient.New(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create JIRA client: %w", err)
+	}
+
+	return client, nil
+}
@@ -0,0 +1,364 @@
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	jiraclient "github.com/lburgazzoli/gira/internal/client"
+	"github.com/lburgazzoli/gira/pkg/config"
+	"github.com/lburgazzoli/gira/pkg/jira"
+	stringutils "github.com/lburgazzoli/gira/pkg/utils/strings"
+	tableutils "github.com/lburgazzoli/gira/pkg/utils/table"
+	timeutils "github.com/lburgazzoli/gira/pkg/utils/time"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// timeFields are the issue fields needed to aggregate logged time
+var timeFields = []string{"summary", "project"}
+
+var (
+	timeUser string
+	timeFrom string
+	timeTo   string
+	timeJQL  string
+)
+
+var Cmd = &cobra.Command{
+	Use:   "report",
+	Short: "Build reports from JIRA data",
+	Long:  `Build reports, such as timesheets, from JIRA data.`,
+}
+
+var timeCmd = &cobra.Command{
+	Use:   "time",
+	Short: "Report the time logged by a user",
+	Long: `Report the time logged by a user over a period, aggregated by day, issue
+and project.
+
+--from and --to are inclusive and accept dates (YYYY-MM-DD), "today",
+"yesterday" or relative durations such as 2w; the period defaults to the
+current week. --user accepts "me", an account ID, a username, an email
+address or a display name; an empty value reports the time logged by
+everyone.
+
+The csv output has one row per worklog, with the time spent in hours, for
+timesheets.
+
+Examples:
+  gira report time
+  gira report time --user me --from 2026-10-01 --to 2026-10-15
+  gira report time --from 2w --jql "project = PROJ"
+  gira report time --from 2026-10-01 --output csv > timesheet.csv`,
+	Args: cobra.NoArgs,
+	RunE: runTime,
+}
+
+func init() {
+	timeCmd.Flags().StringVar(&timeUser, "user", jira.CurrentUserAlias, "User whose logged time is reported")
+	timeCmd.Flags().StringVar(&timeFrom, "from", "", "First day of the period (default: monday of the current week)")
+	timeCmd.Flags().StringVar(&timeTo, "to", "today", "Last day of the period")
+	timeCmd.Flags().StringVar(&timeJQL, "jql", "", "Only report the time logged on issues matching a JQL query")
+
+	// Override the global output flag to include csv
+	timeCmd.Flags().StringP("output", "o", "", "output format (table|json|yaml|csv)")
+
+	Cmd.AddCommand(timeCmd)
+}
+
+func runTime(cmd *cobra.Command, _ []string) error {
+	now := time.Now()
+
+	from, to, err := parsePeriod(timeFrom, timeTo, now)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	client, err := jiraclient.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create JIRA client: %w", err)
+	}
+
+	// A nil user stands for everyone
+	var user *jira.User
+	if timeUser != "" {
+		user, err = client.ResolveUser(timeUser)
+		if err != nil {
+			return err
+		}
+	}
+
+	issues, err := client.SearchAllIssues(worklogQuery(user, from, to, timeJQL), timeFields)
+	if err != nil {
+		return fmt.Errorf("failed to search issues: %w", err)
+	}
+
+	entries := make([]jira.TimeEntry, 0)
+	for i := range issues {
+		worklogs, err := client.ListWorklogs(issues[i].Key)
+		if err != nil {
+			return err
+		}
+
+		entries = append(entries, jira.NewTimeEntries(&issues[i], worklogs, from, to, authorFilter(user))...)
+	}
+
+	return outputResult(cmd, jira.BuildTimeReport(entries, from, to))
+}
+
+// parsePeriod resolves --from and --to into a [from, to) interval of whole days
+func parsePeriod(fromSpec string, toSpec string, now time.Time) (time.Time, time.Time, error) {
+	from := timeutils.StartOfDay(now)
+	from = from.AddDate(0, 0, -((int(from.Weekday()) + 6) % 7))
+
+	if fromSpec != "" {
+		t, err := timeutils.ParseSince(fromSpec, now)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --from: %w", err)
+		}
+		from = timeutils.StartOfDay(t.In(now.Location()))
+	}
+
+	t, err := timeutils.ParseSince(toSpec, now)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid --to: %w", err)
+	}
+	to := timeutils.StartOfDay(t.In(now.Location())).AddDate(0, 0, 1)
+
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("--from %s is after --to %s", from.Format(timeutils.DateLayout), to.AddDate(0, 0, -1).Format(timeutils.DateLayout))
+	}
+
+	return from, to, nil
+}
+
+// worklogQuery selects the issues with time logged by the user over the
+// period. The upper bound is exclusive since worklogDate compares dates.
+func worklogQuery(user *jira.User, from time.Time, to time.Time, jql string) string {
+	clauses := make([]string, 0, 4)
+
+	if user != nil {
+		clauses = append(clauses, fmt.Sprintf("worklogAuthor = %q", user.ID()))
+	}
+
+	clauses = append(clauses,
+		fmt.Sprintf("worklogDate >= %q", from.Format(timeutils.DateLayout)),
+		fmt.Sprintf("worklogDate < %q", to.Format(timeutils.DateLayout)),
+	)
+
+	if jql != "" {
+		clauses = append(clauses, "("+jql+")")
+	}
+
+	return strings.Join(clauses, " AND ")
+}
+
+// authorFilter matches the worklogs of the user, since the issues returned
+// by the search also carry the worklogs of other users
+func authorFilter(user *jira.User) func(*jira.User) bool {
+	if user == nil {
+		return nil
+	}
+
+	return user.Is
+}
+
+// getOutputFormat checks the local output flag first, then falls back to the global flag
+func getOutputFormat(cmd *cobra.Command) string {
+	outputFormat, _ := cmd.Flags().GetString("output")
+	if outputFormat == "" {
+		outputFormat, _ = cmd.Root().PersistentFlags().GetString("output")
+	}
+	return outputFormat
+}
+
+func outputResult(cmd *cobra.Command, report *jira.TimeReport) error {
+	outputFormat := getOutputFormat(cmd)
+
+	switch outputFormat {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(newReportView(report))
+	case "yaml":
+		encoder := yaml.NewEncoder(os.Stdout)
+		return encoder.Encode(newReportView(report))
+	case "csv":
+		return outputCSV(report)
+	case "table":
+		return outputTable(report)
+	case "":
+		return outputPlain(report)
+	default:
+		return fmt.Errorf("unsupported output format: %s", outputFormat)
+	}
+}
+
+func outputTable(report *jira.TimeReport) error {
+	if len(report.Entries) == 0 {
+		fmt.Println("No time logged.")
+		return nil
+	}
+
+	renderer := tableutils.NewRenderer(
+		tableutils.WithHeaders("DATE", "ISSUE", "PROJECT", "AUTHOR", "TIME SPENT", "COMMENT"),
+	)
+
+	rows := make([][]any, 0, len(report.Entries))
+	for _, e := range report.Entries {
+		rows = append(rows, []any{
+			e.Date.Format(timeutils.DateLayout),
+			e.Key,
+			e.Project,
+			e.Author,
+			timeutils.FormatWorkDuration(e.TimeSpent),
+			stringutils.Truncate(e.Comment, 40),
+		})
+	}
+
+	if err := renderer.AppendAll(rows); err != nil {
+		return err
+	}
+
+	return renderer.Render()
+}
+
+func outputPlain(report *jira.TimeReport) error {
+	bold := color.New(color.Bold).SprintFunc()
+	cyan := color.New(color.FgCyan).SprintFunc()
+
+	fmt.Printf("Time logged from %s to %s: %s\n",
+		report.From.Format(timeutils.DateLayout),
+		report.To.AddDate(0, 0, -1).Format(timeutils.DateLayout),
+		bold(timeutils.FormatWorkDuration(report.Total)))
+
+	if len(report.Entries) == 0 {
+		return nil
+	}
+
+	fmt.Println()
+	fmt.Println(cyan("By day"))
+	for _, t := range report.ByDay {
+		day, _ := time.ParseInLocation(timeutils.DateLayout, t.Name, report.From.Location())
+		fmt.Printf("  %s %s  %8s\n", t.Name, day.Format("Mon"), timeutils.FormatWorkDuration(t.TimeSpent))
+	}
+
+	fmt.Println()
+	fmt.Println(cyan("By project"))
+	for _, t := range report.ByProject {
+		fmt.Printf("  %-14s  %8s\n", t.Name, timeutils.FormatWorkDuration(t.TimeSpent))
+	}
+
+	fmt.Println()
+	fmt.Println(cyan("By issue"))
+	for _, t := range report.ByIssue {
+		fmt.Printf("  %-14s  %8s  %s\n", t.Name, timeutils.FormatWorkDuration(t.TimeSpent), stringutils.Truncate(t.Summary, 50))
+	}
+
+	return nil
+}
+
+// outputCSV writes one row per worklog, with the time spent in hours
+func outputCSV(report *jira.TimeReport) error {
+	w := csv.NewWriter(os.Stdout)
+
+	if err := w.Write([]string{"date", "started", "issue", "project", "summary", "author", "hours", "comment"}); err != nil {
+		return err
+	}
+
+	for _, e := range report.Entries {
+		row := []string{
+			e.Date.Format(timeutils.DateLayout),
+			e.Started.Format(time.RFC3339),
+			e.Key,
+			e.Project,
+			e.Summary,
+			e.Author,
+			strconv.FormatFloat(e.TimeSpent.Hours(), 'f', 2, 64),
+			e.Comment,
+		}
+
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+// reportView renders a time report with durations in hours
+type reportView struct {
+	From      string      `json:"from" yaml:"from"`
+	To        string      `json:"to" yaml:"to"`
+	Hours     float64     `json:"hours" yaml:"hours"`
+	ByDay     []totalView `json:"byDay" yaml:"byDay"`
+	ByProject []totalView `json:"byProject" yaml:"byProject"`
+	ByIssue   []totalView `json:"byIssue" yaml:"byIssue"`
+	Entries   []entryView `json:"entries" yaml:"entries"`
+}
+
+type totalView struct {
+	Name    string  `json:"name" yaml:"name"`
+	Summary string  `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Hours   float64 `json:"hours" yaml:"hours"`
+}
+
+type entryView struct {
+	Date      string    `json:"date" yaml:"date"`
+	Started   time.Time `json:"started" yaml:"started"`
+	Key       string    `json:"key" yaml:"key"`
+	Project   string    `json:"project" yaml:"project"`
+	Summary   string    `json:"summary" yaml:"summary"`
+	Author    string    `json:"author" yaml:"author"`
+	Hours     float64   `json:"hours" yaml:"hours"`
+	Comment   string    `json:"comment,omitempty" yaml:"comment,omitempty"`
+	WorklogID string    `json:"worklogId" yaml:"worklogId"`
+}
+
+func newReportView(report *jira.TimeReport) reportView {
+	view := reportView{
+		From:      report.From.Format(timeutils.DateLayout),
+		To:        report.To.AddDate(0, 0, -1).Format(timeutils.DateLayout),
+		Hours:     report.Total.Hours(),
+		ByDay:     newTotalViews(report.ByDay),
+		ByProject: newTotalViews(report.ByProject),
+		ByIssue:   newTotalViews(report.ByIssue),
+		Entries:   make([]entryView, 0, len(report.Entries)),
+	}
+
+	for _, e := range report.Entries {
+		view.Entries = append(view.Entries, entryView{
+			Date:      e.Date.Format(timeutils.DateLayout),
+			Started:   e.Started,
+			Key:       e.Key,
+			Project:   e.Project,
+			Summary:   e.Summary,
+			Author:    e.Author,
+			Hours:     e.TimeSpent.Hours(),
+			Comment:   e.Comment,
+			WorklogID: e.WorklogID,
+		})
+	}
+
+	return view
+}
+
+func newTotalViews(totals []jira.TimeTotal) []totalView {
+	views := make([]totalView, 0, len(totals))
+	for _, t := range totals {
+		views = append(views, totalView{Name: t.Name, Summary: t.Summary, Hours: t.TimeSpent.Hours()})
+	}
+	return views
+}
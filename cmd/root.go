@@ -2,20 +2,43 @@ package cmd
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
 
+	"github.com/lburgazzoli/gira/cmd/apply"
+	"github.com/lburgazzoli/gira/cmd/attach"
+	"github.com/lburgazzoli/gira/cmd/bulk"
+	"github.com/lburgazzoli/gira/cmd/cache"
 	"github.com/lburgazzoli/gira/cmd/config"
+	"github.com/lburgazzoli/gira/cmd/download"
 	"github.com/lburgazzoli/gira/cmd/get"
+	"github.com/lburgazzoli/gira/cmd/history"
+	"github.com/lburgazzoli/gira/cmd/importer"
+	"github.com/lburgazzoli/gira/cmd/link"
+	"github.com/lburgazzoli/gira/cmd/metrics"
+	"github.com/lburgazzoli/gira/cmd/plan"
+	"github.com/lburgazzoli/gira/cmd/release"
+	"github.com/lburgazzoli/gira/cmd/report"
 	"github.com/lburgazzoli/gira/cmd/search"
+	"github.com/lburgazzoli/gira/cmd/sprint"
+	syncCmd "github.com/lburgazzoli/gira/cmd/sync"
+	"github.com/lburgazzoli/gira/cmd/treediff"
+	"github.com/lburgazzoli/gira/cmd/unlink"
 	versionCmd "github.com/lburgazzoli/gira/cmd/version"
+	"github.com/lburgazzoli/gira/cmd/watch"
+	"github.com/lburgazzoli/gira/cmd/weblink"
+	"github.com/lburgazzoli/gira/cmd/worklog"
+	jiraclient "github.com/lburgazzoli/gira/internal/client"
 	"github.com/lburgazzoli/gira/internal/version"
 	pkgConfig "github.com/lburgazzoli/gira/pkg/config"
 	"github.com/spf13/cobra"
 )
 
 var (
-	cfgFile string
-	cfg     *pkgConfig.Config
+	cfgFile   string
+	cfg       *pkgConfig.Config
+	verbosity int
+	logFormat string
 )
 
 var rootCmd = &cobra.Command{
@@ -36,17 +59,41 @@ func Execute() {
 }
 
 func init() {
-	cobra.OnInitialize(initConfig)
+	cobra.OnInitialize(initConfig, initLogging)
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.gira/config.yaml)")
 	rootCmd.PersistentFlags().StringP("output", "o", "", "output format (table|json|yaml)")
-	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "verbose output")
+	rootCmd.PersistentFlags().CountVarP(&verbosity, "verbose", "v", "verbose output (-v logs HTTP requests, -vv also logs redacted bodies)")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "text", "log format (text|json)")
+	rootCmd.PersistentFlags().BoolVar(&jiraclient.Cache.Disabled, "no-cache", false, "bypass the local response cache")
+	rootCmd.PersistentFlags().BoolVar(&jiraclient.Cache.Refresh, "refresh", false, "ignore cached responses and refresh the local cache")
+	rootCmd.PersistentFlags().BoolVar(&jiraclient.Offline, "offline", false, "read issues from the local mirror populated by gira sync")
 
 	// Add subcommands
+	rootCmd.AddCommand(apply.Cmd)
+	rootCmd.AddCommand(attach.Cmd)
+	rootCmd.AddCommand(bulk.Cmd)
+	rootCmd.AddCommand(cache.Cmd)
 	rootCmd.AddCommand(config.Cmd)
+	rootCmd.AddCommand(download.Cmd)
 	rootCmd.AddCommand(get.Cmd)
+	rootCmd.AddCommand(history.Cmd)
+	rootCmd.AddCommand(importer.Cmd)
+	rootCmd.AddCommand(link.Cmd)
+	rootCmd.AddCommand(metrics.Cmd)
+	rootCmd.AddCommand(plan.Cmd)
+	rootCmd.AddCommand(release.Cmd)
+	rootCmd.AddCommand(report.Cmd)
 	rootCmd.AddCommand(search.Cmd)
+	rootCmd.AddCommand(sprint.Cmd)
+	rootCmd.AddCommand(syncCmd.Cmd)
+	rootCmd.AddCommand(treediff.Cmd)
+	rootCmd.AddCommand(unlink.Cmd)
+	rootCmd.AddCommand(watch.UnwatchCmd)
 	rootCmd.AddCommand(versionCmd.Cmd)
+	rootCmd.AddCommand(watch.Cmd)
+	rootCmd.AddCommand(weblink.Cmd)
+	rootCmd.AddCommand(worklog.Cmd)
 }
 
 func initConfig() {
@@ -57,3 +104,36 @@ func initConfig() {
 		os.Exit(1)
 	}
 }
+
+// initLogging installs the default slog logger writing to stderr. Retries are
+// always reported, -v adds every HTTP request and -vv the request and
+// response bodies.
+func initLogging() {
+	level := slog.LevelWarn
+
+	if verbosity == 0 && cfg != nil && cfg.CLI.Verbose {
+		verbosity = 1
+	}
+
+	switch {
+	case verbosity >= 2:
+		level = slog.LevelDebug
+	case verbosity == 1:
+		level = slog.LevelInfo
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+
+	var handler slog.Handler
+	switch logFormat {
+	case "json":
+		handler = slog.NewJSONHandler(os.Stderr, opts)
+	case "text", "":
+		handler = slog.NewTextHandler(os.Stderr, opts)
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unsupported log format: %s\n", logFormat)
+		os.Exit(1)
+	}
+
+	slog.SetDefault(slog.New(handler))
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 
 	"github.com/fatih/color"
+	jiraclient "github.com/lburgazzoli/gira/internal/client"
 	"github.com/lburgazzoli/gira/pkg/config"
 	"github.com/lburgazzoli/gira/pkg/jira"
 	stringutils "github.com/lburgazzoli/gira/pkg/utils/strings"
@@ -85,9 +86,7 @@ func runSearch(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
 
-	client, err := jira.NewClient(cfg.JIRA.BaseURL, jira.AuthConfig{
-		Token: cfg.JIRA.Token,
-	})
+	client, err := jiraclient.New(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to create JIRA client: %w", err)
 	}
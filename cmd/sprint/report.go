@@ -0,0 +1,504 @@
+package sprint
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/lburgazzoli/gira/pkg/jira"
+	stringutils "github.com/lburgazzoli/gira/pkg/utils/strings"
+	tableutils "github.com/lburgazzoli/gira/pkg/utils/table"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// reportBatchSize is the number of issues fetched per search request
+	reportBatchSize = 100
+	// chartHeight is the number of rows of the burndown chart
+	chartHeight = 10
+	// dayLayout is used to display the days of the burndown
+	dayLayout = "2006-01-02"
+)
+
+// pointsFieldNames are the usual names of the story points field
+var pointsFieldNames = []string{"Story Points", "Story point estimate"}
+
+var (
+	reportPointsField string
+	reportScopeJQL    string
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report SPRINT-ID",
+	Short: "Show the burndown and scope changes of a sprint",
+	Long: `Reconstruct the daily burndown of a sprint, in issues and story points,
+from the changelog of its issues, and list the issues added or removed after
+the sprint started, the completed issues and the carried-over ones.
+
+Issues removed from the sprint are found among the issues of the sprint
+projects updated since the sprint started; use --scope-jql to look elsewhere.
+The story points field is detected by name, use --points-field to set its
+name or ID.
+
+The csv output has one row per day, for dashboards.
+
+Examples:
+  gira sprint report 123
+  gira sprint report 123 --points-field customfield_10016
+  gira sprint report 123 --output csv > burndown.csv`,
+	Args: cobra.ExactArgs(1),
+	RunE: runReport,
+}
+
+func init() {
+	reportCmd.Flags().StringVar(&reportPointsField, "points-field", "", "Name or ID of the story points field (detected by default)")
+	reportCmd.Flags().StringVar(&reportScopeJQL, "scope-jql", "", "JQL query selecting the issues that may have left the sprint")
+
+	// Override the global output flag to include csv
+	reportCmd.Flags().StringP("output", "o", "", "output format (table|json|yaml|csv)")
+
+	Cmd.AddCommand(reportCmd)
+}
+
+func runReport(cmd *cobra.Command, args []string) error {
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid sprint ID %q", args[0])
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	sprint, err := client.GetSprint(id)
+	if err != nil {
+		return fmt.Errorf("failed to get sprint %d: %w", id, err)
+	}
+	if sprint.StartDate == nil || sprint.StartDate.IsZero() {
+		return fmt.Errorf("sprint %s has not started", sprint.Name)
+	}
+
+	statuses, err := client.GetStatuses()
+	if err != nil {
+		return fmt.Errorf("failed to get statuses: %w", err)
+	}
+
+	pointsField, err := findPointsField(client)
+	if err != nil {
+		return err
+	}
+
+	histories, err := collectSprintIssues(client, sprint, pointsField)
+	if err != nil {
+		return err
+	}
+
+	opts := jira.SprintReportOptions{
+		Statuses: statuses,
+		Now:      time.Now(),
+	}
+	if pointsField != nil {
+		opts.PointsField = pointsField.Name
+	}
+
+	report, err := jira.BuildSprintReport(sprint, histories, opts)
+	if err != nil {
+		return err
+	}
+
+	return outputReport(cmd, report)
+}
+
+// findPointsField returns the story points field, or nil when the instance
+// has none
+func findPointsField(client *jira.Client) (*jira.Field, error) {
+	fields, err := client.GetFields()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fields: %w", err)
+	}
+
+	names := pointsFieldNames
+	if reportPointsField != "" {
+		names = []string{reportPointsField}
+	}
+
+	for _, name := range names {
+		for i := range fields {
+			if strings.EqualFold(fields[i].ID, name) || strings.EqualFold(fields[i].Name, name) {
+				return &fields[i], nil
+			}
+		}
+	}
+
+	if reportPointsField != "" {
+		return nil, fmt.Errorf("field %q does not exist", reportPointsField)
+	}
+
+	return nil, nil
+}
+
+// collectSprintIssues fetches the issues currently in the sprint, and the
+// issues that may have left it, with their changelogs
+func collectSprintIssues(client *jira.Client, sprint *jira.Sprint, pointsField *jira.Field) ([]jira.SprintIssueHistory, error) {
+	current, err := client.GetSprintIssues(sprint.ID, "", []string{"project"})
+	if err != nil {
+		return nil, err
+	}
+
+	inSprint := make(map[string]bool, len(current))
+	keys := make([]string, 0, len(current))
+	projects := make([]string, 0)
+	for _, issue := range current {
+		inSprint[issue.Key] = true
+		keys = append(keys, issue.Key)
+
+		project := issue.Fields.Project.Key
+		if project == "" {
+			project, _, _ = strings.Cut(issue.Key, "-")
+		}
+		if !slices.Contains(projects, project) {
+			projects = append(projects, project)
+		}
+	}
+
+	if sprint.OriginBoardID != 0 {
+		board, err := client.GetBoard(sprint.OriginBoardID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get board %d: %w", sprint.OriginBoardID, err)
+		}
+		if board.Location != nil && board.Location.ProjectKey != "" && !slices.Contains(projects, board.Location.ProjectKey) {
+			projects = append(projects, board.Location.ProjectKey)
+		}
+	}
+
+	scopeJQL := reportScopeJQL
+	if scopeJQL == "" && len(projects) > 0 {
+		// A day of margin covers the time zone JQL dates are interpreted in
+		since := sprint.StartDate.AddDate(0, 0, -1).Format(dayLayout)
+		scopeJQL = fmt.Sprintf(`project in (%s) AND updated >= "%s"`, strings.Join(projects, ", "), since)
+	}
+
+	if scopeJQL != "" {
+		issues, err := client.SearchAllIssues(scopeJQL, []string{"key"})
+		if err != nil {
+			return nil, fmt.Errorf("failed to search issues: %w", err)
+		}
+
+		for _, issue := range issues {
+			if !inSprint[issue.Key] && !slices.Contains(keys, issue.Key) {
+				keys = append(keys, issue.Key)
+			}
+		}
+
+	}
+
+	fields := []string{"summary", "status", "issuetype", "created"}
+	if pointsField != nil {
+		fields = append(fields, pointsField.ID)
+	}
+
+	histories := make([]jira.SprintIssueHistory, 0, len(keys))
+	for start := 0; start < len(keys); start += reportBatchSize {
+		batch := keys[start:min(start+reportBatchSize, len(keys))]
+
+		result, err := client.SearchRawIssues(fmt.Sprintf("key in (%s)", strings.Join(batch, ", ")), 0, len(batch), fields)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get issues: %w", err)
+		}
+
+		for _, raw := range result.Issues {
+			h, err := decodeSprintIssue(raw, pointsField)
+			if err != nil {
+				return nil, err
+			}
+			h.InSprint = inSprint[h.Issue.Key]
+
+			h.Histories, err = client.GetChangelog(h.Issue.Key)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get history of %s: %w", h.Issue.Key, err)
+			}
+
+			// Issues that never were in the sprint are left out early
+			if !h.InSprint && !mentionsSprint(h.Histories, sprint.ID) {
+				continue
+			}
+
+			histories = append(histories, h)
+		}
+	}
+
+	return histories, nil
+}
+
+func decodeSprintIssue(raw json.RawMessage, pointsField *jira.Field) (jira.SprintIssueHistory, error) {
+	var h jira.SprintIssueHistory
+
+	var issue jira.Issue
+	if err := json.Unmarshal(raw, &issue); err != nil {
+		return h, fmt.Errorf("failed to decode issue: %w", err)
+	}
+	h.Issue = &issue
+
+	if pointsField != nil {
+		var custom struct {
+			Fields map[string]any `json:"fields"`
+		}
+		if err := json.Unmarshal(raw, &custom); err != nil {
+			return h, fmt.Errorf("failed to decode issue %s: %w", issue.Key, err)
+		}
+		if points, ok := custom.Fields[pointsField.ID].(float64); ok {
+			h.Points = points
+		}
+	}
+
+	return h, nil
+}
+
+func mentionsSprint(histories []jira.ChangeHistory, sprintID int) bool {
+	id := strconv.Itoa(sprintID)
+	for _, history := range histories {
+		for _, item := range history.Items {
+			if !strings.EqualFold(item.Field, jira.SprintChangelogField) {
+				continue
+			}
+			for _, value := range []string{item.From, item.To} {
+				for _, v := range strings.Split(value, ",") {
+					if strings.TrimSpace(v) == id {
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
+}
+
+func outputReport(cmd *cobra.Command, report *jira.SprintReport) error {
+	outputFormat, _ := cmd.Flags().GetString("output")
+	if outputFormat == "" {
+		outputFormat, _ = cmd.Root().PersistentFlags().GetString("output")
+	}
+
+	switch outputFormat {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	case "yaml":
+		encoder := yaml.NewEncoder(os.Stdout)
+		return encoder.Encode(report)
+	case "csv":
+		return outputReportCSV(report)
+	case "table":
+		return outputReportTable(report)
+	case "":
+		return outputReportPlain(report)
+	default:
+		return fmt.Errorf("unsupported output format: %s", outputFormat)
+	}
+}
+
+func outputReportPlain(report *jira.SprintReport) error {
+	bold := color.New(color.Bold).SprintFunc()
+	cyan := color.New(color.FgCyan).SprintFunc()
+	green := color.New(color.FgGreen).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	fmt.Printf("%s (%d, %s)\n", bold(report.Sprint.Name), report.Sprint.ID, report.Sprint.State)
+	fmt.Printf("%s → %s\n", report.Start.Local().Format("2006-01-02 15:04"), report.End.Local().Format("2006-01-02 15:04"))
+	if report.Sprint.Goal != "" {
+		fmt.Printf("Goal: %s\n", report.Sprint.Goal)
+	}
+
+	usePoints := report.Points && totalPoints(report) > 0
+	unit := "issues"
+	if usePoints {
+		unit = "points"
+	}
+
+	fmt.Println()
+	fmt.Println(cyan(fmt.Sprintf("Burndown (%s remaining, · ideal)", unit)))
+	renderBurndown(report, usePoints)
+
+	sections := []struct {
+		title  string
+		issues []jira.SprintIssue
+		format func(a ...interface{}) string
+	}{
+		{"Committed", report.Committed, fmt.Sprint},
+		{"Added after start", report.Added, yellow},
+		{"Removed after start", report.Removed, red},
+		{"Completed", report.Completed, green},
+		{"Carried over", report.CarriedOver, red},
+	}
+
+	for _, section := range sections {
+		fmt.Println()
+		fmt.Println(cyan(fmt.Sprintf("%s: %s", section.title, summarize(section.issues, report.Points))))
+		for _, issue := range section.issues {
+			fmt.Printf("  %s %s\n", section.format(fmt.Sprintf("%-10s", issue.Key)), describeIssue(issue, report.Points))
+		}
+	}
+
+	return nil
+}
+
+// renderBurndown draws the remaining work as bars and the ideal burndown as dots
+func renderBurndown(report *jira.SprintReport, usePoints bool) {
+	if len(report.Days) == 0 {
+		return
+	}
+
+	remaining := make([]float64, len(report.Days))
+	ideal := make([]float64, len(report.Days))
+	top := 0.0
+	for i, day := range report.Days {
+		remaining[i], ideal[i] = float64(day.Remaining), day.Ideal
+		if usePoints {
+			remaining[i], ideal[i] = day.RemainingPoints, day.IdealPoints
+		}
+		top = max(top, remaining[i], ideal[i])
+	}
+	if top == 0 {
+		fmt.Println("  Nothing to burn down.")
+		return
+	}
+
+	step := top / chartHeight
+	labelWidth := max(len(formatNumber(top)), len(formatNumber(step*float64(chartHeight/2))))
+
+	for row := chartHeight; row >= 1; row-- {
+		level := step * float64(row)
+
+		var line strings.Builder
+		for i := range report.Days {
+			switch {
+			case remaining[i] >= level-step/2:
+				line.WriteString("█ ")
+			case math.Abs(ideal[i]-level) <= step/2:
+				line.WriteString("· ")
+			default:
+				line.WriteString("  ")
+			}
+		}
+
+		label := ""
+		if row == chartHeight || row == chartHeight/2 {
+			label = formatNumber(level)
+		}
+		fmt.Printf("  %*s ┤%s\n", labelWidth, label, strings.TrimRight(line.String(), " "))
+	}
+
+	fmt.Printf("  %*s └%s\n", labelWidth, "0", strings.Repeat("──", len(report.Days)))
+
+	first := report.Days[0].Date.Local().Format("01-02")
+	last := report.Days[len(report.Days)-1].Date.Local().Format("01-02")
+	padding := max(2*len(report.Days)-len(first)-len(last), 1)
+	fmt.Printf("  %*s  %s%s%s\n", labelWidth, "", first, strings.Repeat(" ", padding), last)
+}
+
+func outputReportTable(report *jira.SprintReport) error {
+	headers := []string{"DATE", "SCOPE", "REMAINING", "IDEAL"}
+	if report.Points {
+		headers = append(headers, "SCOPE POINTS", "REMAINING POINTS", "IDEAL POINTS")
+	}
+
+	renderer := tableutils.NewRenderer(
+		tableutils.WithHeaders(headers...),
+	)
+
+	rows := make([][]any, 0, len(report.Days))
+	for i, day := range report.Days {
+		date := day.Date.Local().Format(dayLayout)
+		if i == 0 {
+			date += " (start)"
+		}
+
+		row := []any{date, day.Scope, day.Remaining, formatNumber(day.Ideal)}
+		if report.Points {
+			row = append(row, formatNumber(day.ScopePoints), formatNumber(day.RemainingPoints), formatNumber(day.IdealPoints))
+		}
+		rows = append(rows, row)
+	}
+
+	if err := renderer.AppendAll(rows); err != nil {
+		return err
+	}
+
+	return renderer.Render()
+}
+
+// outputReportCSV writes one row per day of the burndown
+func outputReportCSV(report *jira.SprintReport) error {
+	w := csv.NewWriter(os.Stdout)
+
+	if err := w.Write([]string{"date", "scope", "remaining", "ideal", "scope_points", "remaining_points", "ideal_points"}); err != nil {
+		return err
+	}
+
+	for _, day := range report.Days {
+		row := []string{
+			day.Date.Format(time.RFC3339),
+			strconv.Itoa(day.Scope),
+			strconv.Itoa(day.Remaining),
+			strconv.FormatFloat(day.Ideal, 'f', 2, 64),
+			strconv.FormatFloat(day.ScopePoints, 'f', 2, 64),
+			strconv.FormatFloat(day.RemainingPoints, 'f', 2, 64),
+			strconv.FormatFloat(day.IdealPoints, 'f', 2, 64),
+		}
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+func totalPoints(report *jira.SprintReport) float64 {
+	total := 0.0
+	for _, day := range report.Days {
+		total = max(total, day.ScopePoints)
+	}
+	return total
+}
+
+func summarize(issues []jira.SprintIssue, points bool) string {
+	if !points {
+		return fmt.Sprintf("%d issues", len(issues))
+	}
+
+	total := 0.0
+	for _, issue := range issues {
+		total += issue.Points
+	}
+	return fmt.Sprintf("%d issues, %s points", len(issues), formatNumber(total))
+}
+
+func describeIssue(issue jira.SprintIssue, points bool) string {
+	parts := []string{stringutils.Truncate(issue.Summary, 50), "[" + issue.Status + "]"}
+	if points {
+		parts = append(parts, formatNumber(issue.Points)+"pt")
+	}
+	if issue.Added != nil {
+		parts = append(parts, "added "+issue.Added.Local().Format(dayLayout))
+	}
+	if issue.Removed != nil {
+		parts = append(parts, "removed "+issue.Removed.Local().Format(dayLayout))
+	}
+	return strings.Join(parts, " ")
+}
+
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(math.Round(value*10)/10, 'f', -1, 64)
+}
@@ -0,0 +1,249 @@
+package sprint
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	jiraclient "github.com/lburgazzoli/gira/internal/client"
+	"github.com/lburgazzoli/gira/pkg/config"
+	"github.com/lburgazzoli/gira/pkg/jira"
+	stringutils "github.com/lburgazzoli/gira/pkg/utils/strings"
+	tableutils "github.com/lburgazzoli/gira/pkg/utils/table"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// sprintIssueFields are the issue fields displayed for sprint issues
+var sprintIssueFields = []string{"summary", "status", "assignee", "issuetype", "priority"}
+
+var (
+	sprintBoard string
+	sprintNext  bool
+	sprintJQL   string
+	sprintID    int
+)
+
+var Cmd = &cobra.Command{
+	Use:   "sprint",
+	Short: "Work with Agile sprints",
+	Long:  `List and plan the issues of Agile sprints.`,
+}
+
+var issuesCmd = &cobra.Command{
+	Use:   "issues [SPRINT-ID]",
+	Short: "List the issues of a sprint",
+	Long: `List the issues of a sprint, given by ID or as the active sprint of a board.
+
+Examples:
+  gira sprint issues 123
+  gira sprint issues --board 42
+  gira sprint issues --board 42 --next
+  gira sprint issues --board 42 --jql "assignee = currentUser()"`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runIssues,
+}
+
+var addCmd = &cobra.Command{
+	Use:   "add ISSUE-KEY...",
+	Short: "Move issues to a sprint",
+	Long: `Move issues to a sprint, given by ID or as the active (or, with --next,
+the next future) sprint of a board.
+
+Examples:
+  gira sprint add PROJ-1 PROJ-2 --sprint 123
+  gira sprint add PROJ-1 --board 42
+  gira sprint add PROJ-1 PROJ-2 --board "PROJ board" --next`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runAdd,
+}
+
+func init() {
+	issuesCmd.Flags().StringVar(&sprintBoard, "board", "", "Board ID or name, to use its active sprint")
+	issuesCmd.Flags().BoolVar(&sprintNext, "next", false, "Use the next future sprint of the board instead of the active one")
+	issuesCmd.Flags().StringVar(&sprintJQL, "jql", "", "Only list the sprint issues matching a JQL query")
+
+	addCmd.Flags().IntVar(&sprintID, "sprint", 0, "ID of the target sprint")
+	addCmd.Flags().StringVar(&sprintBoard, "board", "", "Board ID or name, to use its active sprint")
+	addCmd.Flags().BoolVar(&sprintNext, "next", false, "Use the next future sprint of the board instead of the active one")
+	addCmd.MarkFlagsMutuallyExclusive("sprint", "board")
+
+	Cmd.AddCommand(issuesCmd)
+	Cmd.AddCommand(addCmd)
+}
+
+func runIssues(cmd *cobra.Command, args []string) error {
+	if len(args) == 1 {
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid sprint ID %q", args[0])
+		}
+		sprintID = id
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	sprint, err := resolveSprint(client)
+	if err != nil {
+		return err
+	}
+
+	issues, err := client.GetSprintIssues(sprint.ID, sprintJQL, sprintIssueFields)
+	if err != nil {
+		return err
+	}
+
+	return outputIssues(cmd, sprint, issues)
+}
+
+func runAdd(_ *cobra.Command, args []string) error {
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	sprint, err := resolveSprint(client)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(args))
+	for _, key := range args {
+		keys = append(keys, strings.ToUpper(key))
+	}
+
+	if err := client.MoveIssuesToSprint(sprint.ID, keys); err != nil {
+		return err
+	}
+
+	green := color.New(color.FgGreen).SprintFunc()
+	fmt.Printf("%s Moved %s to %s (%d)\n", green("✓"), strings.Join(keys, ", "), sprint.Name, sprint.ID)
+
+	return nil
+}
+
+func newClient() (*jira.Client, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	client, err := jiraclient.New(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create JIRA client: %w", err)
+	}
+
+	return client, nil
+}
+
+// resolveSprint returns the sprint given by --sprint, or the active (or next)
+// sprint of the board given by --board
+func resolveSprint(client *jira.Client) (*jira.Sprint, error) {
+	if sprintID != 0 {
+		sprint, err := client.GetSprint(sprintID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get sprint %d: %w", sprintID, err)
+		}
+		return sprint, nil
+	}
+
+	if sprintBoard == "" {
+		return nil, fmt.Errorf("either a sprint ID or --board is required")
+	}
+
+	board, err := client.FindBoard(sprintBoard)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get board %s: %w", sprintBoard, err)
+	}
+
+	state := jira.SprintStateActive
+	if sprintNext {
+		state = jira.SprintStateFuture
+	}
+
+	sprints, err := client.ListSprints(board.ID, state)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case len(sprints) == 0:
+		return nil, fmt.Errorf("board %s has no %s sprint", board.Name, state)
+	case len(sprints) > 1 && !sprintNext:
+		// Boards running parallel sprints need an explicit choice
+		ids := make([]string, 0, len(sprints))
+		for _, s := range sprints {
+			ids = append(ids, fmt.Sprintf("%s (%d)", s.Name, s.ID))
+		}
+		return nil, fmt.Errorf("board %s has several active sprints, pick one of: %s", board.Name, strings.Join(ids, ", "))
+	}
+
+	// Future sprints are listed in planning order
+	return &sprints[0], nil
+}
+
+func outputIssues(cmd *cobra.Command, sprint *jira.Sprint, issues []jira.Issue) error {
+	outputFormat, _ := cmd.Root().PersistentFlags().GetString("output")
+
+	switch outputFormat {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(issues)
+	case "yaml":
+		encoder := yaml.NewEncoder(os.Stdout)
+		return encoder.Encode(issues)
+	case "table", "":
+		return outputIssuesTable(sprint, issues)
+	default:
+		return fmt.Errorf("unsupported output format: %s", outputFormat)
+	}
+}
+
+func outputIssuesTable(sprint *jira.Sprint, issues []jira.Issue) error {
+	bold := color.New(color.Bold).SprintFunc()
+
+	fmt.Printf("%s (%d, %s)\n", bold(sprint.Name), sprint.ID, sprint.State)
+	if sprint.Goal != "" {
+		fmt.Printf("Goal: %s\n", sprint.Goal)
+	}
+	fmt.Println()
+
+	if len(issues) == 0 {
+		fmt.Println("No issues found.")
+		return nil
+	}
+
+	renderer := tableutils.NewRenderer(
+		tableutils.WithHeaders("KEY", "TYPE", "STATUS", "PRIORITY", "ASSIGNEE", "SUMMARY"),
+	)
+
+	rows := make([][]any, 0, len(issues))
+	for _, issue := range issues {
+		assignee := "Unassigned"
+		if issue.Fields.Assignee != nil {
+			assignee = issue.Fields.Assignee.DisplayName
+		}
+
+		rows = append(rows, []any{
+			issue.Key,
+			issue.Fields.IssueType.Name,
+			issue.Fields.Status.Name,
+			issue.Fields.Priority.Name,
+			assignee,
+			stringutils.Truncate(issue.Fields.Summary, 60),
+		})
+	}
+
+	if err := renderer.AppendAll(rows); err != nil {
+		return err
+	}
+
+	return renderer.Render()
+}
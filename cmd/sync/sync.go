@@ -0,0 +1,182 @@
+package sync
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	jiraclient "github.com/lburgazzoli/gira/internal/client"
+	"github.com/lburgazzoli/gira/pkg/config"
+	"github.com/lburgazzoli/gira/pkg/mirror"
+	tableutils "github.com/lburgazzoli/gira/pkg/utils/table"
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	syncJQL  string
+	syncFull bool
+)
+
+var Cmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Mirror JIRA issues into a local database",
+	Long: `Mirror the issues matching a JQL query, with their comments and changelogs,
+into a local SQLite database (mirror.path, by default
+$XDG_DATA_HOME/gira/mirror.db).
+
+Only the issues updated since the previous sync of the same query are
+fetched, unless --full is given. Without --jql, all the previously synced
+queries are refreshed. Issues deleted or moved out of the query are not
+removed from the mirror.
+
+The mirror is used by get, search and tree-diff when --offline is given.
+
+Examples:
+  gira sync --jql "project = PROJ"
+  gira sync
+  gira --offline search "project = PROJ AND assignee = currentUser()"
+  gira --offline get issue EPIC-123 --tree`,
+	Args: cobra.NoArgs,
+	RunE: runSync,
+}
+
+func init() {
+	Cmd.Flags().StringVar(&syncJQL, "jql", "", "JQL query selecting the issues to mirror")
+	Cmd.Flags().BoolVar(&syncFull, "full", false, "Fetch all matching issues, not only the ones updated since the last sync")
+}
+
+func runSync(cmd *cobra.Command, _ []string) error {
+	if jiraclient.Offline {
+		return fmt.Errorf("sync cannot run offline")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	// The mirror must see the current state of the issues
+	jiraclient.Cache.Disabled = true
+
+	client, err := jiraclient.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create JIRA client: %w", err)
+	}
+
+	m, err := jiraclient.OpenMirror(cfg)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = m.Close()
+	}()
+
+	queries := []string{syncJQL}
+	if syncJQL == "" {
+		queries, err = m.Queries()
+		if err != nil {
+			return fmt.Errorf("failed to list synced queries: %w", err)
+		}
+		if len(queries) == 0 {
+			return fmt.Errorf("nothing to sync yet, use --jql to select the issues to mirror")
+		}
+	}
+
+	opts := mirror.SyncOptions{
+		Full: syncFull,
+	}
+
+	// Report progress on the same line, only when someone is watching
+	interactive := isatty.IsTerminal(os.Stderr.Fd())
+	if interactive {
+		opts.Progress = func(key string, done int, total int) {
+			fmt.Fprintf(os.Stderr, "\r\033[K%d/%d %s", done, total, key)
+		}
+	}
+
+	results := make([]*mirror.SyncResult, 0, len(queries))
+	for _, jql := range queries {
+		result, err := mirror.Sync(client, m, jql, opts)
+		if interactive {
+			fmt.Fprint(os.Stderr, "\r\033[K")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to sync %q: %w", jql, err)
+		}
+
+		results = append(results, result)
+	}
+
+	return outputResult(cmd, results)
+}
+
+func outputResult(cmd *cobra.Command, results []*mirror.SyncResult) error {
+	outputFormat, _ := cmd.Root().PersistentFlags().GetString("output")
+
+	switch outputFormat {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(results)
+	case "yaml":
+		encoder := yaml.NewEncoder(os.Stdout)
+		return encoder.Encode(results)
+	case "table":
+		return outputTable(results)
+	case "":
+		return outputPlain(results)
+	default:
+		return fmt.Errorf("unsupported output format: %s", outputFormat)
+	}
+}
+
+func outputTable(results []*mirror.SyncResult) error {
+	renderer := tableutils.NewRenderer(
+		tableutils.WithHeaders("JQL", "SINCE", "ISSUES", "COMMENTS", "CHANGES", "DURATION"),
+	)
+
+	rows := make([][]any, 0, len(results))
+	for _, r := range results {
+		rows = append(rows, []any{
+			r.JQL,
+			formatSince(r),
+			r.Issues,
+			r.Comments,
+			r.Changes,
+			r.Duration,
+		})
+	}
+
+	if err := renderer.AppendAll(rows); err != nil {
+		return err
+	}
+
+	return renderer.Render()
+}
+
+func outputPlain(results []*mirror.SyncResult) error {
+	green := color.New(color.FgGreen).SprintFunc()
+
+	for _, r := range results {
+		fmt.Printf("%s %s: %d issues, %d comments, %d changes (since %s) in %s\n",
+			green("✓"),
+			r.JQL,
+			r.Issues,
+			r.Comments,
+			r.Changes,
+			formatSince(r),
+			r.Duration)
+	}
+
+	return nil
+}
+
+func formatSince(r *mirror.SyncResult) string {
+	if r.Since.IsZero() {
+		return "full sync"
+	}
+	return r.Since.Local().Format("2006-01-02 15:04")
+}
@@ -0,0 +1,204 @@
+package treediff
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/fatih/color"
+	jiraclient "github.com/lburgazzoli/gira/internal/client"
+	"github.com/lburgazzoli/gira/pkg/config"
+	"github.com/lburgazzoli/gira/pkg/jira"
+	stringutils "github.com/lburgazzoli/gira/pkg/utils/strings"
+	tableutils "github.com/lburgazzoli/gira/pkg/utils/table"
+	timeutils "github.com/lburgazzoli/gira/pkg/utils/time"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	diffSince    string
+	diffSnapshot string
+	diffDepth    int
+)
+
+var Cmd = &cobra.Command{
+	Use:   "tree-diff ISSUE-KEY",
+	Short: "Show how an issue tree changed over time",
+	Long: `Show added and removed children, re-parented issues and status changes
+of an issue tree.
+
+The current tree is compared either with a snapshot previously saved with
+"gira get issue ISSUE-KEY --tree -o json", or with the state of the tree at
+a point in time reconstructed from the issue changelogs.
+
+Examples:
+  gira tree-diff EPIC-123 --since 2w
+  gira tree-diff EPIC-123 --since 2025-06-01
+  gira get issue EPIC-123 --tree -o json > sprint-review.json
+  gira tree-diff EPIC-123 --snapshot sprint-review.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTreeDiff,
+}
+
+func init() {
+	Cmd.Flags().StringVar(&diffSince, "since", "", "Point in time to compare with (e.g. 2w, 10d, 2025-06-01)")
+	Cmd.Flags().StringVar(&diffSnapshot, "snapshot", "", "JSON tree snapshot to compare with")
+	Cmd.Flags().IntVar(&diffDepth, "tree-depth", 3, "Maximum depth to traverse")
+}
+
+func runTreeDiff(cmd *cobra.Command, args []string) error {
+	issueKey := args[0]
+
+	if diffSince == "" && diffSnapshot == "" {
+		return fmt.Errorf("either --since or --snapshot must be specified")
+	}
+	if diffSince != "" && diffSnapshot != "" {
+		return fmt.Errorf("--since and --snapshot are mutually exclusive")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	client, err := jiraclient.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create JIRA client: %w", err)
+	}
+
+	issue, err := client.GetIssue(issueKey)
+	if err != nil {
+		return fmt.Errorf("failed to get issue %s: %w", issueKey, err)
+	}
+
+	if err := jira.BuildIssueTree(client, issue, diffDepth); err != nil {
+		return fmt.Errorf("failed to build issue tree: %w", err)
+	}
+
+	now := time.Now()
+	current := jira.SnapshotFromTree(issue, now)
+
+	var before *jira.TreeSnapshot
+
+	if diffSnapshot != "" {
+		before, err = loadSnapshot(diffSnapshot)
+		if err != nil {
+			return err
+		}
+		if before.Root != current.Root {
+			return fmt.Errorf("snapshot root %s does not match issue %s", before.Root, current.Root)
+		}
+	} else {
+		since, err := timeutils.ParseSince(diffSince, now)
+		if err != nil {
+			return err
+		}
+
+		before, err = jira.ReconstructTreeSnapshot(client, current, since)
+		if err != nil {
+			return fmt.Errorf("failed to reconstruct issue tree: %w", err)
+		}
+	}
+
+	return outputResult(cmd, jira.DiffTreeSnapshots(before, current))
+}
+
+func loadSnapshot(path string) (*jira.TreeSnapshot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open snapshot: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat snapshot: %w", err)
+	}
+
+	return jira.LoadTreeSnapshot(f, info.ModTime())
+}
+
+func outputResult(cmd *cobra.Command, diff *jira.TreeDiff) error {
+	outputFormat, _ := cmd.Root().PersistentFlags().GetString("output")
+
+	switch outputFormat {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(diff)
+	case "yaml":
+		encoder := yaml.NewEncoder(os.Stdout)
+		return encoder.Encode(diff)
+	case "table":
+		return outputTable(diff)
+	case "":
+		return outputPlain(diff)
+	default:
+		return fmt.Errorf("unsupported output format: %s", outputFormat)
+	}
+}
+
+func outputTable(diff *jira.TreeDiff) error {
+	if len(diff.Changes) == 0 {
+		fmt.Println("No changes found.")
+		return nil
+	}
+
+	renderer := tableutils.NewRenderer(
+		tableutils.WithHeaders("CHANGE", "KEY", "SUMMARY", "FROM", "TO"),
+	)
+
+	rows := make([][]any, 0, len(diff.Changes))
+	for _, change := range diff.Changes {
+		rows = append(rows, []any{
+			string(change.Kind),
+			change.Key,
+			stringutils.Truncate(change.Summary, 50),
+			change.From,
+			change.To,
+		})
+	}
+
+	if err := renderer.AppendAll(rows); err != nil {
+		return err
+	}
+
+	return renderer.Render()
+}
+
+func outputPlain(diff *jira.TreeDiff) error {
+	green := color.New(color.FgGreen).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	blue := color.New(color.FgBlue).SprintFunc()
+
+	fmt.Printf("Changes in %s since %s\n",
+		diff.Root,
+		diff.From.Format("2006-01-02 15:04"))
+
+	if len(diff.Changes) == 0 {
+		fmt.Println("No changes found.")
+		return nil
+	}
+
+	fmt.Println()
+
+	for _, change := range diff.Changes {
+		switch change.Kind {
+		case jira.TreeChangeAdded:
+			fmt.Printf("%s %s: %s (under %s)\n", green("+"), change.Key, change.Summary, change.To)
+		case jira.TreeChangeRemoved:
+			fmt.Printf("%s %s: %s (was under %s)\n", red("-"), change.Key, change.Summary, change.From)
+		case jira.TreeChangeReparented:
+			fmt.Printf("%s %s: %s moved %s → %s\n", yellow("~"), change.Key, change.Summary, change.From, change.To)
+		case jira.TreeChangeStatus:
+			fmt.Printf("%s %s: %s status %s → %s\n", blue("*"), change.Key, change.Summary, change.From, change.To)
+		}
+	}
+
+	return nil
+}
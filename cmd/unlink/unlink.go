@@ -0,0 +1,147 @@
+package unlink
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	jiraclient "github.com/lburgazzoli/gira/internal/client"
+	"github.com/lburgazzoli/gira/pkg/config"
+	"github.com/lburgazzoli/gira/pkg/jira"
+	"github.com/spf13/cobra"
+)
+
+var (
+	unlinkID  string
+	unlinkAll bool
+)
+
+var Cmd = &cobra.Command{
+	Use:   "unlink ISSUE-KEY [RELATION] ISSUE-KEY",
+	Short: "Remove links between two issues",
+	Long: `Remove the link between two issues. When the issues are linked several
+times, the relation (e.g. "blocks" or "is blocked by") selects the link to
+remove, or --all removes all of them. A link can also be given by ID.
+
+Examples:
+  gira unlink PROJ-1 PROJ-2
+  gira unlink PROJ-1 blocks PROJ-2
+  gira unlink PROJ-1 PROJ-2 --all
+  gira unlink --id 10234`,
+	RunE: runUnlink,
+}
+
+func init() {
+	Cmd.Flags().StringVar(&unlinkID, "id", "", "ID of the link to remove")
+	Cmd.Flags().BoolVar(&unlinkAll, "all", false, "Remove all the links between the two issues")
+}
+
+func runUnlink(_ *cobra.Command, args []string) error {
+	switch {
+	case unlinkID != "" && len(args) > 0:
+		return fmt.Errorf("--id cannot be combined with issue keys")
+	case unlinkID == "" && len(args) < 2:
+		return fmt.Errorf("two issue keys or --id are required")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	client, err := jiraclient.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create JIRA client: %w", err)
+	}
+
+	green := color.New(color.FgGreen).SprintFunc()
+
+	if unlinkID != "" {
+		link, err := client.DeleteIssueLink(unlinkID)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s Removed %s %s %s\n", green("✓"), link.InwardIssue.Key, link.Type.Outward, link.OutwardIssue.Key)
+		return nil
+	}
+
+	from := strings.ToUpper(args[0])
+	to := strings.ToUpper(args[len(args)-1])
+	phrase := strings.Join(args[1:len(args)-1], " ")
+
+	links, err := client.ListIssueLinks(from)
+	if err != nil {
+		return err
+	}
+
+	matches, err := linksTo(client, links, to, phrase)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case len(matches) == 0:
+		return fmt.Errorf("%s is not linked to %s", from, to)
+	case len(matches) > 1 && !unlinkAll:
+		relations := make([]string, 0, len(matches))
+		for _, l := range matches {
+			relations = append(relations, fmt.Sprintf("%q", l.Relation()))
+		}
+		return fmt.Errorf("%s is linked to %s several times (%s), give the relation or use --all",
+			from, to, strings.Join(relations, ", "))
+	}
+
+	for _, l := range matches {
+		if _, err := client.DeleteIssueLink(l.ID); err != nil {
+			return err
+		}
+
+		fmt.Printf("%s Removed %s %s %s\n", green("✓"), from, l.Relation(), to)
+	}
+
+	return nil
+}
+
+// linksTo returns the links to the given issue, restricted to a relation
+// as seen from the issue holding the links when one is given
+func linksTo(client *jira.Client, links []jira.IssueLink, key string, phrase string) ([]jira.IssueLink, error) {
+	var (
+		linkType jira.LinkType
+		reversed bool
+	)
+
+	if phrase != "" {
+		types, err := client.ListLinkTypes()
+		if err != nil {
+			return nil, err
+		}
+
+		linkType, reversed, err = jira.ResolveLinkType(types, phrase)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	matches := make([]jira.IssueLink, 0)
+	for _, l := range links {
+		linked := l.LinkedIssue()
+		if linked == nil || linked.Key != key {
+			continue
+		}
+
+		if phrase != "" {
+			// Outward links go from the issue holding them, inward links to it
+			if l.Type.ID != linkType.ID && l.Type.Name != linkType.Name {
+				continue
+			}
+			if linkType.Inward != linkType.Outward && (l.InwardIssue != nil) != reversed {
+				continue
+			}
+		}
+
+		matches = append(matches, l)
+	}
+
+	return matches, nil
+}
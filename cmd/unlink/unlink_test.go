@@ -0,0 +1,98 @@
+package unlink
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/lburgazzoli/gira/pkg/jira"
+	"github.com/lburgazzoli/gira/pkg/jira/jiratest"
+)
+
+func TestLinksTo(t *testing.T) {
+	srv := jiratest.NewServer()
+	t.Cleanup(srv.Close)
+
+	client, err := srv.NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 1; i <= 3; i++ {
+		srv.AddIssue(jira.Issue{Fields: jira.IssueFields{
+			Summary:   fmt.Sprintf("Issue %d", i),
+			IssueType: jira.IssueType{Name: "Task"},
+			Project:   jira.Project{Key: "DEMO"},
+		}})
+	}
+
+	// Link IDs by description, as seen from DEMO-1
+	ids := make(map[string]string)
+	for _, l := range []struct {
+		name     string
+		linkType string
+		source   string
+		dest     string
+	}{
+		{name: "blocks", linkType: "Blocks", source: "DEMO-1", dest: "DEMO-2"},
+		{name: "is blocked by", linkType: "Blocks", source: "DEMO-2", dest: "DEMO-1"},
+		{name: "relates to", linkType: "Relates", source: "DEMO-2", dest: "DEMO-1"},
+		{name: "other issue", linkType: "Blocks", source: "DEMO-1", dest: "DEMO-3"},
+	} {
+		id, err := srv.AddIssueLink(l.linkType, l.source, l.dest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids[l.name] = id
+	}
+
+	links, err := client.ListIssueLinks("DEMO-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		phrase  string
+		want    []string
+		wantErr bool
+	}{
+		{phrase: "", want: []string{"blocks", "is blocked by", "relates to"}},
+		{phrase: "blocks", want: []string{"blocks"}},
+		{phrase: "is blocked by", want: []string{"is blocked by"}},
+		// Symmetric links match whichever issue holds them
+		{phrase: "relates to", want: []string{"relates to"}},
+		{phrase: "clones", want: []string{}},
+		{phrase: "supersedes", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.phrase, func(t *testing.T) {
+			matches, err := linksTo(client, links, "DEMO-2", tt.phrase)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected an error for an unknown relation")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := make([]string, 0, len(matches))
+			for _, m := range matches {
+				got = append(got, m.ID)
+			}
+			want := make([]string, 0, len(tt.want))
+			for _, name := range tt.want {
+				want = append(want, ids[name])
+			}
+			sort.Strings(got)
+			sort.Strings(want)
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("matched links %v, want %v (%v)", got, want, tt.want)
+			}
+		})
+	}
+}
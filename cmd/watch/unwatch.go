@@ -0,0 +1,36 @@
+package watch
+
+import (
+	"github.com/lburgazzoli/gira/pkg/jira"
+	"github.com/spf13/cobra"
+)
+
+var UnwatchCmd = &cobra.Command{
+	Use:   "unwatch [ISSUE-KEY...]",
+	Short: "Stop watching issues",
+	Long: `Remove a user, by default yourself, from the watchers of issues given by
+key or matching a JQL query. --user accepts "me", an account ID, a username,
+an email address or a display name.
+
+Failures on single issues are reported and the remaining issues are still
+processed; the command fails when any issue could not be unwatched.
+
+Examples:
+  gira unwatch PROJ-1 PROJ-2
+  gira unwatch --jql "watcher = currentUser() AND project = OPS"
+  gira unwatch --jql "project = OPS" --user alice@example.com`,
+	RunE: func(_ *cobra.Command, args []string) error {
+		return run(args, unwatching)
+	},
+}
+
+var unwatching = action{
+	verb:  "unwatch",
+	done:  "no longer watching",
+	apply: (*jira.Client).RemoveWatcher,
+}
+
+func init() {
+	UnwatchCmd.Flags().StringVar(&watchUser, "user", jira.CurrentUserAlias, "User to remove from the watchers")
+	UnwatchCmd.Flags().StringVar(&watchJQL, "jql", "", "Stop watching the issues matching a JQL query")
+}
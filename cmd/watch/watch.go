@@ -0,0 +1,135 @@
+package watch
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	jiraclient "github.com/lburgazzoli/gira/internal/client"
+	"github.com/lburgazzoli/gira/pkg/config"
+	"github.com/lburgazzoli/gira/pkg/jira"
+	"github.com/spf13/cobra"
+)
+
+var (
+	watchUser string
+	watchJQL  string
+)
+
+var Cmd = &cobra.Command{
+	Use:   "watch [ISSUE-KEY...]",
+	Short: "Watch issues",
+	Long: `Add a user, by default yourself, to the watchers of issues given by key or
+matching a JQL query. --user accepts "me", an account ID, a username, an
+email address or a display name.
+
+Failures on single issues are reported and the remaining issues are still
+processed; the command fails when any issue could not be watched.
+
+Examples:
+  gira watch PROJ-1 PROJ-2
+  gira watch PROJ-1 --user alice@example.com
+  gira watch --jql "project = OPS AND resolution = Unresolved" --user alice@example.com`,
+	RunE: func(_ *cobra.Command, args []string) error {
+		return run(args, watching)
+	},
+}
+
+func init() {
+	Cmd.Flags().StringVar(&watchUser, "user", jira.CurrentUserAlias, "User to add to the watchers")
+	Cmd.Flags().StringVar(&watchJQL, "jql", "", "Watch the issues matching a JQL query")
+}
+
+// action is what watch and unwatch do to each issue
+type action struct {
+	// verb names the action in the final error
+	verb string
+	// done describes the new state of an issue
+	done  string
+	apply func(client *jira.Client, key string, user *jira.User) error
+}
+
+var watching = action{
+	verb:  "watch",
+	done:  "watching",
+	apply: (*jira.Client).AddWatcher,
+}
+
+// run applies the action to the issues given by key or matching --jql, for
+// the user given by --user. Both commands share the flag variables, as only
+// one of them runs.
+func run(args []string, act action) error {
+	if len(args) == 0 && watchJQL == "" {
+		return fmt.Errorf("issue keys or --jql are required")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	client, err := jiraclient.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create JIRA client: %w", err)
+	}
+
+	keys, err := issueKeys(client, args, watchJQL)
+	if err != nil {
+		return err
+	}
+
+	// A nil user stands for the authenticated user
+	var user *jira.User
+	who := "You are"
+	if !strings.EqualFold(watchUser, jira.CurrentUserAlias) {
+		user, err = client.ResolveUser(watchUser)
+		if err != nil {
+			return err
+		}
+		who = user.DisplayName + " is"
+	}
+
+	green := color.New(color.FgGreen).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+
+	failed := 0
+	for _, key := range keys {
+		if err := act.apply(client, key, user); err != nil {
+			fmt.Printf("%s %s: %v\n", red("✗"), key, err)
+			failed++
+			continue
+		}
+
+		fmt.Printf("%s %s %s %s\n", green("✓"), who, act.done, key)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to %s %d of %d issues", act.verb, failed, len(keys))
+	}
+
+	return nil
+}
+
+// issueKeys returns the given issue keys followed by the keys of the issues
+// matching the query, if any
+func issueKeys(client *jira.Client, args []string, jql string) ([]string, error) {
+	keys := make([]string, 0, len(args))
+	for _, arg := range args {
+		keys = append(keys, strings.ToUpper(arg))
+	}
+
+	if jql == "" {
+		return keys, nil
+	}
+
+	issues, err := client.SearchAllIssues(jql, []string{"key"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search issues: %w", err)
+	}
+
+	for _, issue := range issues {
+		keys = append(keys, issue.Key)
+	}
+
+	return keys, nil
+}
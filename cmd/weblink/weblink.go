@@ -0,0 +1,209 @@
+package weblink
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	jiraclient "github.com/lburgazzoli/gira/internal/client"
+	"github.com/lburgazzoli/gira/pkg/config"
+	"github.com/lburgazzoli/gira/pkg/jira"
+	stringutils "github.com/lburgazzoli/gira/pkg/utils/strings"
+	tableutils "github.com/lburgazzoli/gira/pkg/utils/table"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	addTitle        string
+	addSummary      string
+	addGlobalID     string
+	addRelationship string
+)
+
+var Cmd = &cobra.Command{
+	Use:   "weblink",
+	Short: "Manage the web links of issues",
+	Long: `Manage the remote links of issues, such as links to pull requests, design
+documents or CI runs.`,
+}
+
+var addCmd = &cobra.Command{
+	Use:   "add ISSUE-KEY URL",
+	Short: "Add a web link to an issue",
+	Long: `Add a web link to an issue. Links are identified by their global ID, which
+defaults to the URL: adding a link with the global ID of an existing one
+updates it instead of adding a duplicate, so CI jobs can run the command
+repeatedly.
+
+Examples:
+  gira weblink add PROJ-123 https://github.com/org/repo/pull/42 --title "PR #42"
+  gira weblink add PROJ-123 "$BUILD_URL" --title "Build $BUILD_NUMBER" --global-id "ci:$JOB_NAME"
+  gira weblink add PROJ-123 https://docs.example.com/design --title "Design" --relationship "documented by"`,
+	Args: cobra.ExactArgs(2),
+	RunE: runAdd,
+}
+
+var listCmd = &cobra.Command{
+	Use:   "list ISSUE-KEY",
+	Short: "List the web links of an issue",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runList,
+}
+
+var removeCmd = &cobra.Command{
+	Use:   "remove ISSUE-KEY LINK-ID",
+	Short: "Remove a web link from an issue",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runRemove,
+}
+
+func init() {
+	addCmd.Flags().StringVar(&addTitle, "title", "", "Title of the link (default: the URL)")
+	addCmd.Flags().StringVar(&addSummary, "summary", "", "Summary of the linked object")
+	addCmd.Flags().StringVar(&addGlobalID, "global-id", "", "Global ID identifying the link (default: the URL)")
+	addCmd.Flags().StringVar(&addRelationship, "relationship", "", "Relationship between the issue and the linked object")
+
+	Cmd.AddCommand(addCmd)
+	Cmd.AddCommand(listCmd)
+	Cmd.AddCommand(removeCmd)
+}
+
+func runAdd(_ *cobra.Command, args []string) error {
+	issueKey := strings.ToUpper(args[0])
+	target := args[1]
+
+	if u, err := url.Parse(target); err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("invalid URL %q", target)
+	}
+
+	link := jira.RemoteLink{
+		GlobalID:     addGlobalID,
+		Relationship: addRelationship,
+		Object: jira.RemoteLinkObject{
+			URL:     target,
+			Title:   addTitle,
+			Summary: addSummary,
+		},
+	}
+	if link.GlobalID == "" {
+		link.GlobalID = target
+	}
+	if link.Object.Title == "" {
+		link.Object.Title = target
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	created, err := client.CreateRemoteLink(issueKey, link)
+	if err != nil {
+		return err
+	}
+
+	green := color.New(color.FgGreen).SprintFunc()
+	fmt.Printf("%s Linked %s to %s (%d)\n", green("✓"), issueKey, created.Object.URL, created.ID)
+
+	return nil
+}
+
+func runList(cmd *cobra.Command, args []string) error {
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	links, err := client.ListRemoteLinks(strings.ToUpper(args[0]))
+	if err != nil {
+		return err
+	}
+
+	return outputLinks(cmd, links)
+}
+
+func runRemove(_ *cobra.Command, args []string) error {
+	issueKey := strings.ToUpper(args[0])
+
+	if _, err := strconv.Atoi(args[1]); err != nil {
+		return fmt.Errorf("invalid link ID %q", args[1])
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	if err := client.DeleteRemoteLink(issueKey, args[1]); err != nil {
+		return err
+	}
+
+	green := color.New(color.FgGreen).SprintFunc()
+	fmt.Printf("%s Removed web link %s from %s\n", green("✓"), args[1], issueKey)
+
+	return nil
+}
+
+func newClient() (*jira.Client, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	client, err := jiraclient.New(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create JIRA client: %w", err)
+	}
+
+	return client, nil
+}
+
+func outputLinks(cmd *cobra.Command, links []jira.RemoteLink) error {
+	outputFormat, _ := cmd.Root().PersistentFlags().GetString("output")
+
+	switch outputFormat {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(links)
+	case "yaml":
+		encoder := yaml.NewEncoder(os.Stdout)
+		return encoder.Encode(links)
+	case "table", "":
+		return outputLinksTable(links)
+	default:
+		return fmt.Errorf("unsupported output format: %s", outputFormat)
+	}
+}
+
+func outputLinksTable(links []jira.RemoteLink) error {
+	if len(links) == 0 {
+		fmt.Println("No web links found.")
+		return nil
+	}
+
+	renderer := tableutils.NewRenderer(
+		tableutils.WithHeaders("ID", "TITLE", "URL", "RELATIONSHIP"),
+	)
+
+	rows := make([][]any, 0, len(links))
+	for _, l := range links {
+		rows = append(rows, []any{
+			l.ID,
+			stringutils.Truncate(l.Object.Title, 50),
+			l.Object.URL,
+			l.Relationship,
+		})
+	}
+
+	if err := renderer.AppendAll(rows); err != nil {
+		return err
+	}
+
+	return renderer.Render()
+}
@@ -0,0 +1,207 @@
+package worklog
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	jiraclient "github.com/lburgazzoli/gira/internal/client"
+	"github.com/lburgazzoli/gira/pkg/config"
+	"github.com/lburgazzoli/gira/pkg/jira"
+	stringutils "github.com/lburgazzoli/gira/pkg/utils/strings"
+	tableutils "github.com/lburgazzoli/gira/pkg/utils/table"
+	timeutils "github.com/lburgazzoli/gira/pkg/utils/time"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// workdayStart is the time of day used for worklogs started on a plain date
+const workdayStart = 9 * time.Hour
+
+var (
+	logComment string
+	logStarted string
+	logList    bool
+	logUpdate  string
+	logDelete  string
+)
+
+var Cmd = &cobra.Command{
+	Use:   "log ISSUE-KEY [DURATION]",
+	Short: "Log time spent on an issue",
+	Long: `Log time spent on an issue, or list, update and delete its worklogs.
+
+Durations use the JIRA time tracking format: weeks (w), days (d), hours (h)
+and minutes (m), where a day is 8 hours and a week 5 days, e.g. 2h30m,
+"1d 4h" or 1.5h.
+
+--started accepts "now", "today", "yesterday" (at the current time), a date
+(at 09:00), a timestamp or a relative duration such as 3h. Updates keep the
+start time and comment of the worklog unless given.
+
+Examples:
+  gira log PROJ-123 2h30m
+  gira log PROJ-123 1h --comment "Code review" --started yesterday
+  gira log PROJ-123 45m --started 2026-10-01
+  gira log PROJ-123 --list
+  gira log PROJ-123 3h --update 10042
+  gira log PROJ-123 --delete 10042`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runLog,
+}
+
+func init() {
+	Cmd.Flags().StringVar(&logComment, "comment", "", "Comment describing the work")
+	Cmd.Flags().StringVar(&logStarted, "started", "now", "When the work started")
+	Cmd.Flags().BoolVar(&logList, "list", false, "List the worklogs of the issue")
+	Cmd.Flags().StringVar(&logUpdate, "update", "", "ID of a worklog to update instead of logging new work")
+	Cmd.Flags().StringVar(&logDelete, "delete", "", "ID of a worklog to delete")
+	Cmd.MarkFlagsMutuallyExclusive("list", "update", "delete")
+}
+
+func runLog(cmd *cobra.Command, args []string) error {
+	key := strings.ToUpper(args[0])
+
+	needsDuration := !logList && logDelete == ""
+	switch {
+	case needsDuration && len(args) < 2:
+		return fmt.Errorf("a duration is required, e.g. gira log %s 2h30m", key)
+	case !needsDuration && len(args) > 1:
+		return fmt.Errorf("unexpected duration %q", args[1])
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	client, err := jiraclient.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create JIRA client: %w", err)
+	}
+
+	green := color.New(color.FgGreen).SprintFunc()
+
+	switch {
+	case logList:
+		worklogs, err := client.ListWorklogs(key)
+		if err != nil {
+			return err
+		}
+		return outputWorklogs(cmd, worklogs)
+	case logDelete != "":
+		if err := client.DeleteWorklog(key, logDelete); err != nil {
+			return err
+		}
+		fmt.Printf("%s Deleted worklog %s of %s\n", green("✓"), logDelete, key)
+		return nil
+	}
+
+	spent, err := timeutils.ParseWorkDuration(args[1])
+	if err != nil {
+		return err
+	}
+
+	input := jira.WorklogInput{
+		TimeSpent: spent,
+		Comment:   logComment,
+	}
+
+	// Updates keep the start time of the worklog unless --started is given
+	if logUpdate == "" || cmd.Flags().Changed("started") {
+		input.Started, err = parseStarted(logStarted, time.Now())
+		if err != nil {
+			return err
+		}
+	}
+
+	if logUpdate != "" {
+		worklog, err := client.UpdateWorklog(key, logUpdate, input)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s Updated worklog %s of %s: %s\n", green("✓"), worklog.ID, key, timeutils.FormatWorkDuration(spent))
+		return nil
+	}
+
+	worklog, err := client.AddWorklog(key, input)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Logged %s on %s, started %s (worklog %s)\n",
+		green("✓"), timeutils.FormatWorkDuration(spent), key, input.Started.Format("2006-01-02 15:04"), worklog.ID)
+
+	return nil
+}
+
+// parseStarted resolves --started: keywords keep the current time of day and
+// plain dates start at the beginning of the working day
+func parseStarted(s string, now time.Time) (time.Time, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "now", "today":
+		return now, nil
+	case "yesterday":
+		return now.AddDate(0, 0, -1), nil
+	}
+
+	if t, err := time.ParseInLocation(timeutils.DateLayout, strings.TrimSpace(s), now.Location()); err == nil {
+		return t.Add(workdayStart), nil
+	}
+
+	return timeutils.ParseSince(s, now)
+}
+
+func outputWorklogs(cmd *cobra.Command, worklogs []jira.Worklog) error {
+	outputFormat, _ := cmd.Root().PersistentFlags().GetString("output")
+
+	switch outputFormat {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(worklogs)
+	case "yaml":
+		encoder := yaml.NewEncoder(os.Stdout)
+		return encoder.Encode(worklogs)
+	case "table", "":
+		return outputWorklogsTable(worklogs)
+	default:
+		return fmt.Errorf("unsupported output format: %s", outputFormat)
+	}
+}
+
+func outputWorklogsTable(worklogs []jira.Worklog) error {
+	if len(worklogs) == 0 {
+		fmt.Println("No worklogs found.")
+		return nil
+	}
+
+	renderer := tableutils.NewRenderer(
+		tableutils.WithHeaders("ID", "STARTED", "AUTHOR", "TIME SPENT", "COMMENT"),
+	)
+
+	rows := make([][]any, 0, len(worklogs))
+	for _, worklog := range worklogs {
+		author := ""
+		if worklog.Author != nil {
+			author = worklog.Author.DisplayName
+		}
+
+		rows = append(rows, []any{
+			worklog.ID,
+			worklog.Started.Local().Format("2006-01-02 15:04"),
+			author,
+			timeutils.FormatWorkDuration(time.Duration(worklog.TimeSpentSeconds) * time.Second),
+			stringutils.Truncate(worklog.Comment, 50),
+		})
+	}
+
+	if err := renderer.AppendAll(rows); err != nil {
+		return err
+	}
+
+	return renderer.Render()
+}
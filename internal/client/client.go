@@ -0,0 +1,183 @@
+package client
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/url"
+
+	"github.com/lburgazzoli/gira/internal/version"
+	"github.com/lburgazzoli/gira/pkg/config"
+	"github.com/lburgazzoli/gira/pkg/jira"
+	"github.com/lburgazzoli/gira/pkg/jira/cassette"
+	"github.com/lburgazzoli/gira/pkg/mirror"
+)
+
+const (
+	// placeholderBaseURL and placeholderToken stand in for missing settings
+	// when replaying a cassette or working offline, as no request reaches JIRA
+	placeholderBaseURL = "https://jira.invalid"
+	placeholderToken   = "offline"
+)
+
+// CacheOptions controls the on-disk response cache
+type CacheOptions struct {
+	// Disabled bypasses the cache entirely
+	Disabled bool
+	// Refresh ignores cached responses and stores fresh ones
+	Refresh bool
+}
+
+// Cache holds the cache settings, bound to the --no-cache and --refresh flags
+var Cache CacheOptions
+
+// Offline serves requests from the local mirror instead of JIRA, bound to
+// the --offline flag
+var Offline bool
+
+// New creates a JIRA client configured from the gira configuration. Setting
+// GIRA_HTTP_MODE to record or replay records HTTP interactions to, or
+// replays them from, the cassette file named by GIRA_HTTP_CASSETTE. With
+// Offline set, requests are answered by the local mirror.
+func New(cfg *config.Config) (*jira.Client, error) {
+	opts, err := Options(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	mode, err := cassette.ModeFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	baseURL := cfg.JIRA.BaseURL
+	token := cfg.JIRA.Token
+
+	// No request reaches JIRA when working offline or replaying a cassette
+	offline := Offline || mode == cassette.ModeReplay
+
+	switch {
+	case Offline:
+		m, err := OpenMirror(cfg)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, jira.WithTransportMiddleware(func(http.RoundTripper) http.RoundTripper {
+			return m.Transport()
+		}))
+	case mode != cassette.ModeDisabled:
+		middleware, err := cassette.Wrap(mode, cassette.PathFromEnv())
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts,
+			jira.WithTransportMiddleware(middleware),
+			jira.WithMultipartBoundary(cassette.MultipartBoundary),
+		)
+	case !Cache.Disabled:
+		cacheOpt, err := cacheOption()
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, cacheOpt)
+	}
+
+	if offline {
+		if baseURL == "" {
+			baseURL = placeholderBaseURL
+		}
+		if token == "" {
+			token = placeholderToken
+		}
+	}
+
+	return jira.NewClient(baseURL, jira.AuthConfig{
+		Token: token,
+	}, opts...)
+}
+
+// OpenMirror opens the offline mirror configured by mirror.path, or the one
+// at the default location
+func OpenMirror(cfg *config.Config) (*mirror.Mirror, error) {
+	path := cfg.Mirror.Path
+	if path == "" {
+		var err error
+		if path, err = mirror.DefaultPath(); err != nil {
+			return nil, fmt.Errorf("failed to determine mirror location: %w", err)
+		}
+	}
+
+	return mirror.Open(path)
+}
+
+// cacheOption enables the response cache under the default cache directory.
+// The cache is never used with cassettes, so that recordings see every request.
+func cacheOption() (jira.Option, error) {
+	dir, err := jira.DefaultCacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine cache directory: %w", err)
+	}
+
+	cache, err := jira.NewFileCache(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := jira.DefaultCachePolicy()
+	policy.Refresh = Cache.Refresh
+
+	return jira.WithCache(cache, policy), nil
+}
+
+// Options translates the JIRA configuration into client options
+func Options(cfg *config.Config) ([]jira.Option, error) {
+	opts := []jira.Option{
+		jira.WithUserAgent("gira/" + version.GetVersion()),
+		jira.WithLogger(slog.Default()),
+	}
+
+	if cfg.JIRA.Timeout > 0 {
+		opts = append(opts, jira.WithTimeout(cfg.JIRA.Timeout))
+	}
+
+	policy := jira.DefaultRetryPolicy()
+	if cfg.JIRA.Retry.Max != nil {
+		policy.MaxRetries = *cfg.JIRA.Retry.Max
+	}
+	if cfg.JIRA.Retry.MaxWait > 0 {
+		policy.WaitMax = cfg.JIRA.Retry.MaxWait
+	}
+	if policy.WaitMin > policy.WaitMax {
+		policy.WaitMin = policy.WaitMax
+	}
+	opts = append(opts, jira.WithRetryPolicy(policy))
+
+	if cfg.JIRA.RateLimit > 0 {
+		burst := int(math.Ceil(cfg.JIRA.RateLimit))
+		opts = append(opts, jira.WithRateLimit(cfg.JIRA.RateLimit, burst))
+	}
+
+	if cfg.JIRA.Proxy != "" {
+		proxyURL, err := url.Parse(cfg.JIRA.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL %q: %w", cfg.JIRA.Proxy, err)
+		}
+		opts = append(opts, jira.WithProxy(proxyURL))
+	}
+
+	tlsConfig, err := jira.NewTLSConfig(jira.TLSOptions{
+		CAFile:             cfg.JIRA.TLS.CAFile,
+		CertFile:           cfg.JIRA.TLS.CertFile,
+		KeyFile:            cfg.JIRA.TLS.KeyFile,
+		InsecureSkipVerify: cfg.JIRA.TLS.InsecureSkipVerify,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid TLS configuration: %w", err)
+	}
+	if tlsConfig != nil {
+		opts = append(opts, jira.WithTLSConfig(tlsConfig))
+	}
+
+	return opts, nil
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/lburgazzoli/gira/pkg/jira/cassette"
+	"github.com/lburgazzoli/gira/pkg/jira/jiratest"
+)
+
+// replayBaseURL is the JIRA instance the cassettes are replayed against, no
+// request reaches it
+const replayBaseURL = "https://jira.example.com"
+
+var update = flag.Bool("update", false, "record the cassettes against the fake JIRA and rewrite the golden files")
+
+// gira is the binary under test, built by TestMain
+var gira string
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+
+	dir, err := os.MkdirTemp("", "gira-golden")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	gira = filepath.Join(dir, "gira")
+	if out, err := exec.Command("go", "build", "-o", gira, ".").CombinedOutput(); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to build gira: %v\n%s", err, out)
+		os.Exit(1)
+	}
+
+	code := m.Run()
+
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// TestGolden replays the cassettes of testdata/cassettes and compares the
+// output with testdata/golden. With -update the cassettes are recorded again
+// against jiratest seeded with testdata/fixtures.json.
+func TestGolden(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "get-issue", args: []string{"get", "issue", "DEMO-3"}},
+		{name: "get-issue-tree", args: []string{"get", "issue", "DEMO-1", "--tree"}},
+		{name: "get-issue-tree-json", args: []string{"get", "issue", "DEMO-1", "--tree", "-o", "json"}},
+		{name: "search", args: []string{"search", "project = DEMO ORDER BY key"}},
+	}
+
+	if *update {
+		fixtures, err := jiratest.LoadFixtures(filepath.Join("testdata", "fixtures.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		srv := jiratest.NewServer(jiratest.WithFixtures(fixtures))
+		defer srv.Close()
+
+		for _, tt := range tests {
+			path := cassettePath(tt.name)
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				t.Fatal(err)
+			}
+			if _, err := run(t, srv.URL, cassette.ModeRecord, path, tt.args...); err != nil {
+				t.Fatalf("failed to record %s: %v", tt.name, err)
+			}
+		}
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := run(t, replayBaseURL, cassette.ModeReplay, cassettePath(tt.name), tt.args...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			golden := filepath.Join("testdata", "golden", tt.name+".golden")
+			if *update {
+				if err := os.WriteFile(golden, got, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(got, want) {
+				t.Errorf("output differs from %s, run go test -update to accept it\n--- got\n%s\n--- want\n%s", golden, got, want)
+			}
+		})
+	}
+}
+
+func cassettePath(name string) string {
+	return filepath.Join("testdata", "cassettes", name+".yaml")
+}
+
+// run executes gira against baseURL with an isolated configuration and
+// returns its standard output
+func run(t *testing.T, baseURL string, mode cassette.Mode, path string, args ...string) ([]byte, error) {
+	t.Helper()
+
+	home := t.TempDir()
+	configDir := filepath.Join(home, "config", "gira")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	config := fmt.Sprintf("jira:\n  base_url: %q\n  token: \"golden\"\n", baseURL)
+	if err := os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(gira, args...)
+	cmd.Env = append(os.Environ(),
+		"HOME="+home,
+		"XDG_CONFIG_HOME="+filepath.Join(home, "config"),
+		"XDG_CACHE_HOME="+filepath.Join(home, "cache"),
+		"XDG_DATA_HOME="+filepath.Join(home, "data"),
+		"TZ=UTC",
+		"NO_COLOR=1",
+		cassette.EnvMode+"="+string(mode),
+		cassette.EnvPath+"="+absPath,
+	)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("gira %v: %w\n%s", args, err, stderr.String())
+	}
+
+	return stdout.Bytes(), nil
+}
@@ -5,31 +5,59 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	JIRA JIRAConfig `mapstructure:"jira"`
-	AI   AIConfig   `mapstructure:"ai"`
-	CLI  CLIConfig  `mapstructure:"cli"`
+	JIRA JIRAConfig `mapstructure:"jira" yaml:"jira"`
+	AI   AIConfig   `mapstructure:"ai" yaml:"ai"`
+	CLI  CLIConfig  `mapstructure:"cli" yaml:"cli"`
+
+	Mirror MirrorConfig `mapstructure:"mirror" yaml:"mirror"`
 }
 
 type JIRAConfig struct {
-	BaseURL string `mapstructure:"base_url"`
-	Token   string `mapstructure:"token"`
+	BaseURL string        `mapstructure:"base_url" yaml:"base_url"`
+	Token   string        `mapstructure:"token" yaml:"token"`
+	Proxy   string        `mapstructure:"proxy" yaml:"proxy,omitempty"`
+	Timeout time.Duration `mapstructure:"timeout" yaml:"timeout,omitempty"`
+	TLS     TLSConfig     `mapstructure:"tls" yaml:"tls,omitempty"`
+	Retry   RetryConfig   `mapstructure:"retry" yaml:"retry,omitempty"`
+	// RateLimit is the maximum number of requests per second (0 disables the limiter)
+	RateLimit float64 `mapstructure:"rate_limit" yaml:"rate_limit,omitempty"`
+}
+
+type RetryConfig struct {
+	// Max is the maximum number of retries, nil when not configured so that
+	// an explicit 0 can disable retries
+	Max     *int          `mapstructure:"max" yaml:"max,omitempty"`
+	MaxWait time.Duration `mapstructure:"max_wait" yaml:"max_wait,omitempty"`
+}
+
+type TLSConfig struct {
+	CAFile             string `mapstructure:"ca_file" yaml:"ca_file,omitempty"`
+	CertFile           string `mapstructure:"cert_file" yaml:"cert_file,omitempty"`
+	KeyFile            string `mapstructure:"key_file" yaml:"key_file,omitempty"`
+	InsecureSkipVerify bool   `mapstructure:"insecure_skip_verify" yaml:"insecure_skip_verify,omitempty"`
 }
 
 type AIConfig struct {
-	Provider string            `mapstructure:"provider"`
-	Models   map[string]string `mapstructure:"models"`
-	APIKey   string            `mapstructure:"api_key"`
+	Provider string            `mapstructure:"provider" yaml:"provider"`
+	Models   map[string]string `mapstructure:"models" yaml:"models"`
+	APIKey   string            `mapstructure:"api_key" yaml:"api_key"`
 }
 
 type CLIConfig struct {
-	OutputFormat string `mapstructure:"output_format"`
-	Color        bool   `mapstructure:"color"`
-	Verbose      bool   `mapstructure:"verbose"`
+	OutputFormat string `mapstructure:"output_format" yaml:"output_format"`
+	Color        bool   `mapstructure:"color" yaml:"color"`
+	Verbose      bool   `mapstructure:"verbose" yaml:"verbose"`
+}
+
+type MirrorConfig struct {
+	// Path is the SQLite file of the offline mirror, empty for the default location
+	Path string `mapstructure:"path" yaml:"path"`
 }
 
 func Load() (*Config, error) {
@@ -88,6 +116,11 @@ func getConfigDir() (string, error) {
 }
 
 func setDefaults(v *viper.Viper) {
+	v.SetDefault("jira.timeout", 30*time.Second)
+	v.SetDefault("jira.tls.insecure_skip_verify", false)
+	v.SetDefault("jira.retry.max", 3)
+	v.SetDefault("jira.retry.max_wait", 30*time.Second)
+	v.SetDefault("jira.rate_limit", 0)
 	v.SetDefault("cli.output_format", "table")
 	v.SetDefault("cli.color", true)
 	v.SetDefault("cli.verbose", false)
@@ -0,0 +1,401 @@
+package cassette
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Mode selects whether HTTP interactions are recorded or replayed
+type Mode string
+
+const (
+	ModeDisabled Mode = ""
+	ModeRecord   Mode = "record"
+	ModeReplay   Mode = "replay"
+
+	// EnvMode is the environment variable selecting the mode
+	EnvMode = "GIRA_HTTP_MODE"
+	// EnvPath is the environment variable selecting the cassette file
+	EnvPath = "GIRA_HTTP_CASSETTE"
+	// DefaultPath is the cassette file used when EnvPath is not set
+	DefaultPath = "gira-cassette.yaml"
+
+	// MultipartBoundary is the multipart boundary clients should use while a
+	// cassette is in use, so that recorded uploads match on replay
+	MultipartBoundary = "gira-cassette-boundary"
+
+	scrubbedValue = "***scrubbed***"
+)
+
+var (
+	// scrubbedHeaders are never written to a cassette
+	scrubbedHeaders = map[string]bool{
+		"Authorization": true,
+		"Cookie":        true,
+		"Set-Cookie":    true,
+	}
+
+	// sensitiveFields matches JSON string values of credential-like fields
+	sensitiveFields = regexp.MustCompile(`(?i)("(?:token|password|secret|api_?key|access_?token)"\s*:\s*)"[^"]*"`)
+)
+
+// Cassette is the on-disk list of recorded interactions
+type Cassette struct {
+	Interactions []Interaction `yaml:"interactions"`
+}
+
+// Interaction is a recorded request/response pair
+type Interaction struct {
+	Request  Request  `yaml:"request"`
+	Response Response `yaml:"response"`
+}
+
+// Request is the recorded part of an HTTP request. The URL is stored without
+// scheme and host so that cassettes can be replayed against any base URL.
+// Non-text bodies, such as uploads, are only stored as a hash.
+type Request struct {
+	Method   string `yaml:"method"`
+	URL      string `yaml:"url"`
+	Body     string `yaml:"body,omitempty"`
+	BodyHash string `yaml:"bodyHash,omitempty"`
+}
+
+// Response is the recorded part of an HTTP response. Non-text bodies, such
+// as downloads, are only stored as a hash and replayed empty.
+type Response struct {
+	Status   int                 `yaml:"status"`
+	Headers  map[string][]string `yaml:"headers,omitempty"`
+	Body     string              `yaml:"body,omitempty"`
+	BodyHash string              `yaml:"bodyHash,omitempty"`
+}
+
+// ModeFromEnv returns the mode selected through GIRA_HTTP_MODE
+func ModeFromEnv() (Mode, error) {
+	mode := Mode(strings.ToLower(strings.TrimSpace(os.Getenv(EnvMode))))
+	switch mode {
+	case ModeDisabled, ModeRecord, ModeReplay:
+		return mode, nil
+	default:
+		return ModeDisabled, fmt.Errorf("unsupported %s value %q (expected record or replay)", EnvMode, mode)
+	}
+}
+
+// PathFromEnv returns the cassette path selected through GIRA_HTTP_CASSETTE
+func PathFromEnv() string {
+	if path := os.Getenv(EnvPath); path != "" {
+		return path
+	}
+	return DefaultPath
+}
+
+// Load reads a cassette from disk
+func Load(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+
+	var c Cassette
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+	}
+
+	return &c, nil
+}
+
+// Save writes the cassette to disk, creating parent directories as needed
+func (c *Cassette) Save(path string) error {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cassette: %w", err)
+	}
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create cassette directory: %w", err)
+		}
+	}
+
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+
+	return nil
+}
+
+// Recorder is an http.RoundTripper that records interactions to a cassette
+// or replays them from it
+type Recorder struct {
+	mode     Mode
+	path     string
+	next     http.RoundTripper
+	cassette *Cassette
+
+	mu sync.Mutex
+	// replayed tracks how many times each interaction was served
+	replayed map[int]int
+}
+
+// NewRecorder creates a recorder for the given mode. In record mode requests
+// are forwarded to next and the cassette is rewritten after every
+// interaction; in replay mode next is never called.
+func NewRecorder(mode Mode, path string, next http.RoundTripper) (*Recorder, error) {
+	r := &Recorder{
+		mode:     mode,
+		path:     path,
+		next:     next,
+		cassette: &Cassette{},
+		replayed: make(map[int]int),
+	}
+
+	switch mode {
+	case ModeRecord:
+		if r.next == nil {
+			r.next = http.DefaultTransport
+		}
+	case ModeReplay:
+		c, err := Load(path)
+		if err != nil {
+			return nil, err
+		}
+		r.cassette = c
+	default:
+		return nil, fmt.Errorf("unsupported cassette mode %q", mode)
+	}
+
+	return r, nil
+}
+
+// Wrap returns a transport middleware that installs the recorder
+func Wrap(mode Mode, path string) (func(http.RoundTripper) http.RoundTripper, error) {
+	if mode == ModeReplay {
+		// Fail early rather than on the first request
+		if _, err := Load(path); err != nil {
+			return nil, err
+		}
+	}
+
+	return func(next http.RoundTripper) http.RoundTripper {
+		r, err := NewRecorder(mode, path, next)
+		if err != nil {
+			return errorTransport{err: err}
+		}
+		return r
+	}, nil
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, req, err := requestBody(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+
+	recorded := Request{
+		Method: req.Method,
+		URL:    req.URL.RequestURI(),
+	}
+	recorded.Body, recorded.BodyHash = recordBody(req.Header, body)
+
+	if r.mode == ModeReplay {
+		return r.replay(req, recorded)
+	}
+
+	return r.record(req, recorded)
+}
+
+func (r *Recorder) record(req *http.Request, recorded Request) (*http.Response, error) {
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	headers := make(map[string][]string, len(resp.Header))
+	for name, values := range resp.Header {
+		if scrubbedHeaders[http.CanonicalHeaderKey(name)] {
+			continue
+		}
+		headers[name] = values
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	recordedResp := Response{
+		Status:  resp.StatusCode,
+		Headers: headers,
+	}
+	recordedResp.Body, recordedResp.BodyHash = recordBody(resp.Header, body)
+
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request:  recorded,
+		Response: recordedResp,
+	})
+
+	if err := r.cassette.Save(r.path); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// replay serves the first matching interaction that has not been served yet;
+// once all matches were served the last one is repeated
+func (r *Recorder) replay(req *http.Request, recorded Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	last := -1
+	for i, interaction := range r.cassette.Interactions {
+		if interaction.Request != recorded {
+			continue
+		}
+
+		last = i
+		if r.replayed[i] == 0 {
+			break
+		}
+	}
+
+	if last < 0 {
+		return nil, &NoInteractionError{Request: recorded, Path: r.path}
+	}
+
+	r.replayed[last]++
+	recordedResp := r.cassette.Interactions[last].Response
+
+	header := make(http.Header, len(recordedResp.Headers))
+	for name, values := range recordedResp.Headers {
+		header[name] = values
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recordedResp.Status, http.StatusText(recordedResp.Status)),
+		StatusCode:    recordedResp.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(recordedResp.Body)),
+		ContentLength: int64(len(recordedResp.Body)),
+		Request:       req,
+	}, nil
+}
+
+// requestBody returns the body of a request with the request to send. A
+// RoundTripper must not modify the request it is given, so the body is read
+// through GetBody when possible, and from a clone of the request otherwise.
+func requestBody(req *http.Request) ([]byte, *http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, req, nil
+	}
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, nil, err
+		}
+		defer func() {
+			_ = body.Close()
+		}()
+
+		data, err := io.ReadAll(body)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return data, req, nil
+	}
+
+	clone := req.Clone(req.Context())
+	data, err := readBody(&clone.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return data, clone, nil
+}
+
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+
+	data, err := io.ReadAll(*body)
+	_ = (*body).Close()
+	if err != nil {
+		return nil, err
+	}
+
+	*body = io.NopCloser(bytes.NewReader(data))
+
+	return data, nil
+}
+
+// recordBody returns the scrubbed body to store when it is text, or the hash
+// of the body otherwise
+func recordBody(header http.Header, body []byte) (string, string) {
+	if len(body) == 0 {
+		return "", ""
+	}
+
+	if isText(header) {
+		return scrub(string(body)), ""
+	}
+
+	sum := sha256.Sum256(body)
+
+	return "", hex.EncodeToString(sum[:])
+}
+
+// isText reports whether a body can be stored as is, mirroring the bodies
+// logged by the client
+func isText(header http.Header) bool {
+	contentType := header.Get("Content-Type")
+
+	return contentType == "" || strings.Contains(contentType, "json") || strings.HasPrefix(contentType, "text/")
+}
+
+func scrub(body string) string {
+	return sensitiveFields.ReplaceAllString(body, `$1"`+scrubbedValue+`"`)
+}
+
+// NoInteractionError is returned in replay mode when a request has no
+// recorded counterpart
+type NoInteractionError struct {
+	Request Request
+	Path    string
+}
+
+func (e *NoInteractionError) Error() string {
+	return fmt.Sprintf("no recorded interaction for %s %s in cassette %s", e.Request.Method, e.Request.URL, e.Path)
+}
+
+// Retryable reports that replaying the same request cannot succeed
+func (e *NoInteractionError) Retryable() bool {
+	return false
+}
+
+// errorTransport fails every request with the recorder creation error
+type errorTransport struct {
+	err error
+}
+
+func (t errorTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, t.err
+}
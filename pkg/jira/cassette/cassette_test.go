@@ -0,0 +1,103 @@
+package cassette_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lburgazzoli/gira/pkg/jira/cassette"
+)
+
+// trackedBody records whether it was read
+type trackedBody struct {
+	io.Reader
+	read bool
+}
+
+func (b *trackedBody) Read(p []byte) (int, error) {
+	b.read = true
+	return b.Reader.Read(p)
+}
+
+func (b *trackedBody) Close() error {
+	return nil
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"echo":` + string(body) + `}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	path := filepath.Join(t.TempDir(), "cassette.yaml")
+	const payload = `{"summary":"New issue"}`
+
+	tests := []struct {
+		name string
+		// newRequest builds the request, with or without GetBody
+		newRequest func(t *testing.T) (*http.Request, *trackedBody)
+	}{
+		{
+			name: "with GetBody",
+			newRequest: func(t *testing.T) (*http.Request, *trackedBody) {
+				req, err := http.NewRequest(http.MethodPost, srv.URL+"/rest/api/2/issue", strings.NewReader(payload))
+				if err != nil {
+					t.Fatal(err)
+				}
+				body := &trackedBody{Reader: strings.NewReader(payload)}
+				req.Body = body
+				return req, body
+			},
+		},
+		{
+			name: "without GetBody",
+			newRequest: func(t *testing.T) (*http.Request, *trackedBody) {
+				body := &trackedBody{Reader: strings.NewReader(payload)}
+				req, err := http.NewRequest(http.MethodPost, srv.URL+"/rest/api/2/issue", body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return req, body
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, mode := range []cassette.Mode{cassette.ModeRecord, cassette.ModeReplay} {
+				recorder, err := cassette.NewRecorder(mode, path, http.DefaultTransport)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				req, body := tt.newRequest(t)
+				req.Header.Set("Content-Type", "application/json")
+
+				resp, err := recorder.RoundTrip(req)
+				if err != nil {
+					t.Fatalf("%s: %v", mode, err)
+				}
+				got, err := io.ReadAll(resp.Body)
+				_ = resp.Body.Close()
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if want := `{"echo":` + payload + `}`; string(got) != want {
+					t.Errorf("%s: response = %s, want %s", mode, got, want)
+				}
+				if req.Body != body {
+					t.Errorf("%s: the request body was replaced", mode)
+				}
+				if mode == cassette.ModeReplay && req.GetBody != nil && body.read {
+					t.Errorf("replay read the request body although GetBody is set")
+				}
+			}
+		})
+	}
+}
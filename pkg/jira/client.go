@@ -1,12 +1,16 @@
 package jira
 
 import (
-	"context"
+	"crypto/tls"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"net/url"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/hashicorp/go-cleanhttp"
 	"github.com/hashicorp/go-retryablehttp"
 )
 
@@ -17,12 +21,29 @@ const (
 	headerAuthorization = "Authorization"
 	headerContentType   = "Content-Type"
 	headerAccept        = "Accept"
+	headerUserAgent     = "User-Agent"
+
+	// defaultUserAgent is sent when no user agent is configured
+	defaultUserAgent = "gira"
 	
 	// JIRA API endpoints
 	apiIssueEndpoint   = "/rest/api/2/issue/%s"
 	apiCreateEndpoint  = "/rest/api/2/issue"
 	apiSearchEndpoint  = "/rest/api/2/search"
 	apiProjectEndpoint = "/rest/api/2/project/%s"
+	apiMyselfEndpoint  = "/rest/api/2/myself"
+
+	apiChangelogEndpoint = "/rest/api/2/issue/%s/changelog"
+	apiCommentEndpoint   = "/rest/api/2/issue/%s/comment"
+	apiFieldEndpoint     = "/rest/api/2/field"
+	apiStatusEndpoint    = "/rest/api/2/status"
+
+	// changelogPageSize is the number of changelog entries fetched per request
+	changelogPageSize = 100
+	// commentPageSize is the number of comments fetched per request
+	commentPageSize = 100
+	// searchPageSize is the number of issues fetched per search request
+	searchPageSize = 100
 	
 	// URL prefixes
 	httpPrefix  = "http://"
@@ -33,6 +54,24 @@ type Client struct {
 	baseURL         string
 	retryableClient *retryablehttp.Client
 	auth            authConfig
+
+	httpClient  *http.Client
+	tlsConfig   *tls.Config
+	proxyURL    *url.URL
+	timeout     time.Duration
+	retryPolicy RetryPolicy
+	rateLimit   float64
+	rateBurst   int
+	userAgent   string
+	logger      *slog.Logger
+	middlewares []func(http.RoundTripper) http.RoundTripper
+	cache       Cache
+	cachePolicy CachePolicy
+	// cacheIdentity keys cached responses by credentials
+	cacheIdentity string
+	pruneOnce     sync.Once
+	// multipartBoundary overrides the random boundary of uploads
+	multipartBoundary string
 }
 
 type authConfig struct {
@@ -43,7 +82,7 @@ type AuthConfig struct {
 	Token string
 }
 
-func NewClient(baseURL string, auth AuthConfig) (*Client, error) {
+func NewClient(baseURL string, auth AuthConfig, opts ...Option) (*Client, error) {
 	if baseURL == "" {
 		return nil, fmt.Errorf("base URL cannot be empty")
 	}
@@ -56,39 +95,144 @@ func NewClient(baseURL string, auth AuthConfig) (*Client, error) {
 		baseURL = httpsPrefix + baseURL
 	}
 
-	// Create retryable HTTP client with rate limiting
+	c := &Client{
+		baseURL: baseURL,
+		auth: authConfig{
+			token: auth.Token,
+		},
+		retryPolicy:   DefaultRetryPolicy(),
+		userAgent:     defaultUserAgent,
+		logger:        discardLogger(),
+		cacheIdentity: cacheIdentity(auth.Token),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.logger == nil {
+		c.logger = discardLogger()
+	}
+
+	httpClient, err := c.newHTTPClient()
+	if err != nil {
+		return nil, err
+	}
+
+	// Create retryable HTTP client honoring the server rate limiting hints
 	retryClient := retryablehttp.NewClient()
-	retryClient.RetryMax = 3
-	retryClient.RetryWaitMin = 1 * time.Second
-	retryClient.RetryWaitMax = 30 * time.Second
-	retryClient.Backoff = retryablehttp.DefaultBackoff
-	retryClient.Logger = nil // Disable debug logging
-
-	// Configure which HTTP status codes to retry
-	retryClient.CheckRetry = func(ctx context.Context, resp *http.Response, err error) (bool, error) {
-		// Default retry logic for network errors
-		if err != nil {
-			return retryablehttp.DefaultRetryPolicy(ctx, resp, err)
-		}
+	retryClient.HTTPClient = httpClient
+	retryClient.RetryMax = c.retryPolicy.MaxRetries
+	retryClient.RetryWaitMin = c.retryPolicy.WaitMin
+	retryClient.RetryWaitMax = c.retryPolicy.WaitMax
+	retryClient.Backoff = backoff
+	retryClient.CheckRetry = checkRetry
+	retryClient.Logger = nil // Attempts and retries are logged by the hooks below
+	retryClient.RequestLogHook = c.logRetry
 
-		// Retry on rate limiting
-		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
-			return true, nil
-		}
+	c.retryableClient = retryClient
 
-		// Use default retry policy for other cases
-		return retryablehttp.DefaultRetryPolicy(ctx, resp, err)
+	return c, nil
+}
+
+// newHTTPClient returns the HTTP client configured through the options, or a
+// pooled client honoring the TLS, proxy and timeout settings
+func (c *Client) newHTTPClient() (*http.Client, error) {
+	if c.httpClient != nil {
+		httpClient := *c.httpClient
+		return c.withLogging(c.withTimeout(c.withRateLimit(c.withMiddlewares(&httpClient)))), nil
 	}
 
-	return &Client{
-		baseURL:         baseURL,
-		retryableClient: retryClient,
-		auth: authConfig{
-			token: auth.Token,
-		},
-	}, nil
+	httpClient := cleanhttp.DefaultPooledClient()
+
+	transport, ok := httpClient.Transport.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("unexpected HTTP transport type %T", httpClient.Transport)
+	}
+
+	if c.tlsConfig != nil {
+		transport.TLSClientConfig = c.tlsConfig
+	}
+	if c.proxyURL != nil {
+		transport.Proxy = http.ProxyURL(c.proxyURL)
+	}
+
+	return c.withLogging(c.withTimeout(c.withRateLimit(c.withMiddlewares(httpClient)))), nil
+}
+
+// withMiddlewares wraps the client transport with the configured middlewares
+func (c *Client) withMiddlewares(httpClient *http.Client) *http.Client {
+	if len(c.middlewares) == 0 {
+		return httpClient
+	}
+
+	transport := httpClient.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	for _, middleware := range c.middlewares {
+		transport = middleware(transport)
+	}
+
+	httpClient.Transport = transport
+
+	return httpClient
+}
+
+// withLogging wraps the client transport so that every attempt is logged
+func (c *Client) withLogging(httpClient *http.Client) *http.Client {
+	next := httpClient.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	httpClient.Transport = &loggingTransport{
+		next:   next,
+		logger: c.logger,
+	}
+
+	return httpClient
+}
+
+// withTimeout wraps the client transport so that every attempt waits at most
+// the configured timeout for a response
+func (c *Client) withTimeout(httpClient *http.Client) *http.Client {
+	if c.timeout <= 0 {
+		return httpClient
+	}
+
+	next := httpClient.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	httpClient.Transport = &timeoutTransport{
+		next:    next,
+		timeout: c.timeout,
+	}
+
+	return httpClient
 }
 
+// withRateLimit wraps the client transport with the client-side rate limiter
+func (c *Client) withRateLimit(httpClient *http.Client) *http.Client {
+	if c.rateLimit <= 0 {
+		return httpClient
+	}
+
+	next := httpClient.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	httpClient.Transport = &rateLimitedTransport{
+		next:    next,
+		limiter: newRateLimiter(c.rateLimit, c.rateBurst),
+	}
+
+	return httpClient
+}
 
 // JIRA Operations
 
@@ -117,6 +261,33 @@ func (c *Client) CreateIssue(issue *Issue) (*Issue, error) {
 		return nil, err
 	}
 
+	c.invalidateIssue("")
+
+	return &createdIssue, nil
+}
+
+// CreateIssueWithFields creates an issue from raw field values keyed by field
+// ID, which unlike CreateIssue may include custom fields. The entity
+// properties are stored in the same request, so the issue never exists
+// without them.
+func (c *Client) CreateIssueWithFields(fields map[string]interface{}, properties ...EntityProperty) (*Issue, error) {
+	body := map[string]interface{}{"fields": fields}
+	if len(properties) > 0 {
+		body["properties"] = properties
+	}
+
+	resp, err := c.post(apiCreateEndpoint, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create issue: %w", err)
+	}
+
+	var createdIssue Issue
+	if err := handleResponse(resp, &createdIssue); err != nil {
+		return nil, err
+	}
+
+	c.invalidateIssue("")
+
 	return &createdIssue, nil
 }
 
@@ -130,29 +301,57 @@ func (c *Client) UpdateIssue(key string, update IssueUpdate) (*Issue, error) {
 		return nil, err
 	}
 
+	c.invalidateIssue(key)
+
 	return c.GetIssue(key)
 }
 
 func (c *Client) SearchIssues(jql string, startAt, maxResults int, fields []string) (*SearchResult, error) {
-	var params []Parameter
-	params = append(params, Parameter{Key: "jql", Value: jql})
-	
-	// Add pagination parameters
-	params = append(params, Parameter{Key: "startAt", Value: fmt.Sprintf("%d", startAt)})
-	params = append(params, Parameter{Key: "maxResults", Value: fmt.Sprintf("%d", maxResults)})
-	
-	if len(fields) > 0 {
-		for _, field := range fields {
-			params = append(params, Parameter{Key: "fields", Value: field})
+	resp, err := c.get(apiSearchEndpoint, searchParams(jql, startAt, maxResults, fields)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search issues: %w", err)
+	}
+
+	var result SearchResult
+	if err := handleResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// SearchAllIssues returns all the issues matching a query, fetching them page
+// by page
+func (c *Client) SearchAllIssues(jql string, fields []string) ([]Issue, error) {
+	issues := make([]Issue, 0)
+
+	for startAt := 0; ; {
+		result, err := c.SearchIssues(jql, startAt, searchPageSize, fields)
+		if err != nil {
+			return nil, err
+		}
+
+		issues = append(issues, result.Issues...)
+
+		if len(result.Issues) == 0 || startAt+len(result.Issues) >= result.Total {
+			break
 		}
+
+		startAt += len(result.Issues)
 	}
 
-	resp, err := c.get(apiSearchEndpoint, params...)
+	return issues, nil
+}
+
+// SearchRawIssues is like SearchIssues but keeps the issues as returned by
+// the server, including the custom fields that are not part of Issue
+func (c *Client) SearchRawIssues(jql string, startAt, maxResults int, fields []string) (*RawSearchResult, error) {
+	resp, err := c.get(apiSearchEndpoint, searchParams(jql, startAt, maxResults, fields)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search issues: %w", err)
 	}
 
-	var result SearchResult
+	var result RawSearchResult
 	if err := handleResponse(resp, &result); err != nil {
 		return nil, err
 	}
@@ -160,8 +359,25 @@ func (c *Client) SearchIssues(jql string, startAt, maxResults int, fields []stri
 	return &result, nil
 }
 
+func searchParams(jql string, startAt, maxResults int, fields []string) []Parameter {
+	var params []Parameter
+	params = append(params, Parameter{Key: "jql", Value: jql})
+
+	// Add pagination parameters
+	params = append(params, Parameter{Key: "startAt", Value: fmt.Sprintf("%d", startAt)})
+	params = append(params, Parameter{Key: "maxResults", Value: fmt.Sprintf("%d", maxResults)})
+
+	for _, field := range fields {
+		params = append(params, Parameter{Key: "fields", Value: field})
+	}
+
+	return params
+}
+
+// GetProject returns a project with its lead, components, versions, issue
+// types and roles
 func (c *Client) GetProject(key string) (*Project, error) {
-	resp, err := c.get(fmt.Sprintf(apiProjectEndpoint, key))
+	resp, err := c.get(fmt.Sprintf(apiProjectEndpoint, key), Parameter{Key: "expand", Value: projectExpand})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get project: %w", err)
 	}
@@ -173,3 +389,138 @@ func (c *Client) GetProject(key string) (*Project, error) {
 
 	return &project, nil
 }
+
+func (c *Client) Myself() (*User, error) {
+	resp, err := c.get(apiMyselfEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current user: %w", err)
+	}
+
+	var user User
+	if err := handleResponse(resp, &user); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// GetChangelog returns the full change history of an issue, oldest first.
+// Instances that do not provide the paginated changelog endpoint (JIRA Data
+// Center) are queried through the issue endpoint with expand=changelog.
+func (c *Client) GetChangelog(key string) ([]ChangeHistory, error) {
+	histories := make([]ChangeHistory, 0)
+	startAt := 0
+
+	for {
+		resp, err := c.get(fmt.Sprintf(apiChangelogEndpoint, key),
+			Parameter{Key: "startAt", Value: fmt.Sprintf("%d", startAt)},
+			Parameter{Key: "maxResults", Value: fmt.Sprintf("%d", changelogPageSize)},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get changelog: %w", err)
+		}
+
+		if resp.StatusCode == http.StatusNotFound && startAt == 0 {
+			_ = resp.Body.Close()
+			return c.getExpandedChangelog(key)
+		}
+
+		var page ChangelogPage
+		if err := handleResponse(resp, &page); err != nil {
+			return nil, err
+		}
+
+		histories = append(histories, page.Values...)
+
+		if page.IsLast || len(page.Values) == 0 || startAt+len(page.Values) >= page.Total {
+			break
+		}
+
+		startAt += len(page.Values)
+	}
+
+	return histories, nil
+}
+
+func (c *Client) getExpandedChangelog(key string) ([]ChangeHistory, error) {
+	resp, err := c.get(fmt.Sprintf(apiIssueEndpoint, key),
+		Parameter{Key: "expand", Value: "changelog"},
+		Parameter{Key: "fields", Value: "created"},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get changelog: %w", err)
+	}
+
+	var issue struct {
+		Changelog struct {
+			Histories []ChangeHistory `json:"histories"`
+		} `json:"changelog"`
+	}
+	if err := handleResponse(resp, &issue); err != nil {
+		return nil, err
+	}
+
+	return issue.Changelog.Histories, nil
+}
+
+// GetComments returns all the comments of an issue, oldest first
+func (c *Client) GetComments(key string) ([]Comment, error) {
+	comments := make([]Comment, 0)
+	startAt := 0
+
+	for {
+		resp, err := c.get(fmt.Sprintf(apiCommentEndpoint, key),
+			Parameter{Key: "startAt", Value: fmt.Sprintf("%d", startAt)},
+			Parameter{Key: "maxResults", Value: fmt.Sprintf("%d", commentPageSize)},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get comments: %w", err)
+		}
+
+		var page CommentPage
+		if err := handleResponse(resp, &page); err != nil {
+			return nil, err
+		}
+
+		comments = append(comments, page.Comments...)
+
+		if len(page.Comments) == 0 || startAt+len(page.Comments) >= page.Total {
+			break
+		}
+
+		startAt += len(page.Comments)
+	}
+
+	return comments, nil
+}
+
+// GetFields returns the system and custom fields known to the instance
+func (c *Client) GetFields() ([]Field, error) {
+	resp, err := c.get(apiFieldEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fields: %w", err)
+	}
+
+	var fields []Field
+	if err := handleResponse(resp, &fields); err != nil {
+		return nil, err
+	}
+
+	return fields, nil
+}
+
+// GetStatuses returns the workflow statuses known to the instance, with
+// their status category
+func (c *Client) GetStatuses() ([]Status, error) {
+	resp, err := c.get(apiStatusEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get statuses: %w", err)
+	}
+
+	var statuses []Status
+	if err := handleResponse(resp, &statuses); err != nil {
+		return nil, err
+	}
+
+	return statuses, nil
+}
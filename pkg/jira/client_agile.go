@@ -0,0 +1,231 @@
+package jira
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// JIRA Agile API endpoints
+	agileBoardsEndpoint       = "/rest/agile/1.0/board"
+	agileBoardEndpoint        = "/rest/agile/1.0/board/%d"
+	agileBoardSprintsEndpoint = "/rest/agile/1.0/board/%d/sprint"
+	agileSprintEndpoint       = "/rest/agile/1.0/sprint/%d"
+	agileSprintIssuesEndpoint = "/rest/agile/1.0/sprint/%d/issue"
+
+	// agilePageSize is the number of values fetched per Agile API request
+	agilePageSize = 50
+	// maxSprintIssues is the number of issues that can be moved to a sprint at once
+	maxSprintIssues = 50
+)
+
+// Sprint states, as accepted by ListSprints
+const (
+	SprintStateFuture = "future"
+	SprintStateActive = "active"
+	SprintStateClosed = "closed"
+)
+
+// BoardFilter restricts the boards returned by ListBoards. Empty fields match
+// any board.
+type BoardFilter struct {
+	// ProjectKey selects the boards of a project
+	ProjectKey string
+	// Type is either scrum or kanban
+	Type string
+	// Name matches boards whose name contains the value
+	Name string
+}
+
+// ListBoards returns the Agile boards visible to the user
+func (c *Client) ListBoards(filter BoardFilter) ([]Board, error) {
+	params := make([]Parameter, 0, 3)
+	if filter.ProjectKey != "" {
+		params = append(params, Parameter{Key: "projectKeyOrId", Value: filter.ProjectKey})
+	}
+	if filter.Type != "" {
+		params = append(params, Parameter{Key: "type", Value: filter.Type})
+	}
+	if filter.Name != "" {
+		params = append(params, Parameter{Key: "name", Value: filter.Name})
+	}
+
+	boards, err := getAgilePages[Board](c, agileBoardsEndpoint, params...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list boards: %w", err)
+	}
+
+	return boards, nil
+}
+
+// GetBoard returns an Agile board by ID
+func (c *Client) GetBoard(id int) (*Board, error) {
+	resp, err := c.get(fmt.Sprintf(agileBoardEndpoint, id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get board: %w", err)
+	}
+
+	var board Board
+	if err := handleResponse(resp, &board); err != nil {
+		return nil, err
+	}
+
+	return &board, nil
+}
+
+// FindBoard returns a board by ID or by name. Names match ignoring case;
+// a partial name is accepted when it identifies a single board.
+func (c *Client) FindBoard(ref string) (*Board, error) {
+	if id, err := strconv.Atoi(ref); err == nil {
+		return c.GetBoard(id)
+	}
+
+	boards, err := c.ListBoards(BoardFilter{Name: ref})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range boards {
+		if strings.EqualFold(boards[i].Name, ref) {
+			return &boards[i], nil
+		}
+	}
+
+	switch len(boards) {
+	case 0:
+		return nil, fmt.Errorf("no board matches %q", ref)
+	case 1:
+		return &boards[0], nil
+	default:
+		names := make([]string, 0, len(boards))
+		for _, b := range boards {
+			names = append(names, fmt.Sprintf("%s (%d)", b.Name, b.ID))
+		}
+		return nil, fmt.Errorf("%q matches several boards: %s", ref, strings.Join(names, ", "))
+	}
+}
+
+// ListSprints returns the sprints of a Scrum board. The state is a comma
+// separated list of future, active and closed; empty means all sprints.
+func (c *Client) ListSprints(boardID int, state string) ([]Sprint, error) {
+	params := make([]Parameter, 0, 1)
+	if state != "" {
+		params = append(params, Parameter{Key: "state", Value: state})
+	}
+
+	sprints, err := getAgilePages[Sprint](c, fmt.Sprintf(agileBoardSprintsEndpoint, boardID), params...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sprints: %w", err)
+	}
+
+	return sprints, nil
+}
+
+// GetSprint returns a sprint by ID
+func (c *Client) GetSprint(id int) (*Sprint, error) {
+	resp, err := c.get(fmt.Sprintf(agileSprintEndpoint, id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sprint: %w", err)
+	}
+
+	var sprint Sprint
+	if err := handleResponse(resp, &sprint); err != nil {
+		return nil, err
+	}
+
+	return &sprint, nil
+}
+
+// GetSprintIssues returns all the issues of a sprint, optionally narrowed by a
+// JQL query
+func (c *Client) GetSprintIssues(sprintID int, jql string, fields []string) ([]Issue, error) {
+	issues := make([]Issue, 0)
+	startAt := 0
+
+	for {
+		params := searchParams(jql, startAt, agilePageSize, fields)
+		if jql == "" {
+			// Drop the empty jql parameter, which the sprint endpoint rejects
+			params = params[1:]
+		}
+
+		resp, err := c.get(fmt.Sprintf(agileSprintIssuesEndpoint, sprintID), params...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get sprint issues: %w", err)
+		}
+
+		var page SearchResult
+		if err := handleResponse(resp, &page); err != nil {
+			return nil, err
+		}
+
+		issues = append(issues, page.Issues...)
+
+		if len(page.Issues) == 0 || startAt+len(page.Issues) >= page.Total {
+			break
+		}
+
+		startAt += len(page.Issues)
+	}
+
+	return issues, nil
+}
+
+// MoveIssuesToSprint moves issues to a sprint, in batches of the size
+// accepted by JIRA
+func (c *Client) MoveIssuesToSprint(sprintID int, keys []string) error {
+	for start := 0; start < len(keys); start += maxSprintIssues {
+		batch := keys[start:min(start+maxSprintIssues, len(keys))]
+
+		resp, err := c.post(fmt.Sprintf(agileSprintIssuesEndpoint, sprintID), map[string]any{
+			"issues": batch,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to move issues to sprint: %w", err)
+		}
+
+		if err := handleResponse(resp, nil); err != nil {
+			return fmt.Errorf("failed to move %s to sprint %d: %w", strings.Join(batch, ", "), sprintID, err)
+		}
+
+		for _, key := range batch {
+			c.invalidateIssue(key)
+		}
+	}
+
+	return nil
+}
+
+// getAgilePages fetches all the values of a paginated Agile API endpoint
+func getAgilePages[T any](c *Client, endpoint string, params ...Parameter) ([]T, error) {
+	values := make([]T, 0)
+	startAt := 0
+
+	for {
+		pageParams := append([]Parameter{
+			{Key: "startAt", Value: strconv.Itoa(startAt)},
+			{Key: "maxResults", Value: strconv.Itoa(agilePageSize)},
+		}, params...)
+
+		resp, err := c.get(endpoint, pageParams...)
+		if err != nil {
+			return nil, err
+		}
+
+		var page agilePage[T]
+		if err := handleResponse(resp, &page); err != nil {
+			return nil, err
+		}
+
+		values = append(values, page.Values...)
+
+		if page.IsLast || len(page.Values) == 0 || (page.Total > 0 && startAt+len(page.Values) >= page.Total) {
+			break
+		}
+
+		startAt += len(page.Values)
+	}
+
+	return values, nil
+}
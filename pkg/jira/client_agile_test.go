@@ -0,0 +1,230 @@
+package jira_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/lburgazzoli/gira/pkg/jira"
+)
+
+func boardNames(boards []jira.Board) []string {
+	names := make([]string, 0, len(boards))
+	for _, b := range boards {
+		names = append(names, b.Name)
+	}
+	return names
+}
+
+func TestListBoards(t *testing.T) {
+	srv, client := newTestClient(t, nil)
+
+	srv.AddBoard(jira.Board{Name: "Demo Scrum", Type: "scrum", Location: &jira.BoardLocation{ProjectKey: "DEMO"}})
+	srv.AddBoard(jira.Board{Name: "Demo Kanban", Type: "kanban", Location: &jira.BoardLocation{ProjectKey: "DEMO"}})
+	srv.AddBoard(jira.Board{Name: "Other Scrum", Type: "scrum", Location: &jira.BoardLocation{ProjectKey: "OTHER"}})
+
+	tests := []struct {
+		name   string
+		filter jira.BoardFilter
+		want   []string
+	}{
+		{name: "all", want: []string{"Demo Scrum", "Demo Kanban", "Other Scrum"}},
+		{name: "project", filter: jira.BoardFilter{ProjectKey: "DEMO"}, want: []string{"Demo Scrum", "Demo Kanban"}},
+		{name: "type", filter: jira.BoardFilter{Type: "scrum"}, want: []string{"Demo Scrum", "Other Scrum"}},
+		{name: "name", filter: jira.BoardFilter{Name: "kanban"}, want: []string{"Demo Kanban"}},
+		{name: "no match", filter: jira.BoardFilter{ProjectKey: "NONE"}, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			boards, err := client.ListBoards(tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := boardNames(boards); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("boards = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListBoardsPagination(t *testing.T) {
+	srv, client := newTestClient(t, nil)
+	for i := 1; i <= 120; i++ {
+		srv.AddBoard(jira.Board{Name: fmt.Sprintf("Board %d", i)})
+	}
+
+	boards, err := client.ListBoards(jira.BoardFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(boards) != 120 {
+		t.Errorf("got %d boards, want 120", len(boards))
+	}
+	if got := countRequests(srv, http.MethodGet, "/rest/agile/1.0/board"); got != 3 {
+		t.Errorf("requests = %d, want 3 pages", got)
+	}
+}
+
+func TestFindBoard(t *testing.T) {
+	srv, client := newTestClient(t, nil)
+	srv.AddBoard(jira.Board{ID: 7, Name: "Platform"})
+	srv.AddBoard(jira.Board{ID: 8, Name: "Platform Ops"})
+	srv.AddBoard(jira.Board{ID: 9, Name: "Mobile"})
+
+	tests := []struct {
+		ref     string
+		wantID  int
+		wantErr string
+	}{
+		{ref: "8", wantID: 8},
+		{ref: "platform", wantID: 7},
+		{ref: "mob", wantID: 9},
+		{ref: "form", wantErr: "matches several boards"},
+		{ref: "web", wantErr: "no board matches"},
+		{ref: "42", wantErr: "Board does not exist"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			board, err := client.FindBoard(tt.ref)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if board.ID != tt.wantID {
+				t.Errorf("board = %d, want %d", board.ID, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestListSprints(t *testing.T) {
+	srv, client := newTestClient(t, nil)
+	board := srv.AddBoard(jira.Board{Name: "Demo"})
+	other := srv.AddBoard(jira.Board{Name: "Other"})
+
+	for _, sprint := range []jira.Sprint{
+		{Name: "Sprint 1", State: jira.SprintStateClosed, OriginBoardID: board.ID},
+		{Name: "Sprint 2", State: jira.SprintStateActive, OriginBoardID: board.ID},
+		{Name: "Sprint 3", State: jira.SprintStateFuture, OriginBoardID: board.ID},
+		{Name: "Elsewhere", State: jira.SprintStateActive, OriginBoardID: other.ID},
+	} {
+		if _, err := srv.AddSprint(sprint); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		state string
+		want  []string
+	}{
+		{state: "", want: []string{"Sprint 1", "Sprint 2", "Sprint 3"}},
+		{state: jira.SprintStateActive, want: []string{"Sprint 2"}},
+		{state: "active,future", want: []string{"Sprint 2", "Sprint 3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run("state "+tt.state, func(t *testing.T) {
+			sprints, err := client.ListSprints(board.ID, tt.state)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := make([]string, 0, len(sprints))
+			for _, s := range sprints {
+				got = append(got, s.Name)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("sprints = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := client.ListSprints(99, ""); err == nil {
+		t.Error("listing the sprints of a missing board succeeded")
+	}
+}
+
+func TestSprintIssues(t *testing.T) {
+	srv, client := newTestClient(t, nil)
+	board := srv.AddBoard(jira.Board{Name: "Demo"})
+	addIssues(srv, "DEMO", 130)
+
+	sprint, err := srv.AddSprint(jira.Sprint{Name: "Sprint 1", State: jira.SprintStateActive, OriginBoardID: board.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed, err := srv.AddSprint(jira.Sprint{Name: "Sprint 0", State: jira.SprintStateClosed, OriginBoardID: board.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := make([]string, 0, 120)
+	for i := 1; i <= 120; i++ {
+		keys = append(keys, fmt.Sprintf("DEMO-%d", i))
+	}
+
+	// More issues than can be moved at once
+	if err := client.MoveIssuesToSprint(sprint.ID, keys); err != nil {
+		t.Fatal(err)
+	}
+	if got := countRequests(srv, http.MethodPost, fmt.Sprintf("/rest/agile/1.0/sprint/%d/issue", sprint.ID)); got != 3 {
+		t.Errorf("move requests = %d, want 3 batches", got)
+	}
+
+	tests := []struct {
+		name string
+		jql  string
+		want int
+	}{
+		{name: "all", want: 120},
+		{name: "narrowed", jql: "key = DEMO-7", want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues, err := client.GetSprintIssues(sprint.ID, tt.jql, []string{"summary"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(issues) != tt.want {
+				t.Errorf("got %d issues, want %d", len(issues), tt.want)
+			}
+		})
+	}
+
+	if err := client.MoveIssuesToSprint(closed.ID, []string{"DEMO-121"}); err == nil {
+		t.Error("moving an issue to a closed sprint succeeded")
+	}
+	if err := client.MoveIssuesToSprint(sprint.ID, []string{"DEMO-999"}); err == nil {
+		t.Error("moving a missing issue succeeded")
+	}
+}
+
+func TestGetSprint(t *testing.T) {
+	srv, client := newTestClient(t, nil)
+	board := srv.AddBoard(jira.Board{Name: "Demo"})
+	stored, err := srv.AddSprint(jira.Sprint{Name: "Sprint 1", Goal: "Ship it", OriginBoardID: board.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sprint, err := client.GetSprint(stored.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sprint.Name != "Sprint 1" || sprint.Goal != "Ship it" || sprint.State != jira.SprintStateFuture {
+		t.Errorf("sprint = %+v", sprint)
+	}
+
+	if _, err := client.GetSprint(99); err == nil {
+		t.Error("getting a missing sprint succeeded")
+	}
+}
@@ -0,0 +1,233 @@
+package jira
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+const (
+	apiIssueAttachmentsEndpoint = "/rest/api/2/issue/%s/attachments"
+	apiAttachmentEndpoint       = "/rest/api/2/attachment/%s"
+	apiAttachmentMetaEndpoint   = "/rest/api/2/attachment/meta"
+	// attachmentContentEndpoint serves the content of attachments that do
+	// not carry a content URL
+	attachmentContentEndpoint = "/secure/attachment/%s/%s"
+
+	// headerAtlassianToken disables the XSRF check JIRA enforces on
+	// multipart requests
+	headerAtlassianToken  = "X-Atlassian-Token"
+	atlassianTokenNoCheck = "no-check"
+
+	// attachmentFormField is the multipart field carrying uploaded files
+	attachmentFormField = "file"
+)
+
+// errBodyReplaced stops the upload of a request body that was superseded
+var errBodyReplaced = errors.New("request body replaced by a retry")
+
+// AttachmentUpload is a file to attach to an issue
+type AttachmentUpload struct {
+	Filename string
+	// Open returns the content of the file. It is called again when the
+	// request is retried, so that files are streamed rather than buffered.
+	Open func() (io.ReadCloser, error)
+}
+
+// ListAttachments returns the attachments of an issue
+func (c *Client) ListAttachments(key string) ([]Attachment, error) {
+	resp, err := c.get(fmt.Sprintf(apiIssueEndpoint, key), Parameter{Key: "fields", Value: "attachment"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachments: %w", err)
+	}
+
+	var issue Issue
+	if err := handleResponse(resp, &issue); err != nil {
+		return nil, err
+	}
+
+	if issue.Fields.Attachments == nil {
+		return []Attachment{}, nil
+	}
+
+	return issue.Fields.Attachments, nil
+}
+
+// GetAttachment returns the metadata of an attachment
+func (c *Client) GetAttachment(id string) (*Attachment, error) {
+	resp, err := c.get(fmt.Sprintf(apiAttachmentEndpoint, id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+
+	var attachment Attachment
+	if err := handleResponse(resp, &attachment); err != nil {
+		return nil, err
+	}
+
+	return &attachment, nil
+}
+
+// GetAttachmentMeta returns whether attachments are enabled and their
+// maximum size
+func (c *Client) GetAttachmentMeta() (*AttachmentMeta, error) {
+	resp, err := c.get(apiAttachmentMetaEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachment settings: %w", err)
+	}
+
+	var meta AttachmentMeta
+	if err := handleResponse(resp, &meta); err != nil {
+		return nil, err
+	}
+
+	return &meta, nil
+}
+
+// UploadAttachment attaches files to an issue in a single multipart request
+// and returns the created attachments
+func (c *Client) UploadAttachment(key string, uploads ...AttachmentUpload) ([]Attachment, error) {
+	if len(uploads) == 0 {
+		return []Attachment{}, nil
+	}
+
+	requestURL, err := c.buildURL(fmt.Sprintf(apiIssueAttachmentsEndpoint, key))
+	if err != nil {
+		return nil, err
+	}
+
+	// The boundary is fixed up front since the body is rebuilt on retries
+	boundary := c.multipartBoundary
+	if boundary == "" {
+		boundary = multipart.NewWriter(io.Discard).Boundary()
+	}
+
+	header := make(http.Header)
+	header.Set(headerContentType, "multipart/form-data; boundary="+boundary)
+	header.Set(headerAtlassianToken, atlassianTokenNoCheck)
+
+	// Each attempt streams the files through a new pipe. Closing the reader
+	// of the previous attempt stops its writer, which would otherwise block
+	// forever when the transport gave up on the body before reading it all.
+	var current *io.PipeReader
+	closeCurrent := func() {
+		if current != nil {
+			_ = current.CloseWithError(errBodyReplaced)
+		}
+	}
+	defer closeCurrent()
+
+	body := retryablehttp.ReaderFunc(func() (io.Reader, error) {
+		closeCurrent()
+
+		pr, pw := io.Pipe()
+		current = pr
+
+		go func() {
+			_ = pw.CloseWithError(writeMultipart(pw, boundary, uploads))
+		}()
+
+		return pr, nil
+	})
+
+	resp, err := c.send(http.MethodPost, requestURL, body, header)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload attachments: %w", err)
+	}
+
+	var attachments []Attachment
+	if err := handleResponse(resp, &attachments); err != nil {
+		return nil, err
+	}
+
+	c.invalidateIssue(key)
+
+	return attachments, nil
+}
+
+// writeMultipart streams the files as a multipart form
+func writeMultipart(w io.Writer, boundary string, uploads []AttachmentUpload) error {
+	mw := multipart.NewWriter(w)
+	if err := mw.SetBoundary(boundary); err != nil {
+		return err
+	}
+
+	for _, upload := range uploads {
+		part, err := mw.CreateFormFile(attachmentFormField, upload.Filename)
+		if err != nil {
+			return err
+		}
+
+		content, err := upload.Open()
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", upload.Filename, err)
+		}
+
+		_, err = io.Copy(part, content)
+		_ = content.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", upload.Filename, err)
+		}
+	}
+
+	return mw.Close()
+}
+
+// DownloadAttachment streams the content of an attachment to w and returns
+// the number of bytes written. A positive maxSize bounds the content read,
+// whatever size the attachment metadata claims; larger content fails.
+func (c *Client) DownloadAttachment(attachment *Attachment, w io.Writer, maxSize int64) (int64, error) {
+	requestURL := attachment.Content
+	if requestURL == "" {
+		u, err := c.buildURL(fmt.Sprintf(attachmentContentEndpoint, attachment.ID, url.PathEscape(attachment.Filename)))
+		if err != nil {
+			return 0, err
+		}
+		requestURL = u
+	} else if !strings.HasPrefix(requestURL, httpPrefix) && !strings.HasPrefix(requestURL, httpsPrefix) {
+		u, err := c.buildURL(requestURL)
+		if err != nil {
+			return 0, err
+		}
+		requestURL = u
+	}
+
+	header := make(http.Header)
+	header.Set(headerAccept, "*/*")
+
+	resp, err := c.send(http.MethodGet, requestURL, nil, header)
+	if err != nil {
+		return 0, fmt.Errorf("failed to download attachment %s: %w", attachment.Filename, err)
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return 0, handleResponse(resp, nil)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	var body io.Reader = resp.Body
+	if maxSize > 0 {
+		// Read one byte more than allowed to detect larger content
+		body = io.LimitReader(resp.Body, maxSize+1)
+	}
+
+	n, err := io.Copy(w, body)
+	if err != nil {
+		return n, fmt.Errorf("failed to download attachment %s: %w", attachment.Filename, err)
+	}
+
+	if maxSize > 0 && n > maxSize {
+		return n, fmt.Errorf("attachment %s exceeds %d bytes", attachment.Filename, maxSize)
+	}
+
+	return n, nil
+}
@@ -0,0 +1,194 @@
+package jira_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/lburgazzoli/gira/pkg/jira"
+	"github.com/lburgazzoli/gira/pkg/jira/jiratest"
+)
+
+// headerRecorder is a transport middleware that keeps the headers of the
+// requests sent
+type headerRecorder struct {
+	mu      sync.Mutex
+	headers []http.Header
+}
+
+func (h *headerRecorder) middleware(next http.RoundTripper) http.RoundTripper {
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		h.mu.Lock()
+		h.headers = append(h.headers, req.Header.Clone())
+		h.mu.Unlock()
+
+		return next.RoundTrip(req)
+	})
+}
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// countingUpload returns an upload of content that counts how often it is opened
+func countingUpload(filename string, content []byte, opens *atomic.Int32) jira.AttachmentUpload {
+	return jira.AttachmentUpload{
+		Filename: filename,
+		Open: func() (io.ReadCloser, error) {
+			opens.Add(1)
+			return io.NopCloser(bytes.NewReader(content)), nil
+		},
+	}
+}
+
+func TestUploadAttachment(t *testing.T) {
+	// Larger than the transport buffers, so that the injected fault answers
+	// before the body was fully sent
+	large := bytes.Repeat([]byte("0123456789abcdef"), 256*1024)
+	small := []byte("hello world")
+
+	tests := []struct {
+		name      string
+		fault     *jiratest.Fault
+		wantOpens int32
+	}{
+		{name: "single attempt", wantOpens: 2},
+		{
+			name:      "retried after 429",
+			fault:     &jiratest.Fault{Status: http.StatusTooManyRequests, Times: 2},
+			wantOpens: 6,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &headerRecorder{}
+			srv, client := newTestClient(t, nil, jira.WithTransportMiddleware(recorder.middleware))
+			addIssues(srv, "DEMO", 1)
+
+			if tt.fault != nil {
+				tt.fault.Method = http.MethodPost
+				tt.fault.Path = "/rest/api/2/issue/DEMO-1/attachments"
+				srv.InjectFault(*tt.fault)
+			}
+
+			var opens atomic.Int32
+			attachments, err := client.UploadAttachment("DEMO-1",
+				countingUpload("large.bin", large, &opens),
+				countingUpload("small.txt", small, &opens),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(attachments) != 2 {
+				t.Fatalf("got %d attachments, want 2", len(attachments))
+			}
+			for i, want := range [][]byte{large, small} {
+				content, ok := srv.AttachmentContent(attachments[i].ID)
+				if !ok {
+					t.Fatalf("attachment %s not stored", attachments[i].Filename)
+				}
+				if !bytes.Equal(content, want) {
+					t.Errorf("%s: stored %d bytes, want %d", attachments[i].Filename, len(content), len(want))
+				}
+			}
+
+			// A retry may stop streaming before opening every file
+			if got := opens.Load(); got > tt.wantOpens {
+				t.Errorf("files opened %d times, want at most %d", got, tt.wantOpens)
+			}
+
+			for _, header := range recorder.headers {
+				if got := header.Get("X-Atlassian-Token"); got != "no-check" {
+					t.Errorf("X-Atlassian-Token = %q, want no-check", got)
+				}
+				if got := header.Get("Content-Type"); !strings.HasPrefix(got, "multipart/form-data; boundary=") {
+					t.Errorf("Content-Type = %q, want multipart/form-data", got)
+				}
+			}
+			if len(recorder.headers) != int(tt.wantOpens/2) {
+				t.Errorf("sent %d requests, want %d", len(recorder.headers), tt.wantOpens/2)
+			}
+		})
+	}
+}
+
+func TestUploadAttachmentBoundary(t *testing.T) {
+	recorder := &headerRecorder{}
+	srv, client := newTestClient(t, nil,
+		jira.WithTransportMiddleware(recorder.middleware),
+		jira.WithMultipartBoundary("fixed-boundary"),
+	)
+	addIssues(srv, "DEMO", 1)
+
+	var opens atomic.Int32
+	if _, err := client.UploadAttachment("DEMO-1", countingUpload("a.txt", []byte("a"), &opens)); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := recorder.headers[0].Get("Content-Type"); got != "multipart/form-data; boundary=fixed-boundary" {
+		t.Errorf("Content-Type = %q", got)
+	}
+}
+
+func TestDownloadAttachment(t *testing.T) {
+	content := []byte("0123456789")
+
+	tests := []struct {
+		name    string
+		maxSize int64
+		// claimedSize overrides the size of the attachment metadata
+		claimedSize int64
+		wantErr     bool
+	}{
+		{name: "unbounded", maxSize: 0},
+		{name: "exact bound", maxSize: 10},
+		{name: "larger bound", maxSize: 1024},
+		{name: "content over the bound", maxSize: 4, wantErr: true},
+		{name: "content over the bound with smaller metadata", maxSize: 4, claimedSize: 2, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, client := newTestClient(t, nil)
+			addIssues(srv, "DEMO", 1)
+
+			var opens atomic.Int32
+			attachments, err := client.UploadAttachment("DEMO-1", countingUpload("digits.txt", content, &opens))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			attachment := attachments[0]
+			if tt.claimedSize > 0 {
+				attachment.Size = tt.claimedSize
+			}
+
+			var buf bytes.Buffer
+			n, err := client.DownloadAttachment(&attachment, &buf, tt.maxSize)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected the download to fail")
+				}
+				if n > tt.maxSize+1 {
+					t.Errorf("read %d bytes, want at most %d", n, tt.maxSize+1)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if n != int64(len(content)) || !bytes.Equal(buf.Bytes(), content) {
+				t.Errorf("downloaded %q (%d bytes), want %q", buf.Bytes(), n, content)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+const (
+	apiBulkCreateEndpoint = "/rest/api/2/issue/bulk"
+
+	// BulkCreateLimit is the largest number of issues created by a single
+	// bulk request
+	BulkCreateLimit = 50
+)
+
+// BulkCreateResult is the outcome of a bulk creation: the issues created,
+// in request order, and the requests that failed
+type BulkCreateResult struct {
+	Issues []Issue             `json:"issues"`
+	Errors []BulkCreateFailure `json:"errors"`
+}
+
+// BulkCreateFailure describes why an issue of a bulk creation failed
+type BulkCreateFailure struct {
+	Status        int `json:"status"`
+	ElementErrors struct {
+		ErrorMessages []string          `json:"errorMessages"`
+		Errors        map[string]string `json:"errors"`
+	} `json:"elementErrors"`
+	// FailedElementNumber is the index of the issue in the request
+	FailedElementNumber int `json:"failedElementNumber"`
+}
+
+func (f BulkCreateFailure) Error() string {
+	messages := append([]string(nil), f.ElementErrors.ErrorMessages...)
+
+	fields := make([]string, 0, len(f.ElementErrors.Errors))
+	for field := range f.ElementErrors.Errors {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		messages = append(messages, fmt.Sprintf("%s: %s", field, f.ElementErrors.Errors[field]))
+	}
+
+	if len(messages) == 0 {
+		return fmt.Sprintf("failed with status %d", f.Status)
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// Outcome returns, for each of the n issues of the request, the key of the
+// created issue or the reason of the failure
+func (r *BulkCreateResult) Outcome(n int) ([]string, []error) {
+	keys := make([]string, n)
+	errs := make([]error, n)
+
+	for _, failure := range r.Errors {
+		if failure.FailedElementNumber >= 0 && failure.FailedElementNumber < n {
+			errs[failure.FailedElementNumber] = failure
+		}
+	}
+
+	// Created issues are listed in request order, skipping failures
+	created := 0
+	for i := 0; i < n; i++ {
+		if errs[i] != nil {
+			continue
+		}
+		if created >= len(r.Issues) {
+			errs[i] = fmt.Errorf("no issue was returned for this request")
+			continue
+		}
+		keys[i] = r.Issues[created].Key
+		created++
+	}
+
+	return keys, errs
+}
+
+// CreateIssuesBulk creates up to BulkCreateLimit issues at once from raw field
+// values keyed by field ID. Failures of individual issues are reported in the
+// result, not as an error.
+func (c *Client) CreateIssuesBulk(issues []map[string]interface{}) (*BulkCreateResult, error) {
+	if len(issues) > BulkCreateLimit {
+		return nil, fmt.Errorf("cannot create more than %d issues at once", BulkCreateLimit)
+	}
+
+	updates := make([]map[string]interface{}, 0, len(issues))
+	for _, fields := range issues {
+		updates = append(updates, map[string]interface{}{"fields": fields})
+	}
+
+	resp, err := c.post(apiBulkCreateEndpoint, map[string]interface{}{"issueUpdates": updates})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create issues: %w", err)
+	}
+
+	// A partial failure is answered with 400 and the same body as a success
+	if resp.StatusCode == http.StatusBadRequest {
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response body: %w", err)
+		}
+
+		var result BulkCreateResult
+		if err := json.Unmarshal(body, &result); err != nil || len(result.Errors) == 0 {
+			return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		}
+
+		c.invalidateIssue("")
+
+		return &result, nil
+	}
+
+	var result BulkCreateResult
+	if err := handleResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	c.invalidateIssue("")
+
+	return &result, nil
+}
@@ -0,0 +1,61 @@
+package jira_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/lburgazzoli/gira/pkg/jira"
+	"github.com/lburgazzoli/gira/pkg/jira/jiratest"
+)
+
+func TestCreateIssuesBulk(t *testing.T) {
+	srv, client := newTestClient(t, nil)
+	srv.AddProject(jira.Project{Key: "DEMO"})
+
+	fields := func(project string, summary string) map[string]interface{} {
+		return map[string]interface{}{
+			"project":   map[string]string{"key": project},
+			"issuetype": map[string]string{"name": "Task"},
+			"summary":   summary,
+		}
+	}
+
+	// A partial failure is answered with 400 along with the created issues
+	result, err := client.CreateIssuesBulk([]map[string]interface{}{
+		fields("DEMO", "First"),
+		fields("NOPE", "Second"),
+		fields("DEMO", "Third"),
+		fields("DEMO", ""),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys, errs := result.Outcome(4)
+	wantKeys := []string{"DEMO-1", "", "DEMO-2", ""}
+	for i := range wantKeys {
+		if keys[i] != wantKeys[i] || (errs[i] == nil) != (wantKeys[i] != "") {
+			t.Errorf("issue %d = %q (%v), want %q", i, keys[i], errs[i], wantKeys[i])
+		}
+	}
+	if errs[1] == nil || !strings.Contains(errs[1].Error(), "NOPE") {
+		t.Errorf("error of the second issue = %v, want the missing project", errs[1])
+	}
+
+	// More issues requested than returned are reported as failed
+	keys, errs = result.Outcome(5)
+	if keys[4] != "" || errs[4] == nil {
+		t.Errorf("issue 4 = %q (%v), want a failure", keys[4], errs[4])
+	}
+
+	srv.InjectFault(jiratest.Fault{Method: http.MethodPost, Path: "/rest/api/2/issue/bulk", Status: http.StatusBadRequest})
+	if _, err := client.CreateIssuesBulk([]map[string]interface{}{fields("DEMO", "Fourth")}); err == nil {
+		t.Error("a 400 without issue errors succeeded")
+	}
+
+	issues := make([]map[string]interface{}, jira.BulkCreateLimit+1)
+	if _, err := client.CreateIssuesBulk(issues); err == nil {
+		t.Errorf("creating %d issues at once succeeded", len(issues))
+	}
+}
@@ -0,0 +1,412 @@
+package jira
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	headerCacheStatus = "X-Gira-Cache"
+
+	cacheStatusHit         = "hit"
+	cacheStatusRevalidated = "revalidated"
+)
+
+// CacheResource identifies the kind of resource a cached response belongs to
+type CacheResource string
+
+const (
+	CacheResourceProject CacheResource = "project"
+	CacheResourceField   CacheResource = "field"
+	CacheResourceIssue   CacheResource = "issue"
+	CacheResourceSearch  CacheResource = "search"
+)
+
+// CachePolicy controls what is cached and for how long
+type CachePolicy struct {
+	// TTLs per kind of resource, a zero TTL disables caching for the kind
+	ProjectTTL time.Duration
+	FieldTTL   time.Duration
+	IssueTTL   time.Duration
+	SearchTTL  time.Duration
+	// MaxAge bounds how long stale issues are kept for revalidation, older
+	// entries are pruned
+	MaxAge time.Duration
+	// Refresh ignores cached responses but still stores fresh ones
+	Refresh bool
+}
+
+// DefaultCachePolicy caches metadata for a day and issues for a few minutes
+func DefaultCachePolicy() CachePolicy {
+	return CachePolicy{
+		ProjectTTL: 24 * time.Hour,
+		FieldTTL:   24 * time.Hour,
+		IssueTTL:   5 * time.Minute,
+		SearchTTL:  1 * time.Minute,
+		MaxAge:     7 * 24 * time.Hour,
+	}
+}
+
+func (p CachePolicy) ttl(resource CacheResource) time.Duration {
+	switch resource {
+	case CacheResourceProject:
+		return p.ProjectTTL
+	case CacheResourceField:
+		return p.FieldTTL
+	case CacheResourceIssue:
+		return p.IssueTTL
+	case CacheResourceSearch:
+		return p.SearchTTL
+	default:
+		return 0
+	}
+}
+
+// Expired reports whether an entry can no longer be served and may be pruned.
+// Stale issues are kept until MaxAge as they can still be revalidated.
+func (p CachePolicy) Expired(entry *CacheEntry) bool {
+	age := time.Since(entry.Stored)
+	if p.MaxAge > 0 && age >= p.MaxAge {
+		return true
+	}
+	if entry.Resource == CacheResourceIssue {
+		return p.IssueTTL <= 0
+	}
+	return age >= p.ttl(entry.Resource)
+}
+
+// CacheEntry is a cached response body
+type CacheEntry struct {
+	// Identity is a hash of the credentials the response was fetched with,
+	// so that users sharing a cache directory never see each other's data
+	Identity string        `json:"identity"`
+	URL      string        `json:"url"`
+	Resource CacheResource `json:"resource"`
+	Stored   time.Time     `json:"stored"`
+	Body     []byte        `json:"body"`
+}
+
+// Cache stores responses of GET requests keyed by identity and request URL
+type Cache interface {
+	Get(identity string, url string) (*CacheEntry, bool)
+	Set(entry *CacheEntry) error
+	// Invalidate removes the entries for which match returns true
+	Invalidate(match func(entry *CacheEntry) bool) error
+}
+
+// WithCache enables response caching
+func WithCache(cache Cache, policy CachePolicy) Option {
+	return func(c *Client) {
+		c.cache = cache
+		c.cachePolicy = policy
+	}
+}
+
+// cacheResource classifies an endpoint, returning an empty resource for
+// endpoints that are never cached
+func cacheResource(endpoint string) CacheResource {
+	switch {
+	case strings.HasPrefix(endpoint, "/rest/api/2/project"):
+		return CacheResourceProject
+	case strings.HasPrefix(endpoint, "/rest/api/2/field"):
+		return CacheResourceField
+	case endpoint == apiSearchEndpoint:
+		return CacheResourceSearch
+	case strings.HasPrefix(endpoint, "/rest/api/2/issue/") && !strings.Contains(strings.TrimPrefix(endpoint, "/rest/api/2/issue/"), "/"):
+		return CacheResourceIssue
+	default:
+		return ""
+	}
+}
+
+// cachedGet serves GET requests from the cache when the entry is fresh. Stale
+// issues are revalidated by comparing their `updated` timestamp through a
+// search limited to that field, which is much cheaper than a full fetch.
+func (c *Client) cachedGet(endpoint string, params ...Parameter) (*http.Response, error) {
+	resource := cacheResource(endpoint)
+	ttl := c.cachePolicy.ttl(resource)
+
+	if ttl <= 0 {
+		return c.doRequest(http.MethodGet, endpoint, nil, params...)
+	}
+
+	requestURL, err := c.buildURL(endpoint, params...)
+	if err != nil {
+		return nil, err
+	}
+
+	if !c.cachePolicy.Refresh {
+		if entry, ok := c.cache.Get(c.cacheIdentity, requestURL); ok {
+			if time.Since(entry.Stored) < ttl {
+				return cachedResponse(entry, cacheStatusHit), nil
+			}
+
+			if resource == CacheResourceIssue && c.revalidateIssue(entry) {
+				entry.Stored = time.Now()
+				if err := c.cache.Set(entry); err != nil {
+					c.logger.Warn("failed to update cache entry", "url", requestURL, "error", err)
+				}
+				return cachedResponse(entry, cacheStatusRevalidated), nil
+			}
+		}
+	}
+
+	resp, err := c.send(http.MethodGet, requestURL, nil, nil)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	err = c.cache.Set(&CacheEntry{
+		Identity: c.cacheIdentity,
+		URL:      requestURL,
+		Resource: resource,
+		Stored:   time.Now(),
+		Body:     body,
+	})
+	if err != nil {
+		c.logger.Warn("failed to store cache entry", "url", requestURL, "error", err)
+	}
+
+	c.pruneOnce.Do(c.pruneCache)
+
+	return resp, nil
+}
+
+// pruneCache drops the expired entries, it runs once per client on the first
+// write so that the cache does not grow without bounds
+func (c *Client) pruneCache() {
+	if err := c.cache.Invalidate(c.cachePolicy.Expired); err != nil {
+		c.logger.Warn("failed to prune cache", "error", err)
+	}
+}
+
+// cacheIdentity hashes the token, which is never stored in the cache
+func cacheIdentity(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// revalidateIssue reports whether the cached issue is still up to date
+func (c *Client) revalidateIssue(entry *CacheEntry) bool {
+	var cached struct {
+		Key    string `json:"key"`
+		Fields struct {
+			Updated string `json:"updated"`
+		} `json:"fields"`
+	}
+	if err := json.Unmarshal(entry.Body, &cached); err != nil || cached.Key == "" || cached.Fields.Updated == "" {
+		return false
+	}
+
+	resp, err := c.doRequest(http.MethodGet, apiSearchEndpoint, nil,
+		Parameter{Key: "jql", Value: fmt.Sprintf("key = %s", cached.Key)},
+		Parameter{Key: "fields", Value: "updated"},
+		Parameter{Key: "maxResults", Value: "1"},
+	)
+	if err != nil {
+		return false
+	}
+
+	var result struct {
+		Issues []struct {
+			Fields struct {
+				Updated string `json:"updated"`
+			} `json:"fields"`
+		} `json:"issues"`
+	}
+	if err := handleResponse(resp, &result); err != nil || len(result.Issues) != 1 {
+		return false
+	}
+
+	return result.Issues[0].Fields.Updated == cached.Fields.Updated
+}
+
+// invalidateIssue drops the cached copies of an issue and all cached searches,
+// which may include the issue
+func (c *Client) invalidateIssue(key string) {
+	if c.cache == nil {
+		return
+	}
+
+	issuePath := fmt.Sprintf(apiIssueEndpoint, key)
+
+	err := c.cache.Invalidate(func(entry *CacheEntry) bool {
+		if entry.Resource == CacheResourceSearch {
+			return true
+		}
+		if key == "" || entry.Resource != CacheResourceIssue {
+			return false
+		}
+
+		u, err := url.Parse(entry.URL)
+		return err == nil && strings.HasSuffix(u.Path, issuePath)
+	})
+	if err != nil {
+		c.logger.Warn("failed to invalidate cache", "issue", key, "error", err)
+	}
+}
+
+// InvalidateSearches drops the cached search results, so that the next
+// searches see the issues changed by other clients in the meantime
+func (c *Client) InvalidateSearches() {
+	c.invalidateResources(CacheResourceSearch)
+}
+
+// InvalidateProjects drops the cached projects, including their versions and
+// components, so that the next requests see the changes made in the meantime
+func (c *Client) InvalidateProjects() {
+	c.invalidateResources(CacheResourceProject)
+}
+
+// invalidateResources drops all the cached responses of the given resources
+func (c *Client) invalidateResources(resources ...CacheResource) {
+	if c.cache == nil {
+		return
+	}
+
+	err := c.cache.Invalidate(func(entry *CacheEntry) bool {
+		return slices.Contains(resources, entry.Resource)
+	})
+	if err != nil {
+		c.logger.Warn("failed to invalidate cache", "resources", resources, "error", err)
+	}
+}
+
+func cachedResponse(entry *CacheEntry, status string) *http.Response {
+	header := make(http.Header)
+	header.Set(headerContentType, contentTypeJSON)
+	header.Set(headerCacheStatus, status)
+
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(entry.Body)),
+		ContentLength: int64(len(entry.Body)),
+	}
+}
+
+// FileCache is a Cache storing one JSON file per entry in a directory
+type FileCache struct {
+	dir string
+}
+
+// DefaultCacheDir returns $XDG_CACHE_HOME/gira, falling back to the user
+// cache directory of the platform
+func DefaultCacheDir() (string, error) {
+	if xdgCacheHome := os.Getenv("XDG_CACHE_HOME"); xdgCacheHome != "" {
+		return filepath.Join(xdgCacheHome, "gira"), nil
+	}
+
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "gira"), nil
+}
+
+// NewFileCache creates a file cache rooted at dir
+func NewFileCache(dir string) (*FileCache, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	return &FileCache{dir: dir}, nil
+}
+
+func (f *FileCache) path(identity string, url string) string {
+	sum := sha256.Sum256([]byte(identity + "\x00" + url))
+	return filepath.Join(f.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (f *FileCache) Get(identity string, url string) (*CacheEntry, bool) {
+	data, err := os.ReadFile(f.path(identity, url))
+	if err != nil {
+		return nil, false
+	}
+
+	var entry CacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Identity != identity || entry.URL != url {
+		return nil, false
+	}
+
+	return &entry, true
+}
+
+func (f *FileCache) Set(entry *CacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache entry: %w", err)
+	}
+
+	// Write to a temporary file first so that readers never see partial entries
+	tmp, err := os.CreateTemp(f.dir, ".entry-*")
+	if err != nil {
+		return fmt.Errorf("failed to create cache entry: %w", err)
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+
+	return os.Rename(tmp.Name(), f.path(entry.Identity, entry.URL))
+}
+
+func (f *FileCache) Invalidate(match func(entry *CacheEntry) bool) error {
+	return filepath.WalkDir(f.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+
+		var entry CacheEntry
+		if err := json.Unmarshal(data, &entry); err != nil || match(&entry) {
+			if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
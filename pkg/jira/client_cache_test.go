@@ -0,0 +1,158 @@
+package jira_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/lburgazzoli/gira/pkg/jira"
+	"github.com/lburgazzoli/gira/pkg/jira/jiratest"
+)
+
+func newCachedClient(t *testing.T, srv *jiratest.Server, cache jira.Cache, token string) *jira.Client {
+	t.Helper()
+
+	client, err := jira.NewClient(srv.URL, jira.AuthConfig{Token: token},
+		jira.WithCache(cache, jira.DefaultCachePolicy()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return client
+}
+
+func newFileCache(t *testing.T) *jira.FileCache {
+	t.Helper()
+
+	cache, err := jira.NewFileCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cache
+}
+
+func TestCacheServesRepeatedRequests(t *testing.T) {
+	srv := jiratest.NewServer()
+	t.Cleanup(srv.Close)
+	addIssues(srv, "DEMO", 1)
+
+	client := newCachedClient(t, srv, newFileCache(t), "token")
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.GetIssue("DEMO-1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := countRequests(srv, http.MethodGet, "/rest/api/2/issue/DEMO-1"); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+
+	// Updating the issue evicts it
+	if _, err := client.UpdateIssue("DEMO-1", jira.IssueUpdate{Fields: map[string]interface{}{"summary": "Updated"}}); err != nil {
+		t.Fatal(err)
+	}
+	issue, err := client.GetIssue("DEMO-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issue.Fields.Summary != "Updated" {
+		t.Errorf("summary = %q, want the updated one", issue.Fields.Summary)
+	}
+}
+
+func TestCacheIsKeyedByIdentity(t *testing.T) {
+	srv := jiratest.NewServer()
+	t.Cleanup(srv.Close)
+	addIssues(srv, "DEMO", 1)
+
+	cache := newFileCache(t)
+
+	tests := []struct {
+		token        string
+		wantRequests int
+	}{
+		{token: "alice", wantRequests: 1},
+		{token: "alice", wantRequests: 1},
+		{token: "bob", wantRequests: 2},
+		{token: "bob", wantRequests: 2},
+	}
+
+	for _, tt := range tests {
+		client := newCachedClient(t, srv, cache, tt.token)
+		if _, err := client.GetIssue("DEMO-1"); err != nil {
+			t.Fatal(err)
+		}
+		if got := countRequests(srv, http.MethodGet, "/rest/api/2/issue/DEMO-1"); got != tt.wantRequests {
+			t.Errorf("after a request as %s: requests = %d, want %d", tt.token, got, tt.wantRequests)
+		}
+	}
+}
+
+func TestCachePrunedOnWrite(t *testing.T) {
+	srv := jiratest.NewServer()
+	t.Cleanup(srv.Close)
+	addIssues(srv, "DEMO", 1)
+
+	cache := newFileCache(t)
+	now := time.Now()
+
+	entries := []*jira.CacheEntry{
+		{URL: "https://jira/expired-search", Resource: jira.CacheResourceSearch, Stored: now.Add(-time.Hour)},
+		{URL: "https://jira/old-issue", Resource: jira.CacheResourceIssue, Stored: now.Add(-30 * 24 * time.Hour)},
+		{URL: "https://jira/stale-issue", Resource: jira.CacheResourceIssue, Stored: now.Add(-time.Hour)},
+		{URL: "https://jira/project", Resource: jira.CacheResourceProject, Stored: now.Add(-time.Hour)},
+	}
+	for _, entry := range entries {
+		if err := cache.Set(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	client := newCachedClient(t, srv, cache, "token")
+	if _, err := client.GetIssue("DEMO-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]bool{
+		"https://jira/expired-search": false,
+		"https://jira/old-issue":      false,
+		"https://jira/stale-issue":    true,
+		"https://jira/project":        true,
+	}
+	for url, kept := range want {
+		if _, ok := cache.Get("", url); ok != kept {
+			t.Errorf("%s kept = %v, want %v", url, ok, kept)
+		}
+	}
+}
+
+func TestCachePolicyExpired(t *testing.T) {
+	policy := jira.DefaultCachePolicy()
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		resource jira.CacheResource
+		age      time.Duration
+		want     bool
+	}{
+		{name: "fresh search", resource: jira.CacheResourceSearch, age: 10 * time.Second, want: false},
+		{name: "expired search", resource: jira.CacheResourceSearch, age: 2 * time.Minute, want: true},
+		{name: "fresh project", resource: jira.CacheResourceProject, age: time.Hour, want: false},
+		{name: "expired field", resource: jira.CacheResourceField, age: 25 * time.Hour, want: true},
+		{name: "stale issue", resource: jira.CacheResourceIssue, age: 2 * time.Hour, want: false},
+		{name: "issue past max age", resource: jira.CacheResourceIssue, age: 8 * 24 * time.Hour, want: true},
+		{name: "unknown resource", resource: "other", age: time.Second, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := &jira.CacheEntry{Resource: tt.resource, Stored: now.Add(-tt.age)}
+			if got := policy.Expired(entry); got != tt.want {
+				t.Errorf("Expired = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package jira
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FindField returns the field with the given ID or name, IDs taking
+// precedence since custom field names are not unique
+func FindField(fields []Field, ref string) (*Field, error) {
+	for i := range fields {
+		if strings.EqualFold(fields[i].ID, ref) {
+			return &fields[i], nil
+		}
+	}
+
+	var found *Field
+	for i := range fields {
+		if !strings.EqualFold(fields[i].Name, ref) {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("%q matches several fields, use one of: %s, %s", ref, found.ID, fields[i].ID)
+		}
+		found = &fields[i]
+	}
+
+	if found == nil {
+		return nil, fmt.Errorf("field %q does not exist", ref)
+	}
+
+	return found, nil
+}
+
+// EpicLinkField returns the Epic Link custom field, nil when the instance has
+// none as on team-managed projects
+func EpicLinkField(fields []Field) *Field {
+	for i := range fields {
+		if strings.HasSuffix(fields[i].Schema.Custom, ":gh-epic-link") {
+			return &fields[i]
+		}
+	}
+
+	return nil
+}
+
+// ParentValue returns the value referencing a parent issue in the given
+// field, either parent or the Epic Link field
+func ParentValue(field string, key string) interface{} {
+	if field == "parent" {
+		return map[string]string{"key": key}
+	}
+	// The Epic Link field takes the bare key
+	return key
+}
+
+// FieldValue converts a textual value to the representation a field takes in
+// create and edit requests. Arrays take comma separated values, users are
+// resolved with ResolveUser, and an empty value or "none" clears the field.
+func (c *Client) FieldValue(field *Field, value string) (interface{}, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || strings.EqualFold(value, "none") {
+		if field.Schema.Type == "array" {
+			return []interface{}{}, nil
+		}
+		return nil, nil
+	}
+
+	if field.Schema.Type != "array" {
+		v, err := c.scalarFieldValue(field, field.Schema.Type, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", field.Name, err)
+		}
+		return v, nil
+	}
+
+	values := make([]interface{}, 0)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		v, err := c.scalarFieldValue(field, field.Schema.Items, item)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", field.Name, err)
+		}
+		values = append(values, v)
+	}
+
+	return values, nil
+}
+
+func (c *Client) scalarFieldValue(field *Field, typ string, value string) (interface{}, error) {
+	switch {
+	case strings.HasSuffix(field.Schema.Custom, ":gh-sprint"):
+		// Sprints are set by ID
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a sprint ID", value)
+		}
+		return id, nil
+	case strings.HasSuffix(field.Schema.Custom, ":gh-epic-link"):
+		return strings.ToUpper(value), nil
+	}
+
+	switch typ {
+	case "string", "any", "datetime":
+		return value, nil
+	case "number":
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", value)
+		}
+		return n, nil
+	case "date":
+		if _, err := time.Parse("2006-01-02", value); err != nil {
+			return nil, fmt.Errorf("%q is not a YYYY-MM-DD date", value)
+		}
+		return value, nil
+	case "user":
+		user, err := c.ResolveUser(value)
+		if err != nil {
+			return nil, err
+		}
+		if user.AccountID != "" {
+			return map[string]string{"accountId": user.AccountID}, nil
+		}
+		return map[string]string{"name": user.Name}, nil
+	case "option":
+		return map[string]string{"value": value}, nil
+	case "issuelink":
+		return map[string]string{"key": strings.ToUpper(value)}, nil
+	case "project":
+		return map[string]string{"key": strings.ToUpper(value)}, nil
+	case "priority", "issuetype", "version", "component", "resolution", "securitylevel":
+		return map[string]string{"name": value}, nil
+	default:
+		return nil, fmt.Errorf("fields of type %s are not supported", typ)
+	}
+}
@@ -0,0 +1,130 @@
+package jira_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lburgazzoli/gira/pkg/jira"
+	"github.com/lburgazzoli/gira/pkg/jira/jiratest"
+)
+
+var testFields = []jira.Field{
+	{ID: "summary", Name: "Summary", Schema: jira.FieldSchema{Type: "string"}},
+	{ID: "customfield_10001", Name: "Team", Schema: jira.FieldSchema{Type: "option"}},
+	{ID: "customfield_10002", Name: "Team", Schema: jira.FieldSchema{Type: "string"}},
+	// A custom field named like the ID of another one
+	{ID: "customfield_10003", Name: "summary", Schema: jira.FieldSchema{Type: "string"}},
+}
+
+func TestFindField(t *testing.T) {
+	tests := []struct {
+		ref     string
+		want    string
+		wantErr bool
+	}{
+		{ref: "summary", want: "summary"},
+		{ref: "CUSTOMFIELD_10002", want: "customfield_10002"},
+		{ref: "Team", wantErr: true},
+		{ref: "Sprint", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			field, err := jira.FindField(testFields, tt.ref)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("found %s for %q", field.ID, tt.ref)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if field.ID != tt.want {
+				t.Errorf("found %s for %q, want %s", field.ID, tt.ref, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldValue(t *testing.T) {
+	field := func(typ string, items string, custom string) *jira.Field {
+		return &jira.Field{ID: "f", Name: "F", Schema: jira.FieldSchema{Type: typ, Items: items, Custom: custom}}
+	}
+
+	tests := []struct {
+		name    string
+		field   *jira.Field
+		value   string
+		want    interface{}
+		wantErr bool
+	}{
+		{name: "string", field: field("string", "", ""), value: " text ", want: "text"},
+		{name: "number", field: field("number", "", ""), value: "3.5", want: 3.5},
+		{name: "not a number", field: field("number", "", ""), value: "three", wantErr: true},
+		{name: "date", field: field("date", "", ""), value: "2024-03-01", want: "2024-03-01"},
+		{name: "not a date", field: field("date", "", ""), value: "01/03/2024", wantErr: true},
+		{name: "option", field: field("option", "", ""), value: "Red", want: map[string]string{"value": "Red"}},
+		{name: "priority", field: field("priority", "", ""), value: "High", want: map[string]string{"name": "High"}},
+		{name: "parent", field: field("issuelink", "", ""), value: "demo-1", want: map[string]string{"key": "DEMO-1"}},
+		{name: "user", field: field("user", "", ""), value: "john@example.com", want: map[string]string{"accountId": "acc-john"}},
+		{name: "unknown user", field: field("user", "", ""), value: "nobody", wantErr: true},
+		{name: "unsupported", field: field("status", "", ""), value: "Done", wantErr: true},
+		{
+			name:  "array",
+			field: field("array", "version", ""),
+			value: "1.0, ,1.1",
+			want:  []interface{}{map[string]string{"name": "1.0"}, map[string]string{"name": "1.1"}},
+		},
+		{name: "clear scalar", field: field("string", "", ""), value: "none", want: nil},
+		{name: "clear array", field: field("array", "string", ""), value: " ", want: []interface{}{}},
+		{name: "sprint", field: field("array", "json", "com.pyxis.greenhopper.jira:gh-sprint"), value: "124", want: []interface{}{124}},
+		{name: "not a sprint", field: field("array", "json", "com.pyxis.greenhopper.jira:gh-sprint"), value: "Sprint 1", wantErr: true},
+		{name: "epic link", field: field("any", "", "com.pyxis.greenhopper.jira:gh-epic-link"), value: "demo-1", want: "DEMO-1"},
+	}
+
+	_, client := newTestClient(t, []jiratest.Option{jiratest.WithUsers(testUsers...)})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := client.FieldValue(tt.field, tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("converted %q to %v", tt.value, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("converted %q to %#v, want %#v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParentHelpers(t *testing.T) {
+	for ref, want := range map[string]bool{"DEMO-1": true, "A_B2-10": true, "demo-1": false, "DEMO": false, "DEMO-1x": false, "1-1": false} {
+		if got := jira.IsIssueKey(ref); got != want {
+			t.Errorf("IsIssueKey(%q) = %v, want %v", ref, got, want)
+		}
+	}
+
+	if field := jira.EpicLinkField(testFields); field != nil {
+		t.Errorf("found Epic Link field %s", field.ID)
+	}
+	fields := append(testFields, jira.Field{ID: "customfield_10014", Name: "Epic Link", Schema: jira.FieldSchema{Custom: "com.pyxis.greenhopper.jira:gh-epic-link"}})
+	if field := jira.EpicLinkField(fields); field == nil || field.ID != "customfield_10014" {
+		t.Errorf("Epic Link field = %+v", field)
+	}
+
+	if got := jira.ParentValue("parent", "DEMO-1"); !reflect.DeepEqual(got, map[string]string{"key": "DEMO-1"}) {
+		t.Errorf("parent value = %#v", got)
+	}
+	if got := jira.ParentValue("customfield_10014", "DEMO-1"); got != "DEMO-1" {
+		t.Errorf("Epic Link value = %#v", got)
+	}
+}
@@ -0,0 +1,160 @@
+package jira
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	apiIssueLinkTypesEndpoint = "/rest/api/2/issueLinkType"
+	apiIssueLinksEndpoint     = "/rest/api/2/issueLink"
+	apiIssueLinkEndpoint      = "/rest/api/2/issueLink/%s"
+)
+
+// ListLinkTypes returns the issue link types of the instance
+func (c *Client) ListLinkTypes() ([]LinkType, error) {
+	resp, err := c.get(apiIssueLinkTypesEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get link types: %w", err)
+	}
+
+	var result struct {
+		IssueLinkTypes []LinkType `json:"issueLinkTypes"`
+	}
+	if err := handleResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.IssueLinkTypes, nil
+}
+
+// ListIssueLinks returns the links of an issue
+func (c *Client) ListIssueLinks(key string) ([]IssueLink, error) {
+	resp, err := c.get(fmt.Sprintf(apiIssueEndpoint, key), Parameter{Key: "fields", Value: "issuelinks"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issue links: %w", err)
+	}
+
+	var issue Issue
+	if err := handleResponse(resp, &issue); err != nil {
+		return nil, err
+	}
+
+	if issue.Fields.IssueLinks == nil {
+		return []IssueLink{}, nil
+	}
+
+	return issue.Fields.IssueLinks, nil
+}
+
+// GetIssueLink returns a link by ID
+func (c *Client) GetIssueLink(id string) (*IssueLink, error) {
+	resp, err := c.get(fmt.Sprintf(apiIssueLinkEndpoint, id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issue link: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("issue link %s does not exist", id)
+	}
+
+	var link IssueLink
+	if err := handleResponse(resp, &link); err != nil {
+		return nil, err
+	}
+
+	return &link, nil
+}
+
+// CreateIssueLink links two issues so that "from <outward> to" holds, e.g.
+// from blocks to for the Blocks link type. linkType is the name of the type.
+func (c *Client) CreateIssueLink(linkType string, from string, to string) error {
+	// The REST API takes the source of the outward relation as inwardIssue
+	body := map[string]any{
+		"type":         map[string]string{"name": linkType},
+		"inwardIssue":  map[string]string{"key": from},
+		"outwardIssue": map[string]string{"key": to},
+	}
+
+	resp, err := c.post(apiIssueLinksEndpoint, body)
+	if err != nil {
+		return fmt.Errorf("failed to create issue link: %w", err)
+	}
+
+	if err := handleResponse(resp, nil); err != nil {
+		return err
+	}
+
+	c.invalidateIssue(from)
+	c.invalidateIssue(to)
+
+	return nil
+}
+
+// DeleteIssueLink removes a link between two issues and returns the removed link
+func (c *Client) DeleteIssueLink(id string) (*IssueLink, error) {
+	// Fetch the link first to evict both issues from the cache
+	link, err := c.GetIssueLink(id)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.delete(fmt.Sprintf(apiIssueLinkEndpoint, id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete issue link: %w", err)
+	}
+
+	if err := handleResponse(resp, nil); err != nil {
+		return nil, err
+	}
+
+	for _, issue := range []*Issue{link.InwardIssue, link.OutwardIssue} {
+		if issue != nil {
+			c.invalidateIssue(issue.Key)
+		}
+	}
+
+	return link, nil
+}
+
+// ResolveLinkType finds the link type matching a relation phrase, such as
+// "blocks" or "is blocked by", or a link type name. Reversed is true when the
+// phrase is the inward description, i.e. when "A is blocked by B" must be
+// created as "B blocks A". Phrases match case insensitively, with dashes and
+// underscores standing for spaces.
+func ResolveLinkType(types []LinkType, phrase string) (linkType LinkType, reversed bool, err error) {
+	normalized := normalizeLinkPhrase(phrase)
+
+	for _, t := range types {
+		if normalizeLinkPhrase(t.Outward) == normalized {
+			return t, false, nil
+		}
+	}
+	for _, t := range types {
+		if normalizeLinkPhrase(t.Inward) == normalized {
+			return t, true, nil
+		}
+	}
+	for _, t := range types {
+		if normalizeLinkPhrase(t.Name) == normalized {
+			return t, false, nil
+		}
+	}
+
+	phrases := make([]string, 0, 2*len(types))
+	for _, t := range types {
+		phrases = append(phrases, fmt.Sprintf("%q", t.Outward))
+		if t.Inward != t.Outward {
+			phrases = append(phrases, fmt.Sprintf("%q", t.Inward))
+		}
+	}
+
+	return LinkType{}, false, fmt.Errorf("unknown link type %q, expected one of: %s", phrase, strings.Join(phrases, ", "))
+}
+
+func normalizeLinkPhrase(phrase string) string {
+	phrase = strings.NewReplacer("-", " ", "_", " ").Replace(strings.ToLower(phrase))
+	return strings.Join(strings.Fields(phrase), " ")
+}
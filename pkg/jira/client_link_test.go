@@ -0,0 +1,118 @@
+package jira_test
+
+import (
+	"testing"
+
+	"github.com/lburgazzoli/gira/pkg/jira"
+)
+
+var testLinkTypes = []jira.LinkType{
+	{ID: "10000", Name: "Blocks", Inward: "is blocked by", Outward: "blocks"},
+	{ID: "10001", Name: "Cloners", Inward: "is cloned by", Outward: "clones"},
+	{ID: "10003", Name: "Relates", Inward: "relates to", Outward: "relates to"},
+	{ID: "10004", Name: "Depends", Inward: "is depended on by", Outward: "depends on"},
+}
+
+func TestResolveLinkType(t *testing.T) {
+	tests := []struct {
+		phrase       string
+		wantName     string
+		wantReversed bool
+		wantErr      bool
+	}{
+		{phrase: "blocks", wantName: "Blocks"},
+		{phrase: "is blocked by", wantName: "Blocks", wantReversed: true},
+		{phrase: "IS-BLOCKED-BY", wantName: "Blocks", wantReversed: true},
+		{phrase: "is_cloned_by", wantName: "Cloners", wantReversed: true},
+		{phrase: "  depends   on ", wantName: "Depends"},
+		// Symmetric types resolve to the outward phrasing
+		{phrase: "relates to", wantName: "Relates"},
+		// Type names are accepted when no phrase matches
+		{phrase: "cloners", wantName: "Cloners"},
+		{phrase: "Relates", wantName: "Relates"},
+		{phrase: "duplicates", wantErr: true},
+		{phrase: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.phrase, func(t *testing.T) {
+			linkType, reversed, err := jira.ResolveLinkType(testLinkTypes, tt.phrase)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("resolved %q to %s", tt.phrase, linkType.Name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if linkType.Name != tt.wantName || reversed != tt.wantReversed {
+				t.Errorf("got %s (reversed %v), want %s (reversed %v)", linkType.Name, reversed, tt.wantName, tt.wantReversed)
+			}
+		})
+	}
+}
+
+func TestIssueLinks(t *testing.T) {
+	srv, client := newTestClient(t, nil)
+	addIssues(srv, "DEMO", 2)
+
+	types, err := client.ListLinkTypes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(types) == 0 {
+		t.Fatal("no link types")
+	}
+
+	if err := client.CreateIssueLink("Blocks", "DEMO-1", "DEMO-2"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Each side sees the link with its own phrasing
+	for _, side := range []struct {
+		key      string
+		relation string
+		linked   string
+	}{
+		{key: "DEMO-1", relation: "blocks", linked: "DEMO-2"},
+		{key: "DEMO-2", relation: "is blocked by", linked: "DEMO-1"},
+	} {
+		links, err := client.ListIssueLinks(side.key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(links) != 1 {
+			t.Fatalf("%s has %d links, want 1", side.key, len(links))
+		}
+		if links[0].Relation() != side.relation || links[0].LinkedIssue().Key != side.linked {
+			t.Errorf("%s %s %s, want %s %s", side.key, links[0].Relation(), links[0].LinkedIssue().Key, side.relation, side.linked)
+		}
+	}
+
+	links, err := client.ListIssueLinks("DEMO-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	removed, err := client.DeleteIssueLink(links[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed.InwardIssue == nil || removed.InwardIssue.Key != "DEMO-1" ||
+		removed.OutwardIssue == nil || removed.OutwardIssue.Key != "DEMO-2" {
+		t.Errorf("removed link = %+v, want DEMO-1 blocks DEMO-2", removed)
+	}
+
+	if links, err := client.ListIssueLinks("DEMO-2"); err != nil || len(links) != 0 {
+		t.Errorf("DEMO-2 links = %v (%v), want none", links, err)
+	}
+	if _, err := client.DeleteIssueLink(links[0].ID); err == nil {
+		t.Error("deleting a missing link succeeded")
+	}
+
+	if err := client.CreateIssueLink("Blocks", "DEMO-1", "DEMO-99"); err == nil {
+		t.Error("linking to a missing issue succeeded")
+	}
+}
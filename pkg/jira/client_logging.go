@@ -0,0 +1,218 @@
+package jira
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+const (
+	// maxLoggedBodySize is the maximum number of body bytes written to debug logs
+	maxLoggedBodySize = 4096
+
+	redactedValue = "***redacted***"
+)
+
+// sensitiveBodyFields matches JSON string values of credential-like fields
+var sensitiveBodyFields = regexp.MustCompile(`(?i)("(?:token|password|secret|api_?key|access_?token)"\s*:\s*)"[^"]*"`)
+
+// WithLogger sets the logger used to trace HTTP requests. Requests are logged
+// at info level, request and response bodies at debug level and retries at
+// warn level.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// logRetry is installed as the retryable client RequestLogHook, which is
+// invoked before every attempt
+func (c *Client) logRetry(_ retryablehttp.Logger, req *http.Request, attempt int) {
+	if attempt == 0 {
+		return
+	}
+
+	c.logger.Warn("retrying request",
+		"method", req.Method,
+		"url", req.URL.String(),
+		"attempt", attempt,
+		"max", c.retryPolicy.MaxRetries)
+}
+
+// loggingTransport logs every attempt performed by the HTTP client
+type loggingTransport struct {
+	next   http.RoundTripper
+	logger *slog.Logger
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	debug := t.logger.Enabled(req.Context(), slog.LevelDebug)
+
+	if debug {
+		var body []byte
+		var err error
+		if body, req, err = requestBody(req); err != nil {
+			return nil, err
+		}
+
+		t.logger.Debug("request",
+			"method", req.Method,
+			"url", req.URL.String(),
+			"headers", redactHeaders(req.Header),
+			"body", redactBody(body))
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	duration := time.Since(start)
+
+	if err != nil {
+		t.logger.Info("request failed",
+			"method", req.Method,
+			"url", req.URL.String(),
+			"duration", duration,
+			"error", err)
+		return resp, err
+	}
+
+	t.logger.Info("request",
+		"method", req.Method,
+		"url", req.URL.String(),
+		"status", resp.StatusCode,
+		"duration", duration)
+
+	if debug {
+		body, err := drainTextBody(resp.Header, &resp.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		t.logger.Debug("response",
+			"status", resp.StatusCode,
+			"headers", redactHeaders(resp.Header),
+			"body", redactBody(body))
+	}
+
+	return resp, nil
+}
+
+// requestBody returns the body of a request for logging, with the request to
+// send. A RoundTripper must not modify the request it is given, so the body is
+// read through GetBody when possible, and from a clone of the request
+// otherwise.
+func requestBody(req *http.Request) ([]byte, *http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, req, nil
+	}
+	if !isTextContent(req.Header) {
+		return notLogged(req.Header), req, nil
+	}
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, nil, err
+		}
+		defer func() {
+			_ = body.Close()
+		}()
+
+		data, err := io.ReadAll(body)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return data, req, nil
+	}
+
+	clone := req.Clone(req.Context())
+	data, err := drainBody(&clone.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return data, clone, nil
+}
+
+// drainTextBody drains bodies that can be logged, leaving binary content
+// such as attachments streaming
+func drainTextBody(header http.Header, body *io.ReadCloser) ([]byte, error) {
+	if !isTextContent(header) {
+		return notLogged(header), nil
+	}
+
+	return drainBody(body)
+}
+
+// isTextContent tells whether a body can be logged, binary content such as
+// attachments is not
+func isTextContent(header http.Header) bool {
+	contentType := header.Get(headerContentType)
+	return contentType == "" || strings.Contains(contentType, "json") || strings.HasPrefix(contentType, "text/")
+}
+
+func notLogged(header http.Header) []byte {
+	return []byte("(" + header.Get(headerContentType) + " body not logged)")
+}
+
+// drainBody reads the body and replaces it with an equivalent reader
+func drainBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+
+	data, err := io.ReadAll(*body)
+	_ = (*body).Close()
+	if err != nil {
+		return nil, err
+	}
+
+	*body = io.NopCloser(bytes.NewReader(data))
+
+	return data, nil
+}
+
+func redactHeaders(headers http.Header) map[string]string {
+	redacted := make(map[string]string, len(headers))
+	for name, values := range headers {
+		switch http.CanonicalHeaderKey(name) {
+		case headerAuthorization, "Cookie", "Set-Cookie":
+			redacted[name] = redactedValue
+		default:
+			if len(values) > 0 {
+				redacted[name] = values[0]
+			}
+		}
+	}
+	return redacted
+}
+
+func redactBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	truncated := false
+	if len(body) > maxLoggedBodySize {
+		body = body[:maxLoggedBodySize]
+		truncated = true
+	}
+
+	s := sensitiveBodyFields.ReplaceAllString(string(body), `$1"`+redactedValue+`"`)
+	if truncated {
+		s += "...(truncated)"
+	}
+
+	return s
+}
+
+// discardLogger returns a logger that drops every record
+func discardLogger() *slog.Logger {
+	return slog.New(slog.DiscardHandler)
+}
@@ -0,0 +1,161 @@
+package jira
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// Option configures optional settings of a Client
+type Option func(*Client)
+
+// RetryPolicy controls how failed requests are retried
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of retries after the first attempt
+	MaxRetries int
+	// WaitMin is the minimum time to wait between retries
+	WaitMin time.Duration
+	// WaitMax is the maximum time to wait between retries
+	WaitMax time.Duration
+}
+
+// DefaultRetryPolicy returns the retry policy used when none is configured
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: 3,
+		WaitMin:    1 * time.Second,
+		WaitMax:    30 * time.Second,
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to perform requests. A custom
+// client takes precedence over WithTLSConfig and WithProxy.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithTLSConfig sets the TLS configuration used to connect to JIRA
+func WithTLSConfig(tlsConfig *tls.Config) Option {
+	return func(c *Client) {
+		c.tlsConfig = tlsConfig
+	}
+}
+
+// WithProxy sets the proxy used to connect to JIRA. When not set, the proxy
+// is taken from the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables.
+func WithProxy(proxyURL *url.URL) Option {
+	return func(c *Client) {
+		c.proxyURL = proxyURL
+	}
+}
+
+// WithTimeout sets how long each HTTP request attempt waits for a response.
+// Reading the response body is not bounded, so that attachment transfers of
+// any duration complete.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithRetryPolicy sets how failed requests are retried
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
+// WithTransportMiddleware wraps the HTTP transport, e.g. to record or replay
+// interactions. Middlewares are applied in order, the last one being the
+// outermost; logging and rate limiting are applied on top of them.
+func WithTransportMiddleware(middleware func(http.RoundTripper) http.RoundTripper) Option {
+	return func(c *Client) {
+		c.middlewares = append(c.middlewares, middleware)
+	}
+}
+
+// WithRateLimit limits the client to requestsPerSecond requests, allowing
+// bursts of up to burst requests. A rate of zero disables the limiter.
+func WithRateLimit(requestsPerSecond float64, burst int) Option {
+	return func(c *Client) {
+		c.rateLimit = requestsPerSecond
+		c.rateBurst = burst
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithMultipartBoundary makes uploads use a fixed multipart boundary instead
+// of a random one, so that their bodies are reproducible
+func WithMultipartBoundary(boundary string) Option {
+	return func(c *Client) {
+		c.multipartBoundary = boundary
+	}
+}
+
+// TLSOptions describes the TLS settings that can be loaded from files
+type TLSOptions struct {
+	// CAFile is a PEM bundle of additional certificate authorities to trust
+	CAFile string
+	// CertFile and KeyFile are the PEM client certificate and key used for mTLS
+	CertFile string
+	KeyFile  string
+	// InsecureSkipVerify disables the verification of the server certificate
+	InsecureSkipVerify bool
+}
+
+// NewTLSConfig builds a tls.Config from the given options. It returns nil when
+// no option is set, so that the default TLS settings are used.
+func NewTLSConfig(opts TLSOptions) (*tls.Config, error) {
+	if opts == (TLSOptions{}) {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: opts.InsecureSkipVerify,
+	}
+
+	if opts.CAFile != "" {
+		pem, err := os.ReadFile(opts.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no valid certificates found in CA file %s", opts.CAFile)
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	if opts.CertFile != "" || opts.KeyFile != "" {
+		if opts.CertFile == "" || opts.KeyFile == "" {
+			return nil, fmt.Errorf("both a client certificate and a key file are required for mTLS")
+		}
+
+		cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
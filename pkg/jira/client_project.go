@@ -0,0 +1,70 @@
+package jira
+
+import (
+	"fmt"
+	"net/http"
+)
+
+const (
+	apiProjectsEndpoint          = "/rest/api/2/project"
+	apiProjectComponentsEndpoint = "/rest/api/2/project/%s/components"
+	apiProjectVersionsEndpoint   = "/rest/api/2/project/%s/versions"
+
+	// projectExpand are the project fields only returned on request
+	projectExpand = "description,lead,issueTypes,url"
+)
+
+// ListProjects returns the projects visible to the user
+func (c *Client) ListProjects() ([]Project, error) {
+	resp, err := c.get(apiProjectsEndpoint, Parameter{Key: "expand", Value: projectExpand})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list projects: %w", err)
+	}
+
+	projects := make([]Project, 0)
+	if err := handleResponse(resp, &projects); err != nil {
+		return nil, err
+	}
+
+	return projects, nil
+}
+
+// ListComponents returns the components of a project
+func (c *Client) ListComponents(projectKey string) ([]Component, error) {
+	resp, err := c.get(fmt.Sprintf(apiProjectComponentsEndpoint, projectKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list components: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("project %s does not exist", projectKey)
+	}
+
+	components := make([]Component, 0)
+	if err := handleResponse(resp, &components); err != nil {
+		return nil, err
+	}
+
+	return components, nil
+}
+
+// ListVersions returns the versions of a project, in the order set in JIRA
+func (c *Client) ListVersions(projectKey string) ([]Version, error) {
+	resp, err := c.get(fmt.Sprintf(apiProjectVersionsEndpoint, projectKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list versions: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("project %s does not exist", projectKey)
+	}
+
+	versions := make([]Version, 0)
+	if err := handleResponse(resp, &versions); err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
@@ -0,0 +1,99 @@
+package jira_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/lburgazzoli/gira/pkg/jira"
+)
+
+func TestProjectMetadata(t *testing.T) {
+	srv, client := newTestClient(t, nil)
+
+	srv.AddProject(jira.Project{
+		Key:             "DEMO",
+		Name:            "Demo",
+		Description:     "The demo project",
+		Lead:            &jira.User{AccountID: "acc-lead", DisplayName: "Project Lead"},
+		ProjectCategory: &jira.ProjectCategory{ID: "1", Name: "Engineering"},
+		Components: []jira.Component{
+			{Name: "Backend"},
+			{Name: "Frontend", Description: "The web UI"},
+		},
+		Versions: []jira.Version{
+			{Name: "1.0.0", Released: true, ReleaseDate: "2024-01-15"},
+			{Name: "1.1.0"},
+		},
+	})
+	addIssues(srv, "DEMO", 1)
+	srv.AddIssue(jira.Issue{Fields: jira.IssueFields{
+		Summary:   "A bug",
+		IssueType: jira.IssueType{Name: "Bug"},
+		Project:   jira.Project{Key: "DEMO"},
+	}})
+
+	project, err := client.GetProject("DEMO")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if project.Description != "The demo project" || project.Lead == nil || project.Lead.DisplayName != "Project Lead" {
+		t.Errorf("project = %+v, want the description and lead", project)
+	}
+	if project.ProjectCategory == nil || project.ProjectCategory.Name != "Engineering" {
+		t.Errorf("category = %+v, want Engineering", project.ProjectCategory)
+	}
+	if len(project.IssueTypes) != 2 || project.IssueTypes[0].Name != "Task" || project.IssueTypes[1].Name != "Bug" {
+		t.Errorf("issue types = %+v, want Task and Bug", project.IssueTypes)
+	}
+	if len(project.Roles) == 0 {
+		t.Error("no project roles")
+	}
+
+	var expand string
+	for _, r := range srv.Requests() {
+		if r.Method == http.MethodGet && r.Path == "/rest/api/2/project/DEMO" {
+			expand = r.Query
+		}
+	}
+	for _, field := range []string{"description", "lead", "issueTypes"} {
+		if !strings.Contains(expand, field) {
+			t.Errorf("query %q does not expand %s", expand, field)
+		}
+	}
+
+	components, err := client.ListComponents("DEMO")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(components) != 2 || components[0].Name != "Backend" || components[0].ID == "" || components[1].Description != "The web UI" {
+		t.Errorf("components = %+v", components)
+	}
+
+	versions, err := client.ListVersions("DEMO")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 2 || versions[0].Name != "1.0.0" || !versions[0].Released || versions[1].Released {
+		t.Errorf("versions = %+v", versions)
+	}
+
+	projects, err := client.ListProjects()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(projects) != 1 || projects[0].Key != "DEMO" {
+		t.Errorf("projects = %+v, want DEMO", projects)
+	}
+
+	for name, call := range map[string]func() error{
+		"project":    func() error { _, err := client.GetProject("NOPE"); return err },
+		"components": func() error { _, err := client.ListComponents("NOPE"); return err },
+		"versions":   func() error { _, err := client.ListVersions("NOPE"); return err },
+	} {
+		if err := call(); err == nil || (name != "project" && !strings.Contains(err.Error(), "NOPE does not exist")) {
+			t.Errorf("%s of a missing project: err = %v", name, err)
+		}
+	}
+}
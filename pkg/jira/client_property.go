@@ -0,0 +1,97 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const apiIssuePropertyEndpoint = "/rest/api/2/issue/%s/properties/%s"
+
+// GetIssueProperty decodes the value of an issue entity property into v, and
+// reports whether the property is set
+func (c *Client) GetIssueProperty(key string, property string, v interface{}) (bool, error) {
+	resp, err := c.get(fmt.Sprintf(apiIssuePropertyEndpoint, key, property))
+	if err != nil {
+		return false, fmt.Errorf("failed to get issue property: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		_ = resp.Body.Close()
+		return false, nil
+	}
+
+	var result struct {
+		Value json.RawMessage `json:"value"`
+	}
+	if err := handleResponse(resp, &result); err != nil {
+		return false, err
+	}
+
+	if err := json.Unmarshal(result.Value, v); err != nil {
+		return false, fmt.Errorf("failed to decode issue property %s: %w", property, err)
+	}
+
+	return true, nil
+}
+
+// SetIssueProperty stores a JSON value in an issue entity property
+func (c *Client) SetIssueProperty(key string, property string, value interface{}) error {
+	resp, err := c.put(fmt.Sprintf(apiIssuePropertyEndpoint, key, property), value)
+	if err != nil {
+		return fmt.Errorf("failed to set issue property: %w", err)
+	}
+
+	if err := handleResponse(resp, nil); err != nil {
+		return err
+	}
+
+	c.invalidateIssue(key)
+
+	return nil
+}
+
+// SearchRawIssuesWithProperties is like SearchRawIssues but also returns the
+// given entity properties of the issues, under "properties"
+func (c *Client) SearchRawIssuesWithProperties(jql string, startAt, maxResults int, fields []string, properties []string) (*RawSearchResult, error) {
+	params := searchParams(jql, startAt, maxResults, fields)
+	if len(properties) > 0 {
+		params = append(params, Parameter{Key: "properties", Value: strings.Join(properties, ",")})
+	}
+
+	resp, err := c.get(apiSearchEndpoint, params...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search issues: %w", err)
+	}
+
+	var result RawSearchResult
+	if err := handleResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// SearchAllRawIssuesWithProperties returns all the issues matching a query,
+// as SearchRawIssuesWithProperties does, fetching them page by page
+func (c *Client) SearchAllRawIssuesWithProperties(jql string, fields []string, properties []string) ([]json.RawMessage, error) {
+	issues := make([]json.RawMessage, 0)
+
+	for startAt := 0; ; {
+		result, err := c.SearchRawIssuesWithProperties(jql, startAt, searchPageSize, fields, properties)
+		if err != nil {
+			return nil, err
+		}
+
+		issues = append(issues, result.Issues...)
+
+		if len(result.Issues) == 0 || startAt+len(result.Issues) >= result.Total {
+			break
+		}
+
+		startAt += len(result.Issues)
+	}
+
+	return issues, nil
+}
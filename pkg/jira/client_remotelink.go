@@ -0,0 +1,78 @@
+package jira
+
+import (
+	"fmt"
+	"net/http"
+)
+
+const (
+	apiRemoteLinksEndpoint = "/rest/api/2/issue/%s/remotelink"
+	apiRemoteLinkEndpoint  = "/rest/api/2/issue/%s/remotelink/%s"
+)
+
+// ListRemoteLinks returns the remote links of an issue
+func (c *Client) ListRemoteLinks(key string) ([]RemoteLink, error) {
+	resp, err := c.get(fmt.Sprintf(apiRemoteLinksEndpoint, key))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get remote links: %w", err)
+	}
+
+	var links []RemoteLink
+	if err := handleResponse(resp, &links); err != nil {
+		return nil, err
+	}
+
+	if links == nil {
+		return []RemoteLink{}, nil
+	}
+
+	return links, nil
+}
+
+// CreateRemoteLink adds a remote link to an issue, or updates the link with
+// the same GlobalID when there is one. The ID of the link is set on return.
+func (c *Client) CreateRemoteLink(key string, link RemoteLink) (*RemoteLink, error) {
+	link.ID = 0
+	link.Self = ""
+
+	resp, err := c.post(fmt.Sprintf(apiRemoteLinksEndpoint, key), link)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create remote link: %w", err)
+	}
+
+	var created struct {
+		ID   int    `json:"id"`
+		Self string `json:"self"`
+	}
+	if err := handleResponse(resp, &created); err != nil {
+		return nil, err
+	}
+
+	c.invalidateIssue(key)
+
+	link.ID = created.ID
+	link.Self = created.Self
+
+	return &link, nil
+}
+
+// DeleteRemoteLink removes a remote link from an issue
+func (c *Client) DeleteRemoteLink(key string, id string) error {
+	resp, err := c.delete(fmt.Sprintf(apiRemoteLinkEndpoint, key, id))
+	if err != nil {
+		return fmt.Errorf("failed to delete remote link: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		_ = resp.Body.Close()
+		return fmt.Errorf("remote link %s of %s does not exist", id, key)
+	}
+
+	if err := handleResponse(resp, nil); err != nil {
+		return err
+	}
+
+	c.invalidateIssue(key)
+
+	return nil
+}
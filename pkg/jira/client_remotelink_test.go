@@ -0,0 +1,77 @@
+package jira_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/lburgazzoli/gira/pkg/jira"
+)
+
+func TestRemoteLinks(t *testing.T) {
+	srv, client := newTestClient(t, nil)
+	addIssues(srv, "DEMO", 1)
+
+	pr := jira.RemoteLink{
+		GlobalID:     "github=org/repo/pull/1",
+		Relationship: "implemented by",
+		Object:       jira.RemoteLinkObject{URL: "https://github.com/org/repo/pull/1", Title: "PR #1"},
+	}
+	doc := jira.RemoteLink{
+		Object: jira.RemoteLinkObject{URL: "https://example.com/design", Title: "Design"},
+	}
+
+	createdPR, err := client.CreateRemoteLink("DEMO-1", pr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if createdPR.ID == 0 || !strings.Contains(createdPR.Self, fmt.Sprint(createdPR.ID)) {
+		t.Errorf("created link has ID %d and self %q", createdPR.ID, createdPR.Self)
+	}
+
+	createdDoc, err := client.CreateRemoteLink("DEMO-1", doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A link with the same global ID updates the existing one
+	pr.Object.Title = "PR #1 (merged)"
+	pr.ID = 12345
+	updated, err := client.CreateRemoteLink("DEMO-1", pr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.ID != createdPR.ID {
+		t.Errorf("updated link ID = %d, want %d", updated.ID, createdPR.ID)
+	}
+
+	// Links without a global ID are always added
+	if _, err := client.CreateRemoteLink("DEMO-1", doc); err != nil {
+		t.Fatal(err)
+	}
+
+	links, err := client.ListRemoteLinks("DEMO-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(links) != 3 {
+		t.Fatalf("got %d remote links, want 3", len(links))
+	}
+	if links[0].ID != createdPR.ID || links[0].Object.Title != "PR #1 (merged)" || links[0].Relationship != "implemented by" {
+		t.Errorf("first link = %+v, want the updated pull request", links[0])
+	}
+
+	if err := client.DeleteRemoteLink("DEMO-1", fmt.Sprint(createdDoc.ID)); err != nil {
+		t.Fatal(err)
+	}
+	if got := srv.RemoteLinks("DEMO-1"); len(got) != 2 {
+		t.Errorf("%d remote links left, want 2", len(got))
+	}
+	if err := client.DeleteRemoteLink("DEMO-1", fmt.Sprint(createdDoc.ID)); err == nil {
+		t.Error("deleting a missing remote link succeeded")
+	}
+
+	if links, err := client.ListRemoteLinks("DEMO-99"); err == nil {
+		t.Errorf("listing the links of a missing issue gave %v", links)
+	}
+}
@@ -0,0 +1,146 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+const (
+	headerRetryAfter         = "Retry-After"
+	headerRateLimitRemaining = "X-RateLimit-Remaining"
+	headerRateLimitReset     = "X-RateLimit-Reset"
+)
+
+// retryContextKey marks requests whose method is not safe to retry blindly
+type retryContextKey struct{}
+
+// withNonIdempotent flags the request context so that checkRetry only retries
+// when the server guarantees the request was not processed
+func withNonIdempotent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retryContextKey{}, true)
+}
+
+func isNonIdempotent(ctx context.Context) bool {
+	v, _ := ctx.Value(retryContextKey{}).(bool)
+	return v
+}
+
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryableError can be implemented by transport errors to opt out of retries
+type retryableError interface {
+	Retryable() bool
+}
+
+// checkRetry decides whether a request should be retried.
+//
+// Idempotent requests are retried on network errors, rate limiting and server
+// errors. Non-idempotent requests (e.g. POST to create an issue) are only
+// retried when the server rejected them with 429 Too Many Requests, as any
+// other failure may have happened after the request was processed.
+func checkRetry(ctx context.Context, resp *http.Response, err error) (bool, error) {
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	if isNonIdempotent(ctx) {
+		if err == nil && resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+			return true, nil
+		}
+		return false, nil
+	}
+
+	// Default retry logic for network errors, unless the transport reports
+	// the error as permanent
+	if err != nil {
+		var r retryableError
+		if errors.As(err, &r) && !r.Retryable() {
+			return false, nil
+		}
+		return retryablehttp.DefaultRetryPolicy(ctx, resp, err)
+	}
+
+	// Retry on rate limiting
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		return true, nil
+	}
+
+	// Use default retry policy for other cases
+	return retryablehttp.DefaultRetryPolicy(ctx, resp, err)
+}
+
+// backoff computes the wait before the next attempt. Server hints from the
+// Retry-After header, or X-RateLimit-Reset when the quota is exhausted, take
+// precedence over exponential backoff; they are capped at max.
+func backoff(minWait, maxWait time.Duration, attemptNum int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if wait, ok := serverRetryHint(resp, time.Now()); ok {
+			if wait > maxWait {
+				return maxWait
+			}
+			if wait < 0 {
+				return 0
+			}
+			return wait
+		}
+	}
+
+	return retryablehttp.DefaultBackoff(minWait, maxWait, attemptNum, nil)
+}
+
+func serverRetryHint(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return 0, false
+	}
+
+	if v := strings.TrimSpace(resp.Header.Get(headerRetryAfter)); v != "" {
+		if seconds, err := strconv.Atoi(v); err == nil {
+			return time.Duration(seconds) * time.Second, true
+		}
+		if t, err := http.ParseTime(v); err == nil {
+			return t.Sub(now), true
+		}
+	}
+
+	if resp.Header.Get(headerRateLimitRemaining) == "0" {
+		if reset, ok := parseRateLimitReset(resp.Header.Get(headerRateLimitReset)); ok {
+			return reset.Sub(now), true
+		}
+	}
+
+	return 0, false
+}
+
+// parseRateLimitReset accepts the ISO 8601 timestamps sent by JIRA Cloud
+// as well as Unix epoch seconds
+func parseRateLimitReset(v string) (time.Time, bool) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return time.Time{}, false
+	}
+
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04Z07:00", "2006-01-02T15:04Z"} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t, true
+		}
+	}
+
+	if epoch, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return time.Unix(epoch, 0), true
+	}
+
+	return time.Time{}, false
+}
@@ -0,0 +1,231 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCheckRetry(t *testing.T) {
+	tests := []struct {
+		name          string
+		nonIdempotent bool
+		status        int
+		err           error
+		want          bool
+	}{
+		{name: "429", status: http.StatusTooManyRequests, want: true},
+		{name: "503", status: http.StatusServiceUnavailable, want: true},
+		{name: "500", status: http.StatusInternalServerError, want: true},
+		{name: "404", status: http.StatusNotFound, want: false},
+		{name: "200", status: http.StatusOK, want: false},
+		{name: "network error", err: errors.New("connection reset"), want: true},
+		{name: "permanent error", err: permanentError{}, want: false},
+		{name: "non-idempotent 429", nonIdempotent: true, status: http.StatusTooManyRequests, want: true},
+		{name: "non-idempotent 503", nonIdempotent: true, status: http.StatusServiceUnavailable, want: false},
+		{name: "non-idempotent 500", nonIdempotent: true, status: http.StatusInternalServerError, want: false},
+		{name: "non-idempotent network error", nonIdempotent: true, err: errors.New("connection reset"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.nonIdempotent {
+				ctx = withNonIdempotent(ctx)
+			}
+
+			var resp *http.Response
+			if tt.err == nil {
+				resp = &http.Response{StatusCode: tt.status, Header: http.Header{}}
+			}
+
+			got, _ := checkRetry(ctx, resp, tt.err)
+			if got != tt.want {
+				t.Errorf("checkRetry = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckRetryCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	retry, err := checkRetry(ctx, &http.Response{StatusCode: http.StatusServiceUnavailable}, nil)
+	if retry || !errors.Is(err, context.Canceled) {
+		t.Errorf("checkRetry = %v, %v, want false, context.Canceled", retry, err)
+	}
+}
+
+// permanentError is a transport error that must not be retried
+type permanentError struct{}
+
+func (permanentError) Error() string   { return "permanent" }
+func (permanentError) Retryable() bool { return false }
+
+func TestServerRetryHint(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		status int
+		header map[string]string
+		want   time.Duration
+		wantOK bool
+	}{
+		{
+			name:   "Retry-After seconds",
+			status: http.StatusTooManyRequests,
+			header: map[string]string{headerRetryAfter: "7"},
+			want:   7 * time.Second,
+			wantOK: true,
+		},
+		{
+			name:   "Retry-After date",
+			status: http.StatusServiceUnavailable,
+			header: map[string]string{headerRetryAfter: now.Add(30 * time.Second).Format(http.TimeFormat)},
+			want:   30 * time.Second,
+			wantOK: true,
+		},
+		{
+			name:   "rate limit reset",
+			status: http.StatusTooManyRequests,
+			header: map[string]string{
+				headerRateLimitRemaining: "0",
+				headerRateLimitReset:     now.Add(time.Minute).Format(time.RFC3339),
+			},
+			want:   time.Minute,
+			wantOK: true,
+		},
+		{
+			name:   "rate limit reset as epoch",
+			status: http.StatusTooManyRequests,
+			header: map[string]string{
+				headerRateLimitRemaining: "0",
+				headerRateLimitReset:     "1709294410",
+			},
+			want:   10 * time.Second,
+			wantOK: true,
+		},
+		{
+			name:   "quota left",
+			status: http.StatusTooManyRequests,
+			header: map[string]string{
+				headerRateLimitRemaining: "3",
+				headerRateLimitReset:     now.Add(time.Minute).Format(time.RFC3339),
+			},
+		},
+		{
+			name:   "no header",
+			status: http.StatusTooManyRequests,
+		},
+		{
+			name:   "invalid Retry-After",
+			status: http.StatusTooManyRequests,
+			header: map[string]string{headerRetryAfter: "soon"},
+		},
+		{
+			name:   "not throttled",
+			status: http.StatusInternalServerError,
+			header: map[string]string{headerRetryAfter: "7"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tt.status, Header: http.Header{}}
+			for k, v := range tt.header {
+				resp.Header.Set(k, v)
+			}
+
+			got, ok := serverRetryHint(resp, now)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("serverRetryHint = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  map[string]string
+		minWait time.Duration
+		maxWait time.Duration
+		want    time.Duration
+	}{
+		{
+			name:    "Retry-After within max",
+			header:  map[string]string{headerRetryAfter: "2"},
+			minWait: time.Millisecond,
+			maxWait: time.Minute,
+			want:    2 * time.Second,
+		},
+		{
+			name:    "Retry-After capped at max",
+			header:  map[string]string{headerRetryAfter: "120"},
+			minWait: time.Millisecond,
+			maxWait: 10 * time.Second,
+			want:    10 * time.Second,
+		},
+		{
+			name:    "reset in the past",
+			header:  map[string]string{headerRateLimitRemaining: "0", headerRateLimitReset: "1"},
+			minWait: time.Millisecond,
+			maxWait: time.Minute,
+			want:    0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+			for k, v := range tt.header {
+				resp.Header.Set(k, v)
+			}
+
+			if got := backoff(tt.minWait, tt.maxWait, 0, resp); got != tt.want {
+				t.Errorf("backoff = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Without a hint the wait grows exponentially within the bounds
+	for attempt := 0; attempt < 5; attempt++ {
+		got := backoff(time.Second, 4*time.Second, attempt, nil)
+		if got < time.Second || got > 4*time.Second {
+			t.Errorf("attempt %d: backoff = %v, want between 1s and 4s", attempt, got)
+		}
+	}
+}
+
+func TestRateLimiterReserve(t *testing.T) {
+	tests := []struct {
+		name  string
+		rate  float64
+		burst int
+		// waits are the expected waits of consecutive reservations
+		waits []time.Duration
+	}{
+		{name: "burst", rate: 10, burst: 3, waits: []time.Duration{0, 0, 0, 100 * time.Millisecond, 200 * time.Millisecond}},
+		{name: "no burst", rate: 4, burst: 0, waits: []time.Duration{0, 250 * time.Millisecond, 500 * time.Millisecond}},
+	}
+
+	// Reservations happen within microseconds, the tolerance absorbs the
+	// tokens refilled meanwhile
+	const tolerance = 5 * time.Millisecond
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newRateLimiter(tt.rate, tt.burst)
+			for i, want := range tt.waits {
+				got := l.reserve()
+				if got > want || got < want-tolerance {
+					t.Errorf("reservation %d: wait = %v, want %v", i, got, want)
+				}
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package jira
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -20,6 +21,10 @@ type Parameter struct {
 // HTTP helper methods
 
 func (c *Client) get(endpoint string, params ...Parameter) (*http.Response, error) {
+	if c.cache != nil {
+		return c.cachedGet(endpoint, params...)
+	}
+
 	return c.doRequest(http.MethodGet, endpoint, nil, params...)
 }
 
@@ -47,9 +52,19 @@ func (c *Client) delete(endpoint string) (*http.Response, error) {
 
 // doRequest creates and executes an HTTP request with proper authentication and headers
 func (c *Client) doRequest(method string, endpoint string, body io.Reader, params ...Parameter) (*http.Response, error) {
+	requestURL, err := c.buildURL(endpoint, params...)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.send(method, requestURL, body, nil)
+}
+
+// buildURL joins the endpoint to the base URL and appends the query parameters
+func (c *Client) buildURL(endpoint string, params ...Parameter) (string, error) {
 	requestURL, err := url.JoinPath(c.baseURL, endpoint)
 	if err != nil {
-		return nil, fmt.Errorf("failed to build URL: %w", err)
+		return "", fmt.Errorf("failed to build URL: %w", err)
 	}
 
 	if len(params) > 0 {
@@ -60,7 +75,18 @@ func (c *Client) doRequest(method string, endpoint string, body io.Reader, param
 		requestURL += "?" + urlParams.Encode()
 	}
 
-	req, err := retryablehttp.NewRequest(method, requestURL, body)
+	return requestURL, nil
+}
+
+// send executes a request against a fully built URL. The body is anything
+// accepted by retryablehttp, the header overrides the default JSON headers.
+func (c *Client) send(method string, requestURL string, body interface{}, header http.Header) (*http.Response, error) {
+	ctx := context.Background()
+	if !isIdempotentMethod(method) {
+		ctx = withNonIdempotent(ctx)
+	}
+
+	req, err := retryablehttp.NewRequestWithContext(ctx, method, requestURL, body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -69,6 +95,12 @@ func (c *Client) doRequest(method string, endpoint string, body io.Reader, param
 	req.Request.Header.Set(headerAuthorization, "Bearer "+c.auth.token)
 	req.Request.Header.Set(headerContentType, contentTypeJSON)
 	req.Request.Header.Set(headerAccept, contentTypeJSON)
+	if c.userAgent != "" {
+		req.Request.Header.Set(headerUserAgent, c.userAgent)
+	}
+	for name, values := range header {
+		req.Request.Header[name] = values
+	}
 
 	return c.retryableClient.Do(req)
 }
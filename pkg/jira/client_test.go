@@ -0,0 +1,547 @@
+package jira_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/lburgazzoli/gira/pkg/jira"
+	"github.com/lburgazzoli/gira/pkg/jira/jiratest"
+)
+
+// newTestClient starts a fake JIRA and returns a client pointing at it
+func newTestClient(t *testing.T, srvOpts []jiratest.Option, opts ...jira.Option) (*jiratest.Server, *jira.Client) {
+	t.Helper()
+
+	srv := jiratest.NewServer(srvOpts...)
+	t.Cleanup(srv.Close)
+
+	client, err := srv.NewClient(opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return srv, client
+}
+
+// countRequests returns how many requests the server received for a method
+// and path
+func countRequests(srv *jiratest.Server, method string, path string) int {
+	n := 0
+	for _, r := range srv.Requests() {
+		if r.Method == method && r.Path == path {
+			n++
+		}
+	}
+	return n
+}
+
+func addIssues(srv *jiratest.Server, project string, n int) {
+	for i := 1; i <= n; i++ {
+		srv.AddIssue(jira.Issue{Fields: jira.IssueFields{
+			Summary:   fmt.Sprintf("Issue %d", i),
+			IssueType: jira.IssueType{Name: "Task"},
+			Project:   jira.Project{Key: project},
+		}})
+	}
+}
+
+func TestSearchIssuesPagination(t *testing.T) {
+	srv, client := newTestClient(t, nil)
+	addIssues(srv, "DEMO", 7)
+	addIssues(srv, "OTHER", 2)
+
+	tests := []struct {
+		name       string
+		startAt    int
+		maxResults int
+		want       []string
+	}{
+		{name: "first page", startAt: 0, maxResults: 3, want: []string{"DEMO-1", "DEMO-2", "DEMO-3"}},
+		{name: "middle page", startAt: 3, maxResults: 3, want: []string{"DEMO-4", "DEMO-5", "DEMO-6"}},
+		{name: "last page", startAt: 6, maxResults: 3, want: []string{"DEMO-7"}},
+		{name: "past the end", startAt: 10, maxResults: 3, want: []string{}},
+		{name: "everything", startAt: 0, maxResults: 50, want: []string{"DEMO-1", "DEMO-2", "DEMO-3", "DEMO-4", "DEMO-5", "DEMO-6", "DEMO-7"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := client.SearchIssues("project = DEMO ORDER BY key", tt.startAt, tt.maxResults, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if result.Total != 7 {
+				t.Errorf("total = %d, want 7", result.Total)
+			}
+			if result.StartAt != tt.startAt {
+				t.Errorf("startAt = %d, want %d", result.StartAt, tt.startAt)
+			}
+
+			got := make([]string, 0, len(result.Issues))
+			for _, issue := range result.Issues {
+				got = append(got, issue.Key)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("keys = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchAllIssues(t *testing.T) {
+	srv, client := newTestClient(t, nil)
+	addIssues(srv, "DEMO", 230)
+	addIssues(srv, "OTHER", 2)
+
+	issues, err := client.SearchAllIssues("project = DEMO ORDER BY key", []string{"key"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(issues) != 230 {
+		t.Fatalf("got %d issues, want 230", len(issues))
+	}
+	for i, issue := range issues {
+		if want := fmt.Sprintf("DEMO-%d", i+1); issue.Key != want {
+			t.Fatalf("issue %d = %s, want %s", i, issue.Key, want)
+		}
+	}
+
+	if n := countRequests(srv, http.MethodGet, "/rest/api/2/search"); n != 3 {
+		t.Errorf("%d search requests, want 3", n)
+	}
+
+	issues, err = client.SearchAllIssues("project = NOPE", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("got %d issues for an empty search", len(issues))
+	}
+}
+
+func TestCreateAndUpdateIssue(t *testing.T) {
+	srv, client := newTestClient(t, nil)
+	srv.AddProject(jira.Project{Key: "DEMO"})
+
+	created, err := client.CreateIssue(&jira.Issue{Fields: jira.IssueFields{
+		Summary:   "Write tests",
+		IssueType: jira.IssueType{Name: "Task"},
+		Project:   jira.Project{Key: "DEMO"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Key != "DEMO-1" {
+		t.Fatalf("created %s, want DEMO-1", created.Key)
+	}
+
+	tests := []struct {
+		name   string
+		update jira.IssueUpdate
+		check  func(*jira.Issue) error
+	}{
+		{
+			name:   "summary",
+			update: jira.IssueUpdate{Fields: map[string]interface{}{"summary": "Write more tests"}},
+			check: func(issue *jira.Issue) error {
+				if issue.Fields.Summary != "Write more tests" {
+					return fmt.Errorf("summary = %q", issue.Fields.Summary)
+				}
+				return nil
+			},
+		},
+		{
+			name:   "description",
+			update: jira.IssueUpdate{Fields: map[string]interface{}{"description": "Cover the client"}},
+			check: func(issue *jira.Issue) error {
+				if issue.Fields.Description != "Cover the client" {
+					return fmt.Errorf("description = %q", issue.Fields.Description)
+				}
+				return nil
+			},
+		},
+		{
+			name:   "labels",
+			update: jira.IssueUpdate{Fields: map[string]interface{}{"labels": []string{"testing"}}},
+			check: func(issue *jira.Issue) error {
+				if fmt.Sprint(issue.Fields.Labels) != "[testing]" {
+					return fmt.Errorf("labels = %v", issue.Fields.Labels)
+				}
+				return nil
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated, err := client.UpdateIssue(created.Key, tt.update)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := tt.check(updated); err != nil {
+				t.Errorf("returned issue: %v", err)
+			}
+
+			stored, ok := srv.Issue(created.Key)
+			if !ok {
+				t.Fatalf("%s is not stored", created.Key)
+			}
+			if err := tt.check(stored); err != nil {
+				t.Errorf("stored issue: %v", err)
+			}
+		})
+	}
+
+	if _, err := client.UpdateIssue("DEMO-99", jira.IssueUpdate{Fields: map[string]interface{}{"summary": "x"}}); err == nil {
+		t.Error("updating a missing issue succeeded")
+	}
+}
+
+func TestRetry(t *testing.T) {
+	tests := []struct {
+		name string
+		// create issues with a POST instead of getting one
+		create       bool
+		fault        jiratest.Fault
+		wantErr      bool
+		wantAttempts int
+	}{
+		{
+			name:         "get retried on 429",
+			fault:        jiratest.Fault{Status: http.StatusTooManyRequests, Times: 2},
+			wantAttempts: 3,
+		},
+		{
+			name: "get retried on 429 with Retry-After",
+			fault: jiratest.Fault{
+				Status: http.StatusTooManyRequests,
+				Header: http.Header{"Retry-After": []string{"1"}},
+			},
+			wantAttempts: 2,
+		},
+		{
+			name:         "get retried on 503",
+			fault:        jiratest.Fault{Status: http.StatusServiceUnavailable, Times: 2},
+			wantAttempts: 3,
+		},
+		{
+			name:         "get gives up after the retries",
+			fault:        jiratest.Fault{Status: http.StatusServiceUnavailable, Times: 10},
+			wantErr:      true,
+			wantAttempts: 4,
+		},
+		{
+			name:         "get not retried on 400",
+			fault:        jiratest.Fault{Status: http.StatusBadRequest},
+			wantErr:      true,
+			wantAttempts: 1,
+		},
+		{
+			name:         "create retried on 429",
+			create:       true,
+			fault:        jiratest.Fault{Status: http.StatusTooManyRequests},
+			wantAttempts: 2,
+		},
+		{
+			name:         "create not retried on 503",
+			create:       true,
+			fault:        jiratest.Fault{Status: http.StatusServiceUnavailable},
+			wantErr:      true,
+			wantAttempts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, client := newTestClient(t, nil)
+			addIssues(srv, "DEMO", 1)
+
+			method, path := http.MethodGet, "/rest/api/2/issue/DEMO-1"
+			if tt.create {
+				method, path = http.MethodPost, "/rest/api/2/issue"
+			}
+			tt.fault.Method, tt.fault.Path = method, path
+			srv.InjectFault(tt.fault)
+
+			var err error
+			if tt.create {
+				_, err = client.CreateIssue(&jira.Issue{Fields: jira.IssueFields{
+					Summary:   "Retried",
+					IssueType: jira.IssueType{Name: "Task"},
+					Project:   jira.Project{Key: "DEMO"},
+				}})
+			} else {
+				_, err = client.GetIssue("DEMO-1")
+			}
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := countRequests(srv, method, path); got != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", got, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestRetryAfterIsHonored(t *testing.T) {
+	srv, client := newTestClient(t, nil, jira.WithRetryPolicy(jira.RetryPolicy{
+		MaxRetries: 1,
+		WaitMin:    time.Millisecond,
+		WaitMax:    5 * time.Second,
+	}))
+	addIssues(srv, "DEMO", 1)
+
+	srv.InjectFault(jiratest.Fault{
+		Path:   "/rest/api/2/issue/DEMO-1",
+		Status: http.StatusTooManyRequests,
+		Header: http.Header{"Retry-After": []string{"1"}},
+	})
+
+	start := time.Now()
+	if _, err := client.GetIssue("DEMO-1"); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %v, want at least the 1s of Retry-After", elapsed)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		rate     float64
+		burst    int
+		requests int
+		// minElapsed is the least time the requests can take
+		minElapsed time.Duration
+	}{
+		{name: "within the burst", rate: 10, burst: 5, requests: 5, minElapsed: 0},
+		{name: "beyond the burst", rate: 20, burst: 1, requests: 5, minElapsed: 150 * time.Millisecond},
+		{name: "burst then rate", rate: 20, burst: 3, requests: 6, minElapsed: 100 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, client := newTestClient(t, nil, jira.WithRateLimit(tt.rate, tt.burst))
+			addIssues(srv, "DEMO", 1)
+
+			start := time.Now()
+			for i := 0; i < tt.requests; i++ {
+				if _, err := client.GetIssue("DEMO-1"); err != nil {
+					t.Fatal(err)
+				}
+			}
+			elapsed := time.Since(start)
+
+			if elapsed < tt.minElapsed {
+				t.Errorf("%d requests took %v, want at least %v", tt.requests, elapsed, tt.minElapsed)
+			}
+		})
+	}
+}
+
+func TestRateLimitHonorsContext(t *testing.T) {
+	srv, client := newTestClient(t, nil,
+		jira.WithRateLimit(0.1, 1),
+		jira.WithTimeout(100*time.Millisecond),
+	)
+	addIssues(srv, "DEMO", 1)
+
+	if _, err := client.GetIssue("DEMO-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The next token comes in 10s, the timeout cancels the wait
+	start := time.Now()
+	_, err := client.GetIssue("DEMO-1")
+	if err == nil {
+		t.Fatal("request beyond the rate limit succeeded before its timeout")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("waited %v for the rate limiter despite the timeout", elapsed)
+	}
+}
+
+func TestSearchRawIssuesKeepsCustomFields(t *testing.T) {
+	srv, client := newTestClient(t, nil)
+
+	points := 5.0
+	srv.Seed(&jiratest.Fixtures{Issues: []jiratest.FixtureIssue{
+		{
+			Issue: jira.Issue{Key: "DEMO-1", Fields: jira.IssueFields{
+				Summary:   "Estimated",
+				IssueType: jira.IssueType{Name: "Story"},
+				Project:   jira.Project{Key: "DEMO"},
+			}},
+			StoryPoints: &points,
+		},
+	}})
+
+	result, err := client.SearchRawIssues("project = DEMO", 0, 10, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Total != 1 || len(result.Issues) != 1 {
+		t.Fatalf("got %d of %d issues, want 1", len(result.Issues), result.Total)
+	}
+
+	var issue struct {
+		Key    string                 `json:"key"`
+		Fields map[string]interface{} `json:"fields"`
+	}
+	if err := json.Unmarshal(result.Issues[0], &issue); err != nil {
+		t.Fatal(err)
+	}
+	if issue.Key != "DEMO-1" {
+		t.Errorf("key = %s, want DEMO-1", issue.Key)
+	}
+	if got := issue.Fields[jiratest.StoryPointsField]; got != 5.0 {
+		t.Errorf("%s = %v, want 5", jiratest.StoryPointsField, got)
+	}
+}
+
+func TestGetComments(t *testing.T) {
+	tests := []struct {
+		name     string
+		comments int
+		// wantRequests is the number of pages fetched
+		wantRequests int
+	}{
+		{name: "none", comments: 0, wantRequests: 1},
+		{name: "one page", comments: 3, wantRequests: 1},
+		{name: "several pages", comments: 230, wantRequests: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, client := newTestClient(t, nil)
+			addIssues(srv, "DEMO", 1)
+			for i := 0; i < tt.comments; i++ {
+				if _, err := srv.AddComment("DEMO-1", fmt.Sprintf("Comment %d", i)); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			comments, err := client.GetComments("DEMO-1")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(comments) != tt.comments {
+				t.Fatalf("got %d comments, want %d", len(comments), tt.comments)
+			}
+			for i, c := range comments {
+				if want := fmt.Sprintf("Comment %d", i); c.Body != want {
+					t.Fatalf("comment %d = %q, want %q", i, c.Body, want)
+				}
+			}
+			if got := countRequests(srv, http.MethodGet, "/rest/api/2/issue/DEMO-1/comment"); got != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+
+	_, client := newTestClient(t, nil)
+	if _, err := client.GetComments("DEMO-99"); err == nil {
+		t.Error("getting the comments of a missing issue succeeded")
+	}
+}
+
+func TestGetFields(t *testing.T) {
+	_, client := newTestClient(t, nil)
+
+	fields, err := client.GetFields()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		ref    string
+		wantID string
+	}{
+		{ref: "summary", wantID: "summary"},
+		{ref: "Story Points", wantID: jiratest.StoryPointsField},
+		{ref: "Epic Link", wantID: jiratest.EpicLinkField},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			field, err := jira.FindField(fields, tt.ref)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if field.ID != tt.wantID {
+				t.Errorf("ID = %s, want %s", field.ID, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestGetChangelog(t *testing.T) {
+	tests := []struct {
+		name    string
+		changes int
+		// dataCenter serves the changelog only through expand=changelog
+		dataCenter bool
+		// wantPages is the number of requests to the changelog endpoint
+		wantPages int
+	}{
+		{name: "empty", changes: 0, wantPages: 1},
+		{name: "one page", changes: 4, wantPages: 1},
+		{name: "several pages", changes: 250, wantPages: 3},
+		{name: "data center", changes: 4, dataCenter: true, wantPages: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, client := newTestClient(t, nil)
+			addIssues(srv, "DEMO", 1)
+
+			created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			for i := 0; i < tt.changes; i++ {
+				err := srv.AddChange("DEMO-1", jira.ChangeHistory{
+					Created: jira.JIRATime{Time: created.Add(time.Duration(i) * time.Hour)},
+					Items:   []jira.ChangeItem{{Field: "summary", ToString: fmt.Sprintf("Summary %d", i)}},
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if tt.dataCenter {
+				srv.InjectFault(jiratest.Fault{
+					Method: http.MethodGet,
+					Path:   "/rest/api/2/issue/DEMO-1/changelog",
+					Status: http.StatusNotFound,
+				})
+			}
+
+			histories, err := client.GetChangelog("DEMO-1")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(histories) != tt.changes {
+				t.Fatalf("got %d histories, want %d", len(histories), tt.changes)
+			}
+			for i, h := range histories {
+				if want := fmt.Sprintf("Summary %d", i); len(h.Items) != 1 || h.Items[0].ToString != want {
+					t.Fatalf("history %d = %+v, want a change to %q", i, h.Items, want)
+				}
+			}
+			if got := countRequests(srv, http.MethodGet, "/rest/api/2/issue/DEMO-1/changelog"); got != tt.wantPages {
+				t.Errorf("changelog requests = %d, want %d", got, tt.wantPages)
+			}
+		})
+	}
+
+	_, client := newTestClient(t, nil)
+	if _, err := client.GetChangelog("DEMO-99"); err == nil {
+		t.Error("getting the changelog of a missing issue succeeded")
+	}
+}
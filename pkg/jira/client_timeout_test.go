@@ -0,0 +1,97 @@
+package jira_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lburgazzoli/gira/pkg/jira"
+)
+
+// slowReader returns at most chunk bytes per read, each after delay
+type slowReader struct {
+	io.ReadCloser
+	chunk int
+	delay time.Duration
+}
+
+func (r *slowReader) Read(p []byte) (int, error) {
+	time.Sleep(r.delay)
+	if len(p) > r.chunk {
+		p = p[:r.chunk]
+	}
+	return r.ReadCloser.Read(p)
+}
+
+func TestTimeoutDoesNotBoundDownloads(t *testing.T) {
+	content := []byte("0123456789")
+
+	// Only the content is slowed down, so that the upload is not affected.
+	// Reading it takes 10 * 20ms, longer than the timeout.
+	srv, client := newTestClient(t, nil,
+		jira.WithTimeout(50*time.Millisecond),
+		jira.WithTransportMiddleware(func(next http.RoundTripper) http.RoundTripper {
+			return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				resp, err := next.RoundTrip(req)
+				if err != nil || req.Method != http.MethodGet {
+					return resp, err
+				}
+				resp.Body = &slowReader{ReadCloser: resp.Body, chunk: 1, delay: 20 * time.Millisecond}
+				return resp, nil
+			})
+		}),
+	)
+	addIssues(srv, "DEMO", 1)
+
+	var opens atomic.Int32
+	attachments, err := client.UploadAttachment("DEMO-1", countingUpload("digits.txt", content, &opens))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	var buf bytes.Buffer
+	n, err := client.DownloadAttachment(&attachments[0], &buf, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Fatalf("download took %v, not longer than the timeout", elapsed)
+	}
+
+	if n != int64(len(content)) || !bytes.Equal(buf.Bytes(), content) {
+		t.Errorf("downloaded %q (%d bytes), want %q", buf.Bytes(), n, content)
+	}
+}
+
+func TestTimeoutBoundsResponseWait(t *testing.T) {
+	srv, client := newTestClient(t, nil,
+		jira.WithRetryPolicy(jira.RetryPolicy{}),
+		jira.WithTimeout(50*time.Millisecond),
+		jira.WithTransportMiddleware(func(next http.RoundTripper) http.RoundTripper {
+			return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				select {
+				case <-req.Context().Done():
+					return nil, req.Context().Err()
+				case <-time.After(5 * time.Second):
+				}
+				return next.RoundTrip(req)
+			})
+		}),
+	)
+	addIssues(srv, "DEMO", 1)
+
+	start := time.Now()
+	_, err := client.GetIssue("DEMO-1")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want a deadline exceeded error", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("waited %v for a response despite the timeout", elapsed)
+	}
+}
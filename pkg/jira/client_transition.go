@@ -0,0 +1,72 @@
+package jira
+
+import (
+	"fmt"
+	"strings"
+)
+
+const apiTransitionsEndpoint = "/rest/api/2/issue/%s/transitions"
+
+// GetTransitions returns the workflow transitions available on an issue
+func (c *Client) GetTransitions(key string) ([]Transition, error) {
+	resp, err := c.get(fmt.Sprintf(apiTransitionsEndpoint, key))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transitions: %w", err)
+	}
+
+	var result struct {
+		Transitions []Transition `json:"transitions"`
+	}
+	if err := handleResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Transitions, nil
+}
+
+// TransitionIssue performs a workflow transition on an issue
+func (c *Client) TransitionIssue(key string, transitionID string) error {
+	body := map[string]interface{}{
+		"transition": map[string]string{"id": transitionID},
+	}
+
+	resp, err := c.post(fmt.Sprintf(apiTransitionsEndpoint, key), body)
+	if err != nil {
+		return fmt.Errorf("failed to transition issue: %w", err)
+	}
+
+	if err := handleResponse(resp, nil); err != nil {
+		return err
+	}
+
+	c.invalidateIssue(key)
+
+	return nil
+}
+
+// FindTransition returns the transition with the given ID or name, or leading
+// to the status with the given name
+func FindTransition(transitions []Transition, ref string) (*Transition, error) {
+	for _, match := range []func(t Transition) bool{
+		func(t Transition) bool { return t.ID == ref },
+		func(t Transition) bool { return strings.EqualFold(t.Name, ref) },
+		func(t Transition) bool { return strings.EqualFold(t.To.Name, ref) },
+	} {
+		for i := range transitions {
+			if match(transitions[i]) {
+				return &transitions[i], nil
+			}
+		}
+	}
+
+	if len(transitions) == 0 {
+		return nil, fmt.Errorf("no transition to %q, the issue has no available transitions", ref)
+	}
+
+	names := make([]string, 0, len(transitions))
+	for _, t := range transitions {
+		names = append(names, t.Name)
+	}
+
+	return nil, fmt.Errorf("no transition to %q, available: %s", ref, strings.Join(names, ", "))
+}
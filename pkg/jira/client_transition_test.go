@@ -0,0 +1,52 @@
+package jira_test
+
+import (
+	"testing"
+
+	"github.com/lburgazzoli/gira/pkg/jira"
+)
+
+func TestFindTransition(t *testing.T) {
+	transitions := []jira.Transition{
+		{ID: "11", Name: "Start", To: jira.Status{Name: "In Progress"}},
+		{ID: "21", Name: "Done", To: jira.Status{Name: "Closed"}},
+		{ID: "31", Name: "Close", To: jira.Status{Name: "Done"}},
+	}
+
+	tests := []struct {
+		ref     string
+		want    string
+		wantErr bool
+	}{
+		{ref: "11", want: "11"},
+		{ref: "start", want: "11"},
+		{ref: "in progress", want: "11"},
+		// Transition names win over target statuses
+		{ref: "Done", want: "21"},
+		{ref: "closed", want: "21"},
+		{ref: "Review", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			transition, err := jira.FindTransition(transitions, tt.ref)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("found %s for %q", transition.ID, tt.ref)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if transition.ID != tt.want {
+				t.Errorf("found %s for %q, want %s", transition.ID, tt.ref, tt.want)
+			}
+		})
+	}
+
+	if _, err := jira.FindTransition(nil, "Done"); err == nil {
+		t.Error("found a transition among none")
+	}
+}
@@ -0,0 +1,155 @@
+package jira
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	apiUserEndpoint       = "/rest/api/2/user"
+	apiUserSearchEndpoint = "/rest/api/2/user/search"
+
+	// CurrentUserAlias refers to the authenticated user wherever a user is expected
+	CurrentUserAlias = "me"
+
+	// userSearchLimit is the number of users returned by a search
+	userSearchLimit = 50
+)
+
+// GetUser returns a user by account ID on Cloud or by username on Data Center
+func (c *Client) GetUser(id string) (*User, error) {
+	var user User
+	if err := c.getUsers(apiUserEndpoint, id, &user); err != nil {
+		return nil, err
+	}
+	if user.AccountID == "" && user.Name == "" {
+		return nil, fmt.Errorf("user %s does not exist", id)
+	}
+
+	return &user, nil
+}
+
+// SearchUsers returns the users whose display name, email address or
+// username match the query
+func (c *Client) SearchUsers(query string) ([]User, error) {
+	users := make([]User, 0)
+	if err := c.getUsers(apiUserSearchEndpoint, query, &users,
+		Parameter{Key: "maxResults", Value: fmt.Sprintf("%d", userSearchLimit)}); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// getUsers queries a user endpoint. Cloud takes the accountId and query
+// parameters and rejects username, Data Center only takes username: the
+// Cloud parameter is tried first, then the Data Center one when rejected.
+func (c *Client) getUsers(endpoint string, value string, v interface{}, params ...Parameter) error {
+	cloudParam := "accountId"
+	if endpoint == apiUserSearchEndpoint {
+		cloudParam = "query"
+	}
+
+	var lastStatus int
+	for _, key := range []string{cloudParam, "username"} {
+		resp, err := c.get(endpoint, append([]Parameter{{Key: key, Value: value}}, params...)...)
+		if err != nil {
+			return fmt.Errorf("failed to get users: %w", err)
+		}
+
+		switch resp.StatusCode {
+		case http.StatusBadRequest, http.StatusNotFound:
+			lastStatus = resp.StatusCode
+			_ = resp.Body.Close()
+			continue
+		}
+
+		return handleResponse(resp, v)
+	}
+
+	if lastStatus == http.StatusNotFound || endpoint == apiUserEndpoint {
+		return fmt.Errorf("user %s does not exist", value)
+	}
+
+	return fmt.Errorf("failed to search users matching %q", value)
+}
+
+// ResolveUser finds the user referred to by "me", an account ID, a username,
+// an email address or a display name. Partial names are accepted as long as
+// they match a single user.
+func (c *Client) ResolveUser(ref string) (*User, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return nil, fmt.Errorf("empty user")
+	}
+
+	if strings.EqualFold(ref, CurrentUserAlias) {
+		me, err := c.Myself()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current user: %w", err)
+		}
+		return me, nil
+	}
+
+	candidates, err := c.SearchUsers(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	// Account IDs are not searchable on every instance
+	if len(candidates) == 0 {
+		user, err := c.GetUser(ref)
+		if err != nil {
+			return nil, fmt.Errorf("no user matches %q", ref)
+		}
+		return user, nil
+	}
+
+	return pickUser(ref, candidates)
+}
+
+// pickUser selects the candidate matching the reference exactly, by
+// identifier, then email address, then display name, or the only candidate
+func pickUser(ref string, candidates []User) (*User, error) {
+	matchers := []func(u *User) bool{
+		func(u *User) bool { return u.AccountID == ref || u.Key == ref || strings.EqualFold(u.Name, ref) },
+		func(u *User) bool { return strings.EqualFold(u.EmailAddress, ref) },
+		func(u *User) bool { return strings.EqualFold(u.DisplayName, ref) },
+	}
+
+	for _, matches := range matchers {
+		found := make([]User, 0, 1)
+		for i := range candidates {
+			if matches(&candidates[i]) {
+				found = append(found, candidates[i])
+			}
+		}
+
+		switch len(found) {
+		case 0:
+			continue
+		case 1:
+			return &found[0], nil
+		default:
+			candidates = found
+		}
+
+		break
+	}
+
+	if len(candidates) == 1 {
+		return &candidates[0], nil
+	}
+
+	names := make([]string, 0, len(candidates))
+	for _, u := range candidates {
+		name := u.DisplayName
+		if u.EmailAddress != "" {
+			name += " <" + u.EmailAddress + ">"
+		}
+		names = append(names, fmt.Sprintf("%s (%s)", name, u.ID()))
+	}
+
+	return nil, fmt.Errorf("%q matches several users, use one of: %s", ref, strings.Join(names, ", "))
+}
@@ -0,0 +1,132 @@
+package jira_test
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/lburgazzoli/gira/pkg/jira"
+	"github.com/lburgazzoli/gira/pkg/jira/jiratest"
+)
+
+var testUsers = []jira.User{
+	{AccountID: "acc-jane", DisplayName: "Jane Doe", EmailAddress: "jane@example.com"},
+	{AccountID: "acc-jane-2", DisplayName: "Jane Doe", EmailAddress: "jane.doe@corp.example.com"},
+	{AccountID: "acc-janet", DisplayName: "Janet", EmailAddress: "janet@example.com"},
+	{AccountID: "acc-john", DisplayName: "John Smith", EmailAddress: "john@example.com"},
+	// A display name that is another user's email address
+	{AccountID: "acc-bot", DisplayName: "john@example.com"},
+}
+
+// userQueries returns the query strings sent to a user endpoint
+func userQueries(srv *jiratest.Server, path string) []string {
+	queries := make([]string, 0)
+	for _, r := range srv.Requests() {
+		if r.Path == path {
+			queries = append(queries, r.Query)
+		}
+	}
+	return queries
+}
+
+func TestResolveUser(t *testing.T) {
+	tests := []struct {
+		ref     string
+		want    string
+		wantErr bool
+	}{
+		{ref: "me", want: "test-user"},
+		{ref: "ME", want: "test-user"},
+		// Several users contain the reference, the exact ID wins
+		{ref: "acc-jane", want: "acc-jane"},
+		// Email addresses win over display names
+		{ref: "JOHN@example.com", want: "acc-john"},
+		// Display names are compared exactly once several users match
+		{ref: "janet", want: "acc-janet"},
+		// A partial reference is fine as long as a single user matches
+		{ref: "Smith", want: "acc-john"},
+		{ref: "Jane Doe", wantErr: true},
+		{ref: "jan", wantErr: true},
+		{ref: "nobody", wantErr: true},
+		{ref: " ", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			_, client := newTestClient(t, []jiratest.Option{
+				jiratest.WithCurrentUser(jira.User{AccountID: "test-user", DisplayName: "Test User"}),
+				jiratest.WithUsers(testUsers...),
+			})
+
+			user, err := client.ResolveUser(tt.ref)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("resolved %q to %s", tt.ref, user.ID())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if user.ID() != tt.want {
+				t.Errorf("resolved %q to %s, want %s", tt.ref, user.ID(), tt.want)
+			}
+		})
+	}
+}
+
+func TestUserDataCenterFallback(t *testing.T) {
+	tests := []struct {
+		name string
+		// status is returned to the Cloud parameter
+		status int
+		call   func(client *jira.Client) (any, error)
+		path   string
+		want   []string
+	}{
+		{
+			name:   "get user rejected with 404",
+			status: http.StatusNotFound,
+			call:   func(client *jira.Client) (any, error) { return client.GetUser("acc-john") },
+			path:   "/rest/api/2/user",
+			want:   []string{"accountId=acc-john", "username=acc-john"},
+		},
+		{
+			name:   "search rejected with 400",
+			status: http.StatusBadRequest,
+			call:   func(client *jira.Client) (any, error) { return client.SearchUsers("john") },
+			path:   "/rest/api/2/user/search",
+			want:   []string{"maxResults=50&query=john", "maxResults=50&username=john"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, client := newTestClient(t, []jiratest.Option{jiratest.WithUsers(testUsers...)})
+			srv.InjectFault(jiratest.Fault{Method: http.MethodGet, Path: tt.path, Status: tt.status})
+
+			result, err := tt.call(client)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result == nil {
+				t.Fatal("no result")
+			}
+
+			if got := userQueries(srv, tt.path); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("queries = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	srv, client := newTestClient(t, []jiratest.Option{jiratest.WithUsers(testUsers...)})
+	srv.InjectFault(jiratest.Fault{Method: http.MethodGet, Path: "/rest/api/2/user/search", Status: http.StatusBadRequest, Times: 2})
+	if users, err := client.SearchUsers("john"); err == nil {
+		t.Errorf("search rejected by both instance types gave %v", users)
+	}
+
+	if user, err := client.GetUser("missing"); err == nil {
+		t.Errorf("getting a missing user gave %v", user)
+	}
+}
@@ -0,0 +1,105 @@
+package jira
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	apiVersionsEndpoint = "/rest/api/2/version"
+	apiVersionEndpoint  = "/rest/api/2/version/%s"
+)
+
+// FindVersion returns the version of a project with the given name or ID
+func (c *Client) FindVersion(projectKey string, nameOrID string) (*Version, error) {
+	versions, err := c.ListVersions(projectKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if i := VersionIndex(versions, nameOrID); i >= 0 {
+		return &versions[i], nil
+	}
+
+	return nil, fmt.Errorf("version %s does not exist in project %s", nameOrID, projectKey)
+}
+
+// VersionIndex returns the index of the version with the given name or ID, or
+// -1 when there is none
+func VersionIndex(versions []Version, nameOrID string) int {
+	for i := range versions {
+		if versions[i].ID == nameOrID || strings.EqualFold(versions[i].Name, nameOrID) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// CreateVersion adds a version to a project
+func (c *Client) CreateVersion(projectKey string, version Version) (*Version, error) {
+	body := struct {
+		Version
+		Project string `json:"project"`
+	}{Version: version, Project: projectKey}
+	body.ID = ""
+	body.Self = ""
+	body.ProjectID = 0
+
+	resp, err := c.post(apiVersionsEndpoint, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create version: %w", err)
+	}
+
+	var created Version
+	if err := handleResponse(resp, &created); err != nil {
+		return nil, err
+	}
+
+	c.invalidateResources(CacheResourceProject)
+
+	return &created, nil
+}
+
+// UpdateVersion changes the fields of a version that are set in the update
+func (c *Client) UpdateVersion(id string, update VersionUpdate) (*Version, error) {
+	resp, err := c.put(fmt.Sprintf(apiVersionEndpoint, id), update)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update version: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("version %s does not exist", id)
+	}
+
+	var version Version
+	if err := handleResponse(resp, &version); err != nil {
+		return nil, err
+	}
+
+	c.invalidateResources(CacheResourceProject)
+	if update.MoveUnfixedIssuesTo != "" {
+		// The moved issues are not known, evict them all
+		c.invalidateResources(CacheResourceIssue, CacheResourceSearch)
+	}
+
+	return &version, nil
+}
+
+// ReleaseVersion marks a version as released on the given date (YYYY-MM-DD).
+// When moveUnresolvedTo is the ID of another version, the unresolved issues
+// of the released version are moved to it.
+func (c *Client) ReleaseVersion(id string, releaseDate string, moveUnresolvedTo string) (*Version, error) {
+	released := true
+	update := VersionUpdate{Released: &released}
+	if releaseDate != "" {
+		update.ReleaseDate = &releaseDate
+	}
+	if moveUnresolvedTo != "" {
+		update.MoveUnfixedIssuesTo = c.baseURL + fmt.Sprintf(apiVersionEndpoint, moveUnresolvedTo)
+	}
+
+	return c.UpdateVersion(id, update)
+}
@@ -0,0 +1,101 @@
+package jira_test
+
+import (
+	"testing"
+
+	"github.com/lburgazzoli/gira/pkg/jira"
+	"github.com/lburgazzoli/gira/pkg/jira/jiratest"
+)
+
+func TestReleaseVersion(t *testing.T) {
+	srv, client := newTestClient(t, nil)
+
+	srv.AddProject(jira.Project{Key: "DEMO", Versions: []jira.Version{{Name: "1.0.0"}, {Name: "1.1.0"}}})
+
+	versions, err := client.ListVersions("DEMO")
+	if err != nil {
+		t.Fatal(err)
+	}
+	released, next := versions[0], versions[1]
+
+	done := jira.Status{Name: "Done", StatusCategory: jira.StatusCategory{Key: jira.StatusCategoryDone}}
+	for _, status := range []jira.Status{{Name: "To Do"}, done} {
+		srv.AddIssue(jira.Issue{Fields: jira.IssueFields{
+			Summary:     status.Name,
+			IssueType:   jira.IssueType{Name: "Task"},
+			Project:     jira.Project{Key: "DEMO"},
+			Status:      status,
+			FixVersions: []jira.Version{{ID: released.ID, Name: released.Name}},
+		}})
+	}
+
+	// JIRA takes the URL of the version, not its ID
+	if _, err := client.UpdateVersion(released.ID, jira.VersionUpdate{MoveUnfixedIssuesTo: next.ID}); err == nil {
+		t.Error("moving the unresolved issues to a version ID succeeded")
+	}
+
+	if i := jira.VersionIndex(versions, "1.1.0"); i != 1 {
+		t.Errorf("index of 1.1.0 = %d, want 1", i)
+	}
+	if i := jira.VersionIndex(versions, "2.0.0"); i != -1 {
+		t.Errorf("index of 2.0.0 = %d, want -1", i)
+	}
+
+	version, err := client.ReleaseVersion(released.ID, "2024-05-01", next.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !version.Released || version.ReleaseDate != "2024-05-01" {
+		t.Errorf("version = %+v, want released on 2024-05-01", version)
+	}
+
+	for key, want := range map[string]string{"DEMO-1": next.Name, "DEMO-2": released.Name} {
+		issue, ok := srv.Issue(key)
+		if !ok {
+			t.Fatalf("%s does not exist", key)
+		}
+		if len(issue.Fields.FixVersions) != 1 || issue.Fields.FixVersions[0].Name != want {
+			t.Errorf("%s fix versions = %+v, want %s", key, issue.Fields.FixVersions, want)
+		}
+	}
+
+	if _, err := client.FindVersion("DEMO", "2.0.0"); err == nil {
+		t.Error("found a missing version")
+	}
+}
+
+func TestInvalidateProjects(t *testing.T) {
+	srv := jiratest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.AddProject(jira.Project{Key: "DEMO", Versions: []jira.Version{{Name: "1.0.0"}}})
+
+	cache, err := jira.NewFileCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := srv.NewClient(jira.WithCache(cache, jira.DefaultCachePolicy()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.ListVersions("DEMO"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Released in the UI meanwhile
+	srv.AddProject(jira.Project{Key: "DEMO", Versions: []jira.Version{{Name: "1.0.0", Released: true}, {Name: "1.1.0"}}})
+
+	if versions, err := client.ListVersions("DEMO"); err != nil || len(versions) != 1 {
+		t.Fatalf("cached versions = %+v (%v), want the cached listing", versions, err)
+	}
+
+	client.InvalidateProjects()
+
+	versions, err := client.ListVersions("DEMO")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 2 || !versions[0].Released {
+		t.Errorf("versions = %+v, want 1.0.0 released and 1.1.0", versions)
+	}
+}
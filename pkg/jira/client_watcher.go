@@ -0,0 +1,117 @@
+package jira
+
+import (
+	"fmt"
+	"net/http"
+)
+
+const (
+	apiWatchersEndpoint = "/rest/api/2/issue/%s/watchers"
+	apiVotesEndpoint    = "/rest/api/2/issue/%s/votes"
+)
+
+// GetWatchers returns the users watching an issue
+func (c *Client) GetWatchers(key string) (*Watchers, error) {
+	resp, err := c.get(fmt.Sprintf(apiWatchersEndpoint, key))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get watchers: %w", err)
+	}
+
+	var watchers Watchers
+	if err := handleResponse(resp, &watchers); err != nil {
+		return nil, err
+	}
+
+	if watchers.Watchers == nil {
+		watchers.Watchers = []User{}
+	}
+
+	return &watchers, nil
+}
+
+// AddWatcher adds a user to the watchers of an issue, or the current user
+// when user is nil
+func (c *Client) AddWatcher(key string, user *User) error {
+	// The body is the bare account ID or username, an empty body stands for
+	// the caller
+	var body interface{}
+	if user != nil {
+		body = user.ID()
+	}
+
+	resp, err := c.post(fmt.Sprintf(apiWatchersEndpoint, key), body)
+	if err != nil {
+		return fmt.Errorf("failed to add watcher: %w", err)
+	}
+
+	if err := handleResponse(resp, nil); err != nil {
+		return err
+	}
+
+	c.invalidateIssue(key)
+
+	return nil
+}
+
+// RemoveWatcher removes a user from the watchers of an issue, or the current
+// user when user is nil
+func (c *Client) RemoveWatcher(key string, user *User) error {
+	// Unlike additions, removals always name the user
+	if user == nil {
+		me, err := c.Myself()
+		if err != nil {
+			return err
+		}
+		user = me
+	}
+
+	param := Parameter{Key: "accountId", Value: user.AccountID}
+	if user.AccountID == "" {
+		param = Parameter{Key: "username", Value: user.Name}
+	}
+
+	resp, err := c.doRequest(http.MethodDelete, fmt.Sprintf(apiWatchersEndpoint, key), nil, param)
+	if err != nil {
+		return fmt.Errorf("failed to remove watcher: %w", err)
+	}
+
+	if err := handleResponse(resp, nil); err != nil {
+		return err
+	}
+
+	c.invalidateIssue(key)
+
+	return nil
+}
+
+// Vote adds the vote of the current user to an issue
+func (c *Client) Vote(key string) error {
+	resp, err := c.post(fmt.Sprintf(apiVotesEndpoint, key), nil)
+	if err != nil {
+		return fmt.Errorf("failed to vote: %w", err)
+	}
+
+	if err := handleResponse(resp, nil); err != nil {
+		return err
+	}
+
+	c.invalidateIssue(key)
+
+	return nil
+}
+
+// Unvote removes the vote of the current user from an issue
+func (c *Client) Unvote(key string) error {
+	resp, err := c.delete(fmt.Sprintf(apiVotesEndpoint, key))
+	if err != nil {
+		return fmt.Errorf("failed to remove vote: %w", err)
+	}
+
+	if err := handleResponse(resp, nil); err != nil {
+		return err
+	}
+
+	c.invalidateIssue(key)
+
+	return nil
+}
@@ -0,0 +1,188 @@
+package jira_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/lburgazzoli/gira/pkg/jira"
+	"github.com/lburgazzoli/gira/pkg/jira/jiratest"
+)
+
+// bodyRecorder is a transport middleware that keeps the bodies of the POST
+// requests sent
+type bodyRecorder struct {
+	mu     sync.Mutex
+	bodies []string
+}
+
+func (b *bodyRecorder) middleware(next http.RoundTripper) http.RoundTripper {
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if req.Method == http.MethodPost {
+			var data []byte
+			if req.Body != nil {
+				var err error
+				if data, err = io.ReadAll(req.Body); err != nil {
+					return nil, err
+				}
+				req.Body = io.NopCloser(bytes.NewReader(data))
+			}
+
+			b.mu.Lock()
+			b.bodies = append(b.bodies, string(bytes.TrimSpace(data)))
+			b.mu.Unlock()
+		}
+
+		return next.RoundTrip(req)
+	})
+}
+
+func TestWatchers(t *testing.T) {
+	cloudUser := jira.User{AccountID: "acc-jane", DisplayName: "Jane Doe"}
+	dcUser := jira.User{Name: "jdoe", Key: "JIRAUSER10100", DisplayName: "Jane Doe"}
+	dcMe := jira.User{Name: "me", Key: "JIRAUSER10000", DisplayName: "Me"}
+
+	tests := []struct {
+		name        string
+		currentUser *jira.User
+		user        *jira.User
+		// wantBody is the body of the addition
+		wantBody string
+		// wantID is the user listed by the server after the addition
+		wantID string
+		// wantQuery is the query of the removal
+		wantQuery string
+	}{
+		{
+			name:      "current user",
+			wantBody:  "",
+			wantID:    "test-user",
+			wantQuery: "accountId=test-user",
+		},
+		{
+			name:      "cloud user",
+			user:      &cloudUser,
+			wantBody:  `"acc-jane"`,
+			wantID:    "acc-jane",
+			wantQuery: "accountId=acc-jane",
+		},
+		{
+			name:        "data center user",
+			currentUser: &dcMe,
+			user:        &dcUser,
+			wantBody:    `"jdoe"`,
+			wantID:      "jdoe",
+			wantQuery:   "username=jdoe",
+		},
+		{
+			name:        "data center current user",
+			currentUser: &dcMe,
+			wantBody:    "",
+			wantID:      "me",
+			wantQuery:   "username=me",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srvOpts := []jiratest.Option{jiratest.WithUsers(cloudUser, dcUser)}
+			if tt.currentUser != nil {
+				srvOpts = append(srvOpts, jiratest.WithCurrentUser(*tt.currentUser))
+			}
+
+			recorder := &bodyRecorder{}
+			srv, client := newTestClient(t, srvOpts, jira.WithTransportMiddleware(recorder.middleware))
+			addIssues(srv, "DEMO", 1)
+
+			if err := client.AddWatcher("DEMO-1", tt.user); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(recorder.bodies) != 1 || recorder.bodies[0] != tt.wantBody {
+				t.Errorf("bodies = %q, want [%q]", recorder.bodies, tt.wantBody)
+			}
+			if got := srv.Watchers("DEMO-1"); !slices.Equal(got, []string{tt.wantID}) {
+				t.Errorf("watchers = %v, want [%s]", got, tt.wantID)
+			}
+
+			watchers, err := client.GetWatchers("DEMO-1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if watchers.WatchCount != 1 || len(watchers.Watchers) != 1 || watchers.Watchers[0].ID() != tt.wantID {
+				t.Errorf("GetWatchers = %+v, want %s only", watchers, tt.wantID)
+			}
+			if wantWatching := tt.user == nil; watchers.IsWatching != wantWatching {
+				t.Errorf("IsWatching = %v, want %v", watchers.IsWatching, wantWatching)
+			}
+
+			if err := client.RemoveWatcher("DEMO-1", tt.user); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := userQueries(srv, "/rest/api/2/issue/DEMO-1/watchers"); !slices.Contains(got, tt.wantQuery) {
+				t.Errorf("watcher queries = %q, want %q", got, tt.wantQuery)
+			}
+			if got := srv.Watchers("DEMO-1"); len(got) != 0 {
+				t.Errorf("watchers after the removal = %v", got)
+			}
+		})
+	}
+}
+
+func TestGetWatchersWithoutWatchers(t *testing.T) {
+	srv, client := newTestClient(t, nil)
+	addIssues(srv, "DEMO", 1)
+
+	watchers, err := client.GetWatchers("DEMO-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if watchers.Watchers == nil || len(watchers.Watchers) != 0 || watchers.IsWatching {
+		t.Errorf("GetWatchers = %+v, want no watchers", watchers)
+	}
+}
+
+func TestVotes(t *testing.T) {
+	srv, client := newTestClient(t, nil)
+	srv.AddIssue(jira.Issue{Key: "DEMO-1", Fields: jira.IssueFields{
+		Summary:  "Reported by someone else",
+		Reporter: &jira.User{AccountID: "acc-jane"},
+	}})
+
+	// Votes are idempotent
+	for range 2 {
+		if err := client.Vote("DEMO-1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := srv.Voters("DEMO-1"); !slices.Equal(got, []string{"test-user"}) {
+		t.Errorf("voters = %v, want [test-user]", got)
+	}
+
+	if err := client.Unvote("DEMO-1"); err != nil {
+		t.Fatal(err)
+	}
+	if got := srv.Voters("DEMO-1"); len(got) != 0 {
+		t.Errorf("voters after unvoting = %v", got)
+	}
+}
+
+func TestVoteOwnIssue(t *testing.T) {
+	srv, client := newTestClient(t, nil)
+	srv.AddIssue(jira.Issue{Key: "DEMO-1", Fields: jira.IssueFields{
+		Summary:  "Reported by me",
+		Reporter: &jira.User{AccountID: "test-user"},
+	}})
+
+	if err := client.Vote("DEMO-1"); err == nil {
+		t.Error("voting for an own issue succeeded")
+	}
+	if got := srv.Voters("DEMO-1"); len(got) != 0 {
+		t.Errorf("voters = %v", got)
+	}
+}
@@ -0,0 +1,131 @@
+package jira
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// JIRA worklog API endpoints
+	apiWorklogsEndpoint = "/rest/api/2/issue/%s/worklog"
+	apiWorklogEndpoint  = "/rest/api/2/issue/%s/worklog/%s"
+
+	// worklogPageSize is the number of worklogs fetched per request
+	worklogPageSize = 100
+	// worklogTimeLayout is the timestamp format accepted for worklog start times
+	worklogTimeLayout = "2006-01-02T15:04:05.000-0700"
+)
+
+// WorklogInput describes time to log on an issue. When updating a worklog,
+// a zero Started or an empty Comment keeps the current value.
+type WorklogInput struct {
+	Started   time.Time
+	TimeSpent time.Duration
+	Comment   string
+}
+
+func (in WorklogInput) body() map[string]any {
+	body := map[string]any{
+		"timeSpentSeconds": int(in.TimeSpent.Seconds()),
+	}
+	if !in.Started.IsZero() {
+		body["started"] = in.Started.Format(worklogTimeLayout)
+	}
+	if in.Comment != "" {
+		body["comment"] = in.Comment
+	}
+	return body
+}
+
+// ListWorklogs returns all the worklogs of an issue, oldest first
+func (c *Client) ListWorklogs(key string) ([]Worklog, error) {
+	worklogs := make([]Worklog, 0)
+	startAt := 0
+
+	for {
+		resp, err := c.get(fmt.Sprintf(apiWorklogsEndpoint, key),
+			Parameter{Key: "startAt", Value: strconv.Itoa(startAt)},
+			Parameter{Key: "maxResults", Value: strconv.Itoa(worklogPageSize)},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list worklogs: %w", err)
+		}
+
+		var page WorklogPage
+		if err := handleResponse(resp, &page); err != nil {
+			return nil, err
+		}
+
+		worklogs = append(worklogs, page.Worklogs...)
+
+		if len(page.Worklogs) == 0 || startAt+len(page.Worklogs) >= page.Total {
+			break
+		}
+
+		startAt += len(page.Worklogs)
+	}
+
+	return worklogs, nil
+}
+
+// AddWorklog logs time on an issue, adjusting the remaining estimate
+func (c *Client) AddWorklog(key string, input WorklogInput) (*Worklog, error) {
+	resp, err := c.post(fmt.Sprintf(apiWorklogsEndpoint, key), input.body())
+	if err != nil {
+		return nil, fmt.Errorf("failed to add worklog: %w", err)
+	}
+
+	var worklog Worklog
+	if err := handleResponse(resp, &worklog); err != nil {
+		return nil, err
+	}
+
+	c.invalidateIssue(key)
+
+	return &worklog, nil
+}
+
+// UpdateWorklog changes the start time, time spent and comment of a worklog
+func (c *Client) UpdateWorklog(key string, id string, input WorklogInput) (*Worklog, error) {
+	resp, err := c.put(fmt.Sprintf(apiWorklogEndpoint, key, id), input.body())
+	if err != nil {
+		return nil, fmt.Errorf("failed to update worklog: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("worklog %s of %s does not exist", id, key)
+	}
+
+	var worklog Worklog
+	if err := handleResponse(resp, &worklog); err != nil {
+		return nil, err
+	}
+
+	c.invalidateIssue(key)
+
+	return &worklog, nil
+}
+
+// DeleteWorklog removes a worklog from an issue
+func (c *Client) DeleteWorklog(key string, id string) error {
+	resp, err := c.delete(fmt.Sprintf(apiWorklogEndpoint, key, id))
+	if err != nil {
+		return fmt.Errorf("failed to delete worklog: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		_ = resp.Body.Close()
+		return fmt.Errorf("worklog %s of %s does not exist", id, key)
+	}
+
+	if err := handleResponse(resp, nil); err != nil {
+		return err
+	}
+
+	c.invalidateIssue(key)
+
+	return nil
+}
@@ -0,0 +1,204 @@
+package jira_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/lburgazzoli/gira/pkg/jira"
+)
+
+func TestWorklogs(t *testing.T) {
+	srv, client := newTestClient(t, nil)
+	addIssues(srv, "DEMO", 1)
+
+	started := time.Date(2024, 3, 4, 9, 30, 0, 0, time.UTC)
+
+	added, err := client.AddWorklog("DEMO-1", jira.WorklogInput{
+		Started:   started,
+		TimeSpent: 90 * time.Minute,
+		Comment:   "Pairing",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added.TimeSpentSeconds != 5400 || added.Comment != "Pairing" || !added.Started.Equal(started) {
+		t.Errorf("added worklog = %+v", added)
+	}
+
+	tests := []struct {
+		name        string
+		input       jira.WorklogInput
+		wantSeconds int
+		wantComment string
+		wantStarted time.Time
+	}{
+		{
+			name:        "time spent only",
+			input:       jira.WorklogInput{TimeSpent: 2 * time.Hour},
+			wantSeconds: 7200,
+			wantComment: "Pairing",
+			wantStarted: started,
+		},
+		{
+			name:        "everything",
+			input:       jira.WorklogInput{Started: started.Add(time.Hour), TimeSpent: 30 * time.Minute, Comment: "Review"},
+			wantSeconds: 1800,
+			wantComment: "Review",
+			wantStarted: started.Add(time.Hour),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated, err := client.UpdateWorklog("DEMO-1", added.ID, tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if updated.TimeSpentSeconds != tt.wantSeconds || updated.Comment != tt.wantComment || !updated.Started.Equal(tt.wantStarted) {
+				t.Errorf("updated worklog = %+v", updated)
+			}
+		})
+	}
+
+	if err := client.DeleteWorklog("DEMO-1", added.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got := srv.Worklogs("DEMO-1"); len(got) != 0 {
+		t.Errorf("worklogs after deletion = %+v", got)
+	}
+
+	missing := []struct {
+		name string
+		call func() error
+	}{
+		{name: "update", call: func() error {
+			_, err := client.UpdateWorklog("DEMO-1", added.ID, jira.WorklogInput{TimeSpent: time.Hour})
+			return err
+		}},
+		{name: "delete", call: func() error { return client.DeleteWorklog("DEMO-1", added.ID) }},
+		{name: "add", call: func() error {
+			_, err := client.AddWorklog("DEMO-99", jira.WorklogInput{TimeSpent: time.Hour})
+			return err
+		}},
+	}
+	for _, m := range missing {
+		if err := m.call(); err == nil {
+			t.Errorf("%s on a missing worklog succeeded", m.name)
+		}
+	}
+}
+
+func TestListWorklogsPagination(t *testing.T) {
+	srv, client := newTestClient(t, nil)
+	addIssues(srv, "DEMO", 1)
+
+	for i := 0; i < 150; i++ {
+		_, err := client.AddWorklog("DEMO-1", jira.WorklogInput{
+			Started:   time.Date(2024, 3, 4, 0, i, 0, 0, time.UTC),
+			TimeSpent: time.Minute,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	worklogs, err := client.ListWorklogs("DEMO-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(worklogs) != 150 {
+		t.Errorf("got %d worklogs, want 150", len(worklogs))
+	}
+	if got := countRequests(srv, http.MethodGet, "/rest/api/2/issue/DEMO-1/worklog"); got != 2 {
+		t.Errorf("requests = %d, want 2 pages", got)
+	}
+}
+
+func TestTimeReport(t *testing.T) {
+	alice := &jira.User{AccountID: "alice", DisplayName: "Alice"}
+	bob := &jira.User{AccountID: "bob", DisplayName: "Bob"}
+
+	at := func(day int, hour int) jira.JIRATime {
+		return jira.JIRATime{Time: time.Date(2024, 3, day, hour, 0, 0, 0, time.UTC)}
+	}
+	worklog := func(id string, author *jira.User, started jira.JIRATime, hours int) jira.Worklog {
+		return jira.Worklog{ID: id, Author: author, Started: started, TimeSpentSeconds: hours * 3600}
+	}
+
+	from := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+
+	demo := &jira.Issue{Key: "DEMO-1", Fields: jira.IssueFields{Summary: "Demo", Project: jira.Project{Key: "DEMO"}}}
+	other := &jira.Issue{Key: "OPS-7", Fields: jira.IssueFields{Summary: "Ops"}}
+
+	tests := []struct {
+		name        string
+		author      func(*jira.User) bool
+		wantEntries int
+		wantByDay   string
+		wantByIssue string
+		wantTotal   time.Duration
+	}{
+		{
+			name:        "everyone",
+			wantEntries: 4,
+			wantByDay:   "[2024-03-04:3h0m0s 2024-03-05:4h0m0s]",
+			wantByIssue: "[OPS-7:4h0m0s DEMO-1:3h0m0s]",
+			wantTotal:   7 * time.Hour,
+		},
+		{
+			name:        "alice",
+			author:      func(u *jira.User) bool { return u != nil && u.AccountID == "alice" },
+			wantEntries: 2,
+			wantByDay:   "[2024-03-04:2h0m0s 2024-03-05:3h0m0s]",
+			wantByIssue: "[OPS-7:3h0m0s DEMO-1:2h0m0s]",
+			wantTotal:   5 * time.Hour,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := jira.NewTimeEntries(demo, []jira.Worklog{
+				worklog("1", alice, at(4, 9), 2),
+				worklog("2", bob, at(4, 14), 1),
+				// Outside of the range
+				worklog("3", alice, at(11, 9), 5),
+			}, from, to, tt.author)
+			entries = append(entries, jira.NewTimeEntries(other, []jira.Worklog{
+				worklog("4", alice, at(5, 9), 3),
+				worklog("5", bob, at(5, 10), 1),
+				worklog("6", bob, at(1, 10), 1),
+			}, from, to, tt.author)...)
+
+			report := jira.BuildTimeReport(entries, from, to)
+
+			totals := func(values []jira.TimeTotal) string {
+				s := make([]string, 0, len(values))
+				for _, v := range values {
+					s = append(s, fmt.Sprintf("%s:%v", v.Name, v.TimeSpent))
+				}
+				return fmt.Sprint(s)
+			}
+
+			if len(report.Entries) != tt.wantEntries {
+				t.Errorf("got %d entries, want %d", len(report.Entries), tt.wantEntries)
+			}
+			if got := totals(report.ByDay); got != tt.wantByDay {
+				t.Errorf("by day = %s, want %s", got, tt.wantByDay)
+			}
+			if got := totals(report.ByIssue); got != tt.wantByIssue {
+				t.Errorf("by issue = %s, want %s", got, tt.wantByIssue)
+			}
+			if report.Total != tt.wantTotal {
+				t.Errorf("total = %v, want %v", report.Total, tt.wantTotal)
+			}
+			for _, p := range report.ByProject {
+				if p.Name != "DEMO" && p.Name != "OPS" {
+					t.Errorf("unexpected project %q, OPS-7 should fall back to its key prefix", p.Name)
+				}
+			}
+		})
+	}
+}
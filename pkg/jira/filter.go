@@ -0,0 +1,423 @@
+package jira
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// TreeFilter is a parsed tree filter expression such as
+// `status != Done AND type in (Story, Bug)`. Clauses joined by AND bind
+// tighter than clauses joined by OR.
+type TreeFilter struct {
+	// groups holds the OR-ed groups, each group is a list of AND-ed conditions
+	groups [][]filterCondition
+
+	// CurrentUser is used to resolve the `me` alias in user fields
+	CurrentUser *User
+}
+
+type filterCondition struct {
+	field  string
+	op     string
+	values []string
+}
+
+// TreeFilterOptions controls how an issue tree is pruned
+type TreeFilterOptions struct {
+	// Filter keeps only matching issues and their ancestors; nil matches everything
+	Filter *TreeFilter
+	// HideDone removes issues whose status category is done
+	HideDone bool
+	// CollapseDepth removes descendants below the given depth (0 disables collapsing)
+	CollapseDepth int
+}
+
+// filterFields maps the accepted field names and aliases to their canonical name
+var filterFields = map[string]string{
+	"key":            "key",
+	"summary":        "summary",
+	"status":         "status",
+	"category":       "category",
+	"statuscategory": "category",
+	"type":           "type",
+	"issuetype":      "type",
+	"priority":       "priority",
+	"assignee":       "assignee",
+	"reporter":       "reporter",
+}
+
+// ParseTreeFilter parses a tree filter expression.
+//
+// Supported operators are `=`, `!=`, `~` (contains), `!~`, `in (...)` and
+// `not in (...)`. Values may be quoted with single or double quotes.
+func ParseTreeFilter(expr string) (*TreeFilter, error) {
+	tokens, err := tokenizeFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty filter expression")
+	}
+
+	p := &filterParser{tokens: tokens}
+	filter := &TreeFilter{}
+
+	group := make([]filterCondition, 0)
+	for {
+		cond, err := p.parseCondition()
+		if err != nil {
+			return nil, err
+		}
+		group = append(group, cond)
+
+		if p.done() {
+			break
+		}
+
+		switch strings.ToUpper(p.next()) {
+		case "AND":
+		case "OR":
+			filter.groups = append(filter.groups, group)
+			group = make([]filterCondition, 0)
+		default:
+			return nil, fmt.Errorf("expected AND or OR at %q", p.tokens[p.pos-1])
+		}
+	}
+
+	filter.groups = append(filter.groups, group)
+
+	return filter, nil
+}
+
+// RequiresCurrentUser reports whether the expression references the `me` alias
+func (f *TreeFilter) RequiresCurrentUser() bool {
+	for _, group := range f.groups {
+		for _, cond := range group {
+			if cond.field != "assignee" && cond.field != "reporter" {
+				continue
+			}
+			for _, v := range cond.values {
+				if strings.EqualFold(v, CurrentUserAlias) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// Match reports whether the issue satisfies the expression
+func (f *TreeFilter) Match(issue *Issue) bool {
+	for _, group := range f.groups {
+		matched := true
+		for _, cond := range group {
+			if !f.matchCondition(cond, issue) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *TreeFilter) matchCondition(cond filterCondition, issue *Issue) bool {
+	var actual []string
+	var user *User
+	isUser := false
+
+	switch cond.field {
+	case "key":
+		actual = []string{issue.Key}
+	case "summary":
+		actual = []string{issue.Fields.Summary}
+	case "status":
+		actual = []string{issue.Fields.Status.Name}
+	case "category":
+		actual = []string{issue.Fields.Status.StatusCategory.Key, issue.Fields.Status.StatusCategory.Name}
+	case "type":
+		actual = []string{issue.Fields.IssueType.Name}
+	case "priority":
+		actual = []string{issue.Fields.Priority.Name}
+	case "assignee":
+		user, isUser = issue.Fields.Assignee, true
+	case "reporter":
+		user, isUser = issue.Fields.Reporter, true
+	}
+
+	matchValue := func(expected string) bool {
+		if isUser {
+			return f.matchUser(user, expected)
+		}
+		for _, a := range actual {
+			if cond.op == "~" || cond.op == "!~" {
+				if strings.Contains(strings.ToLower(a), strings.ToLower(expected)) {
+					return true
+				}
+			} else if strings.EqualFold(a, expected) {
+				return true
+			}
+		}
+		return false
+	}
+
+	found := false
+	for _, v := range cond.values {
+		if matchValue(v) {
+			found = true
+			break
+		}
+	}
+
+	switch cond.op {
+	case "!=", "!~", "not in":
+		return !found
+	default:
+		return found
+	}
+}
+
+func (f *TreeFilter) matchUser(user *User, expected string) bool {
+	switch strings.ToLower(expected) {
+	case "empty", "unassigned", "null":
+		return user == nil
+	case CurrentUserAlias:
+		if user == nil || f.CurrentUser == nil {
+			return false
+		}
+		if f.CurrentUser.Is(user) {
+			return true
+		}
+		return f.CurrentUser.DisplayName != "" && user.DisplayName == f.CurrentUser.DisplayName
+	}
+
+	if user == nil {
+		return false
+	}
+
+	return strings.EqualFold(user.AccountID, expected) ||
+		strings.EqualFold(user.Name, expected) ||
+		strings.EqualFold(user.DisplayName, expected) ||
+		strings.EqualFold(user.EmailAddress, expected)
+}
+
+// FilterIssueTree prunes the tree rooted at issue in place. The root issue is
+// always kept; descendants are kept when they match or have a matching descendant.
+func FilterIssueTree(issue *Issue, opts TreeFilterOptions) {
+	if issue == nil {
+		return
+	}
+
+	pruneChildren(issue, opts)
+
+	if opts.CollapseDepth > 0 {
+		collapseTree(issue, 0, opts.CollapseDepth)
+	}
+}
+
+// pruneChildren removes non-matching subtrees and reports whether the issue
+// itself or any of its descendants matched
+func pruneChildren(issue *Issue, opts TreeFilterOptions) bool {
+	kept := make([]*Issue, 0, len(issue.Children))
+	for _, child := range issue.Children {
+		if pruneChildren(child, opts) {
+			kept = append(kept, child)
+		}
+	}
+	issue.Children = kept
+
+	return len(kept) > 0 || matchesTreeFilter(issue, opts)
+}
+
+func matchesTreeFilter(issue *Issue, opts TreeFilterOptions) bool {
+	if opts.HideDone && issue.Fields.Status.StatusCategory.Key == StatusCategoryDone {
+		return false
+	}
+	if opts.Filter != nil && !opts.Filter.Match(issue) {
+		return false
+	}
+	return true
+}
+
+func collapseTree(issue *Issue, depth int, maxDepth int) {
+	if depth >= maxDepth {
+		issue.Collapsed = countDescendants(issue)
+		issue.Children = make([]*Issue, 0)
+		return
+	}
+
+	for _, child := range issue.Children {
+		collapseTree(child, depth+1, maxDepth)
+	}
+}
+
+func countDescendants(issue *Issue) int {
+	count := 0
+	for _, child := range issue.Children {
+		count += 1 + countDescendants(child)
+	}
+	return count
+}
+
+// Expression parsing
+
+type filterParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *filterParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *filterParser) peek() string {
+	if p.done() {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *filterParser) next() string {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *filterParser) parseCondition() (filterCondition, error) {
+	name := p.next()
+	field, ok := filterFields[strings.ToLower(name)]
+	if !ok {
+		return filterCondition{}, fmt.Errorf("unsupported filter field %q", name)
+	}
+
+	cond := filterCondition{field: field}
+
+	op := strings.ToLower(p.next())
+	switch op {
+	case "=", "!=", "~", "!~":
+		cond.op = op
+		value := p.parseValue()
+		if value == "" {
+			return filterCondition{}, fmt.Errorf("missing value for field %q", name)
+		}
+		cond.values = []string{value}
+	case "in":
+		cond.op = "in"
+	case "not":
+		if !strings.EqualFold(p.next(), "in") {
+			return filterCondition{}, fmt.Errorf("expected 'in' after 'not' for field %q", name)
+		}
+		cond.op = "not in"
+	case "":
+		return filterCondition{}, fmt.Errorf("missing operator for field %q", name)
+	default:
+		return filterCondition{}, fmt.Errorf("unsupported operator %q for field %q", op, name)
+	}
+
+	if cond.op == "in" || cond.op == "not in" {
+		values, err := p.parseList()
+		if err != nil {
+			return filterCondition{}, fmt.Errorf("invalid list for field %q: %w", name, err)
+		}
+		cond.values = values
+	}
+
+	return cond, nil
+}
+
+// parseValue joins bare words until the next AND/OR keyword so that values
+// like `In Progress` do not need quoting
+func (p *filterParser) parseValue() string {
+	words := make([]string, 0, 1)
+	for !p.done() {
+		t := p.peek()
+		if strings.EqualFold(t, "AND") || strings.EqualFold(t, "OR") {
+			break
+		}
+		words = append(words, unquoteFilterToken(p.next()))
+	}
+	return strings.Join(words, " ")
+}
+
+func (p *filterParser) parseList() ([]string, error) {
+	if p.next() != "(" {
+		return nil, fmt.Errorf("expected '('")
+	}
+
+	values := make([]string, 0)
+	words := make([]string, 0, 1)
+
+	for {
+		if p.done() {
+			return nil, fmt.Errorf("missing ')'")
+		}
+
+		t := p.next()
+		switch t {
+		case ",", ")":
+			if len(words) > 0 {
+				values = append(values, strings.Join(words, " "))
+				words = words[:0]
+			}
+			if t == ")" {
+				if len(values) == 0 {
+					return nil, fmt.Errorf("empty list")
+				}
+				return values, nil
+			}
+		default:
+			words = append(words, unquoteFilterToken(t))
+		}
+	}
+}
+
+func tokenizeFilter(expr string) ([]string, error) {
+	tokens := make([]string, 0)
+	runes := []rune(expr)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')' || r == ',' || r == '=' || r == '~':
+			tokens = append(tokens, string(r))
+			i++
+		case r == '!':
+			if i+1 >= len(runes) || (runes[i+1] != '=' && runes[i+1] != '~') {
+				return nil, fmt.Errorf("unexpected '!' at position %d", i)
+			}
+			tokens = append(tokens, string(runes[i:i+2]))
+			i += 2
+		case r == '"' || r == '\'':
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				end++
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("unterminated quoted value at position %d", i)
+			}
+			tokens = append(tokens, string(runes[i:end+1]))
+			i = end + 1
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune("()=,!~\"'", runes[i]) {
+				i++
+			}
+			tokens = append(tokens, string(runes[start:i]))
+		}
+	}
+
+	return tokens, nil
+}
+
+func unquoteFilterToken(t string) string {
+	if len(t) >= 2 && (t[0] == '"' || t[0] == '\'') && t[len(t)-1] == t[0] {
+		return t[1 : len(t)-1]
+	}
+	return t
+}
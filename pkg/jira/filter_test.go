@@ -0,0 +1,220 @@
+package jira_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lburgazzoli/gira/pkg/jira"
+)
+
+func filterIssue(key string, issueType string, status string, category string, assignee *jira.User, children ...*jira.Issue) *jira.Issue {
+	issue := &jira.Issue{Key: key, Children: children}
+	issue.Fields.Summary = "Summary of " + key
+	issue.Fields.IssueType.Name = issueType
+	issue.Fields.Status.Name = status
+	issue.Fields.Status.StatusCategory.Key = category
+	issue.Fields.Assignee = assignee
+	return issue
+}
+
+// treeKeys lists the keys of a tree in depth-first order
+func treeKeys(issue *jira.Issue) []string {
+	keys := []string{issue.Key}
+	for _, child := range issue.Children {
+		keys = append(keys, treeKeys(child)...)
+	}
+	return keys
+}
+
+func TestParseTreeFilterErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+	}{
+		{name: "empty", expr: "  "},
+		{name: "unknown field", expr: "color = red"},
+		{name: "missing operator", expr: "status"},
+		{name: "unsupported operator", expr: "status > Done"},
+		{name: "missing value", expr: "status = AND type = Bug"},
+		{name: "lone bang", expr: "status ! Done"},
+		{name: "unterminated quote", expr: `status = "In Progress`},
+		{name: "not without in", expr: "type not (Bug)"},
+		{name: "list without parenthesis", expr: "type in Bug"},
+		{name: "unclosed list", expr: "type in (Bug, Story"},
+		{name: "empty list", expr: "type in ()"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := jira.ParseTreeFilter(tt.expr); err == nil {
+				t.Errorf("parsing %q succeeded", tt.expr)
+			}
+		})
+	}
+}
+
+func TestTreeFilterMatch(t *testing.T) {
+	me := &jira.User{AccountID: "acc-1", DisplayName: "Jane Doe"}
+	other := &jira.User{AccountID: "acc-2", DisplayName: "John Roe", EmailAddress: "john@example.com"}
+
+	story := filterIssue("DEMO-1", "Story", "In Progress", jira.StatusCategoryInProgress, me)
+	bug := filterIssue("DEMO-2", "Bug", "Done", jira.StatusCategoryDone, other)
+	task := filterIssue("DEMO-3", "Task", "To Do", jira.StatusCategoryNew, nil)
+	issues := []*jira.Issue{story, bug, task}
+
+	tests := []struct {
+		name string
+		expr string
+		want []string
+	}{
+		{name: "equals is case insensitive", expr: "status = done", want: []string{"DEMO-2"}},
+		{name: "not equals", expr: "status != Done", want: []string{"DEMO-1", "DEMO-3"}},
+		{name: "unquoted value with spaces", expr: "status = In Progress", want: []string{"DEMO-1"}},
+		{name: "quoted value", expr: `status = 'In Progress'`, want: []string{"DEMO-1"}},
+		{name: "contains", expr: "summary ~ demo-3", want: []string{"DEMO-3"}},
+		{name: "does not contain", expr: "summary !~ DEMO-3", want: []string{"DEMO-1", "DEMO-2"}},
+		{name: "in", expr: "type in (Story, Bug)", want: []string{"DEMO-1", "DEMO-2"}},
+		{name: "not in", expr: "issuetype not in (Story, Bug)", want: []string{"DEMO-3"}},
+		{name: "status category", expr: "category = new", want: []string{"DEMO-3"}},
+		{name: "assignee me", expr: "assignee = me", want: []string{"DEMO-1"}},
+		{name: "assignee by email", expr: "assignee = john@example.com", want: []string{"DEMO-2"}},
+		{name: "unassigned", expr: "assignee = empty", want: []string{"DEMO-3"}},
+		{name: "and", expr: "type != Task AND status != Done", want: []string{"DEMO-1"}},
+		{name: "or", expr: "type = Task OR status = Done", want: []string{"DEMO-2", "DEMO-3"}},
+		// Read as `type = Task OR (type = Bug AND status != Done)`
+		{name: "and binds tighter than or", expr: "type = Task OR type = Bug AND status != Done", want: []string{"DEMO-3"}},
+		{name: "keywords are case insensitive", expr: "type = Task or type = Story and assignee = me", want: []string{"DEMO-1", "DEMO-3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := jira.ParseTreeFilter(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			filter.CurrentUser = me
+
+			got := make([]string, 0)
+			for _, issue := range issues {
+				if filter.Match(issue) {
+					got = append(got, issue.Key)
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("matched %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTreeFilterCurrentUser(t *testing.T) {
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{expr: "assignee = me", want: true},
+		{expr: "reporter in (Jane, ME)", want: true},
+		{expr: "summary ~ me", want: false},
+		{expr: "assignee = Jane", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			filter, err := jira.ParseTreeFilter(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := filter.RequiresCurrentUser(); got != tt.want {
+				t.Errorf("RequiresCurrentUser() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Without a resolved current user `me` matches nobody
+	filter, err := jira.ParseTreeFilter("assignee = me")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filter.Match(filterIssue("DEMO-1", "Task", "To Do", jira.StatusCategoryNew, &jira.User{AccountID: "acc-1"})) {
+		t.Error("me matched without a current user")
+	}
+}
+
+func TestFilterIssueTree(t *testing.T) {
+	// DEMO-1 (epic)
+	// ├── DEMO-2 story, done
+	// │   └── DEMO-4 bug, in progress
+	// └── DEMO-3 story, to do
+	//     └── DEMO-5 sub-task, done
+	//         └── DEMO-6 sub-task, done
+	newTree := func() *jira.Issue {
+		return filterIssue("DEMO-1", "Epic", "In Progress", jira.StatusCategoryInProgress, nil,
+			filterIssue("DEMO-2", "Story", "Done", jira.StatusCategoryDone, nil,
+				filterIssue("DEMO-4", "Bug", "In Progress", jira.StatusCategoryInProgress, nil)),
+			filterIssue("DEMO-3", "Story", "To Do", jira.StatusCategoryNew, nil,
+				filterIssue("DEMO-5", "Sub-task", "Done", jira.StatusCategoryDone, nil,
+					filterIssue("DEMO-6", "Sub-task", "Done", jira.StatusCategoryDone, nil))),
+		)
+	}
+
+	tests := []struct {
+		name          string
+		expr          string
+		hideDone      bool
+		collapseDepth int
+		want          []string
+		// wantCollapsed maps keys to their number of collapsed descendants
+		wantCollapsed map[string]int
+	}{
+		{name: "no options", want: []string{"DEMO-1", "DEMO-2", "DEMO-4", "DEMO-3", "DEMO-5", "DEMO-6"}},
+		{name: "keeps ancestors of matches", expr: "type = Bug", want: []string{"DEMO-1", "DEMO-2", "DEMO-4"}},
+		{name: "root is always kept", expr: "type = Task", want: []string{"DEMO-1"}},
+		{name: "hide done", hideDone: true, want: []string{"DEMO-1", "DEMO-2", "DEMO-4", "DEMO-3"}},
+		{name: "filter and hide done", expr: "type = Story", hideDone: true, want: []string{"DEMO-1", "DEMO-3"}},
+		{
+			name:          "collapse",
+			collapseDepth: 1,
+			want:          []string{"DEMO-1", "DEMO-2", "DEMO-3"},
+			wantCollapsed: map[string]int{"DEMO-2": 1, "DEMO-3": 2},
+		},
+		{
+			name:          "collapse after pruning",
+			expr:          "status = Done",
+			collapseDepth: 2,
+			want:          []string{"DEMO-1", "DEMO-2", "DEMO-3", "DEMO-5"},
+			wantCollapsed: map[string]int{"DEMO-5": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := jira.TreeFilterOptions{HideDone: tt.hideDone, CollapseDepth: tt.collapseDepth}
+			if tt.expr != "" {
+				filter, err := jira.ParseTreeFilter(tt.expr)
+				if err != nil {
+					t.Fatal(err)
+				}
+				opts.Filter = filter
+			}
+
+			root := newTree()
+			jira.FilterIssueTree(root, opts)
+
+			if got := treeKeys(root); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tree = %v, want %v", got, tt.want)
+			}
+
+			var check func(issue *jira.Issue)
+			check = func(issue *jira.Issue) {
+				if issue.Collapsed != tt.wantCollapsed[issue.Key] {
+					t.Errorf("%s collapsed %d, want %d", issue.Key, issue.Collapsed, tt.wantCollapsed[issue.Key])
+				}
+				for _, child := range issue.Children {
+					check(child)
+				}
+			}
+			check(root)
+		})
+	}
+}
@@ -0,0 +1,320 @@
+package jira
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FlowGrouping selects how time in status is aggregated
+type FlowGrouping string
+
+const (
+	// FlowGroupByStatus reports the time spent in each status
+	FlowGroupByStatus FlowGrouping = "status"
+	// FlowGroupByCategory reports the time spent in each status category,
+	// which allows comparing projects whose workflows use different names
+	FlowGroupByCategory FlowGrouping = "category"
+)
+
+// FlowOptions controls how flow metrics are computed
+type FlowOptions struct {
+	// FromStatus starts the cycle time, ToStatus ends it. They match a status
+	// by name or by status category (name or key), so "In Progress" matches
+	// "In Review" as well when both belong to the In Progress category.
+	FromStatus string
+	ToStatus   string
+	GroupBy    FlowGrouping
+	// Statuses provides the category of the statuses found in changelogs
+	Statuses []Status
+	// Now ends the open intervals of unfinished issues
+	Now time.Time
+}
+
+// IssueFlow holds the flow metrics of a single issue
+type IssueFlow struct {
+	Key      string     `json:"key"`
+	Summary  string     `json:"summary"`
+	Type     string     `json:"type"`
+	Status   string     `json:"status"`
+	Created  time.Time  `json:"created"`
+	Started  *time.Time `json:"started,omitempty"`
+	Finished *time.Time `json:"finished,omitempty"`
+	// CycleTime and LeadTime are only set for finished issues
+	CycleTime    time.Duration            `json:"cycleTime,omitempty"`
+	LeadTime     time.Duration            `json:"leadTime,omitempty"`
+	TimeInStatus map[string]time.Duration `json:"timeInStatus"`
+}
+
+// Percentiles summarizes a distribution of durations
+type Percentiles struct {
+	Count int           `json:"count"`
+	P50   time.Duration `json:"p50"`
+	P85   time.Duration `json:"p85"`
+	P95   time.Duration `json:"p95"`
+}
+
+// WeeklyThroughput is the number of issues finished in the week starting on
+// Week, a Monday at midnight UTC
+type WeeklyThroughput struct {
+	Week     time.Time `json:"week"`
+	Finished int       `json:"finished"`
+}
+
+// FlowReport aggregates the flow metrics of a set of issues
+type FlowReport struct {
+	FromStatus   string                 `json:"fromStatus"`
+	ToStatus     string                 `json:"toStatus"`
+	GroupBy      FlowGrouping           `json:"groupBy"`
+	Issues       []IssueFlow            `json:"issues"`
+	CycleTime    Percentiles            `json:"cycleTime"`
+	LeadTime     Percentiles            `json:"leadTime"`
+	Throughput   []WeeklyThroughput     `json:"throughput"`
+	TimeInStatus map[string]Percentiles `json:"timeInStatus"`
+}
+
+// statusInterval is a period of time an issue spent in a status
+type statusInterval struct {
+	status Status
+	start  time.Time
+}
+
+// ComputeIssueFlow derives the flow metrics of an issue from its changelog.
+//
+// The cycle time runs from the first time the issue entered the from status
+// to the last time it entered the to status; the lead time runs from the
+// creation of the issue to the same point. Issues not currently in the to
+// status are not finished.
+func ComputeIssueFlow(issue *Issue, histories []ChangeHistory, opts FlowOptions) IssueFlow {
+	categories := statusCategoryIndex(opts.Statuses, issue.Fields.Status)
+
+	flow := IssueFlow{
+		Key:          issue.Key,
+		Summary:      issue.Fields.Summary,
+		Type:         issue.Fields.IssueType.Name,
+		Status:       issue.Fields.Status.Name,
+		Created:      issue.Fields.Created.Time,
+		TimeInStatus: make(map[string]time.Duration),
+	}
+
+	intervals := statusIntervals(issue, histories, categories)
+
+	for _, interval := range intervals {
+		if flow.Started == nil && matchesFlowStatus(interval.status, opts.FromStatus) {
+			started := interval.start
+			flow.Started = &started
+		}
+	}
+
+	last := intervals[len(intervals)-1]
+	if matchesFlowStatus(last.status, opts.ToStatus) {
+		// The issue finished when it last entered a to status, possibly
+		// through several consecutive done statuses
+		finished := last.start
+		for i := len(intervals) - 2; i >= 0 && matchesFlowStatus(intervals[i].status, opts.ToStatus); i-- {
+			finished = intervals[i].start
+		}
+		flow.Finished = &finished
+
+		flow.LeadTime = finished.Sub(flow.Created)
+		if flow.Started != nil && !flow.Started.After(finished) {
+			flow.CycleTime = finished.Sub(*flow.Started)
+		}
+	}
+
+	end := opts.Now
+	if flow.Finished != nil {
+		end = *flow.Finished
+	}
+
+	for i, interval := range intervals {
+		if !interval.start.Before(end) {
+			break
+		}
+
+		stop := end
+		if i+1 < len(intervals) && intervals[i+1].start.Before(end) {
+			stop = intervals[i+1].start
+		}
+
+		flow.TimeInStatus[flowGroup(interval.status, opts.GroupBy)] += stop.Sub(interval.start)
+	}
+
+	return flow
+}
+
+// BuildFlowReport aggregates per-issue flow metrics
+func BuildFlowReport(flows []IssueFlow, opts FlowOptions) *FlowReport {
+	report := &FlowReport{
+		FromStatus:   opts.FromStatus,
+		ToStatus:     opts.ToStatus,
+		GroupBy:      opts.GroupBy,
+		Issues:       flows,
+		Throughput:   make([]WeeklyThroughput, 0),
+		TimeInStatus: make(map[string]Percentiles),
+	}
+
+	cycleTimes := make([]time.Duration, 0, len(flows))
+	leadTimes := make([]time.Duration, 0, len(flows))
+	inStatus := make(map[string][]time.Duration)
+	weeks := make(map[time.Time]int)
+
+	for _, flow := range flows {
+		for status, d := range flow.TimeInStatus {
+			inStatus[status] = append(inStatus[status], d)
+		}
+
+		if flow.Finished == nil {
+			continue
+		}
+
+		leadTimes = append(leadTimes, flow.LeadTime)
+		if flow.Started != nil {
+			cycleTimes = append(cycleTimes, flow.CycleTime)
+		}
+		weeks[startOfWeek(*flow.Finished)]++
+	}
+
+	report.CycleTime = ComputePercentiles(cycleTimes)
+	report.LeadTime = ComputePercentiles(leadTimes)
+
+	for status, durations := range inStatus {
+		report.TimeInStatus[status] = ComputePercentiles(durations)
+	}
+
+	if len(weeks) > 0 {
+		// Report every week of the range, including the ones without throughput
+		first, last := time.Time{}, time.Time{}
+		for week := range weeks {
+			if first.IsZero() || week.Before(first) {
+				first = week
+			}
+			if week.After(last) {
+				last = week
+			}
+		}
+
+		for week := first; !week.After(last); week = week.AddDate(0, 0, 7) {
+			report.Throughput = append(report.Throughput, WeeklyThroughput{
+				Week:     week,
+				Finished: weeks[week],
+			})
+		}
+	}
+
+	return report
+}
+
+// ComputePercentiles returns the 50th, 85th and 95th percentiles of the
+// durations, using the nearest-rank method
+func ComputePercentiles(durations []time.Duration) Percentiles {
+	p := Percentiles{Count: len(durations)}
+	if len(durations) == 0 {
+		return p
+	}
+
+	sorted := append([]time.Duration(nil), durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	rank := func(percentile float64) time.Duration {
+		i := int(math.Ceil(percentile/100*float64(len(sorted)))) - 1
+		return sorted[max(i, 0)]
+	}
+
+	p.P50 = rank(50)
+	p.P85 = rank(85)
+	p.P95 = rank(95)
+
+	return p
+}
+
+// statusIntervals returns the statuses the issue went through, oldest first.
+// The initial status is the origin of the first status change, or the
+// current status when it never changed.
+func statusIntervals(issue *Issue, histories []ChangeHistory, categories map[string]StatusCategory) []statusInterval {
+	status := func(id string, name string) Status {
+		s := Status{ID: id, Name: name}
+		if c, ok := categories[id]; ok && id != "" {
+			s.StatusCategory = c
+		} else if c, ok := categories[strings.ToLower(name)]; ok {
+			s.StatusCategory = c
+		}
+		return s
+	}
+
+	intervals := make([]statusInterval, 0)
+
+	for _, history := range histories {
+		for _, item := range history.Items {
+			if !strings.EqualFold(item.Field, "status") {
+				continue
+			}
+
+			if len(intervals) == 0 {
+				intervals = append(intervals, statusInterval{
+					status: status(item.From, item.FromString),
+					start:  issue.Fields.Created.Time,
+				})
+			}
+
+			intervals = append(intervals, statusInterval{
+				status: status(item.To, item.ToString),
+				start:  history.Created.Time,
+			})
+		}
+	}
+
+	if len(intervals) == 0 {
+		intervals = append(intervals, statusInterval{
+			status: status(issue.Fields.Status.ID, issue.Fields.Status.Name),
+			start:  issue.Fields.Created.Time,
+		})
+	}
+
+	return intervals
+}
+
+// statusCategoryIndex maps status IDs and lower-cased names to their category
+func statusCategoryIndex(statuses []Status, current Status) map[string]StatusCategory {
+	index := make(map[string]StatusCategory, 2*len(statuses)+2)
+
+	for _, s := range append(statuses, current) {
+		if s.StatusCategory.Key == "" {
+			continue
+		}
+		if s.ID != "" {
+			index[s.ID] = s.StatusCategory
+		}
+		index[strings.ToLower(s.Name)] = s.StatusCategory
+	}
+
+	return index
+}
+
+func matchesFlowStatus(status Status, expected string) bool {
+	if expected == "" {
+		return false
+	}
+
+	return strings.EqualFold(status.Name, expected) ||
+		(status.StatusCategory.Key != "" && (strings.EqualFold(status.StatusCategory.Name, expected) ||
+			strings.EqualFold(status.StatusCategory.Key, expected)))
+}
+
+func flowGroup(status Status, groupBy FlowGrouping) string {
+	if groupBy == FlowGroupByCategory && status.StatusCategory.Name != "" {
+		return status.StatusCategory.Name
+	}
+	return status.Name
+}
+
+// startOfWeek returns the Monday of the calendar week of t, in the location of
+// t, as midnight UTC. Weeks are used as map keys, which compare locations, so
+// the same week must not depend on the offset JIRA sent t with.
+func startOfWeek(t time.Time) time.Time {
+	year, month, day := t.Date()
+	midnight := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	offset := (int(midnight.Weekday()) + 6) % 7
+	return midnight.AddDate(0, 0, -offset)
+}
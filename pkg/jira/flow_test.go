@@ -0,0 +1,245 @@
+package jira_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lburgazzoli/gira/pkg/jira"
+)
+
+var (
+	statusToDo       = jira.Status{ID: "1", Name: "To Do", StatusCategory: jira.StatusCategory{Key: jira.StatusCategoryNew, Name: "To Do"}}
+	statusInProgress = jira.Status{ID: "3", Name: "In Progress", StatusCategory: jira.StatusCategory{Key: jira.StatusCategoryInProgress, Name: "In Progress"}}
+	statusInReview   = jira.Status{ID: "4", Name: "In Review", StatusCategory: jira.StatusCategory{Key: jira.StatusCategoryInProgress, Name: "In Progress"}}
+	statusDone       = jira.Status{ID: "10001", Name: "Done", StatusCategory: jira.StatusCategory{Key: jira.StatusCategoryDone, Name: "Done"}}
+)
+
+// flowStart is the creation time of the issues of the flow tests, a Monday
+var flowStart = time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+
+func day(n float64) time.Time {
+	return flowStart.Add(time.Duration(n * float64(24*time.Hour)))
+}
+
+func transition(at time.Time, from jira.Status, to jira.Status) jira.ChangeHistory {
+	return jira.ChangeHistory{
+		Created: jira.JIRATime{Time: at},
+		Items: []jira.ChangeItem{{
+			Field:      "status",
+			From:       from.ID,
+			FromString: from.Name,
+			To:         to.ID,
+			ToString:   to.Name,
+		}},
+	}
+}
+
+func TestGetStatuses(t *testing.T) {
+	_, client := newTestClient(t, nil)
+
+	statuses, err := client.GetStatuses()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make([]string, 0, len(statuses))
+	for _, s := range statuses {
+		got = append(got, s.Name+"/"+s.StatusCategory.Key)
+	}
+	if want := "[To Do/new In Progress/indeterminate Done/done]"; fmt.Sprint(got) != want {
+		t.Errorf("statuses = %v, want %s", got, want)
+	}
+}
+
+func TestComputeIssueFlow(t *testing.T) {
+	statuses := []jira.Status{statusToDo, statusInProgress, statusInReview, statusDone}
+
+	tests := []struct {
+		name      string
+		status    jira.Status
+		histories []jira.ChangeHistory
+		groupBy   jira.FlowGrouping
+		// wantStarted and wantFinished are days after creation, -1 for unset
+		wantStarted  float64
+		wantFinished float64
+		wantCycle    time.Duration
+		wantLead     time.Duration
+		wantInStatus map[string]time.Duration
+	}{
+		{
+			name:   "finished",
+			status: statusDone,
+			histories: []jira.ChangeHistory{
+				transition(day(1), statusToDo, statusInProgress),
+				transition(day(4), statusInProgress, statusDone),
+			},
+			wantStarted:  1,
+			wantFinished: 4,
+			wantCycle:    3 * 24 * time.Hour,
+			wantLead:     4 * 24 * time.Hour,
+			wantInStatus: map[string]time.Duration{"To Do": 24 * time.Hour, "In Progress": 3 * 24 * time.Hour},
+		},
+		{
+			name:   "in progress",
+			status: statusInProgress,
+			histories: []jira.ChangeHistory{
+				transition(day(1), statusToDo, statusInProgress),
+			},
+			wantStarted:  1,
+			wantFinished: -1,
+			wantInStatus: map[string]time.Duration{"To Do": 24 * time.Hour, "In Progress": 9 * 24 * time.Hour},
+		},
+		{
+			name:   "reopened",
+			status: statusDone,
+			histories: []jira.ChangeHistory{
+				transition(day(1), statusToDo, statusInProgress),
+				transition(day(2), statusInProgress, statusDone),
+				transition(day(3), statusDone, statusInProgress),
+				transition(day(5), statusInProgress, statusDone),
+			},
+			wantStarted:  1,
+			wantFinished: 5,
+			wantCycle:    4 * 24 * time.Hour,
+			wantLead:     5 * 24 * time.Hour,
+			wantInStatus: map[string]time.Duration{"To Do": 24 * time.Hour, "In Progress": 3 * 24 * time.Hour, "Done": 24 * time.Hour},
+		},
+		{
+			name:         "never started",
+			status:       statusToDo,
+			wantStarted:  -1,
+			wantFinished: -1,
+			wantInStatus: map[string]time.Duration{"To Do": 10 * 24 * time.Hour},
+		},
+		{
+			name:   "grouped by category",
+			status: statusDone,
+			histories: []jira.ChangeHistory{
+				transition(day(1), statusToDo, statusInProgress),
+				transition(day(2), statusInProgress, statusInReview),
+				transition(day(4), statusInReview, statusDone),
+			},
+			groupBy:      jira.FlowGroupByCategory,
+			wantStarted:  1,
+			wantFinished: 4,
+			wantCycle:    3 * 24 * time.Hour,
+			wantLead:     4 * 24 * time.Hour,
+			wantInStatus: map[string]time.Duration{"To Do": 24 * time.Hour, "In Progress": 3 * 24 * time.Hour},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issue := &jira.Issue{Key: "DEMO-1", Fields: jira.IssueFields{
+				Status:  tt.status,
+				Created: jira.JIRATime{Time: flowStart},
+			}}
+
+			groupBy := tt.groupBy
+			if groupBy == "" {
+				groupBy = jira.FlowGroupByStatus
+			}
+
+			flow := jira.ComputeIssueFlow(issue, tt.histories, jira.FlowOptions{
+				FromStatus: "In Progress",
+				ToStatus:   "Done",
+				GroupBy:    groupBy,
+				Statuses:   statuses,
+				Now:        day(10),
+			})
+
+			checkTime := func(name string, got *time.Time, want float64) {
+				switch {
+				case want < 0 && got != nil:
+					t.Errorf("%s = %v, want unset", name, *got)
+				case want >= 0 && (got == nil || !got.Equal(day(want))):
+					t.Errorf("%s = %v, want %v", name, got, day(want))
+				}
+			}
+			checkTime("started", flow.Started, tt.wantStarted)
+			checkTime("finished", flow.Finished, tt.wantFinished)
+
+			if flow.CycleTime != tt.wantCycle {
+				t.Errorf("cycle time = %v, want %v", flow.CycleTime, tt.wantCycle)
+			}
+			if flow.LeadTime != tt.wantLead {
+				t.Errorf("lead time = %v, want %v", flow.LeadTime, tt.wantLead)
+			}
+			if fmt.Sprint(flow.TimeInStatus) != fmt.Sprint(tt.wantInStatus) {
+				t.Errorf("time in status = %v, want %v", flow.TimeInStatus, tt.wantInStatus)
+			}
+		})
+	}
+}
+
+func TestComputePercentiles(t *testing.T) {
+	hours := func(values ...int) []time.Duration {
+		durations := make([]time.Duration, 0, len(values))
+		for _, v := range values {
+			durations = append(durations, time.Duration(v)*time.Hour)
+		}
+		return durations
+	}
+
+	tests := []struct {
+		name      string
+		durations []time.Duration
+		want      jira.Percentiles
+	}{
+		{name: "empty", durations: nil, want: jira.Percentiles{}},
+		{name: "single", durations: hours(5), want: jira.Percentiles{Count: 1, P50: 5 * time.Hour, P85: 5 * time.Hour, P95: 5 * time.Hour}},
+		{
+			name:      "unsorted",
+			durations: hours(10, 1, 9, 2, 8, 3, 7, 4, 6, 5),
+			want:      jira.Percentiles{Count: 10, P50: 5 * time.Hour, P85: 9 * time.Hour, P95: 10 * time.Hour},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := jira.ComputePercentiles(tt.durations); got != tt.want {
+				t.Errorf("ComputePercentiles = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildFlowReportThroughput(t *testing.T) {
+	finished := func(at time.Time) jira.IssueFlow {
+		return jira.IssueFlow{Finished: &at, LeadTime: at.Sub(flowStart), TimeInStatus: map[string]time.Duration{}}
+	}
+
+	// JIRA reports times with the offset of the user who made the change
+	kolkata := time.FixedZone("IST", 5*60*60+30*60)
+	losAngeles := time.FixedZone("PST", -8*60*60)
+
+	flows := []jira.IssueFlow{
+		finished(day(2)),
+		finished(day(3).In(kolkata)),
+		// Nothing is finished in the second week
+		finished(day(15).In(losAngeles)),
+		{TimeInStatus: map[string]time.Duration{"To Do": time.Hour}},
+	}
+
+	report := jira.BuildFlowReport(flows, jira.FlowOptions{FromStatus: "In Progress", ToStatus: "Done"})
+
+	want := "[2024-01-01:2 2024-01-08:0 2024-01-15:1]"
+	got := make([]string, 0, len(report.Throughput))
+	for _, w := range report.Throughput {
+		got = append(got, fmt.Sprintf("%s:%d", w.Week.Format("2006-01-02"), w.Finished))
+		if w.Week.Location() != time.UTC {
+			t.Errorf("week %s is not in UTC", w.Week)
+		}
+	}
+	if fmt.Sprint(got) != want {
+		t.Errorf("throughput = %v, want %s", got, want)
+	}
+
+	if report.LeadTime.Count != 3 {
+		t.Errorf("lead time count = %d, want 3", report.LeadTime.Count)
+	}
+	if report.CycleTime.Count != 0 {
+		t.Errorf("cycle time count = %d, want 0 as no issue started", report.CycleTime.Count)
+	}
+}
@@ -0,0 +1,289 @@
+package jiratest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lburgazzoli/gira/pkg/jira"
+)
+
+// sprintField is the name of the Sprint field in changelogs
+const sprintField = "Sprint"
+
+// FixtureSprint is a sprint and the keys of the issues it contains
+type FixtureSprint struct {
+	jira.Sprint
+
+	Issues []string `json:"issues,omitempty"`
+}
+
+// AddBoard adds a board, assigning an ID when missing. The stored board is
+// returned.
+func (s *Server) AddBoard(board jira.Board) *jira.Board {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if board.ID == 0 {
+		board.ID = len(s.boards) + 1
+	}
+	if board.Type == "" {
+		board.Type = "scrum"
+	}
+
+	s.boards = append(s.boards, board)
+	return &board
+}
+
+// AddSprint adds a sprint to the board given by OriginBoardID, assigning an
+// ID when missing, and moves the given issues into it. The stored sprint is
+// returned.
+func (s *Server) AddSprint(sprint jira.Sprint, keys ...string) (*jira.Sprint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if sprint.ID == 0 {
+		sprint.ID = len(s.sprints) + 1
+	}
+	if sprint.State == "" {
+		sprint.State = jira.SprintStateFuture
+	}
+
+	records := make([]*record, 0, len(keys))
+	for _, key := range keys {
+		rec, ok := s.issues[key]
+		if !ok {
+			return nil, fmt.Errorf("issue %s does not exist", key)
+		}
+		records = append(records, rec)
+	}
+
+	stored := sprint
+	s.sprints = append(s.sprints, &stored)
+
+	for _, rec := range records {
+		rec.sprint = sprint.ID
+	}
+
+	return &sprint, nil
+}
+
+func (s *Server) handleListBoards(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	query := r.URL.Query()
+	project := query.Get("projectKeyOrId")
+	boardType := query.Get("type")
+	name := strings.ToLower(query.Get("name"))
+
+	boards := make([]jira.Board, 0, len(s.boards))
+	for _, b := range s.boards {
+		if project != "" && (b.Location == nil || !strings.EqualFold(b.Location.ProjectKey, project)) {
+			continue
+		}
+		if boardType != "" && !strings.EqualFold(b.Type, boardType) {
+			continue
+		}
+		if name != "" && !strings.Contains(strings.ToLower(b.Name), name) {
+			continue
+		}
+		boards = append(boards, b)
+	}
+
+	writeAgilePage(w, r, boards)
+}
+
+func (s *Server) handleGetBoard(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	board, ok := s.board(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Board does not exist")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, board)
+}
+
+func (s *Server) handleListSprints(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	board, ok := s.board(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Board does not exist")
+		return
+	}
+
+	var states []string
+	if state := r.URL.Query().Get("state"); state != "" {
+		states = strings.Split(strings.ToLower(state), ",")
+	}
+
+	sprints := make([]jira.Sprint, 0)
+	for _, sprint := range s.sprints {
+		if sprint.OriginBoardID != board.ID {
+			continue
+		}
+		if len(states) > 0 && !slices.Contains(states, sprint.State) {
+			continue
+		}
+		sprints = append(sprints, *sprint)
+	}
+
+	writeAgilePage(w, r, sprints)
+}
+
+func (s *Server) handleGetSprint(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sprint, ok := s.sprint(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Sprint does not exist")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, sprint)
+}
+
+func (s *Server) handleSprintIssues(w http.ResponseWriter, r *http.Request) {
+	q, err := parseJQL(r.URL.Query().Get("jql"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Error in the JQL Query: %v", err))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sprint, ok := s.sprint(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Sprint does not exist")
+		return
+	}
+
+	matches := make([]*record, 0)
+	for _, key := range s.order {
+		rec := s.issues[key]
+		if rec.sprint == sprint.ID && q.match(s, rec) {
+			matches = append(matches, rec)
+		}
+	}
+	q.sort(s, matches)
+
+	startAt, maxResults := pagination(r)
+	page := paginate(matches, startAt, maxResults)
+	issues := make([]wireIssue, 0, len(page))
+	for _, rec := range page {
+		issues = append(issues, s.wireIssue(rec))
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"issues":     issues,
+		"startAt":    startAt,
+		"maxResults": maxResults,
+		"total":      len(matches),
+	})
+}
+
+func (s *Server) handleMoveToSprint(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Issues []string `json:"issues"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request: %v", err))
+		return
+	}
+	if len(body.Issues) > 50 {
+		writeError(w, http.StatusBadRequest, "at most 50 issues can be moved at once")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sprint, ok := s.sprint(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Sprint does not exist")
+		return
+	}
+	if sprint.State == jira.SprintStateClosed {
+		writeError(w, http.StatusBadRequest, "Issues cannot be moved to a closed sprint")
+		return
+	}
+
+	records := make([]*record, 0, len(body.Issues))
+	for _, key := range body.Issues {
+		rec, ok := s.lookup(key)
+		if !ok {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Issue %s does not exist", key))
+			return
+		}
+		records = append(records, rec)
+	}
+
+	for _, rec := range records {
+		if rec.sprint == sprint.ID {
+			continue
+		}
+
+		from, fromName := "", ""
+		if previous, ok := s.sprint(strconv.Itoa(rec.sprint)); ok {
+			from, fromName = strconv.Itoa(previous.ID), previous.Name
+		}
+
+		rec.sprint = sprint.ID
+		rec.issue.Fields.Updated = jira.JIRATime{Time: time.Now().UTC()}
+		s.recordChange(rec, jira.ChangeItem{
+			Field:      sprintField,
+			FieldType:  "custom",
+			From:       from,
+			FromString: fromName,
+			To:         strconv.Itoa(sprint.ID),
+			ToString:   sprint.Name,
+		})
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Agile storage helpers, callers must hold the lock
+
+func (s *Server) board(id string) (*jira.Board, bool) {
+	for i := range s.boards {
+		if strconv.Itoa(s.boards[i].ID) == id {
+			return &s.boards[i], true
+		}
+	}
+	return nil, false
+}
+
+func (s *Server) sprint(id string) (*jira.Sprint, bool) {
+	for _, sprint := range s.sprints {
+		if strconv.Itoa(sprint.ID) == id {
+			return sprint, true
+		}
+	}
+	return nil, false
+}
+
+// writeAgilePage writes a page of values in the Agile API format
+func writeAgilePage[T any](w http.ResponseWriter, r *http.Request, values []T) {
+	startAt, maxResults := pagination(r)
+	page := paginate(values, startAt, maxResults)
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"maxResults": maxResults,
+		"startAt":    startAt,
+		"total":      len(values),
+		"isLast":     startAt+len(page) >= len(values),
+		"values":     page,
+	})
+}
@@ -0,0 +1,205 @@
+package jiratest
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/lburgazzoli/gira/pkg/jira"
+)
+
+// defaultUploadLimit is the default maximum attachment size of JIRA
+const defaultUploadLimit = 10 * 1024 * 1024
+
+// WithUploadLimit sets the maximum size of uploaded attachments
+func WithUploadLimit(limit int64) Option {
+	return func(s *Server) {
+		s.uploadLimit = limit
+	}
+}
+
+// AttachmentContent returns the content of an attachment
+func (s *Server) AttachmentContent(id string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	content, ok := s.content[id]
+	return content, ok
+}
+
+// attach stores a file as an attachment of the issue, callers must hold the lock
+func (s *Server) attach(rec *record, filename string, mimeType string, content []byte) jira.Attachment {
+	if mimeType == "" {
+		mimeType = mime.TypeByExtension(filepath.Ext(filename))
+	}
+	if mimeType == "" {
+		mimeType = http.DetectContentType(content)
+	}
+
+	author := s.currentUser
+	attachment := jira.Attachment{
+		ID:       s.newID(),
+		Filename: filename,
+		Author:   &author,
+		Created:  jira.JIRATime{Time: time.Now().UTC()},
+		Size:     int64(len(content)),
+		MimeType: mimeType,
+	}
+
+	rec.attachments = append(rec.attachments, attachment)
+	s.content[attachment.ID] = content
+
+	return s.renderAttachment(attachment)
+}
+
+// renderAttachment sets the content URL, which depends on the server address
+func (s *Server) renderAttachment(attachment jira.Attachment) jira.Attachment {
+	attachment.Content = s.URL + path.Join("/secure/attachment", attachment.ID, url.PathEscape(attachment.Filename))
+	return attachment
+}
+
+// lookupAttachment returns an attachment by ID, callers must hold the lock
+func (s *Server) lookupAttachment(id string) (jira.Attachment, bool) {
+	for _, rec := range s.issues {
+		for _, a := range rec.attachments {
+			if a.ID == id {
+				return a, true
+			}
+		}
+	}
+	return jira.Attachment{}, false
+}
+
+func (s *Server) handleAttachmentMeta(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, jira.AttachmentMeta{
+		Enabled:     true,
+		UploadLimit: s.uploadLimit,
+	})
+}
+
+func (s *Server) handleGetAttachment(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attachment, ok := s.lookupAttachment(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "The attachment does not exist")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.renderAttachment(attachment))
+}
+
+func (s *Server) handleAttachmentContent(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attachment, ok := s.lookupAttachment(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "The attachment does not exist")
+		return
+	}
+
+	w.Header().Set("Content-Type", attachment.MimeType)
+	w.Header().Set("Content-Length", fmt.Sprint(attachment.Size))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(s.content[attachment.ID])
+}
+
+// handleUploadAttachments stores the files of a multipart request, enforcing
+// the XSRF header and the upload limit like JIRA does
+func (s *Server) handleUploadAttachments(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-Atlassian-Token") != "no-check" {
+		writeError(w, http.StatusForbidden, "XSRF check failed")
+		return
+	}
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid multipart request: %v", err))
+		return
+	}
+
+	s.mu.Lock()
+	limit := s.uploadLimit
+	_, exists := s.lookup(r.PathValue("key"))
+	s.mu.Unlock()
+
+	if !exists {
+		writeError(w, http.StatusNotFound, "Issue Does Not Exist")
+		return
+	}
+
+	type upload struct {
+		filename string
+		mimeType string
+		content  []byte
+	}
+
+	uploads := make([]upload, 0)
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid multipart request: %v", err))
+			return
+		}
+
+		if part.FormName() != "file" {
+			continue
+		}
+
+		content, err := io.ReadAll(io.LimitReader(part, limit+1))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid multipart request: %v", err))
+			return
+		}
+		if int64(len(content)) > limit {
+			writeError(w, http.StatusRequestEntityTooLarge,
+				fmt.Sprintf("The file %s is too large: the maximum size is %d bytes", part.FileName(), limit))
+			return
+		}
+
+		mimeType := part.Header.Get("Content-Type")
+		if mimeType == "application/octet-stream" {
+			mimeType = ""
+		}
+
+		uploads = append(uploads, upload{filename: part.FileName(), mimeType: mimeType, content: content})
+	}
+
+	if len(uploads) == 0 {
+		writeError(w, http.StatusBadRequest, "No file was attached")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.lookup(r.PathValue("key"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Issue Does Not Exist")
+		return
+	}
+
+	created := make([]jira.Attachment, 0, len(uploads))
+	for _, u := range uploads {
+		attachment := s.attach(rec, u.filename, u.mimeType, u.content)
+		s.recordChange(rec, changeItem("Attachment", "", "", attachment.ID, attachment.Filename))
+		created = append(created, attachment)
+	}
+	rec.issue.Fields.Updated = jira.JIRATime{Time: time.Now().UTC()}
+
+	writeJSON(w, http.StatusOK, created)
+}
@@ -0,0 +1,215 @@
+package jiratest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/lburgazzoli/gira/pkg/jira"
+)
+
+// Fixtures is the initial content of a Server
+type Fixtures struct {
+	Projects []jira.Project  `json:"projects"`
+	Issues   []FixtureIssue  `json:"issues"`
+	Boards   []jira.Board    `json:"boards,omitempty"`
+	Sprints  []FixtureSprint `json:"sprints,omitempty"`
+	// Users are added to the user directory, see WithUsers
+	Users []jira.User `json:"users,omitempty"`
+}
+
+// FixtureIssue is an issue as returned by the REST API, plus the relations
+// that are not part of jira.Issue
+type FixtureIssue struct {
+	jira.Issue
+
+	// EpicLink is the key of the epic the issue belongs to
+	EpicLink string `json:"epicLink,omitempty"`
+	// StoryPoints is the estimate of the issue
+	StoryPoints *float64 `json:"storyPoints,omitempty"`
+	// Changelog is the change history of the issue, oldest first
+	Changelog []jira.ChangeHistory `json:"changelog,omitempty"`
+	// Worklogs is the time logged on the issue
+	Worklogs []jira.Worklog `json:"worklogs,omitempty"`
+	// Attachments are the files attached to the issue
+	Attachments []FixtureAttachment `json:"attachments,omitempty"`
+	// RemoteLinks are the links to objects outside JIRA
+	RemoteLinks []jira.RemoteLink `json:"remoteLinks,omitempty"`
+	// Watchers are the users watching the issue
+	Watchers []jira.User `json:"watchers,omitempty"`
+}
+
+// FixtureAttachment is a file attached to a fixture issue
+type FixtureAttachment struct {
+	Filename string `json:"filename"`
+	// MimeType defaults to the type inferred from the filename
+	MimeType string `json:"mimeType,omitempty"`
+	Content  string `json:"content"`
+}
+
+// LoadFixtures reads fixtures from a JSON file
+func LoadFixtures(path string) (*Fixtures, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixtures: %w", err)
+	}
+
+	var fixtures Fixtures
+	if err := json.Unmarshal(data, &fixtures); err != nil {
+		return nil, fmt.Errorf("failed to parse fixtures %s: %w", path, err)
+	}
+
+	return &fixtures, nil
+}
+
+// Seed adds the projects, issues, links, boards and sprints of the fixtures
+// to the server. Projects referenced by issues are created when missing.
+func (s *Server) Seed(fixtures *Fixtures) {
+	if fixtures == nil {
+		return
+	}
+
+	for _, p := range fixtures.Projects {
+		s.AddProject(p)
+	}
+
+	for _, u := range fixtures.Users {
+		s.AddUser(u)
+	}
+
+	for _, fi := range fixtures.Issues {
+		s.mu.Lock()
+		s.ensureProject(fi.Fields.Project)
+		rec := s.add(fi.Issue, fi.EpicLink)
+		s.seedVersions(rec)
+		rec.storyPoints = fi.StoryPoints
+		rec.changes = append(rec.changes, fi.Changelog...)
+		rec.worklogs = append(rec.worklogs, fi.Worklogs...)
+		for _, fa := range fi.Attachments {
+			s.attach(rec, fa.Filename, fa.MimeType, []byte(fa.Content))
+		}
+		for _, rl := range fi.RemoteLinks {
+			s.addRemoteLink(rec, rl)
+		}
+		for _, u := range fi.Watchers {
+			s.watch(rec, u)
+		}
+		s.mu.Unlock()
+	}
+
+	// Links are added once all the issues they may reference exist
+	s.mu.Lock()
+	for _, fi := range fixtures.Issues {
+		s.seedLinks(fi.Key, fi.Fields.IssueLinks)
+	}
+	s.mu.Unlock()
+
+	for _, b := range fixtures.Boards {
+		s.AddBoard(b)
+	}
+
+	for _, fs := range fixtures.Sprints {
+		// Sprints referencing unknown issues are skipped, like invalid issues
+		_, _ = s.AddSprint(fs.Sprint, fs.Issues...)
+	}
+}
+
+// AddProject adds or replaces a project
+func (s *Server) AddProject(project jira.Project) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if project.ID == "" {
+		project.ID = s.newID()
+	}
+	if project.Name == "" {
+		project.Name = project.Key
+	}
+	s.prepareProject(&project)
+
+	s.projects[project.Key] = &project
+}
+
+// AddIssue adds an issue, creating its project when missing. Issues without a
+// key get the next key of their project. The stored issue is returned.
+func (s *Server) AddIssue(issue jira.Issue) *jira.Issue {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.ensureProject(issue.Fields.Project)
+	rec := s.add(issue, "")
+
+	stored := rec.issue
+	return &stored
+}
+
+// SetEpicLink links an issue to an epic, so that `"Epic Link" = EPIC` matches it
+func (s *Server) SetEpicLink(key string, epicKey string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.issues[key]
+	if !ok {
+		return fmt.Errorf("issue %s does not exist", key)
+	}
+
+	rec.epicLink = epicKey
+	return nil
+}
+
+// AddChange appends an entry to the changelog of an issue, e.g. to seed
+// status transitions in the past
+func (s *Server) AddChange(key string, history jira.ChangeHistory) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.issues[key]
+	if !ok {
+		return fmt.Errorf("issue %s does not exist", key)
+	}
+
+	if history.ID == "" {
+		history.ID = s.newID()
+	}
+	rec.changes = append(rec.changes, history)
+	return nil
+}
+
+// AddComment adds a comment by the current user to an issue
+func (s *Server) AddComment(key string, body string) (Comment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.issues[key]
+	if !ok {
+		return Comment{}, fmt.Errorf("issue %s does not exist", key)
+	}
+
+	return s.comment(rec, body), nil
+}
+
+func (s *Server) ensureProject(project jira.Project) {
+	if project.Key == "" {
+		return
+	}
+	if _, ok := s.projects[project.Key]; ok {
+		return
+	}
+
+	if project.ID == "" {
+		project.ID = s.newID()
+	}
+	if project.Name == "" {
+		project.Name = project.Key
+	}
+	s.prepareProject(&project)
+
+	s.projects[project.Key] = &project
+}
+
+func sortProjects(projects []jira.Project) {
+	sort.Slice(projects, func(i, j int) bool {
+		return projects[i].Key < projects[j].Key
+	})
+}
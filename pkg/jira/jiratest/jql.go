@@ -0,0 +1,479 @@
+package jiratest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	timeutils "github.com/lburgazzoli/gira/pkg/utils/time"
+)
+
+// query is a parsed JQL query
+type query struct {
+	where   predicate
+	orderBy []orderClause
+}
+
+type orderClause struct {
+	field string
+	desc  bool
+}
+
+// predicate evaluates a JQL condition against an issue record
+type predicate func(s *Server, r *record) bool
+
+// parseJQL parses the subset of JQL supported by the fake server: the
+// project, key, parent, "Epic Link", status, assignee, issuetype and
+// reporter fields with =, !=, IN, NOT IN, IS [NOT] EMPTY, the created and
+// updated fields with <, <=, > and >=, combined with AND, OR, NOT and
+// parentheses, followed by an optional ORDER BY.
+func parseJQL(jql string) (*query, error) {
+	tokens, err := tokenizeJQL(jql)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &jqlParser{tokens: tokens}
+	q := &query{}
+
+	if !p.done() && !p.isKeyword("ORDER") {
+		q.where, err = p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if p.isKeyword("ORDER") {
+		p.next()
+		if !p.isKeyword("BY") {
+			return nil, fmt.Errorf("expected BY after ORDER")
+		}
+		p.next()
+
+		for {
+			field := p.next()
+			if field == "" {
+				return nil, fmt.Errorf("missing ORDER BY field")
+			}
+
+			clause := orderClause{field: normalizeField(field)}
+			if p.isKeyword("ASC") {
+				p.next()
+			} else if p.isKeyword("DESC") {
+				p.next()
+				clause.desc = true
+			}
+			q.orderBy = append(q.orderBy, clause)
+
+			if p.peek() != "," {
+				break
+			}
+			p.next()
+		}
+	}
+
+	if !p.done() {
+		return nil, fmt.Errorf("unexpected token %q", p.peek())
+	}
+
+	return q, nil
+}
+
+func (q *query) match(s *Server, r *record) bool {
+	return q.where == nil || q.where(s, r)
+}
+
+func (q *query) sort(s *Server, records []*record) {
+	orderBy := q.orderBy
+	if len(orderBy) == 0 {
+		orderBy = []orderClause{{field: "key"}}
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		for _, o := range orderBy {
+			c := compareRecords(s, records[i], records[j], o.field)
+			if c == 0 {
+				continue
+			}
+			if o.desc {
+				return c > 0
+			}
+			return c < 0
+		}
+		return false
+	})
+}
+
+func compareRecords(s *Server, a *record, b *record, field string) int {
+	switch field {
+	case "key":
+		pa, na := splitKey(a.issue.Key)
+		pb, nb := splitKey(b.issue.Key)
+		if pa != pb {
+			return strings.Compare(pa, pb)
+		}
+		return na - nb
+	case "created":
+		return a.issue.Fields.Created.Compare(b.issue.Fields.Created.Time)
+	case "updated":
+		return a.issue.Fields.Updated.Compare(b.issue.Fields.Updated.Time)
+	default:
+		return strings.Compare(strings.ToLower(s.fieldValue(a, field)), strings.ToLower(s.fieldValue(b, field)))
+	}
+}
+
+// normalizeField maps JQL field names and aliases to the names used by fieldValue
+func normalizeField(field string) string {
+	switch f := strings.ToLower(field); f {
+	case "type":
+		return "issuetype"
+	case "epic link", "cf[10014]":
+		return "epic link"
+	default:
+		return f
+	}
+}
+
+type jqlParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *jqlParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *jqlParser) peek() string {
+	if p.done() {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *jqlParser) next() string {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *jqlParser) isKeyword(keyword string) bool {
+	return strings.EqualFold(p.peek(), keyword)
+}
+
+func (p *jqlParser) parseOr() (predicate, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isKeyword("OR") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l, r := left, right
+		left = func(s *Server, rec *record) bool { return l(s, rec) || r(s, rec) }
+	}
+
+	return left, nil
+}
+
+func (p *jqlParser) parseAnd() (predicate, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isKeyword("AND") {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		l, r := left, right
+		left = func(s *Server, rec *record) bool { return l(s, rec) && r(s, rec) }
+	}
+
+	return left, nil
+}
+
+func (p *jqlParser) parseNot() (predicate, error) {
+	if p.isKeyword("NOT") {
+		p.next()
+		inner, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return func(s *Server, rec *record) bool { return !inner(s, rec) }, nil
+	}
+
+	if p.peek() == "(" {
+		p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, fmt.Errorf("missing ')'")
+		}
+		return inner, nil
+	}
+
+	return p.parseClause()
+}
+
+func (p *jqlParser) parseClause() (predicate, error) {
+	raw := p.next()
+	if raw == "" {
+		return nil, fmt.Errorf("unexpected end of query")
+	}
+
+	field := normalizeField(unquoteJQL(raw))
+	if !supportedFields[field] {
+		return nil, fmt.Errorf("field %q is not supported by the fake server", unquoteJQL(raw))
+	}
+
+	op := strings.ToUpper(p.next())
+	switch op {
+	case "=", "!=":
+		value := unquoteJQL(p.next())
+		if value == "" {
+			return nil, fmt.Errorf("missing value for field %q", field)
+		}
+		return fieldPredicate(field, []string{value}, op == "!="), nil
+	case "IN":
+		values, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		return fieldPredicate(field, values, false), nil
+	case "NOT":
+		if !p.isKeyword("IN") {
+			return nil, fmt.Errorf("expected IN after NOT")
+		}
+		p.next()
+		values, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		return fieldPredicate(field, values, true), nil
+	case "<", "<=", ">", ">=":
+		if field != "created" && field != "updated" && field != "worklogdate" {
+			return nil, fmt.Errorf("operator %q is only supported for created, updated and worklogDate", op)
+		}
+		bound, err := parseJQLTime(unquoteJQL(p.next()), time.Now())
+		if err != nil {
+			return nil, err
+		}
+		return timePredicate(field, op, bound), nil
+	case "IS":
+		negate := false
+		if p.isKeyword("NOT") {
+			p.next()
+			negate = true
+		}
+		if v := strings.ToUpper(p.next()); v != "EMPTY" && v != "NULL" {
+			return nil, fmt.Errorf("expected EMPTY after IS")
+		}
+		return func(s *Server, r *record) bool {
+			return (s.fieldValue(r, field) == "") != negate
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported operator %q", op)
+	}
+}
+
+func (p *jqlParser) parseList() ([]string, error) {
+	if p.next() != "(" {
+		return nil, fmt.Errorf("expected '('")
+	}
+
+	values := make([]string, 0)
+	for {
+		t := p.next()
+		switch t {
+		case "":
+			return nil, fmt.Errorf("missing ')'")
+		case ",":
+			continue
+		case ")":
+			return values, nil
+		default:
+			values = append(values, unquoteJQL(t))
+		}
+	}
+}
+
+var supportedFields = map[string]bool{
+	"project":    true,
+	"key":        true,
+	"issuekey":   true,
+	"parent":     true,
+	"epic link":  true,
+	"sprint":     true,
+	"status":     true,
+	"assignee":   true,
+	"reporter":   true,
+	"issuetype":  true,
+	"priority":   true,
+	"summary":    true,
+	"resolution": true,
+	"created":    true,
+	"updated":    true,
+
+	"worklogauthor": true,
+	"worklogdate":   true,
+	"watcher":       true,
+	"fixversion":    true,
+	"component":     true,
+	"labels":        true,
+}
+
+func fieldPredicate(field string, values []string, negate bool) predicate {
+	return func(s *Server, r *record) bool {
+		actual := s.fieldValue(r, field)
+		matched := false
+
+		for _, v := range values {
+			if s.valueMatches(r, field, actual, v) {
+				matched = true
+				break
+			}
+		}
+
+		return matched != negate
+	}
+}
+
+// parseJQLTime parses relative times such as -2w or -30m, dates and
+// "yyyy-MM-dd HH:mm" timestamps
+func parseJQLTime(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, fmt.Errorf("missing time value")
+	}
+
+	if strings.HasPrefix(value, "-") {
+		d, err := timeutils.ParseRelativeDuration(value[1:])
+		if err != nil {
+			return time.Time{}, err
+		}
+		return now.Add(-d), nil
+	}
+
+	for _, layout := range []string{"2006-01-02 15:04", "2006/01/02 15:04", timeutils.DateLayout, "2006/01/02"} {
+		if t, err := time.ParseInLocation(layout, value, time.UTC); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time value %q", value)
+}
+
+func timePredicate(field string, op string, bound time.Time) predicate {
+	if field == "worklogdate" {
+		return worklogTimePredicate(op, bound)
+	}
+
+	return func(_ *Server, r *record) bool {
+		t := r.issue.Fields.Updated.Time
+		if field == "created" {
+			t = r.issue.Fields.Created.Time
+		}
+
+		return compareTime(t, op, bound)
+	}
+}
+
+func compareTime(t time.Time, op string, bound time.Time) bool {
+	switch op {
+	case "<":
+		return t.Before(bound)
+	case "<=":
+		return !t.After(bound)
+	case ">":
+		return t.After(bound)
+	default:
+		return !t.Before(bound)
+	}
+}
+
+func tokenizeJQL(jql string) ([]string, error) {
+	tokens := make([]string, 0)
+	runes := []rune(jql)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')' || r == ',' || r == '=':
+			tokens = append(tokens, string(r))
+			i++
+		case r == '!':
+			if i+1 >= len(runes) || runes[i+1] != '=' {
+				return nil, fmt.Errorf("unexpected '!' at position %d", i)
+			}
+			tokens = append(tokens, "!=")
+			i += 2
+		case r == '<' || r == '>':
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				tokens = append(tokens, string(runes[i:i+2]))
+				i += 2
+			} else {
+				tokens = append(tokens, string(r))
+				i++
+			}
+		case r == '"' || r == '\'':
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				end++
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("unterminated string at position %d", i)
+			}
+			tokens = append(tokens, string(runes[i:end+1]))
+			i = end + 1
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune("(),=!<>\"'", runes[i]) {
+				i++
+			}
+			// Keep function calls such as currentUser() as a single token
+			if i+1 < len(runes) && runes[i] == '(' && runes[i+1] == ')' {
+				i += 2
+			}
+			tokens = append(tokens, string(runes[start:i]))
+		}
+	}
+
+	return tokens, nil
+}
+
+func unquoteJQL(t string) string {
+	if len(t) >= 2 && (t[0] == '"' || t[0] == '\'') && t[len(t)-1] == t[0] {
+		return t[1 : len(t)-1]
+	}
+	return t
+}
+
+// splitKey splits an issue key into project key and number for natural ordering
+func splitKey(key string) (string, int) {
+	i := strings.LastIndex(key, "-")
+	if i < 0 {
+		return key, 0
+	}
+
+	n := 0
+	for _, c := range key[i+1:] {
+		if c < '0' || c > '9' {
+			return key, 0
+		}
+		n = n*10 + int(c-'0')
+	}
+
+	return key[:i], n
+}
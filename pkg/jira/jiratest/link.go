@@ -0,0 +1,270 @@
+package jiratest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/lburgazzoli/gira/pkg/jira"
+)
+
+// issueLink is a stored link, where "source <outward> destination" holds
+type issueLink struct {
+	id          string
+	linkType    jira.LinkType
+	source      string
+	destination string
+}
+
+// linkBody is the payload of link creation requests, where inwardIssue is the
+// source of the outward relation
+type linkBody struct {
+	Type struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"type"`
+	InwardIssue  issueRef `json:"inwardIssue"`
+	OutwardIssue issueRef `json:"outwardIssue"`
+}
+
+type issueRef struct {
+	ID  string `json:"id"`
+	Key string `json:"key"`
+}
+
+func (r issueRef) keyOrID() string {
+	if r.Key != "" {
+		return r.Key
+	}
+	return r.ID
+}
+
+// WithLinkTypes replaces the default Blocks, Cloners, Duplicate and Relates
+// link types
+func WithLinkTypes(types ...jira.LinkType) Option {
+	return func(s *Server) {
+		s.linkTypes = types
+	}
+}
+
+func defaultLinkTypes() []jira.LinkType {
+	return []jira.LinkType{
+		{ID: "10000", Name: "Blocks", Inward: "is blocked by", Outward: "blocks"},
+		{ID: "10001", Name: "Cloners", Inward: "is cloned by", Outward: "clones"},
+		{ID: "10002", Name: "Duplicate", Inward: "is duplicated by", Outward: "duplicates"},
+		{ID: "10003", Name: "Relates", Inward: "relates to", Outward: "relates to"},
+	}
+}
+
+// AddIssueLink links two issues so that "source <outward> destination" holds
+// for the link type with the given name, and returns the link ID
+func (s *Server) AddIssueLink(linkType string, source string, destination string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.findLinkType("", linkType)
+	if !ok {
+		return "", fmt.Errorf("link type %q does not exist", linkType)
+	}
+
+	src, ok := s.lookup(source)
+	if !ok {
+		return "", fmt.Errorf("issue %s does not exist", source)
+	}
+	dst, ok := s.lookup(destination)
+	if !ok {
+		return "", fmt.Errorf("issue %s does not exist", destination)
+	}
+
+	return s.link("", t, src, dst).id, nil
+}
+
+// seedLinks stores the links found in fixture issues. A link appears on both
+// of its issues, so links are deduplicated by ID or by their ends.
+func (s *Server) seedLinks(key string, links []jira.IssueLink) {
+	for _, l := range links {
+		source, destination := key, ""
+		switch {
+		case l.OutwardIssue != nil:
+			destination = l.OutwardIssue.Key
+		case l.InwardIssue != nil:
+			source, destination = l.InwardIssue.Key, key
+		default:
+			continue
+		}
+
+		src, ok := s.lookup(source)
+		if !ok {
+			continue
+		}
+		dst, ok := s.lookup(destination)
+		if !ok {
+			continue
+		}
+
+		t, ok := s.findLinkType(l.Type.ID, l.Type.Name)
+		if !ok {
+			t = l.Type
+			if t.ID == "" {
+				t.ID = s.newID()
+			}
+			s.linkTypes = append(s.linkTypes, t)
+		}
+
+		s.link(l.ID, t, src, dst)
+	}
+}
+
+// link stores a link unless an identical one exists, callers must hold the lock
+func (s *Server) link(id string, t jira.LinkType, src *record, dst *record) *issueLink {
+	for _, l := range s.links {
+		if (id != "" && l.id == id) || (l.linkType.ID == t.ID && l.source == src.issue.Key && l.destination == dst.issue.Key) {
+			return l
+		}
+	}
+
+	if id == "" {
+		id = s.newID()
+	}
+
+	l := &issueLink{id: id, linkType: t, source: src.issue.Key, destination: dst.issue.Key}
+	s.links = append(s.links, l)
+
+	return l
+}
+
+// findLinkType looks a link type up by ID or name, callers must hold the lock
+func (s *Server) findLinkType(id string, name string) (jira.LinkType, bool) {
+	for _, t := range s.linkTypes {
+		if (id != "" && t.ID == id) || (name != "" && strings.EqualFold(t.Name, name)) {
+			return t, true
+		}
+	}
+	return jira.LinkType{}, false
+}
+
+// renderLinks returns the links of an issue as seen from it, callers must
+// hold the lock
+func (s *Server) renderLinks(key string) []jira.IssueLink {
+	links := make([]jira.IssueLink, 0)
+
+	for _, l := range s.links {
+		switch key {
+		case l.source:
+			links = append(links, jira.IssueLink{ID: l.id, Type: l.linkType, OutwardIssue: s.linkedIssue(l.destination)})
+		case l.destination:
+			links = append(links, jira.IssueLink{ID: l.id, Type: l.linkType, InwardIssue: s.linkedIssue(l.source)})
+		}
+	}
+
+	return links
+}
+
+// linkedIssue returns the summary of an issue embedded in links
+func (s *Server) linkedIssue(key string) *jira.Issue {
+	rec, ok := s.issues[key]
+	if !ok {
+		return &jira.Issue{Key: key}
+	}
+
+	return &jira.Issue{
+		Key:  rec.issue.Key,
+		ID:   rec.issue.ID,
+		Self: rec.issue.Self,
+		Fields: jira.IssueFields{
+			Summary:   rec.issue.Fields.Summary,
+			Status:    rec.issue.Fields.Status,
+			Priority:  rec.issue.Fields.Priority,
+			IssueType: rec.issue.Fields.IssueType,
+		},
+	}
+}
+
+func (s *Server) handleListLinkTypes(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]any{"issueLinkTypes": s.linkTypes})
+}
+
+func (s *Server) handleCreateLink(w http.ResponseWriter, r *http.Request) {
+	var body linkBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid issue link: %v", err))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.findLinkType(body.Type.ID, body.Type.Name)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("No issue link type with name '%s' found.", body.Type.Name))
+		return
+	}
+
+	src, ok := s.lookup(body.InwardIssue.keyOrID())
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Issue Does Not Exist: %s", body.InwardIssue.keyOrID()))
+		return
+	}
+	dst, ok := s.lookup(body.OutwardIssue.keyOrID())
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Issue Does Not Exist: %s", body.OutwardIssue.keyOrID()))
+		return
+	}
+	if src == dst {
+		writeError(w, http.StatusBadRequest, "You cannot link an issue to itself.")
+		return
+	}
+
+	s.link("", t, src, dst)
+	s.recordChange(src, changeItem("Link", "", "", dst.issue.Key, "This issue "+t.Outward+" "+dst.issue.Key))
+	s.recordChange(dst, changeItem("Link", "", "", src.issue.Key, "This issue "+t.Inward+" "+src.issue.Key))
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) handleGetLink(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := slices.IndexFunc(s.links, func(l *issueLink) bool { return l.id == r.PathValue("id") })
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "No issue link with id exists")
+		return
+	}
+
+	l := s.links[i]
+	writeJSON(w, http.StatusOK, jira.IssueLink{
+		ID:           l.id,
+		Type:         l.linkType,
+		InwardIssue:  s.linkedIssue(l.source),
+		OutwardIssue: s.linkedIssue(l.destination),
+	})
+}
+
+func (s *Server) handleDeleteLink(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := slices.IndexFunc(s.links, func(l *issueLink) bool { return l.id == r.PathValue("id") })
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "No issue link with id exists")
+		return
+	}
+
+	l := s.links[i]
+	s.links = slices.Delete(s.links, i, i+1)
+
+	if src, ok := s.issues[l.source]; ok {
+		s.recordChange(src, changeItem("Link", l.destination, "This issue "+l.linkType.Outward+" "+l.destination, "", ""))
+	}
+	if dst, ok := s.issues[l.destination]; ok {
+		s.recordChange(dst, changeItem("Link", l.source, "This issue "+l.linkType.Inward+" "+l.source, "", ""))
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,98 @@
+package jiratest
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/lburgazzoli/gira/pkg/jira"
+)
+
+// defaultRoles are the roles of projects without roles in their fixture
+var defaultRoles = []string{"Administrators", "Developers", "Users"}
+
+// prepareProject assigns IDs to the components and versions of a project,
+// callers must hold the lock
+func (s *Server) prepareProject(project *jira.Project) {
+	for i := range project.Components {
+		if project.Components[i].ID == "" {
+			project.Components[i].ID = s.newID()
+		}
+	}
+	for i := range project.Versions {
+		if project.Versions[i].ID == "" {
+			project.Versions[i].ID = s.newID()
+		}
+	}
+}
+
+// findProject looks a project up by key or ID, callers must hold the lock
+func (s *Server) findProject(keyOrID string) (*jira.Project, bool) {
+	for _, p := range s.projects {
+		if p.Key == keyOrID || p.ID == keyOrID {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+// renderProject returns a project as sent by the project endpoints, with the
+// issue types used by its issues unless set, callers must hold the lock
+func (s *Server) renderProject(project *jira.Project) jira.Project {
+	p := *project
+	p.Components = append([]jira.Component{}, project.Components...)
+	p.Versions = append([]jira.Version{}, project.Versions...)
+
+	if len(p.IssueTypes) == 0 {
+		p.IssueTypes = make([]jira.IssueType, 0)
+		for _, key := range s.order {
+			rec := s.issues[key]
+			t := rec.issue.Fields.IssueType
+			if rec.issue.Fields.Project.Key != p.Key || t.Name == "" {
+				continue
+			}
+			if !slices.ContainsFunc(p.IssueTypes, func(it jira.IssueType) bool { return it.Name == t.Name }) {
+				p.IssueTypes = append(p.IssueTypes, t)
+			}
+		}
+	}
+
+	if p.Roles == nil {
+		p.Roles = make(map[string]string, len(defaultRoles))
+		for i, role := range defaultRoles {
+			p.Roles[role] = fmt.Sprintf("%s/rest/api/2/project/%s/role/%d", s.URL, p.ID, 10002+i)
+		}
+	}
+
+	for i := range p.Versions {
+		p.Versions[i] = s.renderVersion(project, p.Versions[i])
+	}
+
+	return p
+}
+
+func (s *Server) handleListComponents(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.findProject(r.PathValue("key"))
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("No project could be found with key '%s'.", r.PathValue("key")))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.renderProject(p).Components)
+}
+
+func (s *Server) handleListVersions(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.findProject(r.PathValue("key"))
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("No project could be found with key '%s'.", r.PathValue("key")))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.renderProject(p).Versions)
+}
@@ -0,0 +1,114 @@
+package jiratest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Property returns the value of an issue entity property, nil when unset
+func (s *Server) Property(key string, property string) json.RawMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.issues[key]
+	if !ok {
+		return nil
+	}
+
+	return rec.properties[property]
+}
+
+// selectProperties returns the requested entity properties of an issue, as
+// listed by the properties search parameter. Callers must hold the lock.
+func selectProperties(rec *record, requested string) map[string]json.RawMessage {
+	if requested == "" {
+		return nil
+	}
+
+	properties := make(map[string]json.RawMessage)
+	for _, name := range strings.Split(requested, ",") {
+		if value, ok := rec.properties[strings.TrimSpace(name)]; ok {
+			properties[strings.TrimSpace(name)] = value
+		}
+	}
+
+	return properties
+}
+
+func (s *Server) handleGetProperty(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.lookup(r.PathValue("key"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Issue Does Not Exist")
+		return
+	}
+
+	name := r.PathValue("property")
+	value, ok := rec.properties[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("The property with key '%s' does not exist.", name))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"key":   name,
+		"value": value,
+	})
+}
+
+func (s *Server) handleSetProperty(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil || !json.Valid(body) {
+		writeError(w, http.StatusBadRequest, "The property value must be valid JSON.")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.lookup(r.PathValue("key"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Issue Does Not Exist")
+		return
+	}
+
+	if rec.properties == nil {
+		rec.properties = make(map[string]json.RawMessage)
+	}
+
+	name := r.PathValue("property")
+	_, exists := rec.properties[name]
+	rec.properties[name] = body
+
+	if exists {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) handleDeleteProperty(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.lookup(r.PathValue("key"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Issue Does Not Exist")
+		return
+	}
+
+	name := r.PathValue("property")
+	if _, ok := rec.properties[name]; !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("The property with key '%s' does not exist.", name))
+		return
+	}
+
+	delete(rec.properties, name)
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,131 @@
+package jiratest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/lburgazzoli/gira/pkg/jira"
+)
+
+// RemoteLinks returns the remote links of an issue
+func (s *Server) RemoteLinks(key string) []jira.RemoteLink {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.issues[key]
+	if !ok {
+		return nil
+	}
+
+	return append([]jira.RemoteLink(nil), rec.remoteLinks...)
+}
+
+// addRemoteLink stores a remote link, replacing the one with the same global
+// ID, and reports whether it was created. Callers must hold the lock.
+func (s *Server) addRemoteLink(rec *record, link jira.RemoteLink) (jira.RemoteLink, bool) {
+	if link.GlobalID != "" {
+		for i, existing := range rec.remoteLinks {
+			if existing.GlobalID == link.GlobalID {
+				link.ID = existing.ID
+				rec.remoteLinks[i] = link
+				return link, false
+			}
+		}
+	}
+
+	if link.ID == 0 {
+		link.ID, _ = strconv.Atoi(s.newID())
+	}
+	rec.remoteLinks = append(rec.remoteLinks, link)
+
+	return link, true
+}
+
+func (s *Server) handleListRemoteLinks(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.lookup(r.PathValue("key"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Issue Does Not Exist")
+		return
+	}
+
+	links := make([]jira.RemoteLink, 0, len(rec.remoteLinks))
+	for _, l := range rec.remoteLinks {
+		l.Self = s.remoteLinkURL(rec, l)
+		links = append(links, l)
+	}
+
+	writeJSON(w, http.StatusOK, links)
+}
+
+// handleCreateRemoteLink creates a remote link, or updates the one with the
+// same global ID like JIRA does
+func (s *Server) handleCreateRemoteLink(w http.ResponseWriter, r *http.Request) {
+	var link jira.RemoteLink
+	if err := json.NewDecoder(r.Body).Decode(&link); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid remote link: %v", err))
+		return
+	}
+
+	if link.Object.URL == "" || link.Object.Title == "" {
+		writeError(w, http.StatusBadRequest, "object: url and title are required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.lookup(r.PathValue("key"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Issue Does Not Exist")
+		return
+	}
+
+	link.ID = 0
+	link.Self = ""
+	stored, created := s.addRemoteLink(rec, link)
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+		s.recordChange(rec, changeItem("RemoteIssueLink", "", "", strconv.Itoa(stored.ID), "This issue links to \""+stored.Object.Title+"\""))
+	}
+
+	writeJSON(w, status, map[string]any{"id": stored.ID, "self": s.remoteLinkURL(rec, stored)})
+}
+
+// remoteLinkURL returns the self URL of a remote link, rendered on demand as
+// fixtures are seeded before the server URL is known
+func (s *Server) remoteLinkURL(rec *record, link jira.RemoteLink) string {
+	return fmt.Sprintf("%s/rest/api/2/issue/%s/remotelink/%d", s.URL, rec.issue.Key, link.ID)
+}
+
+func (s *Server) handleDeleteRemoteLink(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.lookup(r.PathValue("key"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Issue Does Not Exist")
+		return
+	}
+
+	i := slices.IndexFunc(rec.remoteLinks, func(l jira.RemoteLink) bool {
+		return strconv.Itoa(l.ID) == r.PathValue("id")
+	})
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "No remote link with the given id exists")
+		return
+	}
+
+	link := rec.remoteLinks[i]
+	rec.remoteLinks = slices.Delete(rec.remoteLinks, i, i+1)
+	s.recordChange(rec, changeItem("RemoteIssueLink", strconv.Itoa(link.ID), "This issue links to \""+link.Object.Title+"\"", "", ""))
+
+	w.WriteHeader(http.StatusNoContent)
+}
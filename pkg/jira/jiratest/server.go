@@ -0,0 +1,1298 @@
+// Package jiratest provides an in-memory fake JIRA server to test code built
+// on top of pkg/jira without a live instance.
+package jiratest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lburgazzoli/gira/pkg/jira"
+)
+
+const (
+	// defaultMaxResults is used when a search does not specify maxResults
+	defaultMaxResults = 50
+	// maxMaxResults caps the page size, like JIRA does
+	maxMaxResults = 100
+
+	// EpicLinkField is the ID of the Epic Link custom field
+	EpicLinkField = "customfield_10014"
+	// StoryPointsField is the ID of the Story Points custom field
+	StoryPointsField = "customfield_10016"
+)
+
+// Comment is an issue comment stored by the fake server
+type Comment struct {
+	ID      string     `json:"id"`
+	Body    string     `json:"body"`
+	Author  *jira.User `json:"author,omitempty"`
+	Created string     `json:"created"`
+}
+
+// Fault describes an error response injected by the server
+type Fault struct {
+	// Method and Path restrict the requests the fault applies to; Path is
+	// matched as a prefix. Empty values match any request.
+	Method string
+	Path   string
+	// Status is the HTTP status code returned, e.g. 429 or 503
+	Status int
+	// Header is added to the error response, e.g. Retry-After
+	Header http.Header
+	// Times is the number of requests the fault applies to (0 means once)
+	Times int
+}
+
+// RecordedRequest is a request received by the server
+type RecordedRequest struct {
+	Method string
+	Path   string
+	Query  string
+	Status int
+}
+
+// record is the server side state of an issue
+type record struct {
+	issue    jira.Issue
+	epicLink string
+	comments []Comment
+	changes  []jira.ChangeHistory
+	// sprint is the ID of the sprint the issue belongs to, 0 for the backlog
+	sprint int
+	// storyPoints is the estimate of the issue, nil when not estimated
+	storyPoints *float64
+	worklogs    []jira.Worklog
+	attachments []jira.Attachment
+	remoteLinks []jira.RemoteLink
+	watchers    []jira.User
+	voters      []jira.User
+	// properties holds the entity properties of the issue by key
+	properties map[string]json.RawMessage
+}
+
+// Server is a fake JIRA REST API backed by httptest
+type Server struct {
+	URL string
+
+	server *httptest.Server
+
+	mu          sync.Mutex
+	projects    map[string]*jira.Project
+	issues      map[string]*record
+	order       []string
+	counters    map[string]int
+	nextID      int
+	transitions []jira.Transition
+	boards      []jira.Board
+	sprints     []*jira.Sprint
+	// content holds the content of attachments by ID
+	content     map[string][]byte
+	uploadLimit int64
+	linkTypes   []jira.LinkType
+	links       []*issueLink
+	currentUser jira.User
+	users       []jira.User
+	faults      []*Fault
+	requests    []RecordedRequest
+}
+
+// Option configures a Server
+type Option func(*Server)
+
+// WithCurrentUser sets the user returned by /myself and matched by currentUser()
+func WithCurrentUser(user jira.User) Option {
+	return func(s *Server) {
+		s.currentUser = user
+	}
+}
+
+// WithTransitions replaces the default To Do / In Progress / Done workflow
+func WithTransitions(transitions ...jira.Transition) Option {
+	return func(s *Server) {
+		s.transitions = transitions
+	}
+}
+
+// WithFixtures seeds the server with the given fixtures
+func WithFixtures(fixtures *Fixtures) Option {
+	return func(s *Server) {
+		s.Seed(fixtures)
+	}
+}
+
+// NewServer starts a fake JIRA server. Call Close when done.
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		projects: make(map[string]*jira.Project),
+		issues:   make(map[string]*record),
+		counters: make(map[string]int),
+		nextID:   10000,
+		currentUser: jira.User{
+			AccountID:    "test-user",
+			DisplayName:  "Test User",
+			EmailAddress: "test@example.com",
+			Active:       true,
+		},
+		transitions: defaultTransitions(),
+		content:     make(map[string][]byte),
+		uploadLimit: defaultUploadLimit,
+		linkTypes:   defaultLinkTypes(),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /rest/api/2/myself", s.handleMyself)
+	mux.HandleFunc("GET /rest/api/2/user", s.handleGetUser)
+	mux.HandleFunc("GET /rest/api/2/user/search", s.handleSearchUsers)
+	mux.HandleFunc("GET /rest/api/2/field", s.handleListFields)
+	mux.HandleFunc("GET /rest/api/2/status", s.handleListStatuses)
+	mux.HandleFunc("GET /rest/api/2/project", s.handleListProjects)
+	mux.HandleFunc("GET /rest/api/2/project/{key}", s.handleGetProject)
+	mux.HandleFunc("GET /rest/api/2/project/{key}/components", s.handleListComponents)
+	mux.HandleFunc("GET /rest/api/2/project/{key}/versions", s.handleListVersions)
+	mux.HandleFunc("POST /rest/api/2/version", s.handleCreateVersion)
+	mux.HandleFunc("PUT /rest/api/2/version/{id}", s.handleUpdateVersion)
+	mux.HandleFunc("GET /rest/api/2/search", s.handleSearch)
+	mux.HandleFunc("POST /rest/api/2/search", s.handleSearch)
+	mux.HandleFunc("POST /rest/api/2/issue", s.handleCreateIssue)
+	mux.HandleFunc("GET /rest/api/2/issue/{key}", s.handleGetIssue)
+	mux.HandleFunc("POST /rest/api/2/issue/bulk", s.handleBulkCreate)
+	mux.HandleFunc("PUT /rest/api/2/issue/{key}", s.handleUpdateIssue)
+	mux.HandleFunc("GET /rest/api/2/issue/{key}/changelog", s.handleChangelog)
+	mux.HandleFunc("GET /rest/api/2/issue/{key}/transitions", s.handleGetTransitions)
+	mux.HandleFunc("POST /rest/api/2/issue/{key}/transitions", s.handleDoTransition)
+	mux.HandleFunc("GET /rest/api/2/issue/{key}/comment", s.handleListComments)
+	mux.HandleFunc("POST /rest/api/2/issue/{key}/comment", s.handleAddComment)
+	mux.HandleFunc("GET /rest/api/2/issue/{key}/worklog", s.handleListWorklogs)
+	mux.HandleFunc("POST /rest/api/2/issue/{key}/worklog", s.handleAddWorklog)
+	mux.HandleFunc("PUT /rest/api/2/issue/{key}/worklog/{id}", s.handleUpdateWorklog)
+	mux.HandleFunc("DELETE /rest/api/2/issue/{key}/worklog/{id}", s.handleDeleteWorklog)
+	mux.HandleFunc("GET /rest/api/2/issue/{key}/watchers", s.handleGetWatchers)
+	mux.HandleFunc("POST /rest/api/2/issue/{key}/watchers", s.handleAddWatcher)
+	mux.HandleFunc("DELETE /rest/api/2/issue/{key}/watchers", s.handleRemoveWatcher)
+	mux.HandleFunc("POST /rest/api/2/issue/{key}/votes", s.handleVote)
+	mux.HandleFunc("DELETE /rest/api/2/issue/{key}/votes", s.handleUnvote)
+	mux.HandleFunc("GET /rest/api/2/issue/{key}/properties/{property}", s.handleGetProperty)
+	mux.HandleFunc("PUT /rest/api/2/issue/{key}/properties/{property}", s.handleSetProperty)
+	mux.HandleFunc("DELETE /rest/api/2/issue/{key}/properties/{property}", s.handleDeleteProperty)
+	mux.HandleFunc("GET /rest/api/2/issue/{key}/remotelink", s.handleListRemoteLinks)
+	mux.HandleFunc("POST /rest/api/2/issue/{key}/remotelink", s.handleCreateRemoteLink)
+	mux.HandleFunc("DELETE /rest/api/2/issue/{key}/remotelink/{id}", s.handleDeleteRemoteLink)
+	mux.HandleFunc("POST /rest/api/2/issue/{key}/attachments", s.handleUploadAttachments)
+	mux.HandleFunc("GET /rest/api/2/attachment/meta", s.handleAttachmentMeta)
+	mux.HandleFunc("GET /rest/api/2/attachment/{id}", s.handleGetAttachment)
+	mux.HandleFunc("GET /secure/attachment/{id}/{filename}", s.handleAttachmentContent)
+	mux.HandleFunc("GET /rest/api/2/issueLinkType", s.handleListLinkTypes)
+	mux.HandleFunc("POST /rest/api/2/issueLink", s.handleCreateLink)
+	mux.HandleFunc("GET /rest/api/2/issueLink/{id}", s.handleGetLink)
+	mux.HandleFunc("DELETE /rest/api/2/issueLink/{id}", s.handleDeleteLink)
+	mux.HandleFunc("GET /rest/agile/1.0/board", s.handleListBoards)
+	mux.HandleFunc("GET /rest/agile/1.0/board/{id}", s.handleGetBoard)
+	mux.HandleFunc("GET /rest/agile/1.0/board/{id}/sprint", s.handleListSprints)
+	mux.HandleFunc("GET /rest/agile/1.0/sprint/{id}", s.handleGetSprint)
+	mux.HandleFunc("GET /rest/agile/1.0/sprint/{id}/issue", s.handleSprintIssues)
+	mux.HandleFunc("POST /rest/agile/1.0/sprint/{id}/issue", s.handleMoveToSprint)
+
+	s.server = httptest.NewServer(s.middleware(mux))
+	s.URL = s.server.URL
+
+	return s
+}
+
+// Close shuts the server down
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// NewClient returns a jira.Client pointing at the server. Retries wait a few
+// milliseconds so that injected faults do not slow tests down.
+func (s *Server) NewClient(opts ...jira.Option) (*jira.Client, error) {
+	defaults := []jira.Option{
+		jira.WithRetryPolicy(jira.RetryPolicy{
+			MaxRetries: 3,
+			WaitMin:    time.Millisecond,
+			WaitMax:    10 * time.Millisecond,
+		}),
+	}
+
+	return jira.NewClient(s.URL, jira.AuthConfig{Token: "test-token"}, append(defaults, opts...)...)
+}
+
+// InjectFault makes the server fail the matching requests
+func (s *Server) InjectFault(fault Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if fault.Times <= 0 {
+		fault.Times = 1
+	}
+
+	s.faults = append(s.faults, &fault)
+}
+
+// Requests returns the requests received so far
+func (s *Server) Requests() []RecordedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]RecordedRequest(nil), s.requests...)
+}
+
+// Issue returns a copy of the stored issue
+func (s *Server) Issue(key string) (*jira.Issue, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.issues[key]
+	if !ok {
+		return nil, false
+	}
+
+	issue := s.renderIssue(r)
+	return &issue, true
+}
+
+// Comments returns the comments of an issue
+func (s *Server) Comments(key string) []Comment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.issues[key]
+	if !ok {
+		return nil
+	}
+
+	return append([]Comment(nil), r.comments...)
+}
+
+// statusRecorder captures the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusRecorder) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (s *Server) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		defer func() {
+			s.mu.Lock()
+			s.requests = append(s.requests, RecordedRequest{
+				Method: r.Method,
+				Path:   r.URL.Path,
+				Query:  r.URL.RawQuery,
+				Status: rec.status,
+			})
+			s.mu.Unlock()
+		}()
+
+		if fault := s.takeFault(r); fault != nil {
+			for name, values := range fault.Header {
+				for _, v := range values {
+					rec.Header().Add(name, v)
+				}
+			}
+			writeError(rec, fault.Status, fmt.Sprintf("injected fault (%d)", fault.Status))
+			return
+		}
+
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+			writeError(rec, http.StatusUnauthorized, "missing bearer token")
+			return
+		}
+
+		next.ServeHTTP(rec, r)
+	})
+}
+
+func (s *Server) takeFault(r *http.Request) *Fault {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, f := range s.faults {
+		if f.Method != "" && !strings.EqualFold(f.Method, r.Method) {
+			continue
+		}
+		if f.Path != "" && !strings.HasPrefix(r.URL.Path, f.Path) {
+			continue
+		}
+
+		f.Times--
+		if f.Times <= 0 {
+			s.faults = append(s.faults[:i], s.faults[i+1:]...)
+		}
+
+		return f
+	}
+
+	return nil
+}
+
+// Handlers
+
+func (s *Server) handleMyself(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, s.currentUser)
+}
+
+func (s *Server) handleListFields(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, defaultFields())
+}
+
+// handleListStatuses returns the target statuses of the workflow transitions
+func (s *Server) handleListStatuses(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := make([]jira.Status, 0, len(s.transitions))
+	seen := make(map[string]bool, len(s.transitions))
+	for _, t := range s.transitions {
+		if seen[t.To.ID] {
+			continue
+		}
+		seen[t.To.ID] = true
+		statuses = append(statuses, t.To)
+	}
+
+	writeJSON(w, http.StatusOK, statuses)
+}
+
+func (s *Server) handleListProjects(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	projects := make([]jira.Project, 0, len(s.projects))
+	for _, p := range s.projects {
+		projects = append(projects, s.renderProject(p))
+	}
+	sortProjects(projects)
+
+	writeJSON(w, http.StatusOK, projects)
+}
+
+func (s *Server) handleGetProject(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := r.PathValue("key")
+	if p, ok := s.findProject(key); ok {
+		writeJSON(w, http.StatusOK, s.renderProject(p))
+		return
+	}
+
+	writeError(w, http.StatusNotFound, fmt.Sprintf("No project could be found with key '%s'.", key))
+}
+
+func (s *Server) handleGetIssue(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.lookup(r.PathValue("key"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Issue Does Not Exist")
+		return
+	}
+
+	issue := s.wireIssue(rec)
+	if slices.Contains(strings.Split(r.URL.Query().Get("expand"), ","), "changelog") {
+		issue.changelog = append(make([]jira.ChangeHistory, 0, len(rec.changes)), rec.changes...)
+	}
+
+	writeJSON(w, http.StatusOK, issue)
+}
+
+func (s *Server) handleCreateIssue(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid issue: %v", err))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	created, err := s.createIssue(body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]string{
+		"id":   created.issue.ID,
+		"key":  created.issue.Key,
+		"self": created.issue.Self,
+	})
+}
+
+// createIssue creates an issue from the body of a creation request, callers
+// must hold the lock
+func (s *Server) createIssue(body []byte) (*record, error) {
+	// Custom fields are not part of jira.Issue, they are decoded separately
+	var issue jira.Issue
+	var raw struct {
+		Fields     map[string]any `json:"fields"`
+		Properties []struct {
+			Key   string          `json:"key"`
+			Value json.RawMessage `json:"value"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(body, &issue); err != nil {
+		return nil, fmt.Errorf("invalid issue: %v", err)
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("invalid issue: %v", err)
+	}
+
+	projectKey := issue.Fields.Project.Key
+	if _, ok := s.projects[projectKey]; !ok {
+		return nil, fmt.Errorf("project %q does not exist", projectKey)
+	}
+	if issue.Fields.Summary == "" {
+		return nil, fmt.Errorf("summary: You must specify a summary of the issue.")
+	}
+	if issue.Fields.IssueType.Name == "" && issue.Fields.IssueType.ID == "" {
+		return nil, fmt.Errorf("issuetype: Specify an issue type")
+	}
+	if issue.Fields.Parent != nil {
+		if _, ok := s.lookup(issue.Fields.Parent.Key); !ok {
+			return nil, fmt.Errorf("parent: Could not find issue by id or key.")
+		}
+	}
+
+	issue.Key = ""
+	issue.Fields.Assignee = s.userFromValue(raw.Fields["assignee"])
+
+	// Components and versions must exist in the project
+	probe := &record{issue: issue}
+	for _, field := range []string{"components", "fixVersions"} {
+		if _, err := s.setListField(probe, field, raw.Fields[field]); err != nil {
+			return nil, err
+		}
+	}
+	issue.Fields.Components, issue.Fields.FixVersions = probe.issue.Fields.Components, probe.issue.Fields.FixVersions
+
+	points, err := storyPointsValue(raw.Fields[StoryPointsField])
+	if err != nil {
+		return nil, err
+	}
+	epicLink, _ := raw.Fields[EpicLinkField].(string)
+
+	created := s.add(issue, epicLink)
+	created.storyPoints = points
+
+	for _, property := range raw.Properties {
+		if created.properties == nil {
+			created.properties = make(map[string]json.RawMessage)
+		}
+		created.properties[property.Key] = property.Value
+	}
+
+	return created, nil
+}
+
+// handleBulkCreate creates issues independently, answering 400 when any of
+// them failed, with the issues that were created
+func (s *Server) handleBulkCreate(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		IssueUpdates []json.RawMessage `json:"issueUpdates"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid bulk creation: %v", err))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	issues := make([]map[string]string, 0, len(body.IssueUpdates))
+	failures := make([]map[string]any, 0)
+
+	for i, update := range body.IssueUpdates {
+		created, err := s.createIssue(update)
+		if err != nil {
+			failures = append(failures, map[string]any{
+				"status":              http.StatusBadRequest,
+				"elementErrors":       map[string]any{"errorMessages": []string{err.Error()}, "errors": map[string]string{}},
+				"failedElementNumber": i,
+			})
+			continue
+		}
+
+		issues = append(issues, map[string]string{
+			"id":   created.issue.ID,
+			"key":  created.issue.Key,
+			"self": created.issue.Self,
+		})
+	}
+
+	status := http.StatusCreated
+	if len(failures) > 0 {
+		status = http.StatusBadRequest
+	}
+
+	writeJSON(w, status, map[string]any{
+		"issues": issues,
+		"errors": failures,
+	})
+}
+
+func (s *Server) handleUpdateIssue(w http.ResponseWriter, r *http.Request) {
+	var update jira.IssueUpdate
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid update: %v", err))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.lookup(r.PathValue("key"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Issue Does Not Exist")
+		return
+	}
+
+	if err := s.applyUpdate(rec, update); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rec.issue.Fields.Updated = jira.JIRATime{Time: time.Now().UTC()}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleChangelog(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.lookup(r.PathValue("key"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Issue Does Not Exist")
+		return
+	}
+
+	startAt, maxResults := pagination(r)
+	page := paginate(rec.changes, startAt, maxResults)
+
+	writeJSON(w, http.StatusOK, jira.ChangelogPage{
+		Values:     page,
+		StartAt:    startAt,
+		MaxResults: maxResults,
+		Total:      len(rec.changes),
+		IsLast:     startAt+len(page) >= len(rec.changes),
+	})
+}
+
+func (s *Server) handleGetTransitions(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.lookup(r.PathValue("key")); !ok {
+		writeError(w, http.StatusNotFound, "Issue Does Not Exist")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"transitions": s.transitions,
+	})
+}
+
+func (s *Server) handleDoTransition(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Transition struct {
+			ID string `json:"id"`
+		} `json:"transition"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid transition: %v", err))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.lookup(r.PathValue("key"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Issue Does Not Exist")
+		return
+	}
+
+	for _, t := range s.transitions {
+		if t.ID == body.Transition.ID {
+			from := rec.issue.Fields.Status
+			s.recordChange(rec, jira.ChangeItem{
+				Field:      "status",
+				FieldType:  "jira",
+				FieldID:    "status",
+				From:       from.ID,
+				FromString: from.Name,
+				To:         t.To.ID,
+				ToString:   t.To.Name,
+			})
+			rec.issue.Fields.Status = t.To
+			rec.issue.Fields.Updated = jira.JIRATime{Time: time.Now().UTC()}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+
+	writeError(w, http.StatusBadRequest, fmt.Sprintf("transition %q is not valid", body.Transition.ID))
+}
+
+func (s *Server) handleListComments(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.lookup(r.PathValue("key"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Issue Does Not Exist")
+		return
+	}
+
+	startAt, maxResults := pagination(r)
+	page := paginate(rec.comments, startAt, maxResults)
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"comments":   page,
+		"startAt":    startAt,
+		"maxResults": maxResults,
+		"total":      len(rec.comments),
+	})
+}
+
+func (s *Server) handleAddComment(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Body string `json:"body"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Body == "" {
+		writeError(w, http.StatusBadRequest, "comment body is required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.lookup(r.PathValue("key"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Issue Does Not Exist")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, s.comment(rec, body.Body))
+}
+
+// comment appends a comment by the current user, callers must hold the lock
+func (s *Server) comment(rec *record, body string) Comment {
+	author := s.currentUser
+	comment := Comment{
+		ID:      s.newID(),
+		Body:    body,
+		Author:  &author,
+		Created: time.Now().UTC().Format("2006-01-02T15:04:05.000-0700"),
+	}
+	rec.comments = append(rec.comments, comment)
+
+	return comment
+}
+
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	jql := r.URL.Query().Get("jql")
+	startAt, maxResults := pagination(r)
+
+	if r.Method == http.MethodPost {
+		var body struct {
+			JQL        string `json:"jql"`
+			StartAt    int    `json:"startAt"`
+			MaxResults int    `json:"maxResults"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid search: %v", err))
+			return
+		}
+		jql, startAt = body.JQL, body.StartAt
+		if body.MaxResults > 0 {
+			maxResults = min(body.MaxResults, maxMaxResults)
+		}
+	}
+
+	q, err := parseJQL(jql)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Error in the JQL Query: %v", err))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	matches := make([]*record, 0)
+	for _, key := range s.order {
+		rec := s.issues[key]
+		if q.match(s, rec) {
+			matches = append(matches, rec)
+		}
+	}
+	q.sort(s, matches)
+
+	page := paginate(matches, startAt, maxResults)
+	issues := make([]wireIssue, 0, len(page))
+	for _, rec := range page {
+		issue := s.wireIssue(rec)
+		issue.properties = selectProperties(rec, r.URL.Query().Get("properties"))
+		issues = append(issues, issue)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"issues":     issues,
+		"startAt":    startAt,
+		"maxResults": maxResults,
+		"total":      len(matches),
+	})
+}
+
+// wireIssue is an issue as sent over the wire, including the custom fields
+// that are not part of jira.Issue
+type wireIssue struct {
+	jira.Issue
+	epicLink    string
+	storyPoints *float64
+	// properties are the entity properties requested by a search
+	properties map[string]json.RawMessage
+	// changelog is set when the request expands the changelog
+	changelog []jira.ChangeHistory
+}
+
+func (w wireIssue) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(w.Issue)
+	if err != nil || (w.epicLink == "" && w.storyPoints == nil && w.properties == nil && w.changelog == nil) {
+		return data, err
+	}
+
+	var issue map[string]any
+	if err := json.Unmarshal(data, &issue); err != nil {
+		return nil, err
+	}
+	if fields, ok := issue["fields"].(map[string]any); ok {
+		if w.epicLink != "" {
+			fields[EpicLinkField] = w.epicLink
+		}
+		if w.storyPoints != nil {
+			fields[StoryPointsField] = *w.storyPoints
+		}
+	}
+	if w.properties != nil {
+		issue["properties"] = w.properties
+	}
+	if w.changelog != nil {
+		issue["changelog"] = map[string]any{
+			"startAt":    0,
+			"maxResults": len(w.changelog),
+			"total":      len(w.changelog),
+			"histories":  w.changelog,
+		}
+	}
+
+	return json.Marshal(issue)
+}
+
+// wireIssue renders a stored issue for the wire, callers must hold the lock
+func (s *Server) wireIssue(rec *record) wireIssue {
+	return wireIssue{Issue: s.renderIssue(rec), epicLink: rec.epicLink, storyPoints: rec.storyPoints}
+}
+
+// Issue storage helpers, callers must hold the lock
+
+func (s *Server) lookup(keyOrID string) (*record, bool) {
+	if rec, ok := s.issues[keyOrID]; ok {
+		return rec, true
+	}
+	for _, rec := range s.issues {
+		if rec.issue.ID == keyOrID {
+			return rec, true
+		}
+	}
+	return nil, false
+}
+
+func (s *Server) newID() string {
+	s.nextID++
+	return strconv.Itoa(s.nextID)
+}
+
+// add stores the issue, assigning a key and ID when missing
+func (s *Server) add(issue jira.Issue, epicLink string) *record {
+	projectKey := issue.Fields.Project.Key
+
+	if issue.Key == "" {
+		s.counters[projectKey]++
+		issue.Key = fmt.Sprintf("%s-%d", projectKey, s.counters[projectKey])
+	} else if p, n := splitKey(issue.Key); n > s.counters[p] {
+		s.counters[p] = n
+	}
+
+	if issue.ID == "" {
+		issue.ID = s.newID()
+	}
+	issue.Self = s.URL + "/rest/api/2/issue/" + issue.ID
+
+	if p, ok := s.projects[projectKey]; ok {
+		issue.Fields.Project = *p
+	}
+	if issue.Fields.Status.Name == "" && len(s.transitions) > 0 {
+		issue.Fields.Status = s.transitions[0].To
+	}
+
+	now := jira.JIRATime{Time: time.Now().UTC()}
+	if issue.Fields.Created.IsZero() {
+		issue.Fields.Created = now
+	}
+	if issue.Fields.Updated.IsZero() {
+		issue.Fields.Updated = issue.Fields.Created
+	}
+	if issue.Fields.Reporter == nil {
+		reporter := s.currentUser
+		issue.Fields.Reporter = &reporter
+	}
+
+	// Tree data is computed, never stored
+	issue.Children = nil
+	issue.Fields.Subtasks = nil
+
+	if _, exists := s.issues[issue.Key]; !exists {
+		s.order = append(s.order, issue.Key)
+	}
+
+	rec := &record{issue: issue, epicLink: epicLink}
+	s.issues[issue.Key] = rec
+
+	return rec
+}
+
+// renderIssue returns the issue as served by the API, with sub-tasks filled in
+func (s *Server) renderIssue(rec *record) jira.Issue {
+	issue := rec.issue
+
+	subtasks := make([]jira.Issue, 0)
+	for _, key := range s.order {
+		child := s.issues[key]
+		if child.issue.Fields.Parent != nil && child.issue.Fields.Parent.Key == issue.Key && isSubtask(child.issue) {
+			subtasks = append(subtasks, jira.Issue{
+				Key: child.issue.Key,
+				ID:  child.issue.ID,
+				Fields: jira.IssueFields{
+					Summary:   child.issue.Fields.Summary,
+					Status:    child.issue.Fields.Status,
+					IssueType: child.issue.Fields.IssueType,
+				},
+			})
+		}
+	}
+	if len(subtasks) > 0 {
+		issue.Fields.Subtasks = subtasks
+	}
+
+	// Links are stored once and rendered on both of their issues
+	issue.Fields.IssueLinks = nil
+	if links := s.renderLinks(issue.Key); len(links) > 0 {
+		issue.Fields.IssueLinks = links
+	}
+
+	if len(rec.attachments) > 0 {
+		issue.Fields.Attachments = make([]jira.Attachment, 0, len(rec.attachments))
+		for _, a := range rec.attachments {
+			issue.Fields.Attachments = append(issue.Fields.Attachments, s.renderAttachment(a))
+		}
+	}
+
+	return issue
+}
+
+func (s *Server) applyUpdate(rec *record, update jira.IssueUpdate) error {
+	f := &rec.issue.Fields
+	items := make([]jira.ChangeItem, 0, len(update.Fields))
+
+	for field, value := range update.Fields {
+		switch field {
+		case "summary":
+			v, ok := value.(string)
+			if !ok || v == "" {
+				return fmt.Errorf("summary: must be a non-empty string")
+			}
+			items = append(items, changeItem(field, "", f.Summary, "", v))
+			f.Summary = v
+		case "description":
+			v, _ := value.(string)
+			items = append(items, changeItem(field, "", f.Description, "", v))
+			f.Description = v
+		case "assignee":
+			user := s.userFromValue(value)
+			items = append(items, changeItem(field, accountID(f.Assignee), displayName(f.Assignee), accountID(user), displayName(user)))
+			f.Assignee = user
+		case "priority":
+			if m, ok := value.(map[string]any); ok {
+				name, _ := m["name"].(string)
+				items = append(items, changeItem(field, f.Priority.ID, f.Priority.Name, "", name))
+				f.Priority = jira.Priority{Name: name}
+			}
+		case "parent":
+			if m, ok := value.(map[string]any); ok {
+				key, _ := m["key"].(string)
+				from := ""
+				if f.Parent != nil {
+					from = f.Parent.Key
+				}
+				items = append(items, changeItem("Parent", "", from, "", key))
+				f.Parent = &jira.Issue{Key: key}
+			}
+		case "labels", "components", "fixVersions":
+			item, err := s.setListField(rec, field, value)
+			if err != nil {
+				return err
+			}
+			if item != nil {
+				items = append(items, *item)
+			}
+		case StoryPointsField:
+			points, err := storyPointsValue(value)
+			if err != nil {
+				return err
+			}
+			items = append(items, changeItem("Story Points", "", formatPoints(rec.storyPoints), "", formatPoints(points)))
+			rec.storyPoints = points
+		case EpicLinkField:
+			key, _ := value.(string)
+			items = append(items, changeItem("Epic Link", "", rec.epicLink, "", key))
+			rec.epicLink = key
+		default:
+			return fmt.Errorf("field %q cannot be set, it is not on the appropriate screen, or unknown", field)
+		}
+	}
+
+	operations, err := s.applyOperations(rec, update.Update)
+	if err != nil {
+		return err
+	}
+	items = append(items, operations...)
+
+	// Map iteration order is random, keep the changelog stable
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Field < items[j].Field
+	})
+	s.recordChange(rec, items...)
+
+	return nil
+}
+
+// recordChange appends a changelog entry authored by the current user
+func (s *Server) recordChange(rec *record, items ...jira.ChangeItem) {
+	if len(items) == 0 {
+		return
+	}
+
+	author := s.currentUser
+	rec.changes = append(rec.changes, jira.ChangeHistory{
+		ID:      s.newID(),
+		Author:  &author,
+		Created: jira.JIRATime{Time: time.Now().UTC()},
+		Items:   items,
+	})
+}
+
+func changeItem(field string, from string, fromString string, to string, toString string) jira.ChangeItem {
+	return jira.ChangeItem{
+		Field:      field,
+		FieldType:  "jira",
+		FieldID:    strings.ToLower(field),
+		From:       from,
+		FromString: fromString,
+		To:         to,
+		ToString:   toString,
+	}
+}
+
+func accountID(user *jira.User) string {
+	if user == nil {
+		return ""
+	}
+	return user.AccountID
+}
+
+func displayName(user *jira.User) string {
+	if user == nil {
+		return ""
+	}
+	return user.DisplayName
+}
+
+func (s *Server) userFromValue(value any) *jira.User {
+	m, ok := value.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	for _, k := range []string{"accountId", "name", "emailAddress"} {
+		if v, _ := m[k].(string); v != "" {
+			if user, ok := s.findUser(v); ok {
+				return user
+			}
+			return &jira.User{AccountID: v, DisplayName: v}
+		}
+	}
+
+	return nil
+}
+
+// fieldValue returns the value of a JQL field of an issue
+func (s *Server) fieldValue(rec *record, field string) string {
+	f := rec.issue.Fields
+
+	switch field {
+	case "project":
+		return f.Project.Key
+	case "key", "issuekey":
+		return rec.issue.Key
+	case "parent":
+		if f.Parent != nil {
+			return f.Parent.Key
+		}
+	case "epic link":
+		return rec.epicLink
+	case "status":
+		return f.Status.Name
+	case "issuetype":
+		return f.IssueType.Name
+	case "priority":
+		return f.Priority.Name
+	case "summary":
+		return f.Summary
+	case "resolution":
+		if f.Status.StatusCategory.Key == jira.StatusCategoryDone {
+			return "Done"
+		}
+	case "assignee":
+		if f.Assignee != nil {
+			return f.Assignee.AccountID
+		}
+	case "reporter":
+		if f.Reporter != nil {
+			return f.Reporter.AccountID
+		}
+	case "sprint":
+		if rec.sprint != 0 {
+			return strconv.Itoa(rec.sprint)
+		}
+	case "worklogauthor":
+		if len(rec.worklogs) > 0 {
+			return accountID(rec.worklogs[0].Author)
+		}
+	case "watcher":
+		if len(rec.watchers) > 0 {
+			return rec.watchers[0].AccountID
+		}
+	case "fixversion":
+		if len(f.FixVersions) > 0 {
+			return f.FixVersions[0].Name
+		}
+	case "component":
+		if len(f.Components) > 0 {
+			return f.Components[0].Name
+		}
+	case "labels":
+		if len(f.Labels) > 0 {
+			return f.Labels[0]
+		}
+	}
+
+	return ""
+}
+
+// valueMatches compares a field value with a JQL operand
+func (s *Server) valueMatches(rec *record, field string, actual string, expected string) bool {
+	switch field {
+	case "assignee", "reporter":
+		user := rec.issue.Fields.Assignee
+		if field == "reporter" {
+			user = rec.issue.Fields.Reporter
+		}
+
+		switch strings.ToLower(expected) {
+		case "empty", "null":
+			return user == nil
+		case "currentuser()":
+			return user != nil && user.AccountID == s.currentUser.AccountID
+		}
+
+		return user != nil && (strings.EqualFold(user.AccountID, expected) ||
+			strings.EqualFold(user.EmailAddress, expected) ||
+			strings.EqualFold(user.DisplayName, expected))
+	case "project":
+		if p, ok := s.projects[actual]; ok && (p.ID == expected || strings.EqualFold(p.Name, expected)) {
+			return true
+		}
+	case "parent", "epic link", "key", "issuekey":
+		// Issues can be referenced by key or ID
+		if other, ok := s.lookup(expected); ok {
+			return other.issue.Key == actual
+		}
+	case "summary":
+		return strings.Contains(strings.ToLower(actual), strings.ToLower(expected))
+	case "worklogauthor":
+		// Any of the worklog authors may match
+		for _, worklog := range rec.worklogs {
+			author := worklog.Author
+			if author == nil {
+				continue
+			}
+			if strings.EqualFold(expected, "currentuser()") && author.AccountID == s.currentUser.AccountID {
+				return true
+			}
+			if strings.EqualFold(author.AccountID, expected) || strings.EqualFold(author.EmailAddress, expected) || strings.EqualFold(author.DisplayName, expected) {
+				return true
+			}
+		}
+		return false
+	case "watcher":
+		return s.isWatcher(rec, expected)
+	case "fixversion":
+		// Any of the fix versions may match, by name or ID
+		return slices.ContainsFunc(rec.issue.Fields.FixVersions, func(v jira.Version) bool {
+			return v.ID == expected || strings.EqualFold(v.Name, expected)
+		})
+	case "component":
+		return slices.ContainsFunc(rec.issue.Fields.Components, func(c jira.Component) bool {
+			return c.ID == expected || strings.EqualFold(c.Name, expected)
+		})
+	case "labels":
+		return slices.ContainsFunc(rec.issue.Fields.Labels, func(l string) bool {
+			return strings.EqualFold(l, expected)
+		})
+	case "sprint":
+		sprint, ok := s.sprint(actual)
+		if !ok {
+			break
+		}
+
+		// Sprints can be referenced by ID, name or state function
+		switch strings.ToLower(expected) {
+		case "opensprints()":
+			return sprint.State != jira.SprintStateClosed
+		case "closedsprints()":
+			return sprint.State == jira.SprintStateClosed
+		}
+		if strings.EqualFold(sprint.Name, expected) {
+			return true
+		}
+	}
+
+	if strings.EqualFold(expected, "empty") || strings.EqualFold(expected, "null") {
+		return actual == ""
+	}
+
+	return strings.EqualFold(actual, expected)
+}
+
+// HTTP helpers
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]any{
+		"errorMessages": []string{message},
+		"errors":        map[string]string{},
+	})
+}
+
+func pagination(r *http.Request) (int, int) {
+	startAt, _ := strconv.Atoi(r.URL.Query().Get("startAt"))
+	if startAt < 0 {
+		startAt = 0
+	}
+
+	maxResults, err := strconv.Atoi(r.URL.Query().Get("maxResults"))
+	if err != nil || maxResults <= 0 {
+		maxResults = defaultMaxResults
+	}
+
+	return startAt, min(maxResults, maxMaxResults)
+}
+
+func paginate[T any](items []T, startAt int, maxResults int) []T {
+	if startAt >= len(items) {
+		return make([]T, 0)
+	}
+	end := min(startAt+maxResults, len(items))
+	return items[startAt:end]
+}
+
+func isSubtask(issue jira.Issue) bool {
+	t := strings.ToLower(issue.Fields.IssueType.Name)
+	return t == "sub-task" || t == "subtask"
+}
+
+func defaultTransitions() []jira.Transition {
+	return []jira.Transition{
+		{ID: "11", Name: "To Do", To: jira.Status{ID: "1", Name: "To Do", StatusCategory: jira.StatusCategory{ID: 2, Key: jira.StatusCategoryNew, Name: "To Do"}}},
+		{ID: "21", Name: "In Progress", To: jira.Status{ID: "3", Name: "In Progress", StatusCategory: jira.StatusCategory{ID: 4, Key: jira.StatusCategoryInProgress, Name: "In Progress"}}},
+		{ID: "31", Name: "Done", To: jira.Status{ID: "10001", Name: "Done", StatusCategory: jira.StatusCategory{ID: 3, Key: jira.StatusCategoryDone, Name: "Done"}}},
+	}
+}
+
+func defaultFields() []jira.Field {
+	system := func(id string, name string, typ string) jira.Field {
+		return jira.Field{ID: id, Key: id, Name: name, Schema: jira.FieldSchema{Type: typ, System: id}}
+	}
+
+	return []jira.Field{
+		system("summary", "Summary", "string"),
+		system("description", "Description", "string"),
+		system("issuetype", "Issue Type", "issuetype"),
+		system("status", "Status", "status"),
+		system("priority", "Priority", "priority"),
+		system("assignee", "Assignee", "user"),
+		system("reporter", "Reporter", "user"),
+		system("project", "Project", "project"),
+		system("parent", "Parent", "issuelink"),
+		{ID: "labels", Key: "labels", Name: "Labels", Schema: jira.FieldSchema{Type: "array", Items: "string", System: "labels"}},
+		{ID: "components", Key: "components", Name: "Component/s", Schema: jira.FieldSchema{Type: "array", Items: "component", System: "components"}},
+		{ID: "fixVersions", Key: "fixVersions", Name: "Fix Version/s", Schema: jira.FieldSchema{Type: "array", Items: "version", System: "fixVersions"}},
+		system("created", "Created", "datetime"),
+		system("updated", "Updated", "datetime"),
+		{
+			ID:     EpicLinkField,
+			Key:    EpicLinkField,
+			Name:   "Epic Link",
+			Custom: true,
+			Schema: jira.FieldSchema{Type: "any", Custom: "com.pyxis.greenhopper.jira:gh-epic-link", CustomID: 10014},
+		},
+		{
+			ID:     StoryPointsField,
+			Key:    StoryPointsField,
+			Name:   "Story Points",
+			Custom: true,
+			Schema: jira.FieldSchema{Type: "number", Custom: "com.atlassian.jira.plugin.system.customfieldtypes:float", CustomID: 10016},
+		},
+	}
+}
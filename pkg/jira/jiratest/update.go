@@ -0,0 +1,235 @@
+package jiratest
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/lburgazzoli/gira/pkg/jira"
+)
+
+// listFields maps the array fields editable with set, add and remove
+// operations to the name of their changelog items
+var listFields = map[string]string{
+	"labels":      "labels",
+	"components":  "Component",
+	"fixVersions": "Fix Version",
+}
+
+// applyOperations applies the "update" section of an edit request, where each
+// field takes a list of set, add and remove operations. Callers must hold the
+// lock.
+func (s *Server) applyOperations(rec *record, update map[string]any) ([]jira.ChangeItem, error) {
+	items := make([]jira.ChangeItem, 0, len(update))
+
+	for field, value := range update {
+		if _, ok := listFields[field]; !ok {
+			return nil, fmt.Errorf("field %q does not support update operations", field)
+		}
+
+		ops, ok := value.([]any)
+		if !ok {
+			return nil, fmt.Errorf("%s: operations must be a list", field)
+		}
+
+		names := s.listValue(rec, field)
+		for _, op := range ops {
+			m, ok := op.(map[string]any)
+			if !ok || len(m) != 1 {
+				return nil, fmt.Errorf("%s: each operation must have a single verb", field)
+			}
+
+			for verb, arg := range m {
+				switch verb {
+				case "set":
+					values, _ := arg.([]any)
+					names = names[:0:0]
+					for _, v := range values {
+						names = appendName(names, s.itemName(rec, v))
+					}
+				case "add":
+					names = appendName(names, s.itemName(rec, arg))
+				case "remove":
+					name := s.itemName(rec, arg)
+					names = slices.DeleteFunc(names, func(n string) bool { return strings.EqualFold(n, name) })
+				default:
+					return nil, fmt.Errorf("%s: unsupported operation %q", field, verb)
+				}
+			}
+		}
+
+		item, err := s.setListValue(rec, field, names)
+		if err != nil {
+			return nil, err
+		}
+		if item != nil {
+			items = append(items, *item)
+		}
+	}
+
+	return items, nil
+}
+
+// setListField sets an array field from the values of a "fields" section
+func (s *Server) setListField(rec *record, field string, value any) (*jira.ChangeItem, error) {
+	values, ok := value.([]any)
+	if !ok && value != nil {
+		return nil, fmt.Errorf("%s: must be a list", field)
+	}
+
+	names := make([]string, 0, len(values))
+	for _, v := range values {
+		names = appendName(names, s.itemName(rec, v))
+	}
+
+	return s.setListValue(rec, field, names)
+}
+
+// listValue returns the names held by an array field
+func (s *Server) listValue(rec *record, field string) []string {
+	f := rec.issue.Fields
+	names := make([]string, 0)
+
+	switch field {
+	case "labels":
+		names = append(names, f.Labels...)
+	case "components":
+		for _, c := range f.Components {
+			names = append(names, c.Name)
+		}
+	case "fixVersions":
+		for _, v := range f.FixVersions {
+			names = append(names, v.Name)
+		}
+	}
+
+	return names
+}
+
+// setListValue replaces the values of an array field, resolving components
+// and versions against the project of the issue
+func (s *Server) setListValue(rec *record, field string, names []string) (*jira.ChangeItem, error) {
+	f := &rec.issue.Fields
+	before := s.listValue(rec, field)
+
+	switch field {
+	case "labels":
+		for _, name := range names {
+			if name == "" || strings.ContainsAny(name, " \t") {
+				return nil, fmt.Errorf("labels: %q is not a valid label", name)
+			}
+		}
+		f.Labels = names
+	case "components":
+		components := make([]jira.Component, 0, len(names))
+		for _, name := range names {
+			c, ok := s.projectComponent(f.Project.Key, name)
+			if !ok {
+				return nil, fmt.Errorf("components: component %q is not valid", name)
+			}
+			components = append(components, jira.Component{ID: c.ID, Name: c.Name})
+		}
+		f.Components = components
+	case "fixVersions":
+		versions := make([]jira.Version, 0, len(names))
+		for _, name := range names {
+			v, ok := s.projectVersion(f.Project.Key, name)
+			if !ok {
+				return nil, fmt.Errorf("fixVersions: version %q is not valid", name)
+			}
+			versions = append(versions, jira.Version{ID: v.ID, Name: v.Name})
+		}
+		f.FixVersions = versions
+	}
+
+	after := s.listValue(rec, field)
+	if slices.Equal(before, after) {
+		return nil, nil
+	}
+
+	item := changeItem(listFields[field], "", strings.Join(before, " "), "", strings.Join(after, " "))
+	return &item, nil
+}
+
+// itemName returns the name of an array item given as a string or as an
+// object with a name or an ID
+func (s *Server) itemName(rec *record, value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case map[string]any:
+		if name, _ := v["name"].(string); name != "" {
+			return name
+		}
+		id, _ := v["id"].(string)
+		if c, ok := s.projectComponent(rec.issue.Fields.Project.Key, id); ok {
+			return c.Name
+		}
+		if pv, ok := s.projectVersion(rec.issue.Fields.Project.Key, id); ok {
+			return pv.Name
+		}
+		return id
+	}
+	return fmt.Sprint(value)
+}
+
+func (s *Server) projectComponent(projectKey string, ref string) (*jira.Component, bool) {
+	p, ok := s.projects[projectKey]
+	if !ok {
+		return nil, false
+	}
+	i := slices.IndexFunc(p.Components, func(c jira.Component) bool {
+		return c.ID == ref || strings.EqualFold(c.Name, ref)
+	})
+	if i < 0 {
+		return nil, false
+	}
+	return &p.Components[i], true
+}
+
+func (s *Server) projectVersion(projectKey string, ref string) (*jira.Version, bool) {
+	p, ok := s.projects[projectKey]
+	if !ok {
+		return nil, false
+	}
+	i := slices.IndexFunc(p.Versions, func(v jira.Version) bool {
+		return v.ID == ref || strings.EqualFold(v.Name, ref)
+	})
+	if i < 0 {
+		return nil, false
+	}
+	return &p.Versions[i], true
+}
+
+// storyPointsValue converts the value of the story points field, nil clears it
+func storyPointsValue(value any) (*float64, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case float64:
+		return &v, nil
+	case string:
+		n, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %q is not a number", StoryPointsField, v)
+		}
+		return &n, nil
+	default:
+		return nil, fmt.Errorf("%s: must be a number", StoryPointsField)
+	}
+}
+
+func appendName(names []string, name string) []string {
+	if slices.ContainsFunc(names, func(n string) bool { return strings.EqualFold(n, name) }) {
+		return names
+	}
+	return append(names, name)
+}
+
+func formatPoints(points *float64) string {
+	if points == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*points, 'f', -1, 64)
+}
@@ -0,0 +1,119 @@
+package jiratest
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/lburgazzoli/gira/pkg/jira"
+)
+
+// WithUsers adds users to the directory searched by the user endpoints,
+// which also holds the current user and the users found on issues
+func WithUsers(users ...jira.User) Option {
+	return func(s *Server) {
+		s.users = append(s.users, users...)
+	}
+}
+
+// AddUser adds a user to the directory
+func (s *Server) AddUser(user jira.User) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.users = append(s.users, user)
+}
+
+// directory returns the known users, callers must hold the lock
+func (s *Server) directory() []jira.User {
+	users := make([]jira.User, 0, len(s.users)+1)
+	seen := make(map[string]bool)
+
+	add := func(u *jira.User) {
+		if u == nil || u.ID() == "" || seen[u.ID()] {
+			return
+		}
+		seen[u.ID()] = true
+		users = append(users, *u)
+	}
+
+	add(&s.currentUser)
+	for i := range s.users {
+		add(&s.users[i])
+	}
+	for _, key := range s.order {
+		rec := s.issues[key]
+		add(rec.issue.Fields.Assignee)
+		add(rec.issue.Fields.Reporter)
+		for i := range rec.watchers {
+			add(&rec.watchers[i])
+		}
+	}
+
+	return users
+}
+
+// findUser looks a user up by account ID, username, key or email address,
+// callers must hold the lock
+func (s *Server) findUser(id string) (*jira.User, bool) {
+	for _, u := range s.directory() {
+		if u.AccountID == id || u.Key == id || (u.Name != "" && strings.EqualFold(u.Name, id)) ||
+			(u.EmailAddress != "" && strings.EqualFold(u.EmailAddress, id)) {
+			return &u, true
+		}
+	}
+
+	return nil, false
+}
+
+// handleGetUser looks a user up by accountId like Cloud, or by username like
+// Data Center
+func (s *Server) handleGetUser(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("accountId")
+	if id == "" {
+		id = r.URL.Query().Get("username")
+	}
+	if id == "" {
+		writeError(w, http.StatusBadRequest, "accountId is required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.findUser(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, "The user does not exist")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, user)
+}
+
+// handleSearchUsers returns the users whose display name, email address,
+// username or account ID contain the query
+func (s *Server) handleSearchUsers(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("query")
+	if query == "" {
+		query = r.URL.Query().Get("username")
+	}
+	if query == "" {
+		writeError(w, http.StatusBadRequest, "query is required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	query = strings.ToLower(query)
+	users := make([]jira.User, 0)
+	for _, u := range s.directory() {
+		for _, v := range []string{u.DisplayName, u.EmailAddress, u.Name, u.AccountID} {
+			if v != "" && strings.Contains(strings.ToLower(v), query) {
+				users = append(users, u)
+				break
+			}
+		}
+	}
+
+	writeJSON(w, http.StatusOK, users)
+}
@@ -0,0 +1,204 @@
+package jiratest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/lburgazzoli/gira/pkg/jira"
+)
+
+// versionBody is the payload of version creation requests
+type versionBody struct {
+	jira.Version
+	Project string `json:"project"`
+}
+
+// findVersion looks a version up by ID, callers must hold the lock
+func (s *Server) findVersion(id string) (*jira.Project, *jira.Version, bool) {
+	for _, p := range s.projects {
+		for i := range p.Versions {
+			if p.Versions[i].ID == id {
+				return p, &p.Versions[i], true
+			}
+		}
+	}
+	return nil, nil, false
+}
+
+// renderVersion returns a version as sent by the version endpoints, callers
+// must hold the lock
+func (s *Server) renderVersion(project *jira.Project, version jira.Version) jira.Version {
+	version.Self = fmt.Sprintf("%s/rest/api/2/version/%s", s.URL, version.ID)
+	version.ProjectID, _ = strconv.Atoi(project.ID)
+	return version
+}
+
+// seedVersions resolves the fix versions and components of a fixture issue
+// by name, adding them to the project when missing. Callers must hold the lock.
+func (s *Server) seedVersions(rec *record) {
+	f := &rec.issue.Fields
+
+	p, ok := s.projects[f.Project.Key]
+	if !ok {
+		return
+	}
+
+	for i, v := range f.FixVersions {
+		j := slices.IndexFunc(p.Versions, func(pv jira.Version) bool {
+			return (v.ID != "" && pv.ID == v.ID) || strings.EqualFold(pv.Name, v.Name)
+		})
+		if j < 0 {
+			if v.ID == "" {
+				v.ID = s.newID()
+			}
+			p.Versions = append(p.Versions, v)
+			j = len(p.Versions) - 1
+		}
+		f.FixVersions[i] = jira.Version{ID: p.Versions[j].ID, Name: p.Versions[j].Name}
+	}
+
+	for i, c := range f.Components {
+		j := slices.IndexFunc(p.Components, func(pc jira.Component) bool {
+			return (c.ID != "" && pc.ID == c.ID) || strings.EqualFold(pc.Name, c.Name)
+		})
+		if j < 0 {
+			if c.ID == "" {
+				c.ID = s.newID()
+			}
+			p.Components = append(p.Components, c)
+			j = len(p.Components) - 1
+		}
+		f.Components[i] = jira.Component{ID: p.Components[j].ID, Name: p.Components[j].Name}
+	}
+}
+
+func (s *Server) handleCreateVersion(w http.ResponseWriter, r *http.Request) {
+	var body versionBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid version: %v", err))
+		return
+	}
+
+	if strings.TrimSpace(body.Name) == "" {
+		writeError(w, http.StatusBadRequest, "name: You must specify a valid version name")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	projectRef := body.Project
+	if projectRef == "" && body.ProjectID != 0 {
+		projectRef = strconv.Itoa(body.ProjectID)
+	}
+
+	p, ok := s.findProject(projectRef)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("No project could be found with key '%s'.", projectRef))
+		return
+	}
+
+	if slices.ContainsFunc(p.Versions, func(v jira.Version) bool { return strings.EqualFold(v.Name, body.Name) }) {
+		writeError(w, http.StatusBadRequest, "name: A version with this name already exists in this project.")
+		return
+	}
+
+	version := body.Version
+	version.ID = s.newID()
+	version.Self = ""
+	version.ProjectID = 0
+	p.Versions = append(p.Versions, version)
+
+	writeJSON(w, http.StatusCreated, s.renderVersion(p, version))
+}
+
+// handleUpdateVersion applies partial updates, moving the unresolved issues
+// of a version to the version whose URL is given in moveUnfixedIssuesTo
+func (s *Server) handleUpdateVersion(w http.ResponseWriter, r *http.Request) {
+	var update jira.VersionUpdate
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid version: %v", err))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, v, ok := s.findVersion(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Could not find version for id '%s'", r.PathValue("id")))
+		return
+	}
+
+	var target *jira.Version
+	if update.MoveUnfixedIssuesTo != "" {
+		// JIRA takes the self URL of the version
+		id, isSelf := strings.CutPrefix(update.MoveUnfixedIssuesTo, s.URL+"/rest/api/2/version/")
+		tp, tv, ok := s.findVersion(id)
+		if !isSelf || !ok || tp != p || tv == v {
+			writeError(w, http.StatusBadRequest, "moveUnfixedIssuesTo: the version must be another version of the project")
+			return
+		}
+		target = tv
+	}
+
+	if update.Name != nil {
+		if slices.ContainsFunc(p.Versions, func(other jira.Version) bool {
+			return other.ID != v.ID && strings.EqualFold(other.Name, *update.Name)
+		}) {
+			writeError(w, http.StatusBadRequest, "name: A version with this name already exists in this project.")
+			return
+		}
+		v.Name = *update.Name
+	}
+	if update.Description != nil {
+		v.Description = *update.Description
+	}
+	if update.StartDate != nil {
+		v.StartDate = *update.StartDate
+	}
+	if update.ReleaseDate != nil {
+		v.ReleaseDate = *update.ReleaseDate
+	}
+	if update.Released != nil {
+		v.Released = *update.Released
+	}
+	if update.Archived != nil {
+		v.Archived = *update.Archived
+	}
+
+	if target != nil {
+		s.moveUnfixedIssues(p, v, target)
+	}
+
+	writeJSON(w, http.StatusOK, s.renderVersion(p, *v))
+}
+
+// moveUnfixedIssues replaces a fix version by another on the unresolved
+// issues of the project, callers must hold the lock
+func (s *Server) moveUnfixedIssues(project *jira.Project, from *jira.Version, to *jira.Version) {
+	for _, key := range s.order {
+		rec := s.issues[key]
+		f := &rec.issue.Fields
+
+		if f.Project.Key != project.Key || f.Status.StatusCategory.Key == jira.StatusCategoryDone {
+			continue
+		}
+
+		i := slices.IndexFunc(f.FixVersions, func(v jira.Version) bool { return v.ID == from.ID })
+		if i < 0 {
+			continue
+		}
+
+		f.FixVersions[i] = jira.Version{ID: to.ID, Name: to.Name}
+		if slices.IndexFunc(f.FixVersions, func(v jira.Version) bool { return v.ID == to.ID }) != i {
+			f.FixVersions = slices.Delete(f.FixVersions, i, i+1)
+		}
+
+		s.recordChange(rec, changeItem("Fix Version", from.ID, from.Name, to.ID, to.Name))
+	}
+}
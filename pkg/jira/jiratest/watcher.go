@@ -0,0 +1,187 @@
+package jiratest
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/lburgazzoli/gira/pkg/jira"
+)
+
+// Watchers returns the account IDs, or the usernames on Data Center, of the
+// users watching an issue
+func (s *Server) Watchers(key string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.issues[key]
+	if !ok {
+		return nil
+	}
+
+	ids := make([]string, 0, len(rec.watchers))
+	for _, w := range rec.watchers {
+		ids = append(ids, w.ID())
+	}
+
+	return ids
+}
+
+// Voters returns the account IDs, or the usernames on Data Center, of the
+// users who voted for an issue
+func (s *Server) Voters(key string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.issues[key]
+	if !ok {
+		return nil
+	}
+
+	ids := make([]string, 0, len(rec.voters))
+	for _, v := range rec.voters {
+		ids = append(ids, v.ID())
+	}
+
+	return ids
+}
+
+// watch adds a user to the watchers of an issue unless already watching,
+// callers must hold the lock
+func (s *Server) watch(rec *record, user jira.User) {
+	if !slices.ContainsFunc(rec.watchers, func(w jira.User) bool { return w.ID() == user.ID() }) {
+		rec.watchers = append(rec.watchers, user)
+	}
+}
+
+// isWatcher reports whether a JQL operand names one of the watchers of an issue
+func (s *Server) isWatcher(rec *record, expected string) bool {
+	for _, w := range rec.watchers {
+		if strings.EqualFold(expected, "currentuser()") && w.ID() == s.currentUser.ID() {
+			return true
+		}
+		if strings.EqualFold(w.AccountID, expected) || strings.EqualFold(w.EmailAddress, expected) || strings.EqualFold(w.DisplayName, expected) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Server) handleGetWatchers(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.lookup(r.PathValue("key"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Issue Does Not Exist")
+		return
+	}
+
+	watchers := append([]jira.User{}, rec.watchers...)
+
+	writeJSON(w, http.StatusOK, jira.Watchers{
+		IsWatching: s.isWatcher(rec, "currentUser()"),
+		WatchCount: len(watchers),
+		Watchers:   watchers,
+	})
+}
+
+// handleAddWatcher adds the user whose account ID, or username on Data
+// Center, is the JSON string body, or the current user when the body is empty
+func (s *Server) handleAddWatcher(w http.ResponseWriter, r *http.Request) {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var id string
+	if len(strings.TrimSpace(string(data))) > 0 {
+		if err := json.Unmarshal(data, &id); err != nil || id == "" {
+			writeError(w, http.StatusBadRequest, "the body must be the account ID of the user as a JSON string")
+			return
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.lookup(r.PathValue("key"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Issue Does Not Exist")
+		return
+	}
+
+	user := s.currentUser
+	if id != "" {
+		user = *s.userFromValue(map[string]any{"accountId": id})
+	}
+
+	s.watch(rec, user)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleRemoveWatcher(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("accountId")
+	if id == "" {
+		id = r.URL.Query().Get("username")
+	}
+	if id == "" {
+		writeError(w, http.StatusBadRequest, "accountId is required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.lookup(r.PathValue("key"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Issue Does Not Exist")
+		return
+	}
+
+	rec.watchers = slices.DeleteFunc(rec.watchers, func(u jira.User) bool { return u.ID() == id })
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleVote(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.lookup(r.PathValue("key"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Issue Does Not Exist")
+		return
+	}
+
+	if reporter := rec.issue.Fields.Reporter; reporter != nil && reporter.ID() == s.currentUser.ID() {
+		writeError(w, http.StatusNotFound, "You cannot vote for an issue you have reported.")
+		return
+	}
+
+	if !slices.ContainsFunc(rec.voters, func(u jira.User) bool { return u.ID() == s.currentUser.ID() }) {
+		rec.voters = append(rec.voters, s.currentUser)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleUnvote(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.lookup(r.PathValue("key"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Issue Does Not Exist")
+		return
+	}
+
+	rec.voters = slices.DeleteFunc(rec.voters, func(u jira.User) bool { return u.ID() == s.currentUser.ID() })
+
+	w.WriteHeader(http.StatusNoContent)
+}
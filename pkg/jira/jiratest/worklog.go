@@ -0,0 +1,231 @@
+package jiratest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lburgazzoli/gira/pkg/jira"
+)
+
+// worklogTimeLayout is the timestamp format of worklog start times
+const worklogTimeLayout = "2006-01-02T15:04:05.000-0700"
+
+// worklogBody is the payload of worklog creation and update requests
+type worklogBody struct {
+	Comment          string `json:"comment"`
+	Started          string `json:"started"`
+	TimeSpentSeconds int    `json:"timeSpentSeconds"`
+}
+
+func (b worklogBody) validate() (time.Time, error) {
+	if b.TimeSpentSeconds <= 0 {
+		return time.Time{}, fmt.Errorf("timeSpentSeconds must be positive")
+	}
+
+	started, err := time.Parse(worklogTimeLayout, b.Started)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid started %q", b.Started)
+	}
+
+	return started, nil
+}
+
+// worklogUpdate is the payload of worklog update requests, where omitted
+// fields keep their value
+type worklogUpdate struct {
+	Comment          *string `json:"comment"`
+	Started          *string `json:"started"`
+	TimeSpentSeconds *int    `json:"timeSpentSeconds"`
+}
+
+// Worklogs returns the worklogs of an issue
+func (s *Server) Worklogs(key string) []jira.Worklog {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.issues[key]
+	if !ok {
+		return nil
+	}
+
+	return append([]jira.Worklog(nil), rec.worklogs...)
+}
+
+func (s *Server) handleListWorklogs(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.lookup(r.PathValue("key"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Issue Does Not Exist")
+		return
+	}
+
+	startAt, maxResults := pagination(r)
+
+	writeJSON(w, http.StatusOK, jira.WorklogPage{
+		Worklogs:   paginate(rec.worklogs, startAt, maxResults),
+		StartAt:    startAt,
+		MaxResults: maxResults,
+		Total:      len(rec.worklogs),
+	})
+}
+
+func (s *Server) handleAddWorklog(w http.ResponseWriter, r *http.Request) {
+	var body worklogBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid worklog: %v", err))
+		return
+	}
+
+	started, err := body.validate()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.lookup(r.PathValue("key"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Issue Does Not Exist")
+		return
+	}
+
+	author := s.currentUser
+	now := jira.JIRATime{Time: time.Now().UTC()}
+	worklog := jira.Worklog{
+		ID:               s.newID(),
+		IssueID:          rec.issue.ID,
+		Author:           &author,
+		Comment:          body.Comment,
+		Started:          jira.JIRATime{Time: started},
+		TimeSpentSeconds: body.TimeSpentSeconds,
+		Created:          now,
+		Updated:          now,
+	}
+	worklog.TimeSpent = formatTimeSpent(worklog.TimeSpentSeconds)
+
+	rec.worklogs = append(rec.worklogs, worklog)
+	rec.issue.Fields.Updated = now
+
+	writeJSON(w, http.StatusCreated, worklog)
+}
+
+func (s *Server) handleUpdateWorklog(w http.ResponseWriter, r *http.Request) {
+	var update worklogUpdate
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid worklog: %v", err))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, i, ok := s.lookupWorklog(r.PathValue("key"), r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Worklog does not exist")
+		return
+	}
+
+	worklog := &rec.worklogs[i]
+
+	body := worklogBody{
+		Comment:          worklog.Comment,
+		Started:          worklog.Started.Format(worklogTimeLayout),
+		TimeSpentSeconds: worklog.TimeSpentSeconds,
+	}
+	if update.Comment != nil {
+		body.Comment = *update.Comment
+	}
+	if update.Started != nil {
+		body.Started = *update.Started
+	}
+	if update.TimeSpentSeconds != nil {
+		body.TimeSpentSeconds = *update.TimeSpentSeconds
+	}
+
+	started, err := body.validate()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	worklog.Comment = body.Comment
+	worklog.Started = jira.JIRATime{Time: started}
+	worklog.TimeSpentSeconds = body.TimeSpentSeconds
+	worklog.TimeSpent = formatTimeSpent(body.TimeSpentSeconds)
+	worklog.Updated = jira.JIRATime{Time: time.Now().UTC()}
+
+	writeJSON(w, http.StatusOK, worklog)
+}
+
+func (s *Server) handleDeleteWorklog(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, i, ok := s.lookupWorklog(r.PathValue("key"), r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Worklog does not exist")
+		return
+	}
+
+	rec.worklogs = slices.Delete(rec.worklogs, i, i+1)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// lookupWorklog finds a worklog of an issue, callers must hold the lock
+func (s *Server) lookupWorklog(key string, id string) (*record, int, bool) {
+	rec, ok := s.lookup(key)
+	if !ok {
+		return nil, 0, false
+	}
+
+	for i := range rec.worklogs {
+		if rec.worklogs[i].ID == id {
+			return rec, i, true
+		}
+	}
+
+	return nil, 0, false
+}
+
+// worklogTimePredicate matches issues with a worklog started within the bound
+func worklogTimePredicate(op string, bound time.Time) predicate {
+	return func(_ *Server, r *record) bool {
+		for _, worklog := range r.worklogs {
+			if compareTime(worklog.Started.Time, op, bound) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// formatTimeSpent formats seconds like JIRA does, e.g. "1d 2h 30m"
+func formatTimeSpent(seconds int) string {
+	minutes := seconds / 60
+	parts := make([]string, 0, 3)
+
+	for _, unit := range []struct {
+		suffix  string
+		minutes int
+	}{{"w", 5 * 8 * 60}, {"d", 8 * 60}, {"h", 60}, {"m", 1}} {
+		if n := minutes / unit.minutes; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d%s", n, unit.suffix))
+			minutes %= unit.minutes
+		}
+	}
+
+	if len(parts) == 0 {
+		return "0m"
+	}
+
+	return strings.Join(parts, " ")
+}
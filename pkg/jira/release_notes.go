@@ -0,0 +1,121 @@
+package jira
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Release notes groupings
+const (
+	GroupByType      = "type"
+	GroupByComponent = "component"
+	GroupByLabel     = "label"
+)
+
+// ReleaseNoteEntry is an issue fixed in a version
+type ReleaseNoteEntry struct {
+	Key        string   `json:"key"`
+	Summary    string   `json:"summary"`
+	Type       string   `json:"type"`
+	Status     string   `json:"status"`
+	Components []string `json:"components,omitempty"`
+	Labels     []string `json:"labels,omitempty"`
+	URL        string   `json:"url"`
+}
+
+// ReleaseNoteGroup is a titled group of release notes entries
+type ReleaseNoteGroup struct {
+	Name    string             `json:"name"`
+	Entries []ReleaseNoteEntry `json:"entries"`
+}
+
+// ReleaseNotes are the issues fixed in a version, grouped by type, component
+// or label. Issues with several components or labels appear in each group.
+type ReleaseNotes struct {
+	Project string             `json:"project"`
+	Version Version            `json:"version"`
+	GroupBy string             `json:"groupBy"`
+	Groups  []ReleaseNoteGroup `json:"groups"`
+	Entries []ReleaseNoteEntry `json:"entries"`
+}
+
+// BuildReleaseNotes groups the issues fixed in a version. browseURL is the
+// base URL of issue links, e.g. https://jira.example.com/browse.
+func BuildReleaseNotes(project string, version Version, issues []Issue, groupBy string, browseURL string) (*ReleaseNotes, error) {
+	var groupNames func(e *ReleaseNoteEntry) []string
+	var other string
+
+	switch groupBy {
+	case GroupByType:
+		groupNames = func(e *ReleaseNoteEntry) []string { return []string{e.Type} }
+		other = "Other"
+	case GroupByComponent:
+		groupNames = func(e *ReleaseNoteEntry) []string { return e.Components }
+		other = "No component"
+	case GroupByLabel:
+		groupNames = func(e *ReleaseNoteEntry) []string { return e.Labels }
+		other = "No label"
+	default:
+		return nil, fmt.Errorf("unsupported grouping %q, expected one of: %s, %s, %s", groupBy, GroupByType, GroupByComponent, GroupByLabel)
+	}
+
+	notes := &ReleaseNotes{
+		Project: project,
+		Version: version,
+		GroupBy: groupBy,
+		Groups:  make([]ReleaseNoteGroup, 0),
+		Entries: make([]ReleaseNoteEntry, 0, len(issues)),
+	}
+
+	for _, issue := range issues {
+		entry := ReleaseNoteEntry{
+			Key:     issue.Key,
+			Summary: issue.Fields.Summary,
+			Type:    issue.Fields.IssueType.Name,
+			Status:  issue.Fields.Status.Name,
+			Labels:  issue.Fields.Labels,
+			URL:     strings.TrimSuffix(browseURL, "/") + "/" + issue.Key,
+		}
+		for _, c := range issue.Fields.Components {
+			entry.Components = append(entry.Components, c.Name)
+		}
+
+		notes.Entries = append(notes.Entries, entry)
+	}
+
+	slices.SortFunc(notes.Entries, func(a, b ReleaseNoteEntry) int {
+		return compareIssueKeys(a.Key, b.Key)
+	})
+
+	groups := make(map[string][]ReleaseNoteEntry)
+	for _, entry := range notes.Entries {
+		names := groupNames(&entry)
+		if len(names) == 0 || (len(names) == 1 && names[0] == "") {
+			names = []string{other}
+		}
+
+		for _, name := range names {
+			groups[name] = append(groups[name], entry)
+		}
+	}
+
+	// Groups are sorted by name, with the catch-all group last
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		if name != other {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	if _, ok := groups[other]; ok {
+		names = append(names, other)
+	}
+
+	for _, name := range names {
+		notes.Groups = append(notes.Groups, ReleaseNoteGroup{Name: name, Entries: groups[name]})
+	}
+
+	return notes, nil
+}
@@ -0,0 +1,80 @@
+package jira_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lburgazzoli/gira/pkg/jira"
+)
+
+func TestBuildReleaseNotes(t *testing.T) {
+	issue := func(key string, typ string, components []string, labels ...string) jira.Issue {
+		i := jira.Issue{Key: key, Fields: jira.IssueFields{
+			Summary:   "Summary of " + key,
+			IssueType: jira.IssueType{Name: typ},
+			Labels:    labels,
+		}}
+		for _, c := range components {
+			i.Fields.Components = append(i.Fields.Components, jira.Component{Name: c})
+		}
+		return i
+	}
+
+	issues := []jira.Issue{
+		issue("DEMO-10", "Bug", []string{"ui"}, "regression"),
+		issue("DEMO-2", "Story", []string{"api", "ui"}),
+		issue("DEMO-9", "", nil, "docs", "regression"),
+		issue("DEMO-1", "Bug", nil),
+	}
+
+	tests := []struct {
+		groupBy string
+		// want maps the group names, in order, to the keys they hold
+		want [][]string
+	}{
+		{
+			groupBy: jira.GroupByType,
+			want:    [][]string{{"Bug", "DEMO-1", "DEMO-10"}, {"Story", "DEMO-2"}, {"Other", "DEMO-9"}},
+		},
+		{
+			groupBy: jira.GroupByComponent,
+			want:    [][]string{{"api", "DEMO-2"}, {"ui", "DEMO-2", "DEMO-10"}, {"No component", "DEMO-1", "DEMO-9"}},
+		},
+		{
+			groupBy: jira.GroupByLabel,
+			want:    [][]string{{"docs", "DEMO-9"}, {"regression", "DEMO-9", "DEMO-10"}, {"No label", "DEMO-1", "DEMO-2"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.groupBy, func(t *testing.T) {
+			notes, err := jira.BuildReleaseNotes("DEMO", jira.Version{Name: "1.0.0"}, issues, tt.groupBy, "https://jira.example.com/browse/")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := make([][]string, 0, len(notes.Groups))
+			for _, group := range notes.Groups {
+				names := []string{group.Name}
+				for _, entry := range group.Entries {
+					names = append(names, entry.Key)
+				}
+				got = append(got, names)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("groups = %v, want %v", got, tt.want)
+			}
+
+			if len(notes.Entries) != 4 || notes.Entries[0].Key != "DEMO-1" || notes.Entries[3].Key != "DEMO-10" {
+				t.Errorf("entries are not sorted by key: %+v", notes.Entries)
+			}
+			if url := notes.Entries[0].URL; url != "https://jira.example.com/browse/DEMO-1" {
+				t.Errorf("URL = %s", url)
+			}
+		})
+	}
+
+	if _, err := jira.BuildReleaseNotes("DEMO", jira.Version{}, issues, "assignee", ""); err == nil {
+		t.Error("grouping by an unsupported field succeeded")
+	}
+}
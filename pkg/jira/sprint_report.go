@@ -0,0 +1,383 @@
+package jira
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SprintChangelogField is the name of the Sprint field in changelogs
+const SprintChangelogField = "Sprint"
+
+// SprintReportOptions controls how a sprint report is reconstructed
+type SprintReportOptions struct {
+	// Statuses provides the category of the statuses found in changelogs
+	Statuses []Status
+	// PointsField is the changelog name of the story points field, empty
+	// when story points are not tracked
+	PointsField string
+	// Now ends the report of active sprints
+	Now time.Time
+}
+
+// SprintIssueHistory is an issue considered by a sprint report
+type SprintIssueHistory struct {
+	Issue *Issue
+	// Points is the current story points estimate of the issue
+	Points float64
+	// InSprint tells whether the issue currently belongs to the sprint
+	InSprint  bool
+	Histories []ChangeHistory
+}
+
+// SprintIssue is the outcome of an issue within a sprint
+type SprintIssue struct {
+	Key     string  `json:"key"`
+	Summary string  `json:"summary"`
+	Type    string  `json:"type"`
+	Status  string  `json:"status"`
+	Points  float64 `json:"points"`
+	// Committed issues were in the sprint when it started
+	Committed bool `json:"committed"`
+	// Added and Removed are set for scope changes after the sprint start
+	Added     *time.Time `json:"added,omitempty"`
+	Removed   *time.Time `json:"removed,omitempty"`
+	Completed bool       `json:"completed"`
+}
+
+// SprintDay is the state of a sprint at the end of a day
+type SprintDay struct {
+	Date            time.Time `json:"date"`
+	Scope           int       `json:"scope"`
+	ScopePoints     float64   `json:"scopePoints"`
+	Remaining       int       `json:"remaining"`
+	RemainingPoints float64   `json:"remainingPoints"`
+	Ideal           float64   `json:"ideal"`
+	IdealPoints     float64   `json:"idealPoints"`
+}
+
+// SprintReport is the burndown and scope change of a sprint
+type SprintReport struct {
+	Sprint Sprint    `json:"sprint"`
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	// Points tells whether story points are tracked
+	Points bool `json:"points"`
+
+	Committed   []SprintIssue `json:"committed"`
+	Added       []SprintIssue `json:"added"`
+	Removed     []SprintIssue `json:"removed"`
+	Completed   []SprintIssue `json:"completed"`
+	CarriedOver []SprintIssue `json:"carriedOver"`
+
+	Days []SprintDay `json:"days"`
+}
+
+// timeline is the value of a field over time
+type timeline[T any] struct {
+	initial T
+	changes []timelineChange[T]
+}
+
+type timelineChange[T any] struct {
+	at    time.Time
+	value T
+}
+
+func (t timeline[T]) at(when time.Time) T {
+	value := t.initial
+	for _, c := range t.changes {
+		if c.at.After(when) {
+			break
+		}
+		value = c.value
+	}
+	return value
+}
+
+// issueTimelines holds the reconstructed history of an issue
+type issueTimelines struct {
+	issue    SprintIssue
+	created  time.Time
+	inSprint timeline[bool]
+	done     timeline[bool]
+	points   timeline[float64]
+}
+
+func (t *issueTimelines) inSprintAt(when time.Time) bool {
+	return !t.created.After(when) && t.inSprint.at(when)
+}
+
+// BuildSprintReport reconstructs the daily burndown and the scope changes of
+// a sprint from the changelog of the issues that belonged to it.
+//
+// The report ends when the sprint was completed, or now for active sprints.
+// The ideal burndown goes from the work remaining at the start to zero at
+// the planned end of the sprint.
+func BuildSprintReport(sprint *Sprint, issues []SprintIssueHistory, opts SprintReportOptions) (*SprintReport, error) {
+	if sprint.StartDate == nil || sprint.StartDate.IsZero() {
+		return nil, fmt.Errorf("sprint %s has not started", sprint.Name)
+	}
+
+	start := sprint.StartDate.Time
+	plannedEnd := opts.Now
+	if sprint.EndDate != nil && !sprint.EndDate.IsZero() {
+		plannedEnd = sprint.EndDate.Time
+	}
+
+	end := opts.Now
+	if sprint.CompleteDate != nil && !sprint.CompleteDate.IsZero() {
+		end = sprint.CompleteDate.Time
+	} else if sprint.State == SprintStateClosed {
+		end = plannedEnd
+	}
+	if end.Before(start) {
+		end = start
+	}
+
+	report := &SprintReport{
+		Sprint:      *sprint,
+		Start:       start,
+		End:         end,
+		Points:      opts.PointsField != "",
+		Committed:   make([]SprintIssue, 0),
+		Added:       make([]SprintIssue, 0),
+		Removed:     make([]SprintIssue, 0),
+		Completed:   make([]SprintIssue, 0),
+		CarriedOver: make([]SprintIssue, 0),
+		Days:        make([]SprintDay, 0),
+	}
+
+	timelines := make([]*issueTimelines, 0, len(issues))
+	for _, h := range issues {
+		t := buildIssueTimelines(sprint, h, opts)
+
+		// Issues never in the sprint during its course are not part of it
+		if !t.inSprintAt(start) && !enteredSprint(t, start, end) {
+			continue
+		}
+
+		timelines = append(timelines, t)
+	}
+
+	sort.Slice(timelines, func(i, j int) bool {
+		return compareIssueKeys(timelines[i].issue.Key, timelines[j].issue.Key) < 0
+	})
+
+	for _, t := range timelines {
+		issue := t.issue
+		issue.Points = t.points.at(end)
+		issue.Committed = t.inSprintAt(start)
+
+		if !issue.Committed {
+			for _, c := range t.inSprint.changes {
+				if c.value && c.at.After(start) && !c.at.After(end) && !t.created.After(c.at) {
+					added := c.at
+					issue.Added = &added
+					break
+				}
+			}
+			if issue.Added == nil {
+				// Created directly into the sprint
+				added := t.created
+				issue.Added = &added
+			}
+		}
+
+		if !t.inSprintAt(end) {
+			for i := len(t.inSprint.changes) - 1; i >= 0; i-- {
+				c := t.inSprint.changes[i]
+				if !c.value && c.at.After(start) && !c.at.After(end) {
+					removed := c.at
+					issue.Removed = &removed
+					break
+				}
+			}
+		} else {
+			issue.Completed = t.done.at(end)
+		}
+
+		if issue.Committed {
+			committed := issue
+			committed.Points = t.points.at(start)
+			report.Committed = append(report.Committed, committed)
+		}
+		if issue.Added != nil {
+			report.Added = append(report.Added, issue)
+		}
+
+		switch {
+		case issue.Removed != nil:
+			report.Removed = append(report.Removed, issue)
+		case issue.Completed:
+			report.Completed = append(report.Completed, issue)
+		default:
+			report.CarriedOver = append(report.CarriedOver, issue)
+		}
+	}
+
+	for _, at := range sprintDays(start, end) {
+		day := SprintDay{Date: at}
+
+		for _, t := range timelines {
+			if !t.inSprintAt(at) {
+				continue
+			}
+
+			points := t.points.at(at)
+			day.Scope++
+			day.ScopePoints += points
+
+			if !t.done.at(at) {
+				day.Remaining++
+				day.RemainingPoints += points
+			}
+		}
+
+		report.Days = append(report.Days, day)
+	}
+
+	if len(report.Days) > 0 {
+		first := report.Days[0]
+		for i := range report.Days {
+			progress := 1.0
+			if plannedEnd.After(start) {
+				progress = min(float64(report.Days[i].Date.Sub(start))/float64(plannedEnd.Sub(start)), 1)
+			}
+
+			report.Days[i].Ideal = float64(first.Remaining) * (1 - progress)
+			report.Days[i].IdealPoints = first.RemainingPoints * (1 - progress)
+		}
+	}
+
+	return report, nil
+}
+
+// sprintDays returns the start of the sprint, the end of each following day
+// and the end of the report
+func sprintDays(start time.Time, end time.Time) []time.Time {
+	days := []time.Time{start}
+
+	year, month, day := start.Date()
+	for at := time.Date(year, month, day+1, 0, 0, 0, 0, start.Location()).Add(-time.Nanosecond); ; at = at.AddDate(0, 0, 1) {
+		if !at.After(start) {
+			continue
+		}
+		if !at.Before(end) {
+			break
+		}
+		days = append(days, at)
+	}
+
+	if end.After(start) {
+		days = append(days, end)
+	}
+
+	return days
+}
+
+func enteredSprint(t *issueTimelines, start time.Time, end time.Time) bool {
+	for _, c := range t.inSprint.changes {
+		if c.value && c.at.After(start) && !c.at.After(end) {
+			return true
+		}
+	}
+
+	// Issues created directly into the sprint have no Sprint change
+	return len(t.inSprint.changes) == 0 && t.inSprint.initial && t.created.After(start) && !t.created.After(end)
+}
+
+func buildIssueTimelines(sprint *Sprint, h SprintIssueHistory, opts SprintReportOptions) *issueTimelines {
+	issue := h.Issue
+	sprintID := strconv.Itoa(sprint.ID)
+
+	t := &issueTimelines{
+		issue: SprintIssue{
+			Key:     issue.Key,
+			Summary: issue.Fields.Summary,
+			Type:    issue.Fields.IssueType.Name,
+			Status:  issue.Fields.Status.Name,
+		},
+		created:  issue.Fields.Created.Time,
+		inSprint: timeline[bool]{initial: h.InSprint},
+		points:   timeline[float64]{initial: h.Points},
+	}
+
+	sprintSeen, pointsSeen := false, false
+	for _, history := range h.Histories {
+		for _, item := range history.Items {
+			switch {
+			case strings.EqualFold(item.Field, SprintChangelogField):
+				if !sprintSeen {
+					t.inSprint.initial = slices.Contains(splitSprintIDs(item.From), sprintID)
+					sprintSeen = true
+				}
+				t.inSprint.changes = append(t.inSprint.changes, timelineChange[bool]{
+					at:    history.Created.Time,
+					value: slices.Contains(splitSprintIDs(item.To), sprintID),
+				})
+			case opts.PointsField != "" && strings.EqualFold(item.Field, opts.PointsField):
+				if !pointsSeen {
+					t.points.initial = parsePoints(item.FromString)
+					pointsSeen = true
+				}
+				t.points.changes = append(t.points.changes, timelineChange[float64]{
+					at:    history.Created.Time,
+					value: parsePoints(item.ToString),
+				})
+			}
+		}
+	}
+
+	categories := statusCategoryIndex(opts.Statuses, issue.Fields.Status)
+	intervals := statusIntervals(issue, h.Histories, categories)
+
+	t.done.initial = intervals[0].status.StatusCategory.Key == StatusCategoryDone
+	for _, interval := range intervals[1:] {
+		t.done.changes = append(t.done.changes, timelineChange[bool]{
+			at:    interval.start,
+			value: interval.status.StatusCategory.Key == StatusCategoryDone,
+		})
+	}
+
+	return t
+}
+
+// splitSprintIDs splits the comma separated sprint IDs of a Sprint change
+func splitSprintIDs(value string) []string {
+	ids := make([]string, 0)
+	for _, id := range strings.Split(value, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func parsePoints(value string) float64 {
+	points, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0
+	}
+	return points
+}
+
+// compareIssueKeys orders issue keys by project, then numerically
+func compareIssueKeys(a string, b string) int {
+	pa, na, _ := strings.Cut(a, "-")
+	pb, nb, _ := strings.Cut(b, "-")
+	if c := strings.Compare(pa, pb); c != 0 {
+		return c
+	}
+
+	ia, errA := strconv.Atoi(na)
+	ib, errB := strconv.Atoi(nb)
+	if errA != nil || errB != nil {
+		return strings.Compare(na, nb)
+	}
+
+	return ia - ib
+}
@@ -0,0 +1,136 @@
+package jira_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lburgazzoli/gira/pkg/jira"
+)
+
+func sprintChange(at time.Time, from string, to string) jira.ChangeHistory {
+	return jira.ChangeHistory{
+		Created: jira.JIRATime{Time: at},
+		Items:   []jira.ChangeItem{{Field: jira.SprintChangelogField, From: from, To: to}},
+	}
+}
+
+func pointsChange(at time.Time, from string, to string) jira.ChangeHistory {
+	return jira.ChangeHistory{
+		Created: jira.JIRATime{Time: at},
+		Items:   []jira.ChangeItem{{Field: "Story Points", FromString: from, ToString: to}},
+	}
+}
+
+func sprintIssue(key string, status jira.Status, points float64, inSprint bool, histories ...jira.ChangeHistory) jira.SprintIssueHistory {
+	return jira.SprintIssueHistory{
+		Issue: &jira.Issue{Key: key, Fields: jira.IssueFields{
+			Summary: "Issue " + key,
+			Status:  status,
+			Created: jira.JIRATime{Time: day(-7)},
+		}},
+		Points:    points,
+		InSprint:  inSprint,
+		Histories: histories,
+	}
+}
+
+func sprintIssueKeys(issues []jira.SprintIssue) string {
+	keys := make([]string, 0, len(issues))
+	for _, i := range issues {
+		keys = append(keys, fmt.Sprintf("%s:%g", i.Key, i.Points))
+	}
+	return fmt.Sprint(keys)
+}
+
+func TestBuildSprintReport(t *testing.T) {
+	start, end, complete := jira.JIRATime{Time: day(0)}, jira.JIRATime{Time: day(4)}, jira.JIRATime{Time: day(4)}
+	sprint := &jira.Sprint{ID: 5, Name: "Sprint 5", State: jira.SprintStateClosed, StartDate: &start, EndDate: &end, CompleteDate: &complete}
+
+	issues := []jira.SprintIssueHistory{
+		// Committed and completed
+		sprintIssue("DEMO-1", statusDone, 3, true,
+			transition(day(2), statusToDo, statusDone),
+		),
+		// Committed, re-estimated and carried over
+		sprintIssue("DEMO-2", statusToDo, 8, true,
+			pointsChange(day(1), "5", "8"),
+		),
+		// Added after the start and completed
+		sprintIssue("DEMO-3", statusDone, 2, true,
+			sprintChange(day(1), "", "5"),
+			transition(day(3), statusToDo, statusDone),
+		),
+		// Committed then moved to the next sprint
+		sprintIssue("DEMO-4", statusToDo, 1, false,
+			sprintChange(day(2), "5", "6"),
+		),
+		// Moved into the sprint once it was over
+		sprintIssue("DEMO-5", statusToDo, 1, true,
+			sprintChange(day(6), "", "5"),
+		),
+	}
+
+	report, err := jira.BuildSprintReport(sprint, issues, jira.SprintReportOptions{
+		Statuses:    []jira.Status{statusToDo, statusInProgress, statusDone},
+		PointsField: "Story Points",
+		Now:         day(10),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	groups := []struct {
+		name   string
+		issues []jira.SprintIssue
+		want   string
+	}{
+		{name: "committed", issues: report.Committed, want: "[DEMO-1:3 DEMO-2:5 DEMO-4:1]"},
+		{name: "added", issues: report.Added, want: "[DEMO-3:2]"},
+		{name: "removed", issues: report.Removed, want: "[DEMO-4:1]"},
+		{name: "completed", issues: report.Completed, want: "[DEMO-1:3 DEMO-3:2]"},
+		{name: "carried over", issues: report.CarriedOver, want: "[DEMO-2:8]"},
+	}
+	for _, g := range groups {
+		if got := sprintIssueKeys(g.issues); got != g.want {
+			t.Errorf("%s = %s, want %s", g.name, got, g.want)
+		}
+	}
+
+	if !report.End.Equal(day(4)) {
+		t.Errorf("end = %v, want the completion date %v", report.End, day(4))
+	}
+
+	days := []struct {
+		at        time.Time
+		scope     int
+		points    float64
+		remaining int
+		ideal     float64
+	}{
+		{at: day(0), scope: 3, points: 9, remaining: 3, ideal: 3},
+		{at: day(4), scope: 3, points: 13, remaining: 1, ideal: 0},
+	}
+
+	first, last := report.Days[0], report.Days[len(report.Days)-1]
+	for i, d := range []jira.SprintDay{first, last} {
+		want := days[i]
+		if !d.Date.Equal(want.at) || d.Scope != want.scope || d.ScopePoints != want.points || d.Remaining != want.remaining || d.Ideal != want.ideal {
+			t.Errorf("day %v = %+v, want scope %d (%g points), remaining %d, ideal %g",
+				want.at, d, want.scope, want.points, want.remaining, want.ideal)
+		}
+	}
+
+	// The start, the end of the 4 days in between and the completion
+	if len(report.Days) != 6 {
+		t.Errorf("got %d days, want 6", len(report.Days))
+	}
+}
+
+func TestBuildSprintReportNotStarted(t *testing.T) {
+	sprint := &jira.Sprint{ID: 1, Name: "Sprint 1", State: jira.SprintStateFuture}
+
+	if _, err := jira.BuildSprintReport(sprint, nil, jira.SprintReportOptions{Now: day(0)}); err == nil {
+		t.Error("building the report of a future sprint succeeded")
+	}
+}
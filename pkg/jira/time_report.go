@@ -0,0 +1,141 @@
+package jira
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// TimeEntry is a worklog together with the issue it was logged on
+type TimeEntry struct {
+	Date      time.Time     `json:"date"`
+	Started   time.Time     `json:"started"`
+	Key       string        `json:"key"`
+	Summary   string        `json:"summary"`
+	Project   string        `json:"project"`
+	Author    string        `json:"author"`
+	TimeSpent time.Duration `json:"timeSpent"`
+	Comment   string        `json:"comment,omitempty"`
+	WorklogID string        `json:"worklogId"`
+}
+
+// TimeTotal is the time logged on a day, an issue or a project
+type TimeTotal struct {
+	Name      string        `json:"name"`
+	Summary   string        `json:"summary,omitempty"`
+	TimeSpent time.Duration `json:"timeSpent"`
+}
+
+// TimeReport aggregates logged time by day, issue and project
+type TimeReport struct {
+	From      time.Time     `json:"from"`
+	To        time.Time     `json:"to"`
+	Entries   []TimeEntry   `json:"entries"`
+	ByDay     []TimeTotal   `json:"byDay"`
+	ByIssue   []TimeTotal   `json:"byIssue"`
+	ByProject []TimeTotal   `json:"byProject"`
+	Total     time.Duration `json:"total"`
+}
+
+// NewTimeEntries returns the worklogs of an issue started within [from, to)
+// and accepted by the author filter, as time entries. Dates are computed in
+// the location of from.
+func NewTimeEntries(issue *Issue, worklogs []Worklog, from time.Time, to time.Time, author func(*User) bool) []TimeEntry {
+	entries := make([]TimeEntry, 0, len(worklogs))
+
+	for _, worklog := range worklogs {
+		started := worklog.Started.In(from.Location())
+		if started.Before(from) || !started.Before(to) {
+			continue
+		}
+		if author != nil && !author(worklog.Author) {
+			continue
+		}
+
+		project := issue.Fields.Project.Key
+		if project == "" {
+			project, _, _ = strings.Cut(issue.Key, "-")
+		}
+
+		name := ""
+		if worklog.Author != nil {
+			name = worklog.Author.DisplayName
+		}
+
+		year, month, day := started.Date()
+		entries = append(entries, TimeEntry{
+			Date:      time.Date(year, month, day, 0, 0, 0, 0, started.Location()),
+			Started:   started,
+			Key:       issue.Key,
+			Summary:   issue.Fields.Summary,
+			Project:   project,
+			Author:    name,
+			TimeSpent: time.Duration(worklog.TimeSpentSeconds) * time.Second,
+			Comment:   worklog.Comment,
+			WorklogID: worklog.ID,
+		})
+	}
+
+	return entries
+}
+
+// BuildTimeReport aggregates time entries by day, issue and project. Days are
+// sorted chronologically, issues and projects by decreasing time spent.
+func BuildTimeReport(entries []TimeEntry, from time.Time, to time.Time) *TimeReport {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Started.Before(entries[j].Started)
+	})
+
+	report := &TimeReport{
+		From:    from,
+		To:      to,
+		Entries: entries,
+	}
+
+	days := newTimeTotals()
+	issues := newTimeTotals()
+	projects := newTimeTotals()
+
+	for _, e := range entries {
+		days.add(e.Date.Format("2006-01-02"), "", e.TimeSpent)
+		issues.add(e.Key, e.Summary, e.TimeSpent)
+		projects.add(e.Project, "", e.TimeSpent)
+		report.Total += e.TimeSpent
+	}
+
+	report.ByDay = days.values
+	report.ByIssue = issues.sorted()
+	report.ByProject = projects.sorted()
+
+	return report
+}
+
+// timeTotals accumulates totals, keeping the order of first appearance
+type timeTotals struct {
+	index  map[string]int
+	values []TimeTotal
+}
+
+func newTimeTotals() *timeTotals {
+	return &timeTotals{
+		index:  make(map[string]int),
+		values: make([]TimeTotal, 0),
+	}
+}
+
+func (t *timeTotals) add(name string, summary string, d time.Duration) {
+	i, ok := t.index[name]
+	if !ok {
+		i = len(t.values)
+		t.index[name] = i
+		t.values = append(t.values, TimeTotal{Name: name, Summary: summary})
+	}
+	t.values[i].TimeSpent += d
+}
+
+func (t *timeTotals) sorted() []TimeTotal {
+	sort.SliceStable(t.values, func(i, j int) bool {
+		return t.values[i].TimeSpent > t.values[j].TimeSpent
+	})
+	return t.values
+}
@@ -0,0 +1,315 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Changelog fields that describe the parent of an issue, as recorded on the child
+var parentChangeFields = map[string]bool{
+	"parent":                 true,
+	"issueparentassociation": true,
+	"epic link":              true,
+}
+
+// epicChildChangeField is recorded on the epic when a child is linked or unlinked
+const epicChildChangeField = "epic child"
+
+// TreeChangeKind describes the type of a change between two tree snapshots
+type TreeChangeKind string
+
+const (
+	TreeChangeAdded      TreeChangeKind = "added"
+	TreeChangeRemoved    TreeChangeKind = "removed"
+	TreeChangeReparented TreeChangeKind = "reparented"
+	TreeChangeStatus     TreeChangeKind = "status"
+)
+
+// TreeNode is the state of a single issue within a tree snapshot
+type TreeNode struct {
+	Key     string    `json:"key"`
+	ID      string    `json:"id,omitempty"`
+	Summary string    `json:"summary"`
+	Type    string    `json:"type"`
+	Status  string    `json:"status"`
+	Parent  string    `json:"parent,omitempty"`
+	Created time.Time `json:"created"`
+}
+
+// TreeSnapshot is a flattened view of an issue tree at a given point in time
+type TreeSnapshot struct {
+	Root  string               `json:"root"`
+	Taken time.Time            `json:"taken"`
+	Nodes map[string]*TreeNode `json:"nodes"`
+}
+
+// TreeChange is a single difference between two tree snapshots
+type TreeChange struct {
+	Kind    TreeChangeKind `json:"kind"`
+	Key     string         `json:"key"`
+	Summary string         `json:"summary"`
+	From    string         `json:"from,omitempty"`
+	To      string         `json:"to,omitempty"`
+}
+
+// TreeDiff holds the changes of an issue tree between two snapshots
+type TreeDiff struct {
+	Root    string       `json:"root"`
+	From    time.Time    `json:"from"`
+	To      time.Time    `json:"to"`
+	Changes []TreeChange `json:"changes"`
+}
+
+// SnapshotFromTree flattens an issue tree as built by BuildIssueTree
+func SnapshotFromTree(root *Issue, taken time.Time) *TreeSnapshot {
+	snapshot := &TreeSnapshot{
+		Root:  root.Key,
+		Taken: taken,
+		Nodes: make(map[string]*TreeNode),
+	}
+
+	var walk func(issue *Issue, parent string)
+	walk = func(issue *Issue, parent string) {
+		if _, ok := snapshot.Nodes[issue.Key]; ok {
+			return
+		}
+
+		snapshot.Nodes[issue.Key] = &TreeNode{
+			Key:     issue.Key,
+			ID:      issue.ID,
+			Summary: issue.Fields.Summary,
+			Type:    issue.Fields.IssueType.Name,
+			Status:  issue.Fields.Status.Name,
+			Parent:  parent,
+			Created: issue.Fields.Created.Time,
+		}
+
+		for _, child := range issue.Children {
+			walk(child, issue.Key)
+		}
+	}
+
+	walk(root, "")
+
+	return snapshot
+}
+
+// LoadTreeSnapshot reads a tree previously exported with `get issue --tree -o json`
+func LoadTreeSnapshot(r io.Reader, taken time.Time) (*TreeSnapshot, error) {
+	var root Issue
+	if err := json.NewDecoder(r).Decode(&root); err != nil {
+		return nil, fmt.Errorf("failed to decode tree snapshot: %w", err)
+	}
+	if root.Key == "" {
+		return nil, fmt.Errorf("tree snapshot has no root issue key")
+	}
+
+	return SnapshotFromTree(&root, taken), nil
+}
+
+// ReconstructTreeSnapshot rewinds the current snapshot to the given point in
+// time by undoing the status and parent changes recorded in the changelogs.
+//
+// Issues removed from an epic are discovered through the "Epic Child" entries
+// of the epic changelog; sub-tasks moved to a different parent are only
+// detected when they are still part of the tree.
+func ReconstructTreeSnapshot(client *Client, current *TreeSnapshot, since time.Time) (*TreeSnapshot, error) {
+	past := &TreeSnapshot{
+		Root:  current.Root,
+		Taken: since,
+		Nodes: make(map[string]*TreeNode, len(current.Nodes)),
+	}
+
+	idToKey := make(map[string]string, len(current.Nodes))
+	queue := make([]string, 0, len(current.Nodes))
+
+	for key, node := range current.Nodes {
+		n := *node
+		past.Nodes[key] = &n
+		if node.ID != "" {
+			idToKey[node.ID] = key
+		}
+		queue = append(queue, key)
+	}
+	sort.Strings(queue)
+
+	resolve := func(id string, value string) string {
+		if IsIssueKey(value) {
+			return value
+		}
+		if key, ok := idToKey[id]; ok {
+			return key
+		}
+		return value
+	}
+
+	for i := 0; i < len(queue); i++ {
+		key := queue[i]
+		node := past.Nodes[key]
+
+		histories, err := client.GetChangelog(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get changelog of %s: %w", key, err)
+		}
+
+		// Undo changes newest first so each field ends up with its value at `since`
+		for h := len(histories) - 1; h >= 0; h-- {
+			history := histories[h]
+			if !history.Created.After(since) {
+				break
+			}
+
+			for _, item := range history.Items {
+				field := strings.ToLower(item.Field)
+
+				switch {
+				case field == "status":
+					node.Status = item.FromString
+				case parentChangeFields[field] && key != past.Root:
+					node.Parent = resolve(item.From, item.FromString)
+				case field == epicChildChangeField && item.FromString != "" && item.ToString == "":
+					removed := resolve(item.From, item.FromString)
+					if _, ok := past.Nodes[removed]; ok {
+						continue
+					}
+
+					issue, err := client.GetIssue(removed)
+					if err != nil {
+						return nil, fmt.Errorf("failed to get removed child %s: %w", removed, err)
+					}
+
+					past.Nodes[removed] = &TreeNode{
+						Key:     issue.Key,
+						ID:      issue.ID,
+						Summary: issue.Fields.Summary,
+						Type:    issue.Fields.IssueType.Name,
+						Status:  issue.Fields.Status.Name,
+						Parent:  key,
+						Created: issue.Fields.Created.Time,
+					}
+					idToKey[issue.ID] = issue.Key
+					queue = append(queue, removed)
+				}
+			}
+		}
+	}
+
+	// Issues created after `since` did not exist at that time
+	for key, node := range past.Nodes {
+		if key != past.Root && node.Created.After(since) {
+			delete(past.Nodes, key)
+		}
+	}
+
+	pruneUnreachable(past)
+
+	return past, nil
+}
+
+// pruneUnreachable removes the nodes whose parent chain does not lead to the root
+func pruneUnreachable(snapshot *TreeSnapshot) {
+	reachable := make(map[string]bool, len(snapshot.Nodes))
+	reachable[snapshot.Root] = true
+
+	var check func(key string, seen map[string]bool) bool
+	check = func(key string, seen map[string]bool) bool {
+		if r, ok := reachable[key]; ok {
+			return r
+		}
+		node, ok := snapshot.Nodes[key]
+		if !ok || node.Parent == "" || seen[key] {
+			return false
+		}
+		seen[key] = true
+
+		r := check(node.Parent, seen)
+		reachable[key] = r
+		return r
+	}
+
+	for key := range snapshot.Nodes {
+		if !check(key, make(map[string]bool)) {
+			delete(snapshot.Nodes, key)
+		}
+	}
+}
+
+// DiffTreeSnapshots computes the changes needed to go from before to after
+func DiffTreeSnapshots(before *TreeSnapshot, after *TreeSnapshot) *TreeDiff {
+	diff := &TreeDiff{
+		Root:    after.Root,
+		From:    before.Taken,
+		To:      after.Taken,
+		Changes: make([]TreeChange, 0),
+	}
+
+	for key, node := range after.Nodes {
+		old, ok := before.Nodes[key]
+		if !ok {
+			diff.Changes = append(diff.Changes, TreeChange{
+				Kind:    TreeChangeAdded,
+				Key:     key,
+				Summary: node.Summary,
+				To:      node.Parent,
+			})
+			continue
+		}
+
+		if old.Parent != node.Parent {
+			diff.Changes = append(diff.Changes, TreeChange{
+				Kind:    TreeChangeReparented,
+				Key:     key,
+				Summary: node.Summary,
+				From:    old.Parent,
+				To:      node.Parent,
+			})
+		}
+
+		if old.Status != node.Status {
+			diff.Changes = append(diff.Changes, TreeChange{
+				Kind:    TreeChangeStatus,
+				Key:     key,
+				Summary: node.Summary,
+				From:    old.Status,
+				To:      node.Status,
+			})
+		}
+	}
+
+	for key, node := range before.Nodes {
+		if _, ok := after.Nodes[key]; !ok {
+			diff.Changes = append(diff.Changes, TreeChange{
+				Kind:    TreeChangeRemoved,
+				Key:     key,
+				Summary: node.Summary,
+				From:    node.Parent,
+			})
+		}
+	}
+
+	sort.Slice(diff.Changes, func(i, j int) bool {
+		if diff.Changes[i].Kind != diff.Changes[j].Kind {
+			return treeChangeOrder(diff.Changes[i].Kind) < treeChangeOrder(diff.Changes[j].Kind)
+		}
+		return diff.Changes[i].Key < diff.Changes[j].Key
+	})
+
+	return diff
+}
+
+func treeChangeOrder(kind TreeChangeKind) int {
+	switch kind {
+	case TreeChangeAdded:
+		return 0
+	case TreeChangeRemoved:
+		return 1
+	case TreeChangeReparented:
+		return 2
+	default:
+		return 3
+	}
+}
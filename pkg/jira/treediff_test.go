@@ -0,0 +1,153 @@
+package jira_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lburgazzoli/gira/pkg/jira"
+)
+
+func TestReconstructTreeSnapshot(t *testing.T) {
+	since := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) jira.JIRATime {
+		return jira.JIRATime{Time: since.Add(d)}
+	}
+
+	srv, client := newTestClient(t, nil)
+
+	seed := []struct {
+		key     string
+		status  string
+		created time.Duration
+	}{
+		{key: "DEMO-1", status: "In Progress", created: -72 * time.Hour},
+		{key: "DEMO-2", status: "Done", created: -48 * time.Hour},
+		{key: "DEMO-3", status: "To Do", created: 2 * time.Hour},
+		{key: "DEMO-4", status: "To Do", created: -48 * time.Hour},
+		{key: "DEMO-5", status: "In Progress", created: -48 * time.Hour},
+		{key: "DEMO-6", status: "To Do", created: -48 * time.Hour},
+	}
+
+	issues := make(map[string]*jira.Issue)
+	for _, s := range seed {
+		issue := jira.Issue{Key: s.key}
+		issue.Fields.Project.Key = "DEMO"
+		issue.Fields.Summary = "Summary of " + s.key
+		issue.Fields.IssueType.Name = "Story"
+		issue.Fields.Status.Name = s.status
+		issue.Fields.Created = at(s.created)
+		issues[s.key] = srv.AddIssue(issue)
+	}
+
+	changes := []struct {
+		key  string
+		when time.Duration
+		item jira.ChangeItem
+	}{
+		// Before `since`, so kept
+		{key: "DEMO-2", when: -time.Hour, item: jira.ChangeItem{Field: "status", FromString: "To Do", ToString: "In Progress"}},
+		// Undone: DEMO-2 was still in progress
+		{key: "DEMO-2", when: time.Hour, item: jira.ChangeItem{Field: "status", FromString: "In Progress", ToString: "Done"}},
+		// Undone: DEMO-4 belonged to another epic, outside of the tree
+		{key: "DEMO-4", when: time.Hour, item: jira.ChangeItem{Field: "Epic Link", FromString: "OTHER-1", ToString: "DEMO-1"}},
+		// Undone: DEMO-5 was a child of DEMO-2, identified by ID only
+		{key: "DEMO-5", when: time.Hour, item: jira.ChangeItem{Field: "Parent", From: issues["DEMO-2"].ID, FromString: "Summary of DEMO-2", To: issues["DEMO-1"].ID}},
+		// Undone: DEMO-6 was removed from the epic, so it is restored
+		{key: "DEMO-1", when: 3 * time.Hour, item: jira.ChangeItem{Field: "Epic Child", FromString: "DEMO-6"}},
+	}
+
+	for _, c := range changes {
+		if err := srv.AddChange(c.key, jira.ChangeHistory{Created: at(c.when), Items: []jira.ChangeItem{c.item}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// DEMO-1 with DEMO-2, DEMO-3, DEMO-4 and DEMO-5 as direct children
+	root := *issues["DEMO-1"]
+	for _, key := range []string{"DEMO-2", "DEMO-3", "DEMO-4", "DEMO-5"} {
+		root.Children = append(root.Children, issues[key])
+	}
+	current := jira.SnapshotFromTree(&root, since.Add(24*time.Hour))
+
+	past, err := jira.ReconstructTreeSnapshot(client, current, since)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"DEMO-1": "",
+		"DEMO-2": "DEMO-1",
+		"DEMO-5": "DEMO-2",
+		"DEMO-6": "DEMO-1",
+	}
+	got := make(map[string]string)
+	for key, node := range past.Nodes {
+		got[key] = node.Parent
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("past parents = %v, want %v", got, want)
+	}
+
+	if status := past.Nodes["DEMO-2"].Status; status != "In Progress" {
+		t.Errorf("DEMO-2 status = %q, want In Progress", status)
+	}
+	if !past.Taken.Equal(since) {
+		t.Errorf("taken = %s, want %s", past.Taken, since)
+	}
+
+	// The current snapshot is left untouched
+	if current.Nodes["DEMO-2"].Status != "Done" || current.Nodes["DEMO-5"].Parent != "DEMO-1" {
+		t.Error("the current snapshot was modified")
+	}
+
+	diff := jira.DiffTreeSnapshots(past, current)
+
+	wantChanges := []string{
+		"added DEMO-3 ->DEMO-1",
+		"added DEMO-4 ->DEMO-1",
+		"removed DEMO-6 DEMO-1->",
+		"reparented DEMO-5 DEMO-2->DEMO-1",
+		"status DEMO-2 In Progress->Done",
+	}
+	gotChanges := make([]string, 0, len(diff.Changes))
+	for _, c := range diff.Changes {
+		gotChanges = append(gotChanges, fmt.Sprintf("%s %s %s->%s", c.Kind, c.Key, c.From, c.To))
+	}
+	if !reflect.DeepEqual(gotChanges, wantChanges) {
+		t.Errorf("changes = %q, want %q", gotChanges, wantChanges)
+	}
+}
+
+func TestDiffTreeSnapshotsOrder(t *testing.T) {
+	node := func(key string, parent string, status string) *jira.TreeNode {
+		return &jira.TreeNode{Key: key, Parent: parent, Status: status}
+	}
+
+	before := &jira.TreeSnapshot{Root: "DEMO-1", Nodes: map[string]*jira.TreeNode{
+		"DEMO-1": node("DEMO-1", "", "To Do"),
+		"DEMO-3": node("DEMO-3", "DEMO-1", "To Do"),
+		"DEMO-9": node("DEMO-9", "DEMO-1", "To Do"),
+	}}
+	after := &jira.TreeSnapshot{Root: "DEMO-1", Nodes: map[string]*jira.TreeNode{
+		"DEMO-1": node("DEMO-1", "", "Done"),
+		"DEMO-3": node("DEMO-3", "DEMO-2", "Done"),
+		"DEMO-2": node("DEMO-2", "DEMO-1", "To Do"),
+	}}
+
+	diff := jira.DiffTreeSnapshots(before, after)
+
+	got := make([]string, 0, len(diff.Changes))
+	for _, c := range diff.Changes {
+		got = append(got, string(c.Kind)+" "+c.Key)
+	}
+	want := []string{"added DEMO-2", "removed DEMO-9", "reparented DEMO-3", "status DEMO-1", "status DEMO-3"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("changes = %v, want %v", got, want)
+	}
+
+	if empty := jira.DiffTreeSnapshots(after, after); len(empty.Changes) != 0 {
+		t.Errorf("diffing a snapshot with itself gave %+v", empty.Changes)
+	}
+}
@@ -1,7 +1,9 @@
 package jira
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -21,9 +23,19 @@ func (jt *JIRATime) UnmarshalJSON(data []byte) error {
 	// JIRA format: "2025-05-12T06:54:41.542+0000"
 	const jiraLayout = "2006-01-02T15:04:05.000-0700"
 
+	if s == "" || s == "null" {
+		jt.Time = time.Time{}
+		return nil
+	}
+
 	t, err := time.Parse(jiraLayout, s)
 	if err != nil {
-		return fmt.Errorf("failed to parse JIRA timestamp %q: %w", s, err)
+		// Fall back to RFC3339, which is what JIRATime marshals to, so that
+		// previously exported JSON documents can be read back
+		var rfcErr error
+		if t, rfcErr = time.Parse(time.RFC3339Nano, s); rfcErr != nil {
+			return fmt.Errorf("failed to parse JIRA timestamp %q: %w", s, err)
+		}
 	}
 
 	jt.Time = t
@@ -46,26 +58,35 @@ type Issue struct {
 	Self        string       `json:"self"`
 	Fields      IssueFields  `json:"fields"`
 	Transitions []Transition `json:"transitions,omitempty"`
+	// RemoteLinks are fetched separately from the issue, see ListRemoteLinks
+	RemoteLinks []RemoteLink `json:"remoteLinks,omitempty"`
 
 	// Tree hierarchy fields (populated during tree traversal)
 	Parent   *Issue   `json:"parent,omitempty"`
 	Children []*Issue `json:"children,omitempty"`
+
+	// Collapsed is the number of descendants hidden by tree collapsing
+	Collapsed int `json:"collapsed,omitempty"`
 }
 
 type IssueFields struct {
-	Summary     string      `json:"summary"`
-	Description string      `json:"description"`
-	IssueType   IssueType   `json:"issuetype"`
-	Status      Status      `json:"status"`
-	Priority    Priority    `json:"priority"`
-	Assignee    *User       `json:"assignee"`
-	Reporter    *User       `json:"reporter"`
-	Project     Project     `json:"project"`
-	Parent      *Issue      `json:"parent,omitempty"`
-	Subtasks    []Issue     `json:"subtasks,omitempty"`
-	IssueLinks  []IssueLink `json:"issuelinks,omitempty"`
-	Created     JIRATime    `json:"created"`
-	Updated     JIRATime    `json:"updated"`
+	Summary     string       `json:"summary"`
+	Description string       `json:"description"`
+	IssueType   IssueType    `json:"issuetype"`
+	Status      Status       `json:"status"`
+	Priority    Priority     `json:"priority"`
+	Assignee    *User        `json:"assignee"`
+	Reporter    *User        `json:"reporter"`
+	Project     Project      `json:"project"`
+	Parent      *Issue       `json:"parent,omitempty"`
+	Subtasks    []Issue      `json:"subtasks,omitempty"`
+	IssueLinks  []IssueLink  `json:"issuelinks,omitempty"`
+	Attachments []Attachment `json:"attachment,omitempty"`
+	Labels      []string     `json:"labels,omitempty"`
+	Components  []Component  `json:"components,omitempty"`
+	FixVersions []Version    `json:"fixVersions,omitempty"`
+	Created     JIRATime     `json:"created"`
+	Updated     JIRATime     `json:"updated"`
 }
 
 type IssueType struct {
@@ -80,15 +101,35 @@ func (in IssueType) String() string {
 }
 
 type Status struct {
-	ID          string `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
+	ID             string         `json:"id"`
+	Name           string         `json:"name"`
+	Description    string         `json:"description"`
+	StatusCategory StatusCategory `json:"statusCategory"`
 }
 
 func (in Status) String() string {
 	return in.Name
 }
 
+// Status category keys as returned by JIRA
+const (
+	StatusCategoryNew        = "new"
+	StatusCategoryInProgress = "indeterminate"
+	StatusCategoryDone       = "done"
+	StatusCategoryUndefined  = "undefined"
+)
+
+type StatusCategory struct {
+	ID        int    `json:"id"`
+	Key       string `json:"key"`
+	Name      string `json:"name"`
+	ColorName string `json:"colorName"`
+}
+
+func (in StatusCategory) String() string {
+	return in.Name
+}
+
 type Priority struct {
 	ID      string `json:"id"`
 	Name    string `json:"name"`
@@ -99,16 +140,142 @@ func (in Priority) String() string {
 	return in.Name
 }
 
+type Resolution struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// User is a JIRA user. Cloud identifies users by AccountID, Data Center by
+// Name (the username) and Key.
 type User struct {
 	AccountID    string `json:"accountId"`
 	DisplayName  string `json:"displayName"`
 	EmailAddress string `json:"emailAddress"`
+	Name         string `json:"name,omitempty"`
+	Key          string `json:"key,omitempty"`
+	Active       bool   `json:"active,omitempty"`
+	TimeZone     string `json:"timeZone,omitempty"`
+}
+
+// ID returns the identifier of the user expected by the REST API, the
+// account ID on Cloud and the username on Data Center
+func (u *User) ID() string {
+	if u.AccountID != "" {
+		return u.AccountID
+	}
+	return u.Name
+}
+
+// Is reports whether both users are the same user
+func (u *User) Is(other *User) bool {
+	if u == nil || other == nil {
+		return false
+	}
+	if u.AccountID != "" || other.AccountID != "" {
+		return u.AccountID == other.AccountID
+	}
+	if u.Key != "" && other.Key != "" {
+		return u.Key == other.Key
+	}
+	return u.Name != "" && strings.EqualFold(u.Name, other.Name)
 }
 
 type Project struct {
 	ID   string `json:"id"`
 	Key  string `json:"key"`
 	Name string `json:"name"`
+
+	// The fields below are only returned by the project endpoints
+	Description     string           `json:"description,omitempty"`
+	Lead            *User            `json:"lead,omitempty"`
+	ProjectTypeKey  string           `json:"projectTypeKey,omitempty"`
+	URL             string           `json:"url,omitempty"`
+	ProjectCategory *ProjectCategory `json:"projectCategory,omitempty"`
+	Components      []Component      `json:"components,omitempty"`
+	Versions        []Version        `json:"versions,omitempty"`
+	IssueTypes      []IssueType      `json:"issueTypes,omitempty"`
+	// Roles maps the names of the project roles to their REST URL
+	Roles map[string]string `json:"roles,omitempty"`
+}
+
+// ProjectCategory groups projects
+type ProjectCategory struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// Component is a project component
+type Component struct {
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Lead        *User  `json:"lead,omitempty"`
+}
+
+// Version is a project version, used as fix and affects version of issues
+type Version struct {
+	ID          string `json:"id,omitempty"`
+	Self        string `json:"self,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Archived    bool   `json:"archived"`
+	Released    bool   `json:"released"`
+	// StartDate and ReleaseDate are dates formatted as YYYY-MM-DD
+	StartDate   string `json:"startDate,omitempty"`
+	ReleaseDate string `json:"releaseDate,omitempty"`
+	Overdue     bool   `json:"overdue,omitempty"`
+	ProjectID   int    `json:"projectId,omitempty"`
+}
+
+// VersionUpdate changes the fields of a version that are set
+type VersionUpdate struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+	StartDate   *string `json:"startDate,omitempty"`
+	ReleaseDate *string `json:"releaseDate,omitempty"`
+	Released    *bool   `json:"released,omitempty"`
+	Archived    *bool   `json:"archived,omitempty"`
+	// MoveUnfixedIssuesTo is the URL of the version receiving the unresolved
+	// issues of the version when it is released
+	MoveUnfixedIssuesTo string `json:"moveUnfixedIssuesTo,omitempty"`
+}
+
+// Board is an Agile (Scrum or Kanban) board
+type Board struct {
+	ID       int            `json:"id"`
+	Name     string         `json:"name"`
+	Type     string         `json:"type"`
+	Location *BoardLocation `json:"location,omitempty"`
+}
+
+// BoardLocation is the project (or user) a board belongs to
+type BoardLocation struct {
+	ProjectID   int    `json:"projectId,omitempty"`
+	ProjectKey  string `json:"projectKey,omitempty"`
+	ProjectName string `json:"projectName,omitempty"`
+	DisplayName string `json:"displayName,omitempty"`
+}
+
+// Sprint is a sprint of a Scrum board
+type Sprint struct {
+	ID            int       `json:"id"`
+	Name          string    `json:"name"`
+	State         string    `json:"state"`
+	Goal          string    `json:"goal,omitempty"`
+	OriginBoardID int       `json:"originBoardId,omitempty"`
+	StartDate     *JIRATime `json:"startDate,omitempty"`
+	EndDate       *JIRATime `json:"endDate,omitempty"`
+	CompleteDate  *JIRATime `json:"completeDate,omitempty"`
+}
+
+// agilePage is a page of values returned by the Agile REST API
+type agilePage[T any] struct {
+	MaxResults int  `json:"maxResults"`
+	StartAt    int  `json:"startAt"`
+	Total      int  `json:"total"`
+	IsLast     bool `json:"isLast"`
+	Values     []T  `json:"values"`
 }
 
 type IssueLink struct {
@@ -118,6 +285,23 @@ type IssueLink struct {
 	OutwardIssue *Issue   `json:"outwardIssue,omitempty"`
 }
 
+// Relation returns how the issue holding the link relates to the linked
+// issue, e.g. "blocks" or "is blocked by"
+func (in IssueLink) Relation() string {
+	if in.OutwardIssue != nil {
+		return in.Type.Outward
+	}
+	return in.Type.Inward
+}
+
+// LinkedIssue returns the issue at the other end of the link
+func (in IssueLink) LinkedIssue() *Issue {
+	if in.OutwardIssue != nil {
+		return in.OutwardIssue
+	}
+	return in.InwardIssue
+}
+
 type LinkType struct {
 	ID      string `json:"id"`
 	Name    string `json:"name"`
@@ -125,6 +309,42 @@ type LinkType struct {
 	Outward string `json:"outward"`
 }
 
+// RemoteLink links an issue to an object outside JIRA, such as a pull
+// request, a design document or a CI run
+type RemoteLink struct {
+	ID   int    `json:"id,omitempty"`
+	Self string `json:"self,omitempty"`
+	// GlobalID identifies the remote object; creating a link with the
+	// GlobalID of an existing one updates it
+	GlobalID     string                 `json:"globalId,omitempty"`
+	Application  *RemoteLinkApplication `json:"application,omitempty"`
+	Relationship string                 `json:"relationship,omitempty"`
+	Object       RemoteLinkObject       `json:"object"`
+}
+
+type RemoteLinkApplication struct {
+	Type string `json:"type,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+type RemoteLinkObject struct {
+	URL     string            `json:"url"`
+	Title   string            `json:"title"`
+	Summary string            `json:"summary,omitempty"`
+	Icon    *RemoteLinkIcon   `json:"icon,omitempty"`
+	Status  *RemoteLinkStatus `json:"status,omitempty"`
+}
+
+type RemoteLinkIcon struct {
+	URL   string `json:"url16x16,omitempty"`
+	Title string `json:"title,omitempty"`
+}
+
+type RemoteLinkStatus struct {
+	Resolved bool            `json:"resolved"`
+	Icon     *RemoteLinkIcon `json:"icon,omitempty"`
+}
+
 type Transition struct {
 	ID   string `json:"id"`
 	Name string `json:"name"`
@@ -138,7 +358,131 @@ type SearchResult struct {
 	Total      int     `json:"total"`
 }
 
+// RawSearchResult is a SearchResult whose issues are left undecoded
+type RawSearchResult struct {
+	Issues     []json.RawMessage `json:"issues"`
+	StartAt    int               `json:"startAt"`
+	MaxResults int               `json:"maxResults"`
+	Total      int               `json:"total"`
+}
+
+// EntityProperty is an entity property set along with a new issue
+type EntityProperty struct {
+	Key   string      `json:"key"`
+	Value interface{} `json:"value"`
+}
+
 type IssueUpdate struct {
 	Fields map[string]interface{} `json:"fields,omitempty"`
 	Update map[string]interface{} `json:"update,omitempty"`
 }
+
+// Worklog is time logged on an issue
+type Worklog struct {
+	ID               string   `json:"id"`
+	IssueID          string   `json:"issueId,omitempty"`
+	Author           *User    `json:"author,omitempty"`
+	Comment          string   `json:"comment,omitempty"`
+	Started          JIRATime `json:"started"`
+	TimeSpent        string   `json:"timeSpent,omitempty"`
+	TimeSpentSeconds int      `json:"timeSpentSeconds"`
+	Created          JIRATime `json:"created"`
+	Updated          JIRATime `json:"updated"`
+}
+
+// Attachment is a file attached to an issue
+type Attachment struct {
+	ID       string   `json:"id"`
+	Filename string   `json:"filename"`
+	Author   *User    `json:"author,omitempty"`
+	Created  JIRATime `json:"created"`
+	Size     int64    `json:"size"`
+	MimeType string   `json:"mimeType"`
+	// Content is the URL of the attachment content
+	Content string `json:"content"`
+}
+
+// AttachmentMeta describes the attachment settings of the instance
+type AttachmentMeta struct {
+	Enabled bool `json:"enabled"`
+	// UploadLimit is the maximum size of an attachment in bytes
+	UploadLimit int64 `json:"uploadLimit"`
+}
+
+// Watchers are the users watching an issue
+type Watchers struct {
+	IsWatching bool   `json:"isWatching"`
+	WatchCount int    `json:"watchCount"`
+	Watchers   []User `json:"watchers"`
+}
+
+// WorklogPage is a page of the worklogs of an issue
+type WorklogPage struct {
+	Worklogs   []Worklog `json:"worklogs"`
+	StartAt    int       `json:"startAt"`
+	MaxResults int       `json:"maxResults"`
+	Total      int       `json:"total"`
+}
+
+// ChangeHistory is a single entry of an issue changelog
+type ChangeHistory struct {
+	ID      string       `json:"id"`
+	Author  *User        `json:"author,omitempty"`
+	Created JIRATime     `json:"created"`
+	Items   []ChangeItem `json:"items"`
+}
+
+// ChangeItem describes the change of a single field within a ChangeHistory
+type ChangeItem struct {
+	Field      string `json:"field"`
+	FieldType  string `json:"fieldtype"`
+	FieldID    string `json:"fieldId,omitempty"`
+	From       string `json:"from"`
+	FromString string `json:"fromString"`
+	To         string `json:"to"`
+	ToString   string `json:"toString"`
+}
+
+// ChangelogPage is a page of results of the issue changelog endpoint
+type ChangelogPage struct {
+	Values     []ChangeHistory `json:"values"`
+	StartAt    int             `json:"startAt"`
+	MaxResults int             `json:"maxResults"`
+	Total      int             `json:"total"`
+	IsLast     bool            `json:"isLast"`
+}
+
+// Comment is a comment of an issue
+type Comment struct {
+	ID      string   `json:"id"`
+	Body    string   `json:"body"`
+	Author  *User    `json:"author,omitempty"`
+	Created JIRATime `json:"created"`
+	Updated JIRATime `json:"updated"`
+}
+
+// CommentPage is a page of results of the issue comment endpoint
+type CommentPage struct {
+	Comments   []Comment `json:"comments"`
+	StartAt    int       `json:"startAt"`
+	MaxResults int       `json:"maxResults"`
+	Total      int       `json:"total"`
+}
+
+// Field describes a system or custom issue field
+type Field struct {
+	ID     string      `json:"id"`
+	Key    string      `json:"key,omitempty"`
+	Name   string      `json:"name"`
+	Custom bool        `json:"custom"`
+	Schema FieldSchema `json:"schema"`
+}
+
+// FieldSchema describes the type of a field
+type FieldSchema struct {
+	Type     string `json:"type"`
+	Items    string `json:"items,omitempty"`
+	System   string `json:"system,omitempty"`
+	Custom   string `json:"custom,omitempty"`
+	CustomID int    `json:"customId,omitempty"`
+}
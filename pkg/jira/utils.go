@@ -2,9 +2,17 @@ package jira
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 )
 
+var issueKeyPattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]*-[0-9]+$`)
+
+// IsIssueKey reports whether a string is an issue key such as PROJ-123
+func IsIssueKey(s string) bool {
+	return issueKeyPattern.MatchString(s)
+}
+
 var (
 	childrenSearchFields = []string{
 		"summary",
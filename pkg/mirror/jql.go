@@ -0,0 +1,538 @@
+package mirror
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/lburgazzoli/gira/pkg/jira"
+	timeutils "github.com/lburgazzoli/gira/pkg/utils/time"
+)
+
+// Query is a JQL query translated to SQL over the issues table
+type Query struct {
+	Where   string
+	OrderBy string
+	Args    []any
+}
+
+// column describes how a JQL field maps onto the issues table
+type column struct {
+	// names are the columns compared with the value, any of them may match
+	names []string
+	// time marks timestamp columns, which support range operators
+	time bool
+	// text marks fields searched with ~ and !~
+	text bool
+	// user marks user fields, which resolve currentUser()
+	user bool
+}
+
+var columns = map[string]column{
+	"project":        {names: []string{"project"}},
+	"key":            {names: []string{"key"}},
+	"issuekey":       {names: []string{"key"}},
+	"parent":         {names: []string{"parent"}},
+	"epic link":      {names: []string{"epic_link"}},
+	"status":         {names: []string{"status"}},
+	"statuscategory": {names: []string{"status_category"}},
+	"issuetype":      {names: []string{"issuetype"}},
+	"priority":       {names: []string{"priority"}},
+	"resolution":     {names: []string{"resolution"}},
+	"assignee":       {names: []string{"assignee", "assignee_username", "assignee_name", "assignee_email"}, user: true},
+	"reporter":       {names: []string{"reporter", "reporter_username", "reporter_name", "reporter_email"}, user: true},
+	"summary":        {names: []string{"summary"}, text: true},
+	"description":    {names: []string{"description"}, text: true},
+	"text":           {names: []string{"summary", "description"}, text: true},
+	"created":        {names: []string{"created"}, time: true},
+	"updated":        {names: []string{"updated"}, time: true},
+	"labels":         {names: []string{"labels"}},
+}
+
+// statusCategories maps the status category names accepted by JQL to keys
+var statusCategories = map[string]string{
+	"to do":       jira.StatusCategoryNew,
+	"in progress": jira.StatusCategoryInProgress,
+	"done":        jira.StatusCategoryDone,
+}
+
+// TranslateJQL translates the commonly used subset of JQL into SQL: the
+// fields in columns with =, !=, IN, NOT IN, IS [NOT] EMPTY, ~ and !~ on
+// text fields, <, <=, > and >= on created and updated, combined with AND,
+// OR, NOT and parentheses, followed by an optional ORDER BY.
+//
+// currentUser() resolves to the given user; relative times such as -2w are
+// resolved against now.
+func TranslateJQL(jql string, currentUser *jira.User, now time.Time) (*Query, error) {
+	tokens, err := tokenizeJQL(jql)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &translator{
+		tokens:      tokens,
+		currentUser: currentUser,
+		now:         now,
+	}
+
+	q := &Query{
+		Where:   "1 = 1",
+		OrderBy: "project, number",
+	}
+
+	if !t.done() && !t.isKeyword("ORDER") {
+		q.Where, err = t.parseOr()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if t.isKeyword("ORDER") {
+		q.OrderBy, err = t.parseOrderBy()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !t.done() {
+		return nil, fmt.Errorf("unexpected token %q", t.peek())
+	}
+
+	q.Args = t.args
+
+	return q, nil
+}
+
+type translator struct {
+	tokens      []string
+	pos         int
+	args        []any
+	currentUser *jira.User
+	now         time.Time
+}
+
+func (t *translator) done() bool {
+	return t.pos >= len(t.tokens)
+}
+
+func (t *translator) peek() string {
+	if t.done() {
+		return ""
+	}
+	return t.tokens[t.pos]
+}
+
+func (t *translator) next() string {
+	tok := t.peek()
+	t.pos++
+	return tok
+}
+
+func (t *translator) isKeyword(keyword string) bool {
+	return strings.EqualFold(t.peek(), keyword)
+}
+
+func (t *translator) parseOr() (string, error) {
+	left, err := t.parseAnd()
+	if err != nil {
+		return "", err
+	}
+
+	for t.isKeyword("OR") {
+		t.next()
+		right, err := t.parseAnd()
+		if err != nil {
+			return "", err
+		}
+		left = fmt.Sprintf("(%s OR %s)", left, right)
+	}
+
+	return left, nil
+}
+
+func (t *translator) parseAnd() (string, error) {
+	left, err := t.parseNot()
+	if err != nil {
+		return "", err
+	}
+
+	for t.isKeyword("AND") {
+		t.next()
+		right, err := t.parseNot()
+		if err != nil {
+			return "", err
+		}
+		left = fmt.Sprintf("(%s AND %s)", left, right)
+	}
+
+	return left, nil
+}
+
+func (t *translator) parseNot() (string, error) {
+	if t.isKeyword("NOT") {
+		t.next()
+		inner, err := t.parseNot()
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("NOT %s", inner), nil
+	}
+
+	if t.peek() == "(" {
+		t.next()
+		inner, err := t.parseOr()
+		if err != nil {
+			return "", err
+		}
+		if t.next() != ")" {
+			return "", fmt.Errorf("missing ')'")
+		}
+		return inner, nil
+	}
+
+	return t.parseClause()
+}
+
+func (t *translator) parseClause() (string, error) {
+	raw := t.next()
+	if raw == "" {
+		return "", fmt.Errorf("unexpected end of query")
+	}
+
+	field := normalizeField(unquoteJQL(raw))
+	col, ok := columns[field]
+	if !ok {
+		return "", fmt.Errorf("field %q is not supported offline", unquoteJQL(raw))
+	}
+
+	op := strings.ToUpper(t.next())
+	switch op {
+	case "=", "!=":
+		value := unquoteJQL(t.next())
+		if value == "" {
+			return "", fmt.Errorf("missing value for field %q", field)
+		}
+		if col.time {
+			return t.timeCondition(field, col, op, value)
+		}
+		return t.equalCondition(field, col, []string{value}, op == "!=")
+	case "IN":
+		values, err := t.parseList()
+		if err != nil {
+			return "", err
+		}
+		return t.equalCondition(field, col, values, false)
+	case "NOT":
+		if !t.isKeyword("IN") {
+			return "", fmt.Errorf("expected IN after NOT")
+		}
+		t.next()
+		values, err := t.parseList()
+		if err != nil {
+			return "", err
+		}
+		return t.equalCondition(field, col, values, true)
+	case "~", "!~":
+		if !col.text {
+			return "", fmt.Errorf("operator %q is not supported for field %q", op, field)
+		}
+		value := unquoteJQL(t.next())
+		return t.textCondition(col, value, op == "!~"), nil
+	case "<", "<=", ">", ">=":
+		if !col.time {
+			return "", fmt.Errorf("operator %q is not supported for field %q", op, field)
+		}
+		return t.timeCondition(field, col, op, unquoteJQL(t.next()))
+	case "IS":
+		negate := false
+		if t.isKeyword("NOT") {
+			t.next()
+			negate = true
+		}
+		if v := strings.ToUpper(t.next()); v != "EMPTY" && v != "NULL" {
+			return "", fmt.Errorf("expected EMPTY after IS")
+		}
+		return emptyCondition(field, col, negate), nil
+	default:
+		return "", fmt.Errorf("unsupported operator %q", op)
+	}
+}
+
+func (t *translator) parseList() ([]string, error) {
+	if t.next() != "(" {
+		return nil, fmt.Errorf("expected '('")
+	}
+
+	values := make([]string, 0)
+	for {
+		tok := t.next()
+		switch tok {
+		case "":
+			return nil, fmt.Errorf("missing ')'")
+		case ",":
+			continue
+		case ")":
+			return values, nil
+		default:
+			values = append(values, unquoteJQL(tok))
+		}
+	}
+}
+
+func (t *translator) parseOrderBy() (string, error) {
+	t.next()
+	if !t.isKeyword("BY") {
+		return "", fmt.Errorf("expected BY after ORDER")
+	}
+	t.next()
+
+	clauses := make([]string, 0)
+	for {
+		raw := t.next()
+		if raw == "" {
+			return "", fmt.Errorf("missing ORDER BY field")
+		}
+
+		field := normalizeField(unquoteJQL(raw))
+		direction := "ASC"
+		if t.isKeyword("ASC") {
+			t.next()
+		} else if t.isKeyword("DESC") {
+			t.next()
+			direction = "DESC"
+		}
+
+		switch field {
+		case "key", "issuekey":
+			clauses = append(clauses, "project "+direction, "number "+direction)
+		case "assignee", "reporter":
+			clauses = append(clauses, field+"_name "+direction)
+		default:
+			col, ok := columns[field]
+			if !ok || field == "labels" || field == "text" {
+				return "", fmt.Errorf("cannot order by field %q offline", unquoteJQL(raw))
+			}
+			clauses = append(clauses, col.names[0]+" "+direction)
+		}
+
+		if t.peek() != "," {
+			break
+		}
+		t.next()
+	}
+
+	return strings.Join(clauses, ", "), nil
+}
+
+// equalCondition matches any of the values, case-insensitively
+func (t *translator) equalCondition(field string, col column, values []string, negate bool) (string, error) {
+	conditions := make([]string, 0, len(values))
+
+	for _, value := range values {
+		switch {
+		case strings.EqualFold(value, "EMPTY") || strings.EqualFold(value, "NULL"),
+			field == "resolution" && strings.EqualFold(value, "Unresolved"):
+			conditions = append(conditions, emptyCondition(field, col, false))
+			continue
+		case col.user && strings.EqualFold(value, "currentUser()"):
+			if t.currentUser == nil {
+				return "", fmt.Errorf("currentUser() is unknown, run gira sync first")
+			}
+			// The account ID on Cloud, the username on Data Center
+			value = t.currentUser.ID()
+		case field == "statuscategory":
+			if key, ok := statusCategories[strings.ToLower(value)]; ok {
+				value = key
+			}
+		case col.time:
+			return "", fmt.Errorf("operator IN is not supported for field %q", field)
+		}
+
+		if field == "labels" {
+			t.args = append(t.args, value)
+			conditions = append(conditions,
+				"EXISTS (SELECT 1 FROM json_each(issues.data, '$.fields.labels') WHERE lower(value) = lower(?))")
+			continue
+		}
+
+		matches := make([]string, 0, len(col.names))
+		for _, name := range col.names {
+			t.args = append(t.args, value)
+			matches = append(matches, fmt.Sprintf("lower(%s) = lower(?)", name))
+		}
+		conditions = append(conditions, "("+strings.Join(matches, " OR ")+")")
+	}
+
+	condition := "(" + strings.Join(conditions, " OR ") + ")"
+	if !negate {
+		return condition, nil
+	}
+
+	// Like JIRA, negated conditions do not match issues where the field is empty
+	return fmt.Sprintf("(NOT %s AND NOT %s)", condition, emptyCondition(field, col, false)), nil
+}
+
+func (t *translator) textCondition(col column, value string, negate bool) string {
+	value = strings.Trim(value, "*")
+
+	matches := make([]string, 0, len(col.names))
+	for _, name := range col.names {
+		t.args = append(t.args, "%"+value+"%")
+		matches = append(matches, fmt.Sprintf("%s LIKE ?", name))
+	}
+
+	condition := "(" + strings.Join(matches, " OR ") + ")"
+	if negate {
+		return "NOT " + condition
+	}
+
+	return condition
+}
+
+func (t *translator) timeCondition(field string, col column, op string, value string) (string, error) {
+	bound, err := parseJQLTime(value, t.now)
+	if err != nil {
+		return "", fmt.Errorf("invalid value for field %q: %w", field, err)
+	}
+
+	if op == "!=" {
+		op = "<>"
+	}
+
+	t.args = append(t.args, bound.UTC().Format(timeLayout))
+
+	return fmt.Sprintf("%s %s ?", col.names[0], op), nil
+}
+
+func emptyCondition(field string, col column, negate bool) string {
+	if field == "labels" {
+		condition := "COALESCE(json_array_length(issues.data, '$.fields.labels'), 0) = 0"
+		if negate {
+			return "NOT " + condition
+		}
+		return condition
+	}
+
+	// User fields are empty when there is neither an account ID nor a
+	// username, whatever the display name
+	if col.user {
+		condition := fmt.Sprintf("(%s IS NULL AND %s_username IS NULL)", col.names[0], col.names[0])
+		if negate {
+			return "NOT " + condition
+		}
+		return condition
+	}
+
+	name := col.names[0]
+	if negate {
+		return fmt.Sprintf("%s IS NOT NULL", name)
+	}
+
+	return fmt.Sprintf("%s IS NULL", name)
+}
+
+// parseJQLTime parses relative times such as -2w or -30m, dates and
+// "yyyy-MM-dd HH:mm" timestamps in the local time zone
+func parseJQLTime(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, fmt.Errorf("missing time value")
+	}
+
+	switch strings.ToLower(value) {
+	case "now()":
+		return now, nil
+	case "startofday()":
+		return timeutils.StartOfDay(now), nil
+	}
+
+	if strings.HasPrefix(value, "-") || strings.HasPrefix(value, "+") {
+		d, err := timeutils.ParseRelativeDuration(value[1:])
+		if err != nil {
+			return time.Time{}, err
+		}
+		if value[0] == '-' {
+			d = -d
+		}
+		return now.Add(d), nil
+	}
+
+	for _, layout := range []string{"2006-01-02 15:04", "2006/01/02 15:04", timeutils.DateLayout, "2006/01/02"} {
+		if t, err := time.ParseInLocation(layout, value, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time value %q", value)
+}
+
+// normalizeField maps JQL field names and aliases to the keys of columns
+func normalizeField(field string) string {
+	switch f := strings.ToLower(field); f {
+	case "type":
+		return "issuetype"
+	case "epic link", "cf[10014]":
+		return "epic link"
+	case "label":
+		return "labels"
+	default:
+		return f
+	}
+}
+
+func tokenizeJQL(jql string) ([]string, error) {
+	tokens := make([]string, 0)
+	runes := []rune(jql)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')' || r == ',' || r == '=' || r == '~':
+			tokens = append(tokens, string(r))
+			i++
+		case r == '!' || r == '<' || r == '>':
+			if i+1 < len(runes) && (runes[i+1] == '=' || (r == '!' && runes[i+1] == '~')) {
+				tokens = append(tokens, string(runes[i:i+2]))
+				i += 2
+				continue
+			}
+			if r == '!' {
+				return nil, fmt.Errorf("unexpected '!' at position %d", i)
+			}
+			tokens = append(tokens, string(r))
+			i++
+		case r == '"' || r == '\'':
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				end++
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("unterminated string at position %d", i)
+			}
+			tokens = append(tokens, string(runes[i:end+1]))
+			i = end + 1
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune("(),=!~<>\"'", runes[i]) {
+				i++
+			}
+			// Keep function calls such as currentUser() as a single token
+			if i+1 < len(runes) && runes[i] == '(' && runes[i+1] == ')' {
+				i += 2
+			}
+			tokens = append(tokens, string(runes[start:i]))
+		}
+	}
+
+	return tokens, nil
+}
+
+func unquoteJQL(t string) string {
+	if len(t) >= 2 && (t[0] == '"' || t[0] == '\'') && t[len(t)-1] == t[0] {
+		return t[1 : len(t)-1]
+	}
+	return t
+}
@@ -0,0 +1,227 @@
+package mirror_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lburgazzoli/gira/pkg/jira"
+	"github.com/lburgazzoli/gira/pkg/mirror"
+)
+
+var (
+	cloudUser = &jira.User{AccountID: "acc-jane", DisplayName: "Jane Doe", EmailAddress: "jane@example.com"}
+	dcUser    = &jira.User{Name: "jdoe", Key: "JIRAUSER10100", DisplayName: "Jane Doe", EmailAddress: "jane@example.com"}
+)
+
+func TestTranslateJQL(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		jql         string
+		currentUser *jira.User
+		wantWhere   string
+		wantOrderBy string
+		wantArgs    []any
+		wantErr     bool
+	}{
+		{
+			name:      "empty",
+			jql:       "",
+			wantWhere: "1 = 1",
+		},
+		{
+			name:      "equal",
+			jql:       "project = DEMO",
+			wantWhere: "((lower(project) = lower(?)))",
+			wantArgs:  []any{"DEMO"},
+		},
+		{
+			name:      "not equal",
+			jql:       "status != Done",
+			wantWhere: "(NOT ((lower(status) = lower(?))) AND NOT status IS NULL)",
+			wantArgs:  []any{"Done"},
+		},
+		{
+			name:      "and or with parentheses",
+			jql:       `project = DEMO AND (status = Open OR status = "In Progress")`,
+			wantWhere: "(((lower(project) = lower(?))) AND (((lower(status) = lower(?))) OR ((lower(status) = lower(?)))))",
+			wantArgs:  []any{"DEMO", "Open", "In Progress"},
+		},
+		{
+			name:      "and binds tighter than or",
+			jql:       "status = Open OR status = Done AND priority = High",
+			wantWhere: "(((lower(status) = lower(?))) OR (((lower(status) = lower(?))) AND ((lower(priority) = lower(?)))))",
+			wantArgs:  []any{"Open", "Done", "High"},
+		},
+		{
+			name:      "not",
+			jql:       "NOT (status = Done OR issuetype = Epic)",
+			wantWhere: "NOT (((lower(status) = lower(?))) OR ((lower(issuetype) = lower(?))))",
+			wantArgs:  []any{"Done", "Epic"},
+		},
+		{
+			name:      "in",
+			jql:       `status IN (Open, "In Progress")`,
+			wantWhere: "((lower(status) = lower(?)) OR (lower(status) = lower(?)))",
+			wantArgs:  []any{"Open", "In Progress"},
+		},
+		{
+			name:      "not in",
+			jql:       "priority NOT IN (Low, Lowest)",
+			wantWhere: "(NOT ((lower(priority) = lower(?)) OR (lower(priority) = lower(?))) AND NOT priority IS NULL)",
+			wantArgs:  []any{"Low", "Lowest"},
+		},
+		{
+			name:      "in with empty",
+			jql:       "assignee IN (EMPTY, acc-jane)",
+			wantWhere: "((assignee IS NULL AND assignee_username IS NULL) OR (lower(assignee) = lower(?) OR lower(assignee_username) = lower(?) OR lower(assignee_name) = lower(?) OR lower(assignee_email) = lower(?)))",
+			wantArgs:  []any{"acc-jane", "acc-jane", "acc-jane", "acc-jane"},
+		},
+		{
+			name:      "is empty",
+			jql:       "assignee IS EMPTY",
+			wantWhere: "(assignee IS NULL AND assignee_username IS NULL)",
+		},
+		{
+			name:      "is not empty",
+			jql:       "resolution IS NOT EMPTY",
+			wantWhere: "resolution IS NOT NULL",
+		},
+		{
+			name:      "empty labels",
+			jql:       "labels is empty",
+			wantWhere: "COALESCE(json_array_length(issues.data, '$.fields.labels'), 0) = 0",
+		},
+		{
+			name:      "unresolved",
+			jql:       "resolution = Unresolved",
+			wantWhere: "(resolution IS NULL)",
+		},
+		{
+			name:      "status category name",
+			jql:       `statusCategory = "In Progress"`,
+			wantWhere: "((lower(status_category) = lower(?)))",
+			wantArgs:  []any{jira.StatusCategoryInProgress},
+		},
+		{
+			name:      "contains",
+			jql:       `summary ~ "login*"`,
+			wantWhere: "(summary LIKE ?)",
+			wantArgs:  []any{"%login%"},
+		},
+		{
+			name:      "text does not contain",
+			jql:       "text !~ flaky",
+			wantWhere: "NOT (summary LIKE ? OR description LIKE ?)",
+			wantArgs:  []any{"%flaky%", "%flaky%"},
+		},
+		{
+			name:    "contains on a field without text",
+			jql:     "status ~ Open",
+			wantErr: true,
+		},
+		{
+			name:      "relative date",
+			jql:       "created >= -2w AND updated < -1d",
+			wantWhere: "(created >= ? AND updated < ?)",
+			wantArgs:  []any{"2026-10-04T12:00:00.000Z", "2026-10-17T12:00:00.000Z"},
+		},
+		{
+			name:      "absolute date",
+			jql:       `updated > "2026-10-01"`,
+			wantWhere: "updated > ?",
+			wantArgs:  []any{"2026-10-01T00:00:00.000Z"},
+		},
+		{
+			name:    "range on a field without time",
+			jql:     "priority > High",
+			wantErr: true,
+		},
+		{
+			name:        "order by",
+			jql:         "project = DEMO ORDER BY priority DESC, key",
+			wantWhere:   "((lower(project) = lower(?)))",
+			wantOrderBy: "priority DESC, project ASC, number ASC",
+			wantArgs:    []any{"DEMO"},
+		},
+		{
+			name:        "order by without condition",
+			jql:         "ORDER BY assignee, updated DESC",
+			wantWhere:   "1 = 1",
+			wantOrderBy: "assignee_name ASC, updated DESC",
+		},
+		{
+			name:    "order by text",
+			jql:     "ORDER BY text",
+			wantErr: true,
+		},
+		{
+			name:        "current user on cloud",
+			jql:         "assignee = currentUser()",
+			currentUser: cloudUser,
+			wantWhere:   "((lower(assignee) = lower(?) OR lower(assignee_username) = lower(?) OR lower(assignee_name) = lower(?) OR lower(assignee_email) = lower(?)))",
+			wantArgs:    []any{"acc-jane", "acc-jane", "acc-jane", "acc-jane"},
+		},
+		{
+			name:        "current user on data center",
+			jql:         "reporter = currentUser()",
+			currentUser: dcUser,
+			wantWhere:   "((lower(reporter) = lower(?) OR lower(reporter_username) = lower(?) OR lower(reporter_name) = lower(?) OR lower(reporter_email) = lower(?)))",
+			wantArgs:    []any{"jdoe", "jdoe", "jdoe", "jdoe"},
+		},
+		{
+			name:    "unknown current user",
+			jql:     "assignee = currentUser()",
+			wantErr: true,
+		},
+		{
+			name:    "unsupported field",
+			jql:     "sprint = 42",
+			wantErr: true,
+		},
+		{
+			name:    "missing parenthesis",
+			jql:     "(status = Open",
+			wantErr: true,
+		},
+		{
+			name:    "trailing token",
+			jql:     "status = Open Done",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := mirror.TranslateJQL(tt.jql, tt.currentUser, now)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("translated %q to %q", tt.jql, q.Where)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			wantOrderBy := tt.wantOrderBy
+			if wantOrderBy == "" {
+				wantOrderBy = "project, number"
+			}
+
+			if q.Where != tt.wantWhere {
+				t.Errorf("Where = %q, want %q", q.Where, tt.wantWhere)
+			}
+			if q.OrderBy != wantOrderBy {
+				t.Errorf("OrderBy = %q, want %q", q.OrderBy, wantOrderBy)
+			}
+			if len(q.Args) != 0 || len(tt.wantArgs) != 0 {
+				if !reflect.DeepEqual(q.Args, tt.wantArgs) {
+					t.Errorf("Args = %v, want %v", q.Args, tt.wantArgs)
+				}
+			}
+		})
+	}
+}
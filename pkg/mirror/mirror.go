@@ -0,0 +1,534 @@
+// Package mirror keeps a local SQLite copy of JIRA issues, their comments
+// and changelogs, so that they can be queried without a connection.
+package mirror
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lburgazzoli/gira/pkg/jira"
+
+	// Pure Go SQLite driver, registered as "sqlite"
+	_ "modernc.org/sqlite"
+)
+
+const (
+	// schemaVersion is bumped whenever the schema changes in an incompatible way
+	schemaVersion = 1
+
+	// timeLayout is used for stored timestamps, fixed width so that they sort lexically
+	timeLayout = "2006-01-02T15:04:05.000Z"
+
+	metaCurrentUser = "current_user"
+)
+
+// ErrNotFound is returned when the mirror does not contain the requested item
+var ErrNotFound = errors.New("not found in the local mirror")
+
+const schema = `
+CREATE TABLE IF NOT EXISTS issues (
+	key             TEXT PRIMARY KEY,
+	id              TEXT NOT NULL,
+	project         TEXT NOT NULL,
+	number          INTEGER NOT NULL,
+	summary         TEXT,
+	description     TEXT,
+	status          TEXT,
+	status_category TEXT,
+	issuetype       TEXT,
+	priority        TEXT,
+	resolution      TEXT,
+	assignee          TEXT,
+	assignee_username TEXT,
+	assignee_name     TEXT,
+	assignee_email    TEXT,
+	reporter          TEXT,
+	reporter_username TEXT,
+	reporter_name     TEXT,
+	reporter_email    TEXT,
+	parent          TEXT,
+	epic_link       TEXT,
+	created         TEXT,
+	updated         TEXT,
+	data            TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS issues_project ON issues(project, number);
+CREATE INDEX IF NOT EXISTS issues_parent ON issues(parent);
+CREATE INDEX IF NOT EXISTS issues_epic_link ON issues(epic_link);
+CREATE INDEX IF NOT EXISTS issues_updated ON issues(updated);
+
+CREATE TABLE IF NOT EXISTS comments (
+	issue_key TEXT NOT NULL,
+	id        TEXT NOT NULL,
+	created   TEXT,
+	data      TEXT NOT NULL,
+	PRIMARY KEY (issue_key, id)
+);
+
+CREATE TABLE IF NOT EXISTS changelog (
+	issue_key TEXT NOT NULL,
+	id        TEXT NOT NULL,
+	created   TEXT,
+	data      TEXT NOT NULL,
+	PRIMARY KEY (issue_key, id)
+);
+
+CREATE TABLE IF NOT EXISTS syncs (
+	jql       TEXT PRIMARY KEY,
+	last_sync TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS meta (
+	key   TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);
+`
+
+// Mirror is a local SQLite copy of JIRA issues
+type Mirror struct {
+	db *sql.DB
+}
+
+// Record is an issue as stored in the mirror
+type Record struct {
+	// Data is the issue as returned by the server
+	Data json.RawMessage
+	// EpicLink is the key of the epic the issue belongs to, if any
+	EpicLink string
+	Comments []jira.Comment
+	Changes  []jira.ChangeHistory
+}
+
+// DefaultPath returns $XDG_DATA_HOME/gira/mirror.db, falling back to
+// ~/.local/share/gira/mirror.db
+func DefaultPath() (string, error) {
+	if xdgDataHome := os.Getenv("XDG_DATA_HOME"); xdgDataHome != "" {
+		return filepath.Join(xdgDataHome, "gira", "mirror.db"), nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(homeDir, ".local", "share", "gira", "mirror.db"), nil
+}
+
+// Open opens the mirror database at path, creating it when missing
+func Open(path string) (*Mirror, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create mirror directory: %w", err)
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open mirror %s: %w", path, err)
+	}
+
+	// SQLite does not support concurrent writers
+	db.SetMaxOpenConns(1)
+
+	m := &Mirror{db: db}
+	if err := m.migrate(); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to initialize mirror %s: %w", path, err)
+	}
+
+	return m, nil
+}
+
+// Close closes the database
+func (m *Mirror) Close() error {
+	return m.db.Close()
+}
+
+func (m *Mirror) migrate() error {
+	var version int
+	if err := m.db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return err
+	}
+
+	if version > schemaVersion {
+		return fmt.Errorf("mirror schema version %d is newer than supported version %d", version, schemaVersion)
+	}
+
+	if _, err := m.db.Exec(schema); err != nil {
+		return err
+	}
+
+	_, err := m.db.Exec(fmt.Sprintf("PRAGMA user_version = %d", schemaVersion))
+	return err
+}
+
+// Store saves an issue with its comments and changelog, replacing any
+// previous copy
+func (m *Mirror) Store(record *Record) error {
+	var issue jira.Issue
+	if err := json.Unmarshal(record.Data, &issue); err != nil {
+		return fmt.Errorf("failed to decode issue: %w", err)
+	}
+	if issue.Key == "" {
+		return fmt.Errorf("issue without key")
+	}
+
+	var raw struct {
+		Fields struct {
+			Resolution *jira.Resolution `json:"resolution"`
+		} `json:"fields"`
+	}
+	if err := json.Unmarshal(record.Data, &raw); err != nil {
+		return fmt.Errorf("failed to decode issue %s: %w", issue.Key, err)
+	}
+
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	project, number := splitKey(issue.Key)
+	if issue.Fields.Project.Key != "" {
+		project = issue.Fields.Project.Key
+	}
+
+	f := issue.Fields
+	_, err = tx.Exec(`INSERT OR REPLACE INTO issues (
+		key, id, project, number, summary, description, status, status_category,
+		issuetype, priority, resolution, assignee, assignee_username, assignee_name, assignee_email,
+		reporter, reporter_username, reporter_name, reporter_email, parent, epic_link, created, updated, data
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		issue.Key, issue.ID, project, number, f.Summary, f.Description, f.Status.Name, nullString(f.Status.StatusCategory.Key),
+		nullString(f.IssueType.Name), nullString(f.Priority.Name), resolutionName(raw.Fields.Resolution),
+		userField(f.Assignee, accountID), userField(f.Assignee, username), userField(f.Assignee, displayName), userField(f.Assignee, emailAddress),
+		userField(f.Reporter, accountID), userField(f.Reporter, username), userField(f.Reporter, displayName), userField(f.Reporter, emailAddress),
+		parentKey(f.Parent), nullString(record.EpicLink), formatTime(f.Created), formatTime(f.Updated), string(record.Data),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to store issue %s: %w", issue.Key, err)
+	}
+
+	if record.Comments != nil {
+		if _, err := tx.Exec(`DELETE FROM comments WHERE issue_key = ?`, issue.Key); err != nil {
+			return err
+		}
+		for _, comment := range record.Comments {
+			data, err := json.Marshal(comment)
+			if err != nil {
+				return err
+			}
+			_, err = tx.Exec(`INSERT INTO comments (issue_key, id, created, data) VALUES (?, ?, ?, ?)`,
+				issue.Key, comment.ID, formatTime(comment.Created), string(data))
+			if err != nil {
+				return fmt.Errorf("failed to store comments of %s: %w", issue.Key, err)
+			}
+		}
+	}
+
+	if record.Changes != nil {
+		if _, err := tx.Exec(`DELETE FROM changelog WHERE issue_key = ?`, issue.Key); err != nil {
+			return err
+		}
+		for _, history := range record.Changes {
+			data, err := json.Marshal(history)
+			if err != nil {
+				return err
+			}
+			_, err = tx.Exec(`INSERT INTO changelog (issue_key, id, created, data) VALUES (?, ?, ?, ?)`,
+				issue.Key, history.ID, formatTime(history.Created), string(data))
+			if err != nil {
+				return fmt.Errorf("failed to store changelog of %s: %w", issue.Key, err)
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Issue returns the stored copy of an issue
+func (m *Mirror) Issue(key string) (json.RawMessage, error) {
+	var data string
+	err := m.db.QueryRow(`SELECT data FROM issues WHERE key = ? OR id = ?`, strings.ToUpper(key), key).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("issue %s %w", key, ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return json.RawMessage(data), nil
+}
+
+// Project returns a project of the mirrored issues
+func (m *Mirror) Project(key string) (json.RawMessage, error) {
+	var data sql.NullString
+	err := m.db.QueryRow(`SELECT json_extract(data, '$.fields.project') FROM issues WHERE project = ? LIMIT 1`, strings.ToUpper(key)).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && !data.Valid) {
+		return nil, fmt.Errorf("project %s %w", key, ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return json.RawMessage(data.String), nil
+}
+
+// Statuses returns the distinct current statuses of the mirrored issues
+func (m *Mirror) Statuses() ([]json.RawMessage, error) {
+	rows, err := m.db.Query(`SELECT DISTINCT json_extract(data, '$.fields.status') FROM issues WHERE json_extract(data, '$.fields.status') IS NOT NULL`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list statuses: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	statuses := make([]json.RawMessage, 0)
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, json.RawMessage(data))
+	}
+
+	return statuses, rows.Err()
+}
+
+// Search returns a page of the issues matching a JQL query, together with
+// the total number of matches
+func (m *Mirror) Search(jql string, startAt int, maxResults int) ([]json.RawMessage, int, error) {
+	currentUser, err := m.CurrentUser()
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, 0, err
+	}
+
+	q, err := TranslateJQL(jql, currentUser, time.Now())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int
+	if err := m.db.QueryRow(`SELECT COUNT(*) FROM issues WHERE `+q.Where, q.Args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to search the local mirror: %w", err)
+	}
+
+	args := append(append([]any{}, q.Args...), maxResults, startAt)
+	rows, err := m.db.Query(`SELECT data FROM issues WHERE `+q.Where+` ORDER BY `+q.OrderBy+` LIMIT ? OFFSET ?`, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search the local mirror: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	issues := make([]json.RawMessage, 0)
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, 0, err
+		}
+		issues = append(issues, json.RawMessage(data))
+	}
+
+	return issues, total, rows.Err()
+}
+
+// Comments returns the stored comments of an issue, oldest first
+func (m *Mirror) Comments(key string) ([]jira.Comment, error) {
+	comments := make([]jira.Comment, 0)
+
+	err := m.scanJSON(`SELECT data FROM comments WHERE issue_key = ? ORDER BY created, id`, []any{key}, func(data []byte) error {
+		var comment jira.Comment
+		if err := json.Unmarshal(data, &comment); err != nil {
+			return err
+		}
+		comments = append(comments, comment)
+		return nil
+	})
+
+	return comments, err
+}
+
+// Changelog returns the stored change history of an issue, oldest first
+func (m *Mirror) Changelog(key string) ([]jira.ChangeHistory, error) {
+	histories := make([]jira.ChangeHistory, 0)
+
+	err := m.scanJSON(`SELECT data FROM changelog WHERE issue_key = ? ORDER BY created, id`, []any{key}, func(data []byte) error {
+		var history jira.ChangeHistory
+		if err := json.Unmarshal(data, &history); err != nil {
+			return err
+		}
+		histories = append(histories, history)
+		return nil
+	})
+
+	return histories, err
+}
+
+func (m *Mirror) scanJSON(query string, args []any, fn func(data []byte) error) error {
+	rows, err := m.db.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return err
+		}
+		if err := fn([]byte(data)); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// LastSync returns when the query was last synchronized
+func (m *Mirror) LastSync(jql string) (time.Time, bool, error) {
+	var value string
+	err := m.db.QueryRow(`SELECT last_sync FROM syncs WHERE jql = ?`, jql).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	t, err := time.Parse(timeLayout, value)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid last sync time %q: %w", value, err)
+	}
+
+	return t, true, nil
+}
+
+// SetLastSync records when the query was last synchronized
+func (m *Mirror) SetLastSync(jql string, t time.Time) error {
+	_, err := m.db.Exec(`INSERT OR REPLACE INTO syncs (jql, last_sync) VALUES (?, ?)`, jql, t.UTC().Format(timeLayout))
+	return err
+}
+
+// Queries returns the JQL queries that have been synchronized
+func (m *Mirror) Queries() ([]string, error) {
+	rows, err := m.db.Query(`SELECT jql FROM syncs ORDER BY jql`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	queries := make([]string, 0)
+	for rows.Next() {
+		var jql string
+		if err := rows.Scan(&jql); err != nil {
+			return nil, err
+		}
+		queries = append(queries, jql)
+	}
+
+	return queries, rows.Err()
+}
+
+// CurrentUser returns the user that synchronized the mirror, which resolves
+// currentUser() in offline queries
+func (m *Mirror) CurrentUser() (*jira.User, error) {
+	var data string
+	err := m.db.QueryRow(`SELECT value FROM meta WHERE key = ?`, metaCurrentUser).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("current user %w", ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var user jira.User
+	if err := json.Unmarshal([]byte(data), &user); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// SetCurrentUser records the user that synchronized the mirror
+func (m *Mirror) SetCurrentUser(user *jira.User) error {
+	data, err := json.Marshal(user)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.db.Exec(`INSERT OR REPLACE INTO meta (key, value) VALUES (?, ?)`, metaCurrentUser, string(data))
+	return err
+}
+
+// Column helpers
+
+func nullString(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func formatTime(t jira.JIRATime) any {
+	if t.IsZero() {
+		return nil
+	}
+	return t.UTC().Format(timeLayout)
+}
+
+func parentKey(parent *jira.Issue) any {
+	if parent == nil {
+		return nil
+	}
+	return nullString(parent.Key)
+}
+
+func resolutionName(resolution *jira.Resolution) any {
+	if resolution == nil {
+		return nil
+	}
+	return nullString(resolution.Name)
+}
+
+func accountID(u *jira.User) string    { return u.AccountID }
+func username(u *jira.User) string     { return u.Name }
+func displayName(u *jira.User) string  { return u.DisplayName }
+func emailAddress(u *jira.User) string { return u.EmailAddress }
+
+func userField(user *jira.User, field func(*jira.User) string) any {
+	if user == nil {
+		return nil
+	}
+	return nullString(field(user))
+}
+
+// splitKey splits an issue key into project key and number for natural ordering
+func splitKey(key string) (string, int) {
+	i := strings.LastIndex(key, "-")
+	if i < 0 {
+		return key, 0
+	}
+
+	n := 0
+	for _, c := range key[i+1:] {
+		if c < '0' || c > '9' {
+			return key, 0
+		}
+		n = n*10 + int(c-'0')
+	}
+
+	return key[:i], n
+}
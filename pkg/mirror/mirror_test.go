@@ -0,0 +1,198 @@
+package mirror_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/lburgazzoli/gira/pkg/jira"
+	"github.com/lburgazzoli/gira/pkg/mirror"
+)
+
+// openMirror returns an empty mirror synchronized by currentUser
+func openMirror(t *testing.T, currentUser *jira.User) *mirror.Mirror {
+	t.Helper()
+
+	m, err := mirror.Open(filepath.Join(t.TempDir(), "mirror.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = m.Close()
+	})
+
+	if err := m.SetCurrentUser(currentUser); err != nil {
+		t.Fatal(err)
+	}
+
+	return m
+}
+
+// storeIssue stores an issue of the DEMO project as returned by the server
+func storeIssue(t *testing.T, m *mirror.Mirror, key string, fields map[string]any) {
+	t.Helper()
+
+	fields["project"] = map[string]any{"key": "DEMO"}
+	data, err := json.Marshal(map[string]any{"id": "1" + key[len("DEMO-"):], "key": key, "fields": fields})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Store(&mirror.Record{Data: data}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// offlineClient returns a client answered by the mirror
+func offlineClient(t *testing.T, m *mirror.Mirror) *jira.Client {
+	t.Helper()
+
+	client, err := jira.NewClient("https://jira.example.com", jira.AuthConfig{Token: "offline"},
+		jira.WithTransportMiddleware(func(http.RoundTripper) http.RoundTripper {
+			return m.Transport()
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return client
+}
+
+func TestSearchRoundTrip(t *testing.T) {
+	recent := time.Now().Add(-time.Hour).UTC().Format("2006-01-02T15:04:05.000-0700")
+	old := time.Now().Add(-30 * 24 * time.Hour).UTC().Format("2006-01-02T15:04:05.000-0700")
+
+	tests := []struct {
+		name        string
+		currentUser *jira.User
+		// assignee is the way the server returns the assigned users
+		assignee func(u *jira.User) map[string]any
+		jql      string
+		want     []string
+	}{
+		{
+			name:        "current user on cloud",
+			currentUser: cloudUser,
+			assignee:    cloudAssignee,
+			jql:         "assignee = currentUser()",
+			want:        []string{"DEMO-1", "DEMO-3"},
+		},
+		{
+			name:        "current user on data center",
+			currentUser: dcUser,
+			assignee:    dcAssignee,
+			jql:         "assignee = currentUser()",
+			want:        []string{"DEMO-1", "DEMO-3"},
+		},
+		{
+			name:        "username on data center",
+			currentUser: dcUser,
+			assignee:    dcAssignee,
+			jql:         "assignee in (jdoe) ORDER BY key DESC",
+			want:        []string{"DEMO-3", "DEMO-1"},
+		},
+		{
+			name:        "unassigned on data center",
+			currentUser: dcUser,
+			assignee:    dcAssignee,
+			jql:         "assignee is EMPTY",
+			want:        []string{"DEMO-2"},
+		},
+		{
+			name:        "assigned on cloud",
+			currentUser: cloudUser,
+			assignee:    cloudAssignee,
+			jql:         "assignee is not EMPTY AND status != Done",
+			want:        []string{"DEMO-1"},
+		},
+		{
+			name:        "recently updated text",
+			currentUser: cloudUser,
+			assignee:    cloudAssignee,
+			jql:         "updated >= -1w AND summary ~ login ORDER BY updated DESC",
+			want:        []string{"DEMO-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := openMirror(t, tt.currentUser)
+
+			storeIssue(t, m, "DEMO-1", map[string]any{
+				"summary":  "Fix the login page",
+				"status":   map[string]any{"name": "In Progress"},
+				"assignee": tt.assignee(tt.currentUser),
+				"updated":  recent,
+			})
+			storeIssue(t, m, "DEMO-2", map[string]any{
+				"summary": "Fix the login API",
+				"status":  map[string]any{"name": "Open"},
+				"updated": old,
+			})
+			storeIssue(t, m, "DEMO-3", map[string]any{
+				"summary":  "Document the release",
+				"status":   map[string]any{"name": "Done"},
+				"assignee": tt.assignee(tt.currentUser),
+				"updated":  recent,
+			})
+
+			client := offlineClient(t, m)
+
+			issues, err := client.SearchAllIssues(tt.jql, []string{"key"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			keys := make([]string, 0, len(issues))
+			for _, issue := range issues {
+				keys = append(keys, issue.Key)
+			}
+			if !slices.Equal(keys, tt.want) {
+				t.Errorf("%s matched %v, want %v", tt.jql, keys, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetIssueRoundTrip(t *testing.T) {
+	m := openMirror(t, dcUser)
+	storeIssue(t, m, "DEMO-1", map[string]any{
+		"summary":  "Fix the login page",
+		"assignee": dcAssignee(dcUser),
+	})
+
+	client := offlineClient(t, m)
+
+	issue, err := client.GetIssue("demo-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issue.Fields.Summary != "Fix the login page" || issue.Fields.Assignee == nil || issue.Fields.Assignee.Name != "jdoe" {
+		t.Errorf("issue = %+v", issue.Fields)
+	}
+
+	me, err := client.Myself()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if me.ID() != "jdoe" {
+		t.Errorf("myself = %s, want jdoe", me.ID())
+	}
+
+	if _, err := client.GetIssue("DEMO-2"); err == nil {
+		t.Error("found an issue missing from the mirror")
+	}
+}
+
+func cloudAssignee(u *jira.User) map[string]any {
+	return map[string]any{"accountId": u.AccountID, "displayName": u.DisplayName, "emailAddress": u.EmailAddress}
+}
+
+// dcAssignee returns a Data Center user, which has a username and no account ID
+func dcAssignee(u *jira.User) map[string]any {
+	return map[string]any{"name": u.Name, "key": u.Key, "displayName": u.DisplayName, "emailAddress": u.EmailAddress}
+}
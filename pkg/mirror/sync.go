@@ -0,0 +1,182 @@
+package mirror
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/lburgazzoli/gira/pkg/jira"
+)
+
+const (
+	// syncPageSize is the number of issues fetched per search request
+	syncPageSize = 100
+
+	// syncOverlap is subtracted from the last sync time so that issues updated
+	// while the previous sync was running are not missed
+	syncOverlap = time.Minute
+
+	epicLinkSchema = "com.pyxis.greenhopper.jira:gh-epic-link"
+)
+
+var orderByPattern = regexp.MustCompile(`(?i)(^|\s+)ORDER\s+BY\s+.*$`)
+
+// SyncOptions controls a synchronization
+type SyncOptions struct {
+	// Full ignores the last sync time and fetches every matching issue
+	Full bool
+	// Progress is called after each stored issue, when set
+	Progress func(key string, done int, total int)
+}
+
+// SyncResult summarizes a synchronization
+type SyncResult struct {
+	JQL      string    `json:"jql"`
+	Since    time.Time `json:"since,omitzero"`
+	Issues   int       `json:"issues"`
+	Comments int       `json:"comments"`
+	Changes  int       `json:"changes"`
+	Duration string    `json:"duration"`
+}
+
+// Sync mirrors the issues matching jql, with their comments and changelogs.
+// Unless a full sync is requested, only the issues updated since the last
+// sync of the same query are fetched.
+func Sync(client *jira.Client, m *Mirror, jql string, opts SyncOptions) (*SyncResult, error) {
+	started := time.Now()
+	result := &SyncResult{JQL: jql}
+
+	user, err := client.Myself()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current user: %w", err)
+	}
+	if err := m.SetCurrentUser(user); err != nil {
+		return nil, fmt.Errorf("failed to store current user: %w", err)
+	}
+
+	epicLinkField, err := findEpicLinkField(client)
+	if err != nil {
+		return nil, err
+	}
+
+	query := jql
+	if !opts.Full {
+		lastSync, ok, err := m.LastSync(jql)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			result.Since = lastSync
+			query = incrementalJQL(jql, started.Sub(lastSync)+syncOverlap)
+		}
+	}
+
+	startAt := 0
+	for {
+		page, err := client.SearchRawIssues(query, startAt, syncPageSize, []string{"*all"})
+		if err != nil {
+			return nil, fmt.Errorf("failed to search issues: %w", err)
+		}
+
+		for _, data := range page.Issues {
+			key, record, err := fetchRecord(client, data, epicLinkField)
+			if err != nil {
+				return nil, err
+			}
+			if err := m.Store(record); err != nil {
+				return nil, err
+			}
+
+			result.Issues++
+			result.Comments += len(record.Comments)
+			result.Changes += len(record.Changes)
+
+			if opts.Progress != nil {
+				opts.Progress(key, result.Issues, page.Total)
+			}
+		}
+
+		if len(page.Issues) == 0 || startAt+len(page.Issues) >= page.Total {
+			break
+		}
+
+		startAt += len(page.Issues)
+	}
+
+	if err := m.SetLastSync(jql, started); err != nil {
+		return nil, fmt.Errorf("failed to record sync time: %w", err)
+	}
+
+	result.Duration = time.Since(started).Round(time.Millisecond).String()
+
+	return result, nil
+}
+
+// fetchRecord completes an issue returned by a search with its comments and
+// changelog
+func fetchRecord(client *jira.Client, data json.RawMessage, epicLinkField string) (string, *Record, error) {
+	var issue struct {
+		Key    string                     `json:"key"`
+		Fields map[string]json.RawMessage `json:"fields"`
+	}
+	if err := json.Unmarshal(data, &issue); err != nil {
+		return "", nil, fmt.Errorf("failed to decode issue: %w", err)
+	}
+
+	record := &Record{Data: data}
+
+	if epicLinkField != "" {
+		if raw, ok := issue.Fields[epicLinkField]; ok {
+			// The Epic Link is null or the key of the epic
+			_ = json.Unmarshal(raw, &record.EpicLink)
+		}
+	}
+
+	comments, err := client.GetComments(issue.Key)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get comments of %s: %w", issue.Key, err)
+	}
+	record.Comments = comments
+
+	changes, err := client.GetChangelog(issue.Key)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get changelog of %s: %w", issue.Key, err)
+	}
+	record.Changes = changes
+
+	return issue.Key, record, nil
+}
+
+// findEpicLinkField returns the ID of the Epic Link custom field, or an empty
+// string on instances without one
+func findEpicLinkField(client *jira.Client) (string, error) {
+	fields, err := client.GetFields()
+	if err != nil {
+		return "", fmt.Errorf("failed to get fields: %w", err)
+	}
+
+	for _, field := range fields {
+		if field.Schema.Custom == epicLinkSchema {
+			return field.ID, nil
+		}
+	}
+
+	return "", nil
+}
+
+// incrementalJQL restricts the query to issues updated in the given window.
+// A relative time is used as JQL interprets absolute times in the time zone
+// of the user, which is not known.
+func incrementalJQL(jql string, window time.Duration) string {
+	minutes := int(math.Ceil(window.Minutes()))
+
+	where := strings.TrimSpace(orderByPattern.ReplaceAllString(jql, ""))
+	if where == "" {
+		return fmt.Sprintf("updated >= -%dm ORDER BY updated ASC", minutes)
+	}
+
+	return fmt.Sprintf("(%s) AND updated >= -%dm ORDER BY updated ASC", where, minutes)
+}
@@ -0,0 +1,188 @@
+package mirror
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	apiPrefix = "/rest/api/2/"
+
+	defaultMaxResults = 50
+)
+
+// Transport returns a RoundTripper answering the read requests issued by
+// jira.Client from the mirror, so that commands work without a connection.
+// Requests that cannot be served offline fail with 501 Not Implemented.
+func (m *Mirror) Transport() http.RoundTripper {
+	return &transport{mirror: m}
+}
+
+type transport struct {
+	mirror *Mirror
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+
+	i := strings.Index(req.URL.Path, apiPrefix)
+	if req.Method != http.MethodGet || i < 0 {
+		return errorResponse(req, http.StatusNotImplemented, fmt.Sprintf("%s %s is not available offline", req.Method, req.URL.Path)), nil
+	}
+
+	parts := strings.Split(strings.Trim(req.URL.Path[i+len(apiPrefix):], "/"), "/")
+	query := req.URL.Query()
+
+	var body any
+	var err error
+
+	switch {
+	case len(parts) == 1 && parts[0] == "myself":
+		body, err = t.mirror.CurrentUser()
+	case len(parts) == 1 && parts[0] == "status":
+		body, err = t.mirror.Statuses()
+	case len(parts) == 1 && parts[0] == "search":
+		body, err = t.search(query)
+	case len(parts) == 2 && parts[0] == "project":
+		body, err = t.mirror.Project(parts[1])
+	case len(parts) == 2 && parts[0] == "issue":
+		body, err = t.issue(parts[1], query)
+	case len(parts) == 3 && parts[0] == "issue" && parts[2] == "changelog":
+		body, err = t.changelog(parts[1])
+	case len(parts) == 3 && parts[0] == "issue" && parts[2] == "comment":
+		body, err = t.comments(parts[1])
+	default:
+		return errorResponse(req, http.StatusNotImplemented, fmt.Sprintf("%s is not available offline", req.URL.Path)), nil
+	}
+
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return errorResponse(req, http.StatusNotFound, err.Error()), nil
+	case err != nil:
+		// Invalid or unsupported queries are reported like JIRA does
+		return errorResponse(req, http.StatusBadRequest, err.Error()), nil
+	}
+
+	return jsonResponse(req, http.StatusOK, body)
+}
+
+func (t *transport) search(query map[string][]string) (any, error) {
+	startAt, _ := strconv.Atoi(first(query["startAt"]))
+	maxResults, err := strconv.Atoi(first(query["maxResults"]))
+	if err != nil || maxResults <= 0 {
+		maxResults = defaultMaxResults
+	}
+
+	issues, total, err := t.mirror.Search(first(query["jql"]), startAt, maxResults)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		"issues":     issues,
+		"startAt":    startAt,
+		"maxResults": maxResults,
+		"total":      total,
+	}, nil
+}
+
+func (t *transport) issue(key string, query map[string][]string) (any, error) {
+	data, err := t.mirror.Issue(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.Contains(first(query["expand"]), "changelog") {
+		return data, nil
+	}
+
+	var issue map[string]any
+	if err := json.Unmarshal(data, &issue); err != nil {
+		return nil, err
+	}
+
+	histories, err := t.mirror.Changelog(strings.ToUpper(key))
+	if err != nil {
+		return nil, err
+	}
+
+	issue["changelog"] = map[string]any{
+		"startAt":    0,
+		"maxResults": len(histories),
+		"total":      len(histories),
+		"histories":  histories,
+	}
+
+	return issue, nil
+}
+
+func (t *transport) changelog(key string) (any, error) {
+	histories, err := t.mirror.Changelog(strings.ToUpper(key))
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		"values":     histories,
+		"startAt":    0,
+		"maxResults": len(histories),
+		"total":      len(histories),
+		"isLast":     true,
+	}, nil
+}
+
+func (t *transport) comments(key string) (any, error) {
+	comments, err := t.mirror.Comments(strings.ToUpper(key))
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		"comments":   comments,
+		"startAt":    0,
+		"maxResults": len(comments),
+		"total":      len(comments),
+	}, nil
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func jsonResponse(req *http.Request, status int, body any) (*http.Response, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode offline response: %w", err)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(data)),
+		ContentLength: int64(len(data)),
+		Request:       req,
+	}, nil
+}
+
+func errorResponse(req *http.Request, status int, message string) *http.Response {
+	resp, _ := jsonResponse(req, status, map[string]any{
+		"errorMessages": []string{message},
+		"errors":        map[string]string{},
+	})
+	return resp
+}
@@ -0,0 +1,606 @@
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lburgazzoli/gira/pkg/jira"
+)
+
+// Action is what applying a plan does to an issue
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionNone   Action = "no-op"
+)
+
+// KnownAfterApply stands for values that depend on issues not created yet
+const KnownAfterApply = "(known after apply)"
+
+// FieldChange is the change of a single field of an issue
+type FieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from,omitempty"`
+	To    string `json:"to"`
+}
+
+// IssueChange is the planned change of an issue of the spec
+type IssueChange struct {
+	Action Action `json:"action"`
+	ID     string `json:"id"`
+	// Key is the key of the issue, empty for issues to create until applied
+	Key     string        `json:"key,omitempty"`
+	Type    string        `json:"type"`
+	Summary string        `json:"summary"`
+	Parent  string        `json:"parent,omitempty"`
+	Changes []FieldChange `json:"changes,omitempty"`
+
+	// fields are the values sent to JIRA, keyed by field ID
+	fields map[string]interface{}
+	// parentField is set when the parent is only known once created, and
+	// names the field referencing it
+	parentField string
+}
+
+// LinkChange is an issue link of the spec, From and To being external IDs or
+// issue keys
+type LinkChange struct {
+	From     string `json:"from"`
+	Relation string `json:"relation"`
+	To       string `json:"to"`
+	Exists   bool   `json:"exists"`
+
+	linkType jira.LinkType
+	reversed bool
+}
+
+// Plan is the set of changes bringing JIRA in line with a spec
+type Plan struct {
+	Project string         `json:"project"`
+	Issues  []*IssueChange `json:"issues"`
+	Links   []*LinkChange  `json:"links,omitempty"`
+
+	spec *Spec
+}
+
+// Count returns the number of issues per action and of links to create
+func (p *Plan) Count() (create int, update int, unchanged int, links int) {
+	for _, issue := range p.Issues {
+		switch issue.Action {
+		case ActionCreate:
+			create++
+		case ActionUpdate:
+			update++
+		default:
+			unchanged++
+		}
+	}
+	for _, link := range p.Links {
+		if !link.Exists {
+			links++
+		}
+	}
+	return create, update, unchanged, links
+}
+
+// HasChanges reports whether applying the plan changes anything
+func (p *Plan) HasChanges() bool {
+	create, update, _, links := p.Count()
+	return create+update+links > 0
+}
+
+// desiredField is the desired value of a field
+type desiredField struct {
+	// name is the name of the field in the spec
+	name    string
+	fieldID string
+	value   interface{}
+	display string
+}
+
+// planner holds what is needed to compare a spec with JIRA
+type planner struct {
+	client   *jira.Client
+	spec     *Spec
+	fields   []jira.Field
+	epicLink *jira.Field
+	existing map[string]*remoteIssue
+}
+
+// remoteIssue is an issue found in JIRA, with its raw field values
+type remoteIssue struct {
+	Key        string                     `json:"key"`
+	Fields     map[string]json.RawMessage `json:"fields"`
+	Properties map[string]json.RawMessage `json:"properties"`
+}
+
+// NewPlan compares a spec with the issues holding its external IDs in JIRA
+func NewPlan(client *jira.Client, spec *Spec) (*Plan, error) {
+	fields, err := client.GetFields()
+	if err != nil {
+		return nil, err
+	}
+
+	p := &planner{client: client, spec: spec, fields: fields, epicLink: jira.EpicLinkField(fields)}
+
+	desired := make(map[string][]desiredField)
+	fieldIDs := []string{"summary", "issuetype", "labels", "issuelinks", "parent"}
+	if p.epicLink != nil {
+		fieldIDs = append(fieldIDs, p.epicLink.ID)
+	}
+
+	if err := spec.Walk(func(issue *Issue, _ *Issue) error {
+		values, err := p.desired(issue)
+		if err != nil {
+			return fmt.Errorf("issue %s: %w", issue.ID, err)
+		}
+		desired[issue.ID] = values
+		for _, v := range values {
+			if !slices.Contains(fieldIDs, v.fieldID) {
+				fieldIDs = append(fieldIDs, v.fieldID)
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if p.existing, err = p.findIssues(fieldIDs); err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{Project: spec.Project, spec: spec}
+
+	if err := spec.Walk(func(issue *Issue, parent *Issue) error {
+		plan.Issues = append(plan.Issues, p.compare(issue, parent, desired[issue.ID]))
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if plan.Links, err = p.links(); err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+// desired returns the desired values of the fields managed by the spec
+func (p *planner) desired(issue *Issue) ([]desiredField, error) {
+	values := make([]desiredField, 0)
+
+	add := func(name string, fieldRef string, value string) error {
+		field, err := jira.FindField(p.fields, fieldRef)
+		if err != nil {
+			return err
+		}
+		v, err := p.client.FieldValue(field, value)
+		if err != nil {
+			return err
+		}
+		display := value
+		if field.Schema.Type == "array" {
+			display = strings.ReplaceAll(value, ",", ", ")
+		}
+		values = append(values, desiredField{name: name, fieldID: field.ID, value: v, display: display})
+		return nil
+	}
+
+	if err := add("summary", "summary", issue.Summary); err != nil {
+		return nil, err
+	}
+
+	// Optional attributes are only managed when given
+	optional := []struct {
+		name  string
+		value string
+	}{
+		{"description", issue.Description},
+		{"assignee", issue.Assignee},
+		{"priority", issue.Priority},
+		{"components", strings.Join(issue.Components, ",")},
+		{"fixVersions", strings.Join(issue.FixVersions, ",")},
+	}
+	for _, o := range optional {
+		if o.value == "" {
+			continue
+		}
+		if err := add(o.name, o.name, o.value); err != nil {
+			return nil, err
+		}
+	}
+
+	labels := slices.Clone(issue.Labels)
+	if p.spec.ExternalID.Store == StoreLabel {
+		labels = append(labels, p.spec.Label(issue.ID))
+	}
+	if len(labels) > 0 {
+		if err := add("labels", "labels", strings.Join(labels, ",")); err != nil {
+			return nil, err
+		}
+	}
+
+	names := make([]string, 0, len(issue.Fields))
+	for name := range issue.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := add(name, name, textValue(issue.Fields[name])); err != nil {
+			return nil, err
+		}
+	}
+
+	return values, nil
+}
+
+// findIssues returns the issues holding the external IDs of the spec
+func (p *planner) findIssues(fieldIDs []string) (map[string]*remoteIssue, error) {
+	ext := p.spec.ExternalID
+
+	jql := ext.Scope
+	var properties []string
+	if ext.Store == StoreLabel {
+		labels := make([]string, 0)
+		_ = p.spec.Walk(func(issue *Issue, _ *Issue) error {
+			labels = append(labels, strconv.Quote(p.spec.Label(issue.ID)))
+			return nil
+		})
+		jql = fmt.Sprintf("project = %q AND labels in (%s)", p.spec.Project, strings.Join(labels, ", "))
+	} else {
+		properties = []string{ext.Property}
+	}
+
+	found := make(map[string]*remoteIssue)
+
+	// Cached results may miss issues created moments ago by another client,
+	// which would then be created twice
+	p.client.InvalidateSearches()
+
+	issues, err := p.client.SearchAllRawIssuesWithProperties(jql, fieldIDs, properties)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search issues: %w", err)
+	}
+
+	for _, raw := range issues {
+		var issue remoteIssue
+		if err := json.Unmarshal(raw, &issue); err != nil {
+			return nil, fmt.Errorf("failed to decode issue: %w", err)
+		}
+
+		for _, id := range p.externalIDs(&issue) {
+			if other, ok := found[id]; ok {
+				return nil, fmt.Errorf("external ID %s is held by both %s and %s", id, other.Key, issue.Key)
+			}
+			found[id] = &issue
+		}
+	}
+
+	return found, nil
+}
+
+// externalIDs returns the external IDs held by an issue
+func (p *planner) externalIDs(issue *remoteIssue) []string {
+	ext := p.spec.ExternalID
+	ids := make([]string, 0, 1)
+
+	if ext.Store == StoreProperty {
+		var property struct {
+			ExternalID string `json:"externalId"`
+		}
+		if raw, ok := issue.Properties[ext.Property]; ok && json.Unmarshal(raw, &property) == nil && property.ExternalID != "" {
+			ids = append(ids, property.ExternalID)
+		}
+		return ids
+	}
+
+	var labels []string
+	_ = json.Unmarshal(issue.Fields["labels"], &labels)
+	for _, label := range labels {
+		if id, ok := strings.CutPrefix(label, ext.Prefix); ok {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+// compare plans the change of an issue from its desired field values
+func (p *planner) compare(issue *Issue, parent *Issue, desired []desiredField) *IssueChange {
+	change := &IssueChange{
+		ID:      issue.ID,
+		Type:    issue.Type,
+		Summary: issue.Summary,
+		fields:  make(map[string]interface{}),
+	}
+	if parent != nil {
+		change.Parent = parent.ID
+	}
+
+	current, exists := p.existing[issue.ID]
+	if !exists {
+		change.Action = ActionCreate
+		change.fields["project"] = map[string]string{"key": p.spec.Project}
+		change.fields["issuetype"] = map[string]string{"name": issue.Type}
+		for _, d := range desired {
+			change.fields[d.fieldID] = d.value
+			change.Changes = append(change.Changes, FieldChange{Field: d.name, To: d.display})
+		}
+	} else {
+		change.Key = current.Key
+		for _, d := range desired {
+			from := current.Fields[d.fieldID]
+			if identity(decode(from)) == identity(roundTrip(d.value)) {
+				continue
+			}
+			change.fields[d.fieldID] = d.value
+			change.Changes = append(change.Changes, FieldChange{Field: d.name, From: display(decode(from)), To: d.display})
+		}
+	}
+
+	if parent != nil {
+		p.compareParent(change, current, parent)
+	}
+
+	switch {
+	case !exists:
+	case len(change.Changes) > 0:
+		change.Action = ActionUpdate
+	default:
+		change.Action = ActionNone
+	}
+
+	return change
+}
+
+// compareParent plans the parent of an issue: epics are referenced through
+// the Epic Link field when the instance has one, other issues through parent
+func (p *planner) compareParent(change *IssueChange, current *remoteIssue, parent *Issue) {
+	field := "parent"
+	if p.epicLink != nil && strings.EqualFold(parent.Type, "Epic") {
+		field = p.epicLink.ID
+	}
+
+	remoteParent, ok := p.existing[parent.ID]
+	if !ok {
+		change.parentField = field
+		change.Changes = append(change.Changes, FieldChange{Field: "parent", To: parent.ID + " " + KnownAfterApply})
+		return
+	}
+
+	if current != nil && identity(decode(current.Fields[field])) == remoteParent.Key {
+		return
+	}
+
+	change.fields[field] = jira.ParentValue(field, remoteParent.Key)
+
+	// The current parent may be held by the other field
+	from := ""
+	if current != nil {
+		from = identity(decode(current.Fields[field]))
+		if from == "" && field != "parent" {
+			from = identity(decode(current.Fields["parent"]))
+		} else if from == "" && p.epicLink != nil {
+			from = identity(decode(current.Fields[p.epicLink.ID]))
+		}
+	}
+	change.Changes = append(change.Changes, FieldChange{Field: "parent", From: from, To: remoteParent.Key})
+}
+
+// links plans the issue links of the spec, existing links being kept
+func (p *planner) links() ([]*LinkChange, error) {
+	var types []jira.LinkType
+	links := make([]*LinkChange, 0)
+
+	err := p.spec.Walk(func(issue *Issue, _ *Issue) error {
+		for _, l := range issue.Links {
+			if types == nil {
+				var err error
+				if types, err = p.client.ListLinkTypes(); err != nil {
+					return err
+				}
+			}
+
+			linkType, reversed, err := jira.ResolveLinkType(types, l.Type)
+			if err != nil {
+				return fmt.Errorf("issue %s: %w", issue.ID, err)
+			}
+
+			change := &LinkChange{From: issue.ID, Relation: l.Type, To: l.To, linkType: linkType, reversed: reversed}
+			change.Exists = p.linkExists(change)
+			links = append(links, change)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return links, nil
+}
+
+func (p *planner) linkExists(link *LinkChange) bool {
+	from, ok := p.existing[link.From]
+	if !ok {
+		return false
+	}
+
+	to := link.To
+	if target, ok := p.existing[link.To]; ok {
+		to = target.Key
+	} else if !jira.IsIssueKey(to) {
+		return false
+	}
+
+	var issueLinks []jira.IssueLink
+	_ = json.Unmarshal(from.Fields["issuelinks"], &issueLinks)
+
+	for _, l := range issueLinks {
+		if l.Type.Name != link.linkType.Name {
+			continue
+		}
+		// The holder of "A blocks B" sees B as its outward issue
+		if !link.reversed && l.OutwardIssue != nil && l.OutwardIssue.Key == to {
+			return true
+		}
+		if link.reversed && l.InwardIssue != nil && l.InwardIssue.Key == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Apply carries out the plan: issues are created or updated parents first,
+// then links are added. Each change is reported through progress, and
+// applying stops at the first failure; since plans are idempotent, applying
+// the spec again resumes where it stopped.
+func (p *Plan) Apply(client *jira.Client, progress func(change *IssueChange, link *LinkChange)) error {
+	keys := make(map[string]string)
+	for _, issue := range p.Issues {
+		if issue.Key != "" {
+			keys[issue.ID] = issue.Key
+		}
+	}
+
+	for _, issue := range p.Issues {
+		if issue.parentField != "" {
+			issue.fields[issue.parentField] = jira.ParentValue(issue.parentField, keys[issue.Parent])
+		}
+
+		switch issue.Action {
+		case ActionCreate:
+			// The external ID property is created along with the issue, a
+			// failure cannot leave an issue the next plan does not find
+			var properties []jira.EntityProperty
+			if p.spec.ExternalID.Store == StoreProperty {
+				properties = append(properties, jira.EntityProperty{
+					Key:   p.spec.ExternalID.Property,
+					Value: map[string]string{"externalId": issue.ID},
+				})
+			}
+
+			created, err := client.CreateIssueWithFields(issue.fields, properties...)
+			if err != nil {
+				return fmt.Errorf("failed to create %s: %w", issue.ID, err)
+			}
+			issue.Key = created.Key
+			keys[issue.ID] = created.Key
+		case ActionUpdate:
+			if _, err := client.UpdateIssue(issue.Key, jira.IssueUpdate{Fields: issue.fields}); err != nil {
+				return fmt.Errorf("failed to update %s (%s): %w", issue.ID, issue.Key, err)
+			}
+		default:
+			continue
+		}
+
+		progress(issue, nil)
+	}
+
+	for _, link := range p.Links {
+		if link.Exists {
+			continue
+		}
+
+		from, to := keys[link.From], link.To
+		if key, ok := keys[link.To]; ok {
+			to = key
+		}
+		if link.reversed {
+			from, to = to, from
+		}
+
+		if err := client.CreateIssueLink(link.linkType.Name, from, to); err != nil {
+			return fmt.Errorf("failed to link %s %s %s: %w", link.From, link.Relation, link.To, err)
+		}
+
+		progress(nil, link)
+	}
+
+	return nil
+}
+
+// textValue renders a YAML value as the text accepted by FieldValue, lists
+// being comma separated
+func textValue(v any) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case []any:
+		items := make([]string, 0, len(value))
+		for _, item := range value {
+			items = append(items, textValue(item))
+		}
+		return strings.Join(items, ",")
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// roundTrip returns a value as it would be decoded from JSON
+func roundTrip(v interface{}) any {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	return decode(data)
+}
+
+func decode(raw json.RawMessage) any {
+	if len(raw) == 0 {
+		return nil
+	}
+	var v any
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return nil
+	}
+	return v
+}
+
+// identity reduces a field value to what identifies it, so that the values
+// read from JSON compare with the values sent to it: the value of options,
+// the account ID or name of users, the name of versions, the key of issues
+func identity(v any) string {
+	return reduce(v, []string{"value", "accountId", "name", "key", "id"})
+}
+
+// display reduces a field value to a human readable form
+func display(v any) string {
+	return reduce(v, []string{"displayName", "value", "name", "key", "id"})
+}
+
+func reduce(v any, keys []string) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(value)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(value)
+	case []any:
+		items := make([]string, 0, len(value))
+		for _, item := range value {
+			items = append(items, reduce(item, keys))
+		}
+		sort.Strings(items)
+		return strings.Join(items, ", ")
+	case map[string]any:
+		for _, key := range keys {
+			if s, ok := value[key].(string); ok && s != "" {
+				return s
+			}
+		}
+	}
+
+	data, _ := json.Marshal(v)
+	return string(data)
+}
@@ -0,0 +1,279 @@
+package spec_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/lburgazzoli/gira/pkg/jira"
+	"github.com/lburgazzoli/gira/pkg/jira/jiratest"
+	"github.com/lburgazzoli/gira/pkg/spec"
+)
+
+const testSpec = `
+project: DEMO
+issues:
+  - id: epic
+    type: Epic
+    summary: The epic
+    children:
+      - id: story
+        type: Story
+        summary: The story
+        labels: [backend]
+        links:
+          - type: blocks
+            to: other
+        children:
+          - id: subtask
+            type: Sub-task
+            summary: The subtask
+  - id: other
+    type: Task
+    summary: Another task
+`
+
+func newTestServer(t *testing.T) (*jiratest.Server, *jira.Client) {
+	t.Helper()
+
+	srv := jiratest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.AddProject(jira.Project{Key: "DEMO"})
+
+	client, err := srv.NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return srv, client
+}
+
+func parse(t *testing.T, data string) *spec.Spec {
+	t.Helper()
+
+	s, err := spec.Parse([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s
+}
+
+// apply plans a spec and applies it, returning the plan and the external IDs
+// of the changed issues in the order they were changed
+func apply(t *testing.T, client *jira.Client, s *spec.Spec) (*spec.Plan, []string) {
+	t.Helper()
+
+	plan, err := spec.NewPlan(client, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changed := make([]string, 0)
+	err = plan.Apply(client, func(change *spec.IssueChange, _ *spec.LinkChange) {
+		if change != nil {
+			changed = append(changed, change.ID)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return plan, changed
+}
+
+// actions returns the planned action of each issue by external ID
+func actions(t *testing.T, client *jira.Client, s *spec.Spec) (*spec.Plan, map[string]spec.Action) {
+	t.Helper()
+
+	plan, err := spec.NewPlan(client, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]spec.Action)
+	for _, issue := range plan.Issues {
+		got[issue.ID] = issue.Action
+	}
+
+	return plan, got
+}
+
+func TestApplyIsIdempotent(t *testing.T) {
+	srv, client := newTestServer(t)
+
+	plan, changed := apply(t, client, parse(t, testSpec))
+
+	// Parents are created before their children
+	want := []string{"epic", "story", "subtask", "other"}
+	if len(changed) != len(want) {
+		t.Fatalf("changed %v, want %v", changed, want)
+	}
+	for i := range want {
+		if changed[i] != want[i] {
+			t.Fatalf("changed %v, want %v", changed, want)
+		}
+	}
+
+	keys := make(map[string]string)
+	for _, issue := range plan.Issues {
+		keys[issue.ID] = issue.Key
+	}
+
+	subtask, ok := srv.Issue(keys["subtask"])
+	if !ok || subtask.Fields.Parent == nil || subtask.Fields.Parent.Key != keys["story"] {
+		t.Errorf("subtask = %+v, want the story as parent", subtask)
+	}
+
+	links, err := client.ListIssueLinks(keys["story"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(links) != 1 || links[0].Relation() != "blocks" || links[0].LinkedIssue().Key != keys["other"] {
+		t.Errorf("story links = %+v, want it to block the other task", links)
+	}
+
+	plan, got := actions(t, client, parse(t, testSpec))
+	for id, action := range got {
+		if action != spec.ActionNone {
+			t.Errorf("%s is planned for %s after apply", id, action)
+		}
+	}
+	if plan.HasChanges() {
+		t.Errorf("plan has changes after apply: %+v", plan.Links)
+	}
+}
+
+func TestPlanDetectsChanges(t *testing.T) {
+	_, client := newTestServer(t)
+
+	apply(t, client, parse(t, testSpec))
+
+	// The same link, held by the other issue in its inward phrasing
+	changed := parse(t, `
+project: DEMO
+issues:
+  - id: epic
+    type: Epic
+    summary: The renamed epic
+    children:
+      - id: story
+        type: Story
+        summary: The story
+        labels: [backend]
+        children:
+          - id: subtask
+            type: Sub-task
+            summary: The subtask
+  - id: other
+    type: Task
+    summary: Another task
+    links:
+      - type: is blocked by
+        to: story
+`)
+
+	plan, got := actions(t, client, changed)
+
+	want := map[string]spec.Action{
+		"epic":    spec.ActionUpdate,
+		"story":   spec.ActionNone,
+		"subtask": spec.ActionNone,
+		"other":   spec.ActionNone,
+	}
+	for id, action := range want {
+		if got[id] != action {
+			t.Errorf("%s is planned for %s, want %s", id, got[id], action)
+		}
+	}
+
+	if len(plan.Links) != 1 || !plan.Links[0].Exists {
+		t.Errorf("links = %+v, want the existing link", plan.Links)
+	}
+
+	for _, issue := range plan.Issues {
+		if issue.ID != "epic" {
+			continue
+		}
+		if len(issue.Changes) != 1 || issue.Changes[0].Field != "summary" || issue.Changes[0].To != "The renamed epic" {
+			t.Errorf("epic changes = %+v, want the summary", issue.Changes)
+		}
+	}
+}
+
+func TestApplyPropertyStore(t *testing.T) {
+	srv, client := newTestServer(t)
+
+	s := parse(t, `
+project: DEMO
+externalId:
+  store: property
+  property: spec
+issues:
+  - id: task
+    type: Task
+    summary: A task
+`)
+
+	plan, _ := apply(t, client, s)
+	key := plan.Issues[0].Key
+
+	var property struct {
+		ExternalID string `json:"externalId"`
+	}
+	if err := json.Unmarshal(srv.Property(key, "spec"), &property); err != nil || property.ExternalID != "task" {
+		t.Errorf("property of %s = %s, want the external ID", key, srv.Property(key, "spec"))
+	}
+
+	// The property is sent along with the issue, never on its own
+	for _, r := range srv.Requests() {
+		if r.Method == http.MethodPut {
+			t.Errorf("unexpected request %s %s", r.Method, r.Path)
+		}
+	}
+
+	issue, ok := srv.Issue(key)
+	if !ok || len(issue.Fields.Labels) != 0 {
+		t.Errorf("issue = %+v, want no external ID label", issue)
+	}
+
+	if _, got := actions(t, client, s); got["task"] != spec.ActionNone {
+		t.Errorf("task is planned for %s after apply", got["task"])
+	}
+}
+
+func TestPlanIgnoresCachedSearches(t *testing.T) {
+	srv, _ := newTestServer(t)
+
+	cache, err := jira.NewFileCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := srv.NewClient(jira.WithCache(cache, jira.DefaultCachePolicy()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := parse(t, `
+project: DEMO
+issues:
+  - id: task
+    type: Task
+    summary: A task
+`)
+
+	if _, got := actions(t, client, s); got["task"] != spec.ActionCreate {
+		t.Fatalf("task is planned for %s, want %s", got["task"], spec.ActionCreate)
+	}
+
+	// Another process applies the spec meanwhile
+	other, err := srv.NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	apply(t, other, s)
+
+	if _, got := actions(t, client, s); got["task"] != spec.ActionNone {
+		t.Errorf("task is planned for %s, want %s", got["task"], spec.ActionNone)
+	}
+}
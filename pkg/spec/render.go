@@ -0,0 +1,76 @@
+package spec
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fatih/color"
+	stringutils "github.com/lburgazzoli/gira/pkg/utils/strings"
+)
+
+// maxValueWidth is the width past which values are truncated in diffs
+const maxValueWidth = 60
+
+// WritePlan renders a plan as a diff: + for issues to create, ~ for issues
+// to update, = for unchanged issues, followed by the links to add
+func WritePlan(w io.Writer, plan *Plan) {
+	green := color.New(color.FgGreen).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	faint := color.New(color.Faint).SprintFunc()
+
+	if !plan.HasChanges() {
+		fmt.Fprintf(w, "No changes, the issues of %s match the spec.\n", plan.Project)
+		return
+	}
+
+	fmt.Fprintf(w, "Changes to apply in %s:\n\n", plan.Project)
+
+	for _, issue := range plan.Issues {
+		switch issue.Action {
+		case ActionCreate:
+			fmt.Fprintf(w, "  %s %s (%s)\n", green("+"), issue.ID, issue.Type)
+		case ActionUpdate:
+			fmt.Fprintf(w, "  %s %s %s (%s)\n", yellow("~"), issue.ID, issue.Key, issue.Type)
+		default:
+			fmt.Fprintf(w, "  %s\n", faint(fmt.Sprintf("= %s %s (%s)", issue.ID, issue.Key, issue.Type)))
+			continue
+		}
+
+		width := 0
+		for _, c := range issue.Changes {
+			width = max(width, len(c.Field))
+		}
+
+		for _, c := range issue.Changes {
+			label := fmt.Sprintf("%-*s", width+1, c.Field+":")
+			if issue.Action == ActionCreate {
+				fmt.Fprintf(w, "      %s %s\n", label, quote(c.To))
+				continue
+			}
+			fmt.Fprintf(w, "      %s %s → %s\n", label, quote(c.From), quote(c.To))
+		}
+	}
+
+	for _, link := range plan.Links {
+		if link.Exists {
+			continue
+		}
+		fmt.Fprintf(w, "  %s link %s %s %s\n", green("+"), link.From, link.Relation, link.To)
+	}
+
+	create, update, unchanged, links := plan.Count()
+	fmt.Fprintf(w, "\nPlan: %d to create, %d to update, %d unchanged, %d links to add.\n", create, update, unchanged, links)
+}
+
+func quote(value string) string {
+	switch {
+	case value == "":
+		return "(none)"
+	case strings.HasSuffix(value, KnownAfterApply):
+		return value
+	}
+
+	value = strings.Join(strings.Fields(value), " ")
+	return fmt.Sprintf("%q", stringutils.Truncate(value, maxValueWidth))
+}
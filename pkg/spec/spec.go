@@ -0,0 +1,197 @@
+package spec
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/lburgazzoli/gira/pkg/jira"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// StoreLabel keeps the external ID of an issue in a label
+	StoreLabel = "label"
+	// StoreProperty keeps the external ID of an issue in an entity property
+	StoreProperty = "property"
+
+	DefaultLabelPrefix = "gira:"
+	DefaultProperty    = "gira"
+)
+
+// idPattern restricts external IDs to characters valid in labels and JQL
+// strings
+var idPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Spec describes a set of issues, identified by stable external IDs, to be
+// created or updated in a project
+type Spec struct {
+	Project    string     `yaml:"project"`
+	ExternalID ExternalID `yaml:"externalId"`
+	Issues     []Issue    `yaml:"issues"`
+}
+
+// ExternalID configures where the external IDs of issues are stored
+type ExternalID struct {
+	// Store is StoreLabel (default) or StoreProperty
+	Store string `yaml:"store"`
+	// Prefix of the labels holding external IDs
+	Prefix string `yaml:"prefix"`
+	// Property is the entity property holding external IDs
+	Property string `yaml:"property"`
+	// Scope is the JQL query searched for issues holding an external ID
+	// property, by default the whole project
+	Scope string `yaml:"scope"`
+}
+
+// Issue is the desired state of an issue and of its children
+type Issue struct {
+	ID          string         `yaml:"id"`
+	Type        string         `yaml:"type"`
+	Summary     string         `yaml:"summary"`
+	Description string         `yaml:"description"`
+	Assignee    string         `yaml:"assignee"`
+	Priority    string         `yaml:"priority"`
+	Labels      []string       `yaml:"labels"`
+	Components  []string       `yaml:"components"`
+	FixVersions []string       `yaml:"fixVersions"`
+	Fields      map[string]any `yaml:"fields"`
+	Links       []Link         `yaml:"links"`
+	Children    []Issue        `yaml:"children"`
+}
+
+// Link relates an issue to another issue of the spec, by external ID, or to
+// any issue by key
+type Link struct {
+	// Type is a relation such as "blocks" or "is blocked by", or a link type name
+	Type string `yaml:"type"`
+	To   string `yaml:"to"`
+}
+
+// Load reads and validates a spec file
+func Load(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spec: %w", err)
+	}
+
+	spec, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid spec %s: %w", path, err)
+	}
+
+	return spec, nil
+}
+
+// Parse decodes and validates a spec, filling in defaults
+func Parse(data []byte) (*Spec, error) {
+	var spec Spec
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&spec); err != nil {
+		return nil, err
+	}
+
+	spec.Project = strings.ToUpper(strings.TrimSpace(spec.Project))
+	if spec.Project == "" {
+		return nil, fmt.Errorf("project is required")
+	}
+
+	ext := &spec.ExternalID
+	switch ext.Store {
+	case "":
+		ext.Store = StoreLabel
+	case StoreLabel, StoreProperty:
+	default:
+		return nil, fmt.Errorf("externalId.store must be %s or %s, not %q", StoreLabel, StoreProperty, ext.Store)
+	}
+	if ext.Prefix == "" {
+		ext.Prefix = DefaultLabelPrefix
+	}
+	if ext.Property == "" {
+		ext.Property = DefaultProperty
+	}
+	if ext.Scope == "" {
+		ext.Scope = fmt.Sprintf("project = %q", spec.Project)
+	}
+
+	if len(spec.Issues) == 0 {
+		return nil, fmt.Errorf("no issues are defined")
+	}
+
+	ids := make(map[string]bool)
+	if err := spec.Walk(func(issue *Issue, _ *Issue) error {
+		return validateIssue(issue, ids)
+	}); err != nil {
+		return nil, err
+	}
+
+	// Links are checked once all the IDs are known
+	if err := spec.Walk(func(issue *Issue, _ *Issue) error {
+		for _, link := range issue.Links {
+			if strings.TrimSpace(link.Type) == "" {
+				return fmt.Errorf("issue %s: links need a type", issue.ID)
+			}
+			if !ids[link.To] && !jira.IsIssueKey(link.To) {
+				return fmt.Errorf("issue %s: link target %q is neither an issue ID of the spec nor an issue key", issue.ID, link.To)
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &spec, nil
+}
+
+// Walk visits the issues of the spec depth first, parents before their
+// children. The parent is nil for top level issues.
+func (s *Spec) Walk(visit func(issue *Issue, parent *Issue) error) error {
+	var walk func(issues []Issue, parent *Issue) error
+	walk = func(issues []Issue, parent *Issue) error {
+		for i := range issues {
+			if err := visit(&issues[i], parent); err != nil {
+				return err
+			}
+			if err := walk(issues[i].Children, &issues[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return walk(s.Issues, nil)
+}
+
+// Label returns the label holding an external ID
+func (s *Spec) Label(id string) string {
+	return s.ExternalID.Prefix + id
+}
+
+func validateIssue(issue *Issue, ids map[string]bool) error {
+	switch {
+	case issue.ID == "":
+		return fmt.Errorf("issue %q has no id", issue.Summary)
+	case !idPattern.MatchString(issue.ID):
+		return fmt.Errorf("issue id %q may only contain letters, digits, '.', '_' and '-'", issue.ID)
+	case ids[issue.ID]:
+		return fmt.Errorf("issue id %q is used more than once", issue.ID)
+	case strings.TrimSpace(issue.Summary) == "":
+		return fmt.Errorf("issue %s has no summary", issue.ID)
+	case strings.TrimSpace(issue.Type) == "":
+		return fmt.Errorf("issue %s has no type", issue.ID)
+	}
+
+	for _, label := range issue.Labels {
+		if label == "" || strings.ContainsAny(label, " \t") {
+			return fmt.Errorf("issue %s: label %q may not be empty or contain spaces", issue.ID, label)
+		}
+	}
+
+	ids[issue.ID] = true
+
+	return nil
+}
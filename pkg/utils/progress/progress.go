@@ -0,0 +1,71 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	sizeutils "github.com/lburgazzoli/gira/pkg/utils/size"
+	"github.com/mattn/go-isatty"
+)
+
+// refreshInterval throttles how often the progress line is redrawn
+const refreshInterval = 100 * time.Millisecond
+
+// Counter is an io.Writer that counts the bytes of a transfer and reports
+// them on a single terminal line. Nothing is reported when the output is not
+// a terminal.
+type Counter struct {
+	out         io.Writer
+	interactive bool
+	label       string
+	total       int64
+	done        int64
+	drawn       time.Time
+}
+
+// NewCounter returns a counter reporting on stderr. total may be 0 when the
+// size of the transfer is unknown.
+func NewCounter(label string, total int64) *Counter {
+	return &Counter{
+		out:         os.Stderr,
+		interactive: isatty.IsTerminal(os.Stderr.Fd()),
+		label:       label,
+		total:       total,
+	}
+}
+
+func (c *Counter) Write(p []byte) (int, error) {
+	c.done += int64(len(p))
+
+	if c.interactive && time.Since(c.drawn) >= refreshInterval {
+		c.draw()
+	}
+
+	return len(p), nil
+}
+
+// Reset restarts the count, e.g. when a transfer is retried
+func (c *Counter) Reset() {
+	c.done = 0
+}
+
+// Done clears the progress line
+func (c *Counter) Done() {
+	if c.interactive {
+		_, _ = fmt.Fprint(c.out, "\r\033[K")
+	}
+}
+
+func (c *Counter) draw() {
+	c.drawn = time.Now()
+
+	if c.total > 0 {
+		_, _ = fmt.Fprintf(c.out, "\r\033[K%s %3d%% (%s / %s)",
+			c.label, c.done*100/c.total, sizeutils.Format(c.done), sizeutils.Format(c.total))
+		return
+	}
+
+	_, _ = fmt.Fprintf(c.out, "\r\033[K%s %s", c.label, sizeutils.Format(c.done))
+}
@@ -0,0 +1,59 @@
+package size
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	KB int64 = 1 << (10 * (iota + 1))
+	MB
+	GB
+)
+
+// Parse parses a size in bytes such as "512", "200KB", "10MB" or "1.5GB".
+// Units are binary multiples and case insensitive; the trailing B is optional.
+func Parse(s string) (int64, error) {
+	value := strings.ToUpper(strings.TrimSpace(s))
+	if value == "" {
+		return 0, fmt.Errorf("empty size")
+	}
+
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffix string
+		size   int64
+	}{
+		{"GB", GB}, {"MB", MB}, {"KB", KB},
+		{"G", GB}, {"M", MB}, {"K", KB},
+		{"B", 1},
+	} {
+		if strings.HasSuffix(value, unit.suffix) {
+			value = strings.TrimSpace(strings.TrimSuffix(value, unit.suffix))
+			multiplier = unit.size
+			break
+		}
+	}
+
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q: expected values such as 512KB or 10MB", s)
+	}
+
+	return int64(n * float64(multiplier)), nil
+}
+
+// Format formats a size in bytes with a binary unit, e.g. "1.5 MB"
+func Format(n int64) string {
+	switch {
+	case n >= GB:
+		return fmt.Sprintf("%.1f GB", float64(n)/float64(GB))
+	case n >= MB:
+		return fmt.Sprintf("%.1f MB", float64(n)/float64(MB))
+	case n >= KB:
+		return fmt.Sprintf("%.1f KB", float64(n)/float64(KB))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
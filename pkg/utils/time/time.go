@@ -0,0 +1,181 @@
+package time
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DateLayout is the layout used for plain dates on the command line
+	DateLayout = "2006-01-02"
+
+	day  = 24 * time.Hour
+	week = 7 * day
+)
+
+// ParseSince resolves a point in time relative to now. It accepts relative
+// durations such as "2w", "3d" or "12h", dates such as "2025-06-01", RFC3339
+// timestamps and the keywords "today" and "yesterday".
+func ParseSince(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, fmt.Errorf("empty time specification")
+	}
+
+	switch strings.ToLower(s) {
+	case "now":
+		return now, nil
+	case "today":
+		return StartOfDay(now), nil
+	case "yesterday":
+		return StartOfDay(now).AddDate(0, 0, -1), nil
+	}
+
+	if t, err := time.ParseInLocation(DateLayout, s, now.Location()); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+
+	d, err := ParseRelativeDuration(s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time specification %q: expected a duration (e.g. 2w, 3d), a date (YYYY-MM-DD) or a timestamp", s)
+	}
+
+	return now.Add(-d), nil
+}
+
+// ParseRelativeDuration parses a duration that, on top of the units supported
+// by time.ParseDuration, accepts calendar days (d) and weeks (w).
+func ParseRelativeDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("empty duration")
+	}
+
+	var total time.Duration
+	rest := s
+
+	for rest != "" {
+		i := 0
+		for i < len(rest) && (rest[i] >= '0' && rest[i] <= '9') {
+			i++
+		}
+		if i == 0 {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+
+		j := i
+		for j < len(rest) && (rest[j] < '0' || rest[j] > '9') {
+			j++
+		}
+
+		n, err := strconv.Atoi(rest[:i])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q: %w", s, err)
+		}
+
+		unit := rest[i:j]
+		switch unit {
+		case "w":
+			total += time.Duration(n) * week
+		case "d":
+			total += time.Duration(n) * day
+		default:
+			d, err := time.ParseDuration(rest[:j])
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q: %w", s, err)
+			}
+			total += d
+		}
+
+		rest = rest[j:]
+	}
+
+	return total, nil
+}
+
+// StartOfDay truncates t to midnight in its own location
+func StartOfDay(t time.Time) time.Time {
+	year, month, d := t.Date()
+	return time.Date(year, month, d, 0, 0, 0, 0, t.Location())
+}
+
+const (
+	// WorkDay and WorkWeek are the lengths of the d and w units of time
+	// tracking durations, as configured by default in JIRA
+	WorkDay  = 8 * time.Hour
+	WorkWeek = 5 * WorkDay
+)
+
+// ParseWorkDuration parses a time tracking duration in the JIRA format, such
+// as "2h30m", "1d 4h" or "1.5h", where a day (d) is 8 hours and a week (w) is
+// 5 days.
+func ParseWorkDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(strings.ToLower(s))
+	if s == "" {
+		return 0, fmt.Errorf("empty duration")
+	}
+
+	var total time.Duration
+	rest := strings.ReplaceAll(s, " ", "")
+
+	for rest != "" {
+		i := 0
+		for i < len(rest) && ((rest[i] >= '0' && rest[i] <= '9') || rest[i] == '.') {
+			i++
+		}
+		if i == 0 || i == len(rest) {
+			return 0, fmt.Errorf("invalid duration %q: expected values such as 2h30m or 1d 4h", s)
+		}
+
+		n, err := strconv.ParseFloat(rest[:i], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q: %w", s, err)
+		}
+
+		var unit time.Duration
+		switch rest[i] {
+		case 'w':
+			unit = WorkWeek
+		case 'd':
+			unit = WorkDay
+		case 'h':
+			unit = time.Hour
+		case 'm':
+			unit = time.Minute
+		default:
+			return 0, fmt.Errorf("invalid duration %q: unknown unit %q", s, rest[i])
+		}
+
+		total += time.Duration(n * float64(unit))
+		rest = rest[i+1:]
+	}
+
+	if total < time.Minute {
+		return 0, fmt.Errorf("invalid duration %q: at least one minute is required", s)
+	}
+
+	return total.Round(time.Minute), nil
+}
+
+// FormatWorkDuration formats a time tracking duration in hours and minutes,
+// e.g. "2h 30m"
+func FormatWorkDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+
+	hours := int(d / time.Hour)
+	minutes := int((d % time.Hour) / time.Minute)
+
+	switch {
+	case hours == 0:
+		return fmt.Sprintf("%dm", minutes)
+	case minutes == 0:
+		return fmt.Sprintf("%dh", hours)
+	default:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	}
+}
@@ -0,0 +1,85 @@
+package time
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRelativeDuration(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{in: "2w", want: 14 * 24 * time.Hour},
+		{in: "3d", want: 72 * time.Hour},
+		{in: "12h", want: 12 * time.Hour},
+		{in: "90m", want: 90 * time.Minute},
+		{in: "1w2d", want: 9 * 24 * time.Hour},
+		{in: "1d12h30m", want: 36*time.Hour + 30*time.Minute},
+		{in: " 5d ", want: 5 * 24 * time.Hour},
+		{in: "", wantErr: true},
+		{in: "d", wantErr: true},
+		{in: "3", wantErr: true},
+		{in: "3y", wantErr: true},
+		{in: "-2d", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseRelativeDuration(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parsing %q gave %s, want an error", tt.in, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	loc := time.FixedZone("CEST", 2*60*60)
+	now := time.Date(2025, 6, 18, 15, 30, 0, 0, loc)
+
+	tests := []struct {
+		in      string
+		want    time.Time
+		wantErr bool
+	}{
+		{in: "now", want: now},
+		{in: "Today", want: time.Date(2025, 6, 18, 0, 0, 0, 0, loc)},
+		{in: "yesterday", want: time.Date(2025, 6, 17, 0, 0, 0, 0, loc)},
+		{in: "2w", want: now.AddDate(0, 0, -14)},
+		{in: "36h", want: now.Add(-36 * time.Hour)},
+		{in: "2025-06-01", want: time.Date(2025, 6, 1, 0, 0, 0, 0, loc)},
+		{in: "2025-06-01T10:00:00Z", want: time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)},
+		{in: "", wantErr: true},
+		{in: "last sprint", wantErr: true},
+		{in: "2025-13-01", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseSince(tt.in, now)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parsing %q gave %s, want an error", tt.in, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}